@@ -9,13 +9,18 @@ import (
 	"os/signal"
 	"syscall"
 	"time"
+	_ "time/tzdata" // Часовые пояса пользователей; в образе нет системной базы tzdata
 
 	"github.com/singl3focus/uniflow/config"
 	_ "github.com/singl3focus/uniflow/docs" // Swagger docs
 	inhttp "github.com/singl3focus/uniflow/internal/adapters/http"
 	"github.com/singl3focus/uniflow/internal/adapters/max"
 	"github.com/singl3focus/uniflow/internal/adapters/postgres"
+	"github.com/singl3focus/uniflow/internal/adapters/redis"
+	"github.com/singl3focus/uniflow/internal/core/models"
+	"github.com/singl3focus/uniflow/internal/core/ports/notifier"
 	"github.com/singl3focus/uniflow/internal/core/usecase"
+	"github.com/singl3focus/uniflow/internal/core/workers"
 	"github.com/singl3focus/uniflow/pkg/jwt"
 	zerologger "github.com/singl3focus/uniflow/pkg/logger/zerolog-wrap"
 )
@@ -48,11 +53,23 @@ func main() {
 
 	log := zerologger.NewZeroLogger(os.Stdout, cfg.LoggerLevel())
 
-	jm := jwt.NewJWTManager(cfg.JWTSecret(), time.Hour*24)
+	jm := jwt.NewJWTManager(cfg.JWTSecret(), cfg.JWTAccessTTL())
 
 	repo := postgres.NewPostgres(cfg.PGDSN())
 	defer repo.Close()
-	uc := usecase.NewUsecase(repo, jm)
+	ucOpts := []usecase.Option{usecase.WithRefreshTokenTTL(cfg.JWTRefreshTTL())}
+	if offsets := cfg.TaskReminderOffsets(); offsets != nil {
+		ucOpts = append(ucOpts, usecase.WithReminderOffsets(offsets))
+	}
+	uc := usecase.NewUsecase(repo, jm, ucOpts...)
+
+	// Фоновые воркеры живут до остановки HTTP сервера
+	workersCtx, stopWorkers := context.WithCancel(context.Background())
+	defer stopWorkers()
+	supervisor := workers.NewSupervisor(log)
+
+	// Отправщики уведомлений по каналам
+	senders := make(map[models.NotificationChannel]notifier.Sender)
 
 	// Инициализация MAX клиента (опционально)
 	var maxWebhook http.Handler
@@ -63,44 +80,91 @@ func main() {
 		} else {
 			log.Info("MAX client initialized successfully")
 
+			maxNotifications := max.NewNotificationService(maxClient)
+			senders[models.NotificationChannelMax] = maxNotifications
+
+			// Утренние сводки и вечерние обзоры в выбранное пользователями время
+			digestScheduler := workers.NewDigestScheduler(repo, uc, maxNotifications, log, workers.DefaultDigestSchedulerConfig())
+			supervisor.Go(workersCtx, "digest-scheduler", digestScheduler.Run)
+
+			// Состояния диалогов бота: в Redis, если он настроен, иначе в памяти процесса
+			var states max.StateStore = max.NewMemoryStateStore()
+			if cfg.RedisAddr() != "" {
+				redisAdapter := redis.NewAdapter(cfg.RedisAddr(), cfg.RedisPassword(), cfg.RedisDB())
+				defer redisAdapter.Close()
+				if err := redisAdapter.Ping(context.Background()); err != nil {
+					log.Warn("redis is unavailable, bot dialogs will fail until it is up", "error", err)
+				}
+				states = max.NewRedisStateStore(redisAdapter, cfg.MaxDialogTTL())
+			} else {
+				log.Warn("REDIS_ADDR not set, bot dialog states are kept in memory")
+			}
+
 			// Создание обработчика обновлений UniFlow
-			updateHandler := max.NewUniFlowUpdateHandler(maxClient, uc, log)
+			updateHandler := max.NewUniFlowUpdateHandler(maxClient, uc, states, cfg.MaxDialogTTL(), log)
+			supervisor.Go(workersCtx, "bot-dialog-expiry", updateHandler.RunStateExpiry)
+
+			// Обновления обрабатываются пулом воркеров; при остановке очереди дорабатываются
+			updateDispatcher := max.NewUpdateDispatcher(updateHandler, log, max.DispatcherConfig{
+				Workers:   cfg.MaxUpdateWorkers(),
+				QueueSize: cfg.MaxUpdateQueueSize(),
+			})
+			supervisor.Go(workersCtx, "bot-update-dispatcher", updateDispatcher.Run)
 
 			// Выбор режима: webhook или long polling
 			if cfg.MaxWebhookURL() != "" {
 				// Режим webhook (для продакшена с публичным URL)
-				ctx := context.Background()
-				if err := maxClient.SetWebhook(ctx, cfg.MaxWebhookURL()); err != nil {
+				if cfg.MaxWebhookSecret() == "" {
+					log.Warn("MAX_WEBHOOK_SECRET not set, webhook requests are not authenticated")
+				}
+
+				// Недоступный MAX API не должен блокировать запуск сервиса
+				ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+				if err := maxClient.SetWebhook(ctx, cfg.MaxWebhookURL(), cfg.MaxWebhookSecret()); err != nil {
 					log.Error("failed to set MAX webhook", "error", err)
 				} else {
 					log.Info("MAX webhook set successfully", "url", cfg.MaxWebhookURL())
 				}
+				cancel()
 
-				// Создание HTTP обработчика для webhook
-				maxWebhook = max.NewWebhookHandler(maxClient, updateHandler)
+				// Создание HTTP обработчика для webhook: обновления только ставятся в очередь диспетчера
+				maxWebhook = max.NewWebhookHandler(updateDispatcher, cfg.MaxWebhookSecret(), log)
 			} else {
 				// Режим long polling (для локальной разработки)
 				log.Info("MAX webhook URL not set, using long polling mode")
 
-				// Запускаем обработку обновлений в отдельной горутине
-				go func() {
-					ctx := context.Background()
-					log.Info("Starting MAX bot updates polling")
-
-					for update := range maxClient.GetUpdates(ctx) {
-						// Обрабатываем каждое обновление
-						updateHandler.HandleUpdate(update)
-					}
-
-					log.Info("MAX bot updates polling stopped")
-				}()
+				supervisor.Go(workersCtx, "bot-updates-polling", func(ctx context.Context) error {
+					return updateDispatcher.Poll(ctx, maxClient)
+				})
 			}
 		}
 	} else {
 		log.Warn("MAX bot token not configured, MAX integration disabled")
 	}
 
-	handler := inhttp.NewHandler(log, uc, maxWebhook, cfg.JWTSecret())
+	if len(senders) > 0 {
+		dispatcherCfg := workers.DefaultDispatcherConfig()
+		dispatcherCfg.PollInterval = cfg.NotificationsPollInterval()
+		dispatcherCfg.BatchSize = cfg.NotificationsBatchSize()
+		dispatcherCfg.MaxAttempts = cfg.NotificationsMaxAttempts()
+
+		dispatcher := workers.NewNotificationDispatcher(repo, senders, log, dispatcherCfg)
+		supervisor.Go(workersCtx, "notification-dispatcher", dispatcher.Run)
+	} else {
+		log.Warn("no notification senders configured, notification dispatcher disabled")
+	}
+
+	if cfg.AuthAllowUserIDHeader() {
+		log.Warn("X-User-ID authentication is enabled, do not use it in production")
+	}
+
+	handler := inhttp.NewHandler(log, uc, maxWebhook, inhttp.AuthConfig{
+		JWTSecret:         cfg.JWTSecret(),
+		AccessTokenTTL:    cfg.JWTAccessTTL(),
+		MaxBotToken:       cfg.MaxBotToken(),
+		InitDataMaxAge:    cfg.AuthInitDataMaxAge(),
+		AllowUserIDHeader: cfg.AuthAllowUserIDHeader(),
+	})
 
 	addr := fmt.Sprintf(":%d", cfg.HTTPPort())
 	srv := &http.Server{
@@ -145,6 +209,10 @@ func main() {
 		log.Error("server shutdown failed", "error", err)
 	}
 
+	log.Info("stopping background workers")
+	stopWorkers()
+	supervisor.Wait()
+
 	if err := log.Flush(); err != nil {
 		log.Error("Failed to flush logs", "error", err)
 	}
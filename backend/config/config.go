@@ -1,6 +1,10 @@
 package config
 
-import "github.com/singl3focus/uniflow/config/env"
+import (
+	"time"
+
+	"github.com/singl3focus/uniflow/config/env"
+)
 
 type Config interface {
 	Load(path string) error
@@ -9,7 +13,11 @@ type Config interface {
 	PGConfig
 	LoggerConfig
 	JWTConfig
+	AuthConfig
 	MaxConfig
+	RedisConfig
+	NotificationsConfig
+	ReminderConfig
 }
 
 type LoggerConfig interface {
@@ -18,6 +26,16 @@ type LoggerConfig interface {
 
 type JWTConfig interface {
 	JWTSecret() string
+	JWTAccessTTL() time.Duration
+	JWTRefreshTTL() time.Duration
+}
+
+type AuthConfig interface {
+	// AuthInitDataMaxAge - сколько init data мини-приложения MAX остается пригодной для входа
+	AuthInitDataMaxAge() time.Duration
+	// AuthAllowUserIDHeader разрешает аутентификацию заголовком X-User-ID без JWT.
+	// Должен быть выключен в продакшене.
+	AuthAllowUserIDHeader() bool
 }
 
 type HTTPConfig interface {
@@ -31,6 +49,32 @@ type PGConfig interface {
 type MaxConfig interface {
 	MaxBotToken() string
 	MaxWebhookURL() string
+	// MaxWebhookSecret - секрет, который MAX присылает в заголовке X-Max-Bot-Api-Secret
+	MaxWebhookSecret() string
+	// MaxDialogTTL - через сколько без ответа пользователя диалог бота отменяется
+	MaxDialogTTL() time.Duration
+	// MaxUpdateWorkers - число воркеров, параллельно обрабатывающих обновления бота
+	MaxUpdateWorkers() int
+	// MaxUpdateQueueSize - емкость очереди обновлений каждого воркера
+	MaxUpdateQueueSize() int
+}
+
+type RedisConfig interface {
+	// RedisAddr пуст, если Redis не используется
+	RedisAddr() string
+	RedisPassword() string
+	RedisDB() int
+}
+
+type NotificationsConfig interface {
+	NotificationsPollInterval() time.Duration
+	NotificationsBatchSize() int
+	NotificationsMaxAttempts() int
+}
+
+type ReminderConfig interface {
+	// TaskReminderOffsets возвращает nil, если используются значения по умолчанию
+	TaskReminderOffsets() []time.Duration
 }
 
 type ConfigType int
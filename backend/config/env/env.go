@@ -3,6 +3,8 @@ package env
 import (
 	"os"
 	"strconv"
+	"strings"
+	"time"
 
 	"github.com/joho/godotenv"
 )
@@ -12,8 +14,43 @@ const (
 	postgresDSN   = "PG_DSN"
 	loggerLevel   = "LOGGER_LEVEL"
 	jwtSecret     = "JWT_SECRET"
+	jwtAccessTTL  = "JWT_ACCESS_TTL"
+	jwtRefreshTTL = "JWT_REFRESH_TTL"
 	maxBotToken   = "MAX_BOT_TOKEN"
 	maxWebhookURL = "MAX_WEBHOOK_URL"
+
+	maxWebhookSecret   = "MAX_WEBHOOK_SECRET"
+	maxDialogTTL       = "MAX_DIALOG_TTL"
+	maxUpdateWorkers   = "MAX_UPDATE_WORKERS"
+	maxUpdateQueueSize = "MAX_UPDATE_QUEUE_SIZE"
+
+	redisAddr     = "REDIS_ADDR"
+	redisPassword = "REDIS_PASSWORD"
+	redisDB       = "REDIS_DB"
+
+	authInitDataMaxAge    = "AUTH_INIT_DATA_MAX_AGE"
+	authAllowUserIDHeader = "AUTH_ALLOW_USER_ID_HEADER"
+
+	notificationsPollInterval = "NOTIFICATIONS_POLL_INTERVAL"
+	notificationsBatchSize    = "NOTIFICATIONS_BATCH_SIZE"
+	notificationsMaxAttempts  = "NOTIFICATIONS_MAX_ATTEMPTS"
+
+	taskReminderOffsets = "TASK_REMINDER_OFFSETS"
+)
+
+const (
+	defaultJWTAccessTTL  = 15 * time.Minute
+	defaultJWTRefreshTTL = 30 * 24 * time.Hour
+
+	defaultAuthInitDataMaxAge = 24 * time.Hour
+
+	defaultMaxDialogTTL       = 30 * time.Minute
+	defaultMaxUpdateWorkers   = 8
+	defaultMaxUpdateQueueSize = 64
+
+	defaultNotificationsPollInterval = 15 * time.Second
+	defaultNotificationsBatchSize    = 50
+	defaultNotificationsMaxAttempts  = 5
 )
 
 type Config struct{}
@@ -52,6 +89,36 @@ func (c Config) MaxWebhookURL() string {
 	return url
 }
 
+func (c Config) MaxWebhookSecret() string {
+	// 5-256 символов из A-Z, a-z, 0-9, _ и -; пустой секрет отключает проверку
+	return os.Getenv(maxWebhookSecret)
+}
+
+func (c Config) MaxDialogTTL() time.Duration {
+	return durationOrDefault(maxDialogTTL, defaultMaxDialogTTL)
+}
+
+func (c Config) MaxUpdateWorkers() int {
+	return intOrDefault(maxUpdateWorkers, defaultMaxUpdateWorkers)
+}
+
+func (c Config) MaxUpdateQueueSize() int {
+	return intOrDefault(maxUpdateQueueSize, defaultMaxUpdateQueueSize)
+}
+
+func (c Config) RedisAddr() string {
+	// Без Redis состояния диалогов бота хранятся в памяти процесса
+	return os.Getenv(redisAddr)
+}
+
+func (c Config) RedisPassword() string {
+	return os.Getenv(redisPassword)
+}
+
+func (c Config) RedisDB() int {
+	return intOrDefault(redisDB, 0)
+}
+
 func (c Config) PGDSN() string {
 	dsn := os.Getenv(postgresDSN)
 	if dsn == "" {
@@ -78,3 +145,89 @@ func (c Config) JWTSecret() string {
 
 	return secret
 }
+
+func (c Config) JWTAccessTTL() time.Duration {
+	return durationOrDefault(jwtAccessTTL, defaultJWTAccessTTL)
+}
+
+func (c Config) JWTRefreshTTL() time.Duration {
+	return durationOrDefault(jwtRefreshTTL, defaultJWTRefreshTTL)
+}
+
+func (c Config) AuthInitDataMaxAge() time.Duration {
+	return durationOrDefault(authInitDataMaxAge, defaultAuthInitDataMaxAge)
+}
+
+func (c Config) AuthAllowUserIDHeader() bool {
+	str := os.Getenv(authAllowUserIDHeader)
+	if str == "" {
+		return false
+	}
+
+	allow, err := strconv.ParseBool(str)
+	if err != nil {
+		panic(err.Error() + " " + authAllowUserIDHeader + ": " + str)
+	}
+
+	return allow
+}
+
+func (c Config) NotificationsPollInterval() time.Duration {
+	return durationOrDefault(notificationsPollInterval, defaultNotificationsPollInterval)
+}
+
+func (c Config) NotificationsBatchSize() int {
+	return intOrDefault(notificationsBatchSize, defaultNotificationsBatchSize)
+}
+
+func (c Config) NotificationsMaxAttempts() int {
+	return intOrDefault(notificationsMaxAttempts, defaultNotificationsMaxAttempts)
+}
+
+func (c Config) TaskReminderOffsets() []time.Duration {
+	str := os.Getenv(taskReminderOffsets)
+	if str == "" {
+		return nil
+	}
+
+	var offsets []time.Duration
+	for _, part := range strings.Split(str, ",") {
+		d, err := time.ParseDuration(strings.TrimSpace(part))
+		if err != nil {
+			panic(err.Error() + " " + taskReminderOffsets + ": " + str)
+		}
+		offsets = append(offsets, d)
+	}
+
+	return offsets
+}
+
+// durationOrDefault читает необязательную переменную в формате time.ParseDuration
+func durationOrDefault(key string, def time.Duration) time.Duration {
+	str := os.Getenv(key)
+	if str == "" {
+		return def
+	}
+
+	d, err := time.ParseDuration(str)
+	if err != nil {
+		panic(err.Error() + " " + key + ": " + str)
+	}
+
+	return d
+}
+
+// intOrDefault читает необязательную целочисленную переменную
+func intOrDefault(key string, def int) int {
+	str := os.Getenv(key)
+	if str == "" {
+		return def
+	}
+
+	i, err := strconv.Atoi(str)
+	if err != nil {
+		panic(err.Error() + " " + key + ": " + str)
+	}
+
+	return i
+}
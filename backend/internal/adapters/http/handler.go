@@ -13,9 +13,19 @@ import (
 	"github.com/singl3focus/uniflow/internal/core/usecase"
 	jwtpkg "github.com/singl3focus/uniflow/pkg/jwt"
 	"github.com/singl3focus/uniflow/pkg/logger"
+	"github.com/singl3focus/uniflow/pkg/maxauth"
 )
 
-func NewHandler(log logger.Logger, uc *usecase.Usecase, maxWebhook http.Handler, jwtSecret string) http.Handler {
+// AuthConfig - параметры аутентификации API
+type AuthConfig struct {
+	JWTSecret         string
+	AccessTokenTTL    time.Duration
+	MaxBotToken       string        // Токен бота, которым MAX подписывает init data мини-приложения
+	InitDataMaxAge    time.Duration // Максимальный возраст init data при входе
+	AllowUserIDHeader bool          // Принимать X-User-ID без JWT; только для локальной разработки
+}
+
+func NewHandler(log logger.Logger, uc *usecase.Usecase, maxWebhook http.Handler, auth AuthConfig) http.Handler {
 	r := chi.NewRouter()
 
 	// Настройка CORS - разрешаем все источники
@@ -32,7 +42,7 @@ func NewHandler(log logger.Logger, uc *usecase.Usecase, maxWebhook http.Handler,
 	r.Use(middleware.Recover(log))
 	r.Use(middleware.Logger(log))
 
-	jwtManager := jwtpkg.NewJWTManager(jwtSecret, 24*time.Hour)
+	jwtManager := jwtpkg.NewJWTManager(auth.JWTSecret, auth.AccessTokenTTL)
 
 	r.Get("/health", func(w http.ResponseWriter, r *http.Request) {
 		logger := log.WithContext(r.Context())
@@ -46,12 +56,19 @@ func NewHandler(log logger.Logger, uc *usecase.Usecase, maxWebhook http.Handler,
 	))
 
 	r.Route("/api", func(r chi.Router) {
-		authHandler := handlers.NewAuthHandler(uc, log)
+		authHandler := handlers.NewAuthHandler(uc, maxauth.NewValidator(auth.MaxBotToken, auth.InitDataMaxAge), log)
 		r.Post("/auth/max", authHandler.AuthWithMAX)
+		r.Post("/auth/refresh", authHandler.RefreshTokens)
+		r.Post("/auth/logout", authHandler.Logout)
 
 		// Protected routes (требуют аутентификацию)
 		r.Group(func(r chi.Router) {
-			r.Use(middleware.RequireAuth(jwtManager))
+			r.Use(middleware.RequireAuth(jwtManager, uc, auth.AllowUserIDHeader))
+
+			// Settings
+			settingsHandler := handlers.NewSettingsHandler(uc, log)
+			r.Get("/me/settings", settingsHandler.GetSettings)
+			r.Patch("/me/settings", settingsHandler.UpdateSettings)
 
 			// Contexts
 			contextHandler := handlers.NewContextHandler(uc, log)
@@ -61,6 +78,15 @@ func NewHandler(log logger.Logger, uc *usecase.Usecase, maxWebhook http.Handler,
 			r.Patch("/contexts/{id}", contextHandler.UpdateContext)
 			r.Delete("/contexts/{id}", contextHandler.DeleteContext)
 
+			// Notes
+			noteHandler := handlers.NewNoteHandler(uc, log)
+			r.Get("/notes", noteHandler.GetNotes)
+			r.Post("/notes", noteHandler.CreateNote)
+			r.Get("/notes/{id}", noteHandler.GetNote)
+			r.Patch("/notes/{id}", noteHandler.UpdateNote)
+			r.Delete("/notes/{id}", noteHandler.DeleteNote)
+			r.Get("/contexts/{id}/notes", noteHandler.GetContextNotes)
+
 			// Tasks
 			taskHandler := handlers.NewTaskHandler(uc, log)
 			r.Get("/tasks", taskHandler.GetTasks)
@@ -71,6 +97,40 @@ func NewHandler(log logger.Logger, uc *usecase.Usecase, maxWebhook http.Handler,
 			r.Patch("/tasks/{id}/status", taskHandler.UpdateTaskStatus)
 			r.Delete("/tasks/{id}", taskHandler.DeleteTask)
 
+			// Subtasks
+			subtaskHandler := handlers.NewSubtaskHandler(uc, log)
+			r.Get("/tasks/{id}/subtasks", subtaskHandler.GetSubtasks)
+			r.Post("/tasks/{id}/subtasks", subtaskHandler.CreateSubtask)
+			r.Patch("/tasks/{id}/subtasks/{subtaskID}", subtaskHandler.UpdateSubtask)
+			r.Delete("/tasks/{id}/subtasks/{subtaskID}", subtaskHandler.DeleteSubtask)
+
+			// Tags
+			tagHandler := handlers.NewTagHandler(uc, log)
+			r.Get("/tags", tagHandler.GetTags)
+			r.Post("/tags", tagHandler.CreateTag)
+			r.Delete("/tags/{id}", tagHandler.DeleteTag)
+			r.Post("/tasks/{id}/tags", tagHandler.AddTaskTags)
+			r.Delete("/tasks/{id}/tags/{name}", tagHandler.RemoveTaskTag)
+
+			// Schedule
+			scheduleHandler := handlers.NewScheduleHandler(uc, log)
+			r.Get("/schedule", scheduleHandler.GetSchedule)
+			r.Post("/schedule", scheduleHandler.CreateScheduleEntry)
+			r.Get("/schedule/{id}", scheduleHandler.GetScheduleEntry)
+			r.Patch("/schedule/{id}", scheduleHandler.UpdateScheduleEntry)
+			r.Delete("/schedule/{id}", scheduleHandler.DeleteScheduleEntry)
+
+			// Focus sessions
+			focusHandler := handlers.NewFocusSessionHandler(uc, log)
+			r.Get("/focus-sessions", focusHandler.GetFocusSessions)
+			r.Post("/focus-sessions", focusHandler.StartFocusSession)
+			r.Get("/focus-sessions/active", focusHandler.GetActiveFocusSession)
+			r.Post("/focus-sessions/{id}/stop", focusHandler.StopFocusSession)
+
+			// Stats
+			statsHandler := handlers.NewStatsHandler(uc, log)
+			r.Get("/stats", statsHandler.GetStats)
+
 			// Search
 			searchHandler := handlers.NewSearchHandler(uc, log)
 			r.Get("/search", searchHandler.Search)
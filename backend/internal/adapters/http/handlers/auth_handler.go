@@ -2,40 +2,54 @@ package handlers
 
 import (
 	"encoding/json"
+	"errors"
 	"net/http"
+	"strconv"
+	"time"
 
 	"github.com/singl3focus/uniflow/internal/adapters/http/response"
 	"github.com/singl3focus/uniflow/internal/core/usecase"
 	"github.com/singl3focus/uniflow/pkg/logger"
+	"github.com/singl3focus/uniflow/pkg/maxauth"
 )
 
 type AuthHandler struct {
-	uc  *usecase.Usecase
-	log logger.Logger
+	uc        *usecase.Usecase
+	validator *maxauth.Validator
+	log       logger.Logger
 }
 
-func NewAuthHandler(uc *usecase.Usecase, log logger.Logger) *AuthHandler {
-	return &AuthHandler{uc: uc, log: log}
+func NewAuthHandler(uc *usecase.Usecase, validator *maxauth.Validator, log logger.Logger) *AuthHandler {
+	return &AuthHandler{uc: uc, validator: validator, log: log}
 }
 
 type AuthWithMAXRequest struct {
-	MaxUserID string `json:"max_user_id"`
+	InitData string `json:"init_data"` // window.WebApp.initData мини-приложения MAX как есть
 }
 
 type AuthWithMAXResponse struct {
-	UserID      string `json:"user_id"`
-	MaxUserID   string `json:"max_user_id"`
-	AccessToken string `json:"access_token"` // JWT token
+	UserID          string    `json:"user_id"`
+	MaxUserID       string    `json:"max_user_id"`
+	AccessToken     string    `json:"access_token"`  // JWT token
+	RefreshToken    string    `json:"refresh_token"` // Одноразовый токен для /auth/refresh
+	AccessExpiresAt time.Time `json:"access_expires_at"`
+}
+
+type RefreshTokenRequest struct {
+	RefreshToken string `json:"refresh_token"`
 }
 
 // AuthWithMAX godoc
 // @Summary      Аутентификация через MAX
-// @Description  Создает или получает пользователя по MAX User ID и возвращает JWT токен
+// @Description  Проверяет подпись init data мини-приложения MAX, создает или получает пользователя и возвращает пару access/refresh токенов.
+// @Description  Неподписанные, поддельные и устаревшие init data отклоняются
 // @Tags         auth
-// @Param        request body AuthWithMAXRequest true "MAX User ID"
+// @Param        request body AuthWithMAXRequest true "Init data мини-приложения"
 // @Success      200 {object} AuthWithMAXResponse
 // @Failure      400 {object} response.ErrorResponse "Некорректный запрос"
+// @Failure      401 {object} response.ErrorResponse "Подпись init data не прошла проверку"
 // @Failure      500 {object} response.ErrorResponse "Внутренняя ошибка сервера"
+// @Failure      503 {object} response.ErrorResponse "Токен бота не настроен"
 // @Router       /auth/max [post]
 func (h *AuthHandler) AuthWithMAX(w http.ResponseWriter, r *http.Request) {
 	ctx := r.Context()
@@ -48,7 +62,23 @@ func (h *AuthHandler) AuthWithMAX(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	user, token, err := h.uc.Login(ctx, req.MaxUserID)
+	if req.InitData == "" {
+		response.Error(w, http.StatusUnauthorized, "init_data required")
+		return
+	}
+
+	data, err := h.validator.Validate(req.InitData)
+	if err != nil {
+		log.Warn("rejected MAX init data", "error", err)
+		if errors.Is(err, maxauth.ErrNotConfigured) {
+			response.Error(w, http.StatusServiceUnavailable, "MAX authentication is not configured")
+			return
+		}
+		response.Error(w, http.StatusUnauthorized, err.Error())
+		return
+	}
+
+	user, tokens, err := h.uc.Login(ctx, strconv.FormatInt(data.User.ID, 10))
 	if err != nil {
 		log.Error("failed to login", "error", err)
 		handleUsecaseError(w, err)
@@ -56,10 +86,71 @@ func (h *AuthHandler) AuthWithMAX(w http.ResponseWriter, r *http.Request) {
 	}
 
 	resp := AuthWithMAXResponse{
-		UserID:      user.ID.String(),
-		MaxUserID:   user.MaxUserID,
-		AccessToken: token,
+		UserID:          user.ID.String(),
+		MaxUserID:       user.MaxUserID,
+		AccessToken:     tokens.AccessToken,
+		RefreshToken:    tokens.RefreshToken,
+		AccessExpiresAt: tokens.AccessExpiresAt,
 	}
 
 	response.Success(w, http.StatusOK, resp)
 }
+
+// RefreshTokens godoc
+// @Summary      Обновить токены
+// @Description  Обменивает refresh-токен на новую пару access/refresh токенов. Старый refresh-токен становится недействительным;
+// @Description  повторное использование уже обмененного токена отзывает все сессии пользователя
+// @Tags         auth
+// @Param        request body RefreshTokenRequest true "Refresh-токен"
+// @Success      200 {object} models.TokenPair
+// @Failure      400 {object} response.ErrorResponse "Некорректный запрос"
+// @Failure      401 {object} response.ErrorResponse "Токен недействителен, истек или отозван"
+// @Failure      500 {object} response.ErrorResponse "Внутренняя ошибка сервера"
+// @Router       /auth/refresh [post]
+func (h *AuthHandler) RefreshTokens(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	log := h.log.WithContext(ctx)
+
+	var req RefreshTokenRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil || req.RefreshToken == "" {
+		response.Error(w, http.StatusBadRequest, "refresh_token required")
+		return
+	}
+
+	tokens, err := h.uc.RefreshTokens(ctx, req.RefreshToken)
+	if err != nil {
+		log.Warn("failed to refresh tokens", "error", err)
+		handleUsecaseError(w, err)
+		return
+	}
+
+	response.Success(w, http.StatusOK, tokens)
+}
+
+// Logout godoc
+// @Summary      Выйти
+// @Description  Отзывает refresh-токен и выданный вместе с ним access-токен. Повторный выход не является ошибкой
+// @Tags         auth
+// @Param        request body RefreshTokenRequest true "Refresh-токен"
+// @Success      200 {object} map[string]string
+// @Failure      400 {object} response.ErrorResponse "Некорректный запрос"
+// @Failure      500 {object} response.ErrorResponse "Внутренняя ошибка сервера"
+// @Router       /auth/logout [post]
+func (h *AuthHandler) Logout(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	log := h.log.WithContext(ctx)
+
+	var req RefreshTokenRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil || req.RefreshToken == "" {
+		response.Error(w, http.StatusBadRequest, "refresh_token required")
+		return
+	}
+
+	if err := h.uc.Logout(ctx, req.RefreshToken); err != nil {
+		log.Error("failed to logout", "error", err)
+		handleUsecaseError(w, err)
+		return
+	}
+
+	response.Success(w, http.StatusOK, map[string]string{"status": "logged out"})
+}
@@ -36,7 +36,7 @@ type UpdateContextRequest struct {
 	Description *string `json:"description"`
 	SubjectID   *string `json:"subject_id"`
 	Color       *string `json:"color"`
-	DeadlineAt  *string `json:"deadline_at"` // ISO 8601 format
+	DeadlineAt  *string `json:"deadline_at"` // ISO 8601 format, "" снимает дедлайн
 }
 
 // GetContexts godoc
@@ -111,7 +111,7 @@ func (h *ContextHandler) CreateContext(w http.ResponseWriter, r *http.Request) {
 // @Summary      Получить контекст по ID
 // @Description  Возвращает подробную информацию о контексте
 // @Tags         contexts
-// @Param        id query string true "Context ID"
+// @Param        id path string true "Context ID"
 // @Success      200 {object} models.Context
 // @Failure      400 {object} response.ErrorResponse "Некорректный запрос"
 // @Failure      404 {object} response.ErrorResponse "Контекст не найден"
@@ -122,13 +122,19 @@ func (h *ContextHandler) GetContext(w http.ResponseWriter, r *http.Request) {
 	ctx := r.Context()
 	log := h.log.WithContext(ctx)
 
+	userIDStr, ok := middleware.GetUserIDFromContext(ctx)
+	if !ok {
+		response.Error(w, http.StatusUnauthorized, "unauthorized")
+		return
+	}
+
 	contextIDStr := chi.URLParam(r, "id")
 	if contextIDStr == "" {
 		response.Error(w, http.StatusBadRequest, "context 'id' required")
 		return
 	}
 
-	context, err := h.uc.GetContextByID(ctx, contextIDStr)
+	context, err := h.uc.GetContextByID(ctx, userIDStr, contextIDStr)
 	if err != nil {
 		log.Error("failed to get context", "error", err)
 		handleUsecaseError(w, err)
@@ -157,6 +163,12 @@ func (h *ContextHandler) UpdateContext(w http.ResponseWriter, r *http.Request) {
 	ctx := r.Context()
 	log := h.log.WithContext(ctx)
 
+	userIDStr, ok := middleware.GetUserIDFromContext(ctx)
+	if !ok {
+		response.Error(w, http.StatusUnauthorized, "unauthorized")
+		return
+	}
+
 	contextIDStr := chi.URLParam(r, "id")
 	if contextIDStr == "" {
 		response.Error(w, http.StatusBadRequest, "context id required")
@@ -175,7 +187,7 @@ func (h *ContextHandler) UpdateContext(w http.ResponseWriter, r *http.Request) {
 		contextType = *req.Type
 	}
 
-	context, err := h.uc.UpdateContext(ctx, contextIDStr, contextType, req.Title, req.Description, req.Color, req.SubjectID, req.DeadlineAt)
+	context, err := h.uc.UpdateContext(ctx, userIDStr, contextIDStr, contextType, req.Title, req.Description, req.Color, req.SubjectID, req.DeadlineAt)
 	if err != nil {
 		log.Error("failed to update context", "error", err)
 		handleUsecaseError(w, err)
@@ -203,13 +215,19 @@ func (h *ContextHandler) DeleteContext(w http.ResponseWriter, r *http.Request) {
 	ctx := r.Context()
 	log := h.log.WithContext(ctx)
 
+	userIDStr, ok := middleware.GetUserIDFromContext(ctx)
+	if !ok {
+		response.Error(w, http.StatusUnauthorized, "unauthorized")
+		return
+	}
+
 	contextIDStr := chi.URLParam(r, "id")
 	if contextIDStr == "" {
 		response.Error(w, http.StatusBadRequest, "context id required")
 		return
 	}
 
-	if err := h.uc.DeleteContext(ctx, contextIDStr); err != nil {
+	if err := h.uc.DeleteContext(ctx, userIDStr, contextIDStr); err != nil {
 		log.Error("failed to delete context", "error", err)
 		handleUsecaseError(w, err)
 		return
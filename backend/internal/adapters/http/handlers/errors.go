@@ -22,6 +22,9 @@ func handleUsecaseError(w http.ResponseWriter, err error) bool {
 	case errors.Is(err, usecase.ErrNotFound):
 		response.Error(w, http.StatusNotFound, fmt.Sprintf("not found: %v", err))
 		return true
+	case errors.Is(err, usecase.ErrUnauthorized):
+		response.Error(w, http.StatusUnauthorized, fmt.Sprintf("unauthorized: %v", err))
+		return true
 	case errors.Is(err, usecase.ErrInternal):
 		response.Error(w, http.StatusInternalServerError, fmt.Sprintf("internal server error: %v", err))
 		return true
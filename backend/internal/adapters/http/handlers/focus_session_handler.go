@@ -0,0 +1,159 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+
+	"github.com/singl3focus/uniflow/internal/adapters/http/middleware"
+	"github.com/singl3focus/uniflow/internal/adapters/http/response"
+	"github.com/singl3focus/uniflow/internal/core/models"
+	"github.com/singl3focus/uniflow/internal/core/usecase"
+	"github.com/singl3focus/uniflow/pkg/logger"
+)
+
+type FocusSessionHandler struct {
+	uc  *usecase.Usecase
+	log logger.Logger
+}
+
+func NewFocusSessionHandler(uc *usecase.Usecase, log logger.Logger) *FocusSessionHandler {
+	return &FocusSessionHandler{uc: uc, log: log}
+}
+
+type StartFocusSessionRequest struct {
+	ContextID       *string `json:"context_id"`
+	DurationMinutes int     `json:"duration_minutes"` // 1-240, по умолчанию 25
+}
+
+// GetFocusSessions godoc
+// @Summary      Получить фокус-сессии пользователя
+// @Description  Возвращает историю фокус-сессий, новые первыми
+// @Tags         focus
+// @Success      200 {object} map[string]interface{} "sessions: array of FocusSession objects"
+// @Failure      401 {object} response.ErrorResponse
+// @Failure      500 {object} response.ErrorResponse
+// @Router       /focus-sessions [get]
+// @Security     BearerAuth
+func (h *FocusSessionHandler) GetFocusSessions(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	log := h.log.WithContext(ctx)
+
+	userIDStr, ok := middleware.GetUserIDFromContext(ctx)
+	if !ok {
+		response.Error(w, http.StatusUnauthorized, "unauthorized")
+		return
+	}
+
+	sessions, err := h.uc.GetFocusSessions(ctx, userIDStr)
+	if err != nil {
+		log.Error("failed to get focus sessions", "error", err)
+		handleUsecaseError(w, err)
+		return
+	}
+
+	response.Success(w, http.StatusOK, map[string]interface{}{
+		"sessions": sessions,
+	})
+}
+
+// GetActiveFocusSession godoc
+// @Summary      Получить текущую фокус-сессию
+// @Description  Возвращает идущую фокус-сессию или 404, если ее нет
+// @Tags         focus
+// @Success      200 {object} models.FocusSession
+// @Failure      401 {object} response.ErrorResponse
+// @Failure      404 {object} response.ErrorResponse
+// @Failure      500 {object} response.ErrorResponse
+// @Router       /focus-sessions/active [get]
+// @Security     BearerAuth
+func (h *FocusSessionHandler) GetActiveFocusSession(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	_ = h.log.WithContext(ctx)
+
+	userIDStr, ok := middleware.GetUserIDFromContext(ctx)
+	if !ok {
+		response.Error(w, http.StatusUnauthorized, "unauthorized")
+		return
+	}
+
+	session, err := h.uc.GetActiveFocusSession(ctx, userIDStr)
+	if err != nil {
+		handleUsecaseError(w, err)
+		return
+	}
+
+	response.Success(w, http.StatusOK, session)
+}
+
+// StartFocusSession godoc
+// @Summary      Начать фокус-сессию
+// @Description  Запускает фокус-сессию. По истечении времени пользователю придет уведомление в MAX
+// @Tags         focus
+// @Param        request body StartFocusSessionRequest true "Параметры сессии"
+// @Success      201 {object} models.FocusSession
+// @Failure      400 {object} response.ErrorResponse
+// @Failure      401 {object} response.ErrorResponse
+// @Failure      404 {object} response.ErrorResponse
+// @Failure      500 {object} response.ErrorResponse
+// @Router       /focus-sessions [post]
+// @Security     BearerAuth
+func (h *FocusSessionHandler) StartFocusSession(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	_ = h.log.WithContext(ctx)
+
+	userIDStr, ok := middleware.GetUserIDFromContext(ctx)
+	if !ok {
+		response.Error(w, http.StatusUnauthorized, "unauthorized")
+		return
+	}
+
+	var req StartFocusSessionRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		response.Error(w, http.StatusBadRequest, "invalid request body")
+		return
+	}
+
+	if req.DurationMinutes == 0 {
+		req.DurationMinutes = models.DefaultFocusMinutes
+	}
+
+	session, err := h.uc.StartFocusSession(ctx, userIDStr, req.ContextID, req.DurationMinutes)
+	if err != nil {
+		handleUsecaseError(w, err)
+		return
+	}
+
+	response.Success(w, http.StatusCreated, session)
+}
+
+// StopFocusSession godoc
+// @Summary      Остановить фокус-сессию
+// @Description  Досрочно завершает фокус-сессию и отменяет уведомление о ее окончании
+// @Tags         focus
+// @Param        id path string true "Focus session ID"
+// @Success      200 {object} models.FocusSession
+// @Failure      400 {object} response.ErrorResponse
+// @Failure      404 {object} response.ErrorResponse
+// @Failure      500 {object} response.ErrorResponse
+// @Router       /focus-sessions/{id}/stop [post]
+// @Security     BearerAuth
+func (h *FocusSessionHandler) StopFocusSession(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	_ = h.log.WithContext(ctx)
+
+	userIDStr, ok := middleware.GetUserIDFromContext(ctx)
+	if !ok {
+		response.Error(w, http.StatusUnauthorized, "unauthorized")
+		return
+	}
+
+	session, err := h.uc.StopFocusSession(ctx, userIDStr, chi.URLParam(r, "id"))
+	if err != nil {
+		handleUsecaseError(w, err)
+		return
+	}
+
+	response.Success(w, http.StatusOK, session)
+}
@@ -0,0 +1,235 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+
+	"github.com/singl3focus/uniflow/internal/adapters/http/middleware"
+	"github.com/singl3focus/uniflow/internal/adapters/http/response"
+	"github.com/singl3focus/uniflow/internal/core/models"
+	"github.com/singl3focus/uniflow/internal/core/usecase"
+	"github.com/singl3focus/uniflow/pkg/logger"
+)
+
+type NoteHandler struct {
+	uc  *usecase.Usecase
+	log logger.Logger
+}
+
+func NewNoteHandler(uc *usecase.Usecase, log logger.Logger) *NoteHandler {
+	return &NoteHandler{uc: uc, log: log}
+}
+
+type CreateNoteRequest struct {
+	ContextID  *string `json:"context_id"`
+	Type       string  `json:"type"`        // text, image, audio, file
+	ContentURL string  `json:"content_url"` // Обязателен для image, audio, file
+	Text       string  `json:"text"`        // Обязателен для text
+}
+
+type UpdateNoteRequest struct {
+	ContextID  *string `json:"context_id"` // Пустая строка отвязывает заметку от контекста
+	ContentURL *string `json:"content_url"`
+	Text       *string `json:"text"`
+}
+
+// GetNotes godoc
+// @Summary      Получить все заметки пользователя
+// @Description  Возвращает список заметок текущего пользователя, новые первыми
+// @Tags         notes
+// @Success      200 {object} map[string]interface{} "notes: array of Note objects"
+// @Failure      401 {object} response.ErrorResponse
+// @Failure      500 {object} response.ErrorResponse
+// @Router       /notes [get]
+// @Security     BearerAuth
+func (h *NoteHandler) GetNotes(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	log := h.log.WithContext(ctx)
+
+	userIDStr, ok := middleware.GetUserIDFromContext(ctx)
+	if !ok {
+		response.Error(w, http.StatusUnauthorized, "unauthorized")
+		return
+	}
+
+	notes, err := h.uc.GetNotesByUserID(ctx, userIDStr)
+	if err != nil {
+		log.Error("failed to get notes", "error", err)
+		handleUsecaseError(w, err)
+		return
+	}
+
+	response.Success(w, http.StatusOK, map[string]interface{}{
+		"notes": notes,
+	})
+}
+
+// GetContextNotes godoc
+// @Summary      Получить заметки контекста
+// @Description  Возвращает заметки, привязанные к контексту
+// @Tags         notes
+// @Param        id path string true "Context ID"
+// @Success      200 {object} map[string]interface{} "notes: array of Note objects"
+// @Failure      400 {object} response.ErrorResponse
+// @Failure      401 {object} response.ErrorResponse
+// @Failure      404 {object} response.ErrorResponse
+// @Failure      500 {object} response.ErrorResponse
+// @Router       /contexts/{id}/notes [get]
+// @Security     BearerAuth
+func (h *NoteHandler) GetContextNotes(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	log := h.log.WithContext(ctx)
+
+	userIDStr, ok := middleware.GetUserIDFromContext(ctx)
+	if !ok {
+		response.Error(w, http.StatusUnauthorized, "unauthorized")
+		return
+	}
+
+	notes, err := h.uc.GetNotesByContextID(ctx, userIDStr, chi.URLParam(r, "id"))
+	if err != nil {
+		log.Error("failed to get context notes", "error", err)
+		handleUsecaseError(w, err)
+		return
+	}
+
+	response.Success(w, http.StatusOK, map[string]interface{}{
+		"notes": notes,
+	})
+}
+
+// CreateNote godoc
+// @Summary      Создать заметку
+// @Description  Создает текстовую или медиа-заметку с привязкой к контексту (опционально)
+// @Tags         notes
+// @Param        request body CreateNoteRequest true "Данные заметки"
+// @Success      201 {object} models.Note
+// @Failure      400 {object} response.ErrorResponse
+// @Failure      401 {object} response.ErrorResponse
+// @Failure      404 {object} response.ErrorResponse
+// @Failure      500 {object} response.ErrorResponse
+// @Router       /notes [post]
+// @Security     BearerAuth
+func (h *NoteHandler) CreateNote(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	_ = h.log.WithContext(ctx)
+
+	userIDStr, ok := middleware.GetUserIDFromContext(ctx)
+	if !ok {
+		response.Error(w, http.StatusUnauthorized, "unauthorized")
+		return
+	}
+
+	var req CreateNoteRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		response.Error(w, http.StatusBadRequest, "invalid request body")
+		return
+	}
+
+	note, err := h.uc.CreateNote(ctx, userIDStr, req.ContextID, models.NoteType(req.Type), req.ContentURL, req.Text)
+	if err != nil {
+		handleUsecaseError(w, err)
+		return
+	}
+
+	response.Success(w, http.StatusCreated, note)
+}
+
+// GetNote godoc
+// @Summary      Получить заметку по ID
+// @Description  Возвращает заметку
+// @Tags         notes
+// @Param        id path string true "Note ID"
+// @Success      200 {object} models.Note
+// @Failure      400 {object} response.ErrorResponse
+// @Failure      404 {object} response.ErrorResponse
+// @Failure      500 {object} response.ErrorResponse
+// @Router       /notes/{id} [get]
+// @Security     BearerAuth
+func (h *NoteHandler) GetNote(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	log := h.log.WithContext(ctx)
+
+	userIDStr, ok := middleware.GetUserIDFromContext(ctx)
+	if !ok {
+		response.Error(w, http.StatusUnauthorized, "unauthorized")
+		return
+	}
+
+	note, err := h.uc.GetNoteByID(ctx, userIDStr, chi.URLParam(r, "id"))
+	if err != nil {
+		log.Error("failed to get note", "error", err)
+		handleUsecaseError(w, err)
+		return
+	}
+
+	response.Success(w, http.StatusOK, note)
+}
+
+// UpdateNote godoc
+// @Summary      Обновить заметку
+// @Description  Обновляет текст, ссылку на файл или контекст заметки. Все поля опциональны
+// @Tags         notes
+// @Param        id path string true "Note ID"
+// @Param        request body UpdateNoteRequest true "Данные для обновления"
+// @Success      200 {object} models.Note
+// @Failure      400 {object} response.ErrorResponse
+// @Failure      404 {object} response.ErrorResponse
+// @Failure      500 {object} response.ErrorResponse
+// @Router       /notes/{id} [patch]
+// @Security     BearerAuth
+func (h *NoteHandler) UpdateNote(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	_ = h.log.WithContext(ctx)
+
+	userIDStr, ok := middleware.GetUserIDFromContext(ctx)
+	if !ok {
+		response.Error(w, http.StatusUnauthorized, "unauthorized")
+		return
+	}
+
+	var req UpdateNoteRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		response.Error(w, http.StatusBadRequest, "invalid request body")
+		return
+	}
+
+	note, err := h.uc.UpdateNote(ctx, userIDStr, chi.URLParam(r, "id"), req.ContextID, req.Text, req.ContentURL)
+	if err != nil {
+		handleUsecaseError(w, err)
+		return
+	}
+
+	response.Success(w, http.StatusOK, note)
+}
+
+// DeleteNote godoc
+// @Summary      Удалить заметку
+// @Description  Удаляет заметку по ID
+// @Tags         notes
+// @Param        id path string true "Note ID"
+// @Success      200 {object} map[string]string
+// @Failure      400 {object} response.ErrorResponse
+// @Failure      404 {object} response.ErrorResponse
+// @Failure      500 {object} response.ErrorResponse
+// @Router       /notes/{id} [delete]
+// @Security     BearerAuth
+func (h *NoteHandler) DeleteNote(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	_ = h.log.WithContext(ctx)
+
+	userIDStr, ok := middleware.GetUserIDFromContext(ctx)
+	if !ok {
+		response.Error(w, http.StatusUnauthorized, "unauthorized")
+		return
+	}
+
+	if err := h.uc.DeleteNote(ctx, userIDStr, chi.URLParam(r, "id")); err != nil {
+		handleUsecaseError(w, err)
+		return
+	}
+
+	response.Success(w, http.StatusOK, map[string]string{"status": "deleted"})
+}
@@ -0,0 +1,273 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+
+	"github.com/singl3focus/uniflow/internal/adapters/http/middleware"
+	"github.com/singl3focus/uniflow/internal/adapters/http/response"
+	"github.com/singl3focus/uniflow/internal/core/models"
+	"github.com/singl3focus/uniflow/internal/core/usecase"
+	"github.com/singl3focus/uniflow/pkg/logger"
+)
+
+type ScheduleHandler struct {
+	uc  *usecase.Usecase
+	log logger.Logger
+}
+
+func NewScheduleHandler(uc *usecase.Usecase, log logger.Logger) *ScheduleHandler {
+	return &ScheduleHandler{uc: uc, log: log}
+}
+
+type CreateScheduleEntryRequest struct {
+	ContextID *string `json:"context_id"`
+	Title     string  `json:"title"`
+	Weekday   int     `json:"weekday"`  // 0 - понедельник, 6 - воскресенье
+	StartAt   string  `json:"start_at"` // HH:MM
+	EndAt     string  `json:"end_at"`   // HH:MM
+	Location  string  `json:"location"`
+}
+
+type UpdateScheduleEntryRequest struct {
+	ContextID *string `json:"context_id"` // Пустая строка отвязывает занятие от контекста
+	Title     *string `json:"title"`
+	Weekday   *int    `json:"weekday"`  // 0 - понедельник, 6 - воскресенье
+	StartAt   *string `json:"start_at"` // HH:MM
+	EndAt     *string `json:"end_at"`   // HH:MM
+	Location  *string `json:"location"`
+}
+
+// ScheduleEntryResponse - запись расписания с временем занятия в формате HH:MM
+type ScheduleEntryResponse struct {
+	ID        string    `json:"id"`
+	UserID    string    `json:"user_id"`
+	ContextID *string   `json:"context_id"`
+	Title     string    `json:"title"`
+	Weekday   int       `json:"weekday"`
+	StartAt   string    `json:"start_at"`
+	EndAt     string    `json:"end_at"`
+	Location  string    `json:"location"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+func toScheduleEntryResponse(entry models.ScheduleEntry) ScheduleEntryResponse {
+	var contextID *string
+	if entry.ContextID != nil {
+		cid := entry.ContextID.String()
+		contextID = &cid
+	}
+
+	return ScheduleEntryResponse{
+		ID:        entry.ID.String(),
+		UserID:    entry.UserID.String(),
+		ContextID: contextID,
+		Title:     entry.Title,
+		Weekday:   int(entry.Weekday),
+		StartAt:   entry.StartAt.Format(models.ScheduleTimeLayout),
+		EndAt:     entry.EndAt.Format(models.ScheduleTimeLayout),
+		Location:  entry.Location,
+		CreatedAt: entry.CreatedAt,
+		UpdatedAt: entry.UpdatedAt,
+	}
+}
+
+func toScheduleEntryResponses(entries []models.ScheduleEntry) []ScheduleEntryResponse {
+	result := make([]ScheduleEntryResponse, 0, len(entries))
+	for _, entry := range entries {
+		result = append(result, toScheduleEntryResponse(entry))
+	}
+	return result
+}
+
+// GetSchedule godoc
+// @Summary      Получить расписание занятий
+// @Description  Возвращает недельное расписание пользователя, отсортированное по дню недели и времени начала
+// @Tags         schedule
+// @Param        weekday query int false "День недели (0 - понедельник, 6 - воскресенье)"
+// @Success      200 {object} map[string]interface{} "entries: array of ScheduleEntryResponse objects"
+// @Failure      400 {object} response.ErrorResponse
+// @Failure      401 {object} response.ErrorResponse
+// @Failure      500 {object} response.ErrorResponse
+// @Router       /schedule [get]
+// @Security     BearerAuth
+func (h *ScheduleHandler) GetSchedule(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	log := h.log.WithContext(ctx)
+
+	userIDStr, ok := middleware.GetUserIDFromContext(ctx)
+	if !ok {
+		response.Error(w, http.StatusUnauthorized, "unauthorized")
+		return
+	}
+
+	var (
+		entries []models.ScheduleEntry
+		err     error
+	)
+
+	if weekdayStr := r.URL.Query().Get("weekday"); weekdayStr != "" {
+		weekday, convErr := strconv.Atoi(weekdayStr)
+		if convErr != nil {
+			response.Error(w, http.StatusBadRequest, "invalid weekday")
+			return
+		}
+		entries, err = h.uc.GetScheduleByWeekday(ctx, userIDStr, models.Weekday(weekday))
+	} else {
+		entries, err = h.uc.GetScheduleEntries(ctx, userIDStr)
+	}
+
+	if err != nil {
+		log.Error("failed to get schedule", "error", err)
+		handleUsecaseError(w, err)
+		return
+	}
+
+	response.Success(w, http.StatusOK, map[string]interface{}{
+		"entries": toScheduleEntryResponses(entries),
+	})
+}
+
+// CreateScheduleEntry godoc
+// @Summary      Добавить занятие в расписание
+// @Description  Создает запись недельного расписания с привязкой к контексту (опционально)
+// @Tags         schedule
+// @Param        request body CreateScheduleEntryRequest true "Данные занятия"
+// @Success      201 {object} ScheduleEntryResponse
+// @Failure      400 {object} response.ErrorResponse
+// @Failure      401 {object} response.ErrorResponse
+// @Failure      500 {object} response.ErrorResponse
+// @Router       /schedule [post]
+// @Security     BearerAuth
+func (h *ScheduleHandler) CreateScheduleEntry(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	_ = h.log.WithContext(ctx)
+
+	userIDStr, ok := middleware.GetUserIDFromContext(ctx)
+	if !ok {
+		response.Error(w, http.StatusUnauthorized, "unauthorized")
+		return
+	}
+
+	var req CreateScheduleEntryRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		response.Error(w, http.StatusBadRequest, "invalid request body")
+		return
+	}
+
+	entry, err := h.uc.CreateScheduleEntry(ctx, userIDStr, req.ContextID, req.Title, models.Weekday(req.Weekday), req.StartAt, req.EndAt, req.Location)
+	if err != nil {
+		handleUsecaseError(w, err)
+		return
+	}
+
+	response.Success(w, http.StatusCreated, toScheduleEntryResponse(entry))
+}
+
+// GetScheduleEntry godoc
+// @Summary      Получить занятие по ID
+// @Description  Возвращает запись расписания
+// @Tags         schedule
+// @Param        id path string true "Schedule entry ID"
+// @Success      200 {object} ScheduleEntryResponse
+// @Failure      400 {object} response.ErrorResponse
+// @Failure      404 {object} response.ErrorResponse
+// @Failure      500 {object} response.ErrorResponse
+// @Router       /schedule/{id} [get]
+// @Security     BearerAuth
+func (h *ScheduleHandler) GetScheduleEntry(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	log := h.log.WithContext(ctx)
+
+	userIDStr, ok := middleware.GetUserIDFromContext(ctx)
+	if !ok {
+		response.Error(w, http.StatusUnauthorized, "unauthorized")
+		return
+	}
+
+	entry, err := h.uc.GetScheduleEntryByID(ctx, userIDStr, chi.URLParam(r, "id"))
+	if err != nil {
+		log.Error("failed to get schedule entry", "error", err)
+		handleUsecaseError(w, err)
+		return
+	}
+
+	response.Success(w, http.StatusOK, toScheduleEntryResponse(entry))
+}
+
+// UpdateScheduleEntry godoc
+// @Summary      Обновить занятие
+// @Description  Обновляет запись расписания. Все поля опциональны, обновляются только переданные
+// @Tags         schedule
+// @Param        id path string true "Schedule entry ID"
+// @Param        request body UpdateScheduleEntryRequest true "Данные для обновления"
+// @Success      200 {object} ScheduleEntryResponse
+// @Failure      400 {object} response.ErrorResponse
+// @Failure      404 {object} response.ErrorResponse
+// @Failure      500 {object} response.ErrorResponse
+// @Router       /schedule/{id} [patch]
+// @Security     BearerAuth
+func (h *ScheduleHandler) UpdateScheduleEntry(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	_ = h.log.WithContext(ctx)
+
+	userIDStr, ok := middleware.GetUserIDFromContext(ctx)
+	if !ok {
+		response.Error(w, http.StatusUnauthorized, "unauthorized")
+		return
+	}
+
+	var req UpdateScheduleEntryRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		response.Error(w, http.StatusBadRequest, "invalid request body")
+		return
+	}
+
+	var weekday *models.Weekday
+	if req.Weekday != nil {
+		wd := models.Weekday(*req.Weekday)
+		weekday = &wd
+	}
+
+	entry, err := h.uc.UpdateScheduleEntry(ctx, userIDStr, chi.URLParam(r, "id"), req.ContextID, req.Title, weekday, req.StartAt, req.EndAt, req.Location)
+	if err != nil {
+		handleUsecaseError(w, err)
+		return
+	}
+
+	response.Success(w, http.StatusOK, toScheduleEntryResponse(entry))
+}
+
+// DeleteScheduleEntry godoc
+// @Summary      Удалить занятие
+// @Description  Удаляет запись расписания по ID
+// @Tags         schedule
+// @Param        id path string true "Schedule entry ID"
+// @Success      200 {object} map[string]string
+// @Failure      400 {object} response.ErrorResponse
+// @Failure      404 {object} response.ErrorResponse
+// @Failure      500 {object} response.ErrorResponse
+// @Router       /schedule/{id} [delete]
+// @Security     BearerAuth
+func (h *ScheduleHandler) DeleteScheduleEntry(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	_ = h.log.WithContext(ctx)
+
+	userIDStr, ok := middleware.GetUserIDFromContext(ctx)
+	if !ok {
+		response.Error(w, http.StatusUnauthorized, "unauthorized")
+		return
+	}
+
+	if err := h.uc.DeleteScheduleEntry(ctx, userIDStr, chi.URLParam(r, "id")); err != nil {
+		handleUsecaseError(w, err)
+		return
+	}
+
+	response.Success(w, http.StatusOK, map[string]string{"status": "deleted"})
+}
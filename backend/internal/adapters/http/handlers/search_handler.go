@@ -19,11 +19,11 @@ func NewSearchHandler(uc *usecase.Usecase, log logger.Logger) *SearchHandler {
 }
 
 // Search godoc
-// @Summary      Поиск по задачам и контекстам
-// @Description  Выполняет поиск по названиям и описаниям задач и контекстов
+// @Summary      Поиск по задачам, контекстам и заметкам
+// @Description  Выполняет поиск по названиям и описаниям задач и контекстов, а также по тексту заметок
 // @Tags         search
 // @Param        q query string true "Поисковый запрос"
-// @Success      200 {object} map[string]interface{} "tasks: array of Task, contexts: array of Context"
+// @Success      200 {object} map[string]interface{} "tasks: array of Task, contexts: array of Context, notes: array of Note"
 // @Failure      400 {object} response.ErrorResponse
 // @Failure      401 {object} response.ErrorResponse
 // @Failure      500 {object} response.ErrorResponse
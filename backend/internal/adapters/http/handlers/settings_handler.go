@@ -0,0 +1,110 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/singl3focus/uniflow/internal/adapters/http/middleware"
+	"github.com/singl3focus/uniflow/internal/adapters/http/response"
+	"github.com/singl3focus/uniflow/internal/core/models"
+	"github.com/singl3focus/uniflow/internal/core/usecase"
+	"github.com/singl3focus/uniflow/pkg/logger"
+)
+
+type SettingsHandler struct {
+	uc  *usecase.Usecase
+	log logger.Logger
+}
+
+func NewSettingsHandler(uc *usecase.Usecase, log logger.Logger) *SettingsHandler {
+	return &SettingsHandler{uc: uc, log: log}
+}
+
+type UpdateSettingsRequest struct {
+	Timezone         *string         `json:"timezone"`          // IANA, например Europe/Moscow
+	Language         *string         `json:"language"`          // ru, en
+	FirstWeekday     *models.Weekday `json:"first_weekday"`     // 0 - понедельник, 6 - воскресенье
+	ReminderOffsets  *[]int          `json:"reminder_offsets"`  // Минуты до дедлайна, [] - без напоминаний
+	DefaultReminders bool            `json:"default_reminders"` // true - вернуть напоминания по умолчанию
+	MorningDigestAt  *string         `json:"morning_digest_at"` // ЧЧ:ММ, "" - выключить утреннюю сводку
+	EveningReviewAt  *string         `json:"evening_review_at"` // ЧЧ:ММ, "" - выключить вечерний обзор
+}
+
+// GetSettings godoc
+// @Summary      Получить настройки пользователя
+// @Description  Возвращает часовой пояс, язык, первый день недели и напоминания текущего пользователя
+// @Tags         settings
+// @Produce      json
+// @Success      200 {object} models.UserSettings
+// @Failure      401 {object} response.ErrorResponse
+// @Failure      404 {object} response.ErrorResponse
+// @Failure      500 {object} response.ErrorResponse
+// @Router       /me/settings [get]
+// @Security     BearerAuth
+func (h *SettingsHandler) GetSettings(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	log := h.log.WithContext(ctx)
+
+	userIDStr, ok := middleware.GetUserIDFromContext(ctx)
+	if !ok {
+		response.Error(w, http.StatusUnauthorized, "unauthorized")
+		return
+	}
+
+	settings, err := h.uc.GetUserSettings(ctx, userIDStr)
+	if err != nil {
+		log.Error("failed to get settings", "error", err)
+		handleUsecaseError(w, err)
+		return
+	}
+
+	response.Success(w, http.StatusOK, settings)
+}
+
+// UpdateSettings godoc
+// @Summary      Обновить настройки пользователя
+// @Description  Обновляет переданные настройки. Смена часового пояса или напоминаний пересоздает напоминания активных задач, смена пояса или времени сводок - расписание сводок
+// @Tags         settings
+// @Accept       json
+// @Produce      json
+// @Param        request body UpdateSettingsRequest true "Новые настройки"
+// @Success      200 {object} models.UserSettings
+// @Failure      400 {object} response.ErrorResponse
+// @Failure      401 {object} response.ErrorResponse
+// @Failure      404 {object} response.ErrorResponse
+// @Failure      500 {object} response.ErrorResponse
+// @Router       /me/settings [patch]
+// @Security     BearerAuth
+func (h *SettingsHandler) UpdateSettings(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	log := h.log.WithContext(ctx)
+
+	userIDStr, ok := middleware.GetUserIDFromContext(ctx)
+	if !ok {
+		response.Error(w, http.StatusUnauthorized, "unauthorized")
+		return
+	}
+
+	var req UpdateSettingsRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		log.Error("failed to decode request", "error", err)
+		response.Error(w, http.StatusBadRequest, "invalid request body")
+		return
+	}
+
+	reminderOffsets := req.ReminderOffsets
+	if req.DefaultReminders {
+		var defaults []int
+		reminderOffsets = &defaults
+	}
+
+	settings, err := h.uc.UpdateUserSettings(ctx, userIDStr, req.Timezone, req.Language, req.FirstWeekday, reminderOffsets,
+		req.MorningDigestAt, req.EveningReviewAt)
+	if err != nil {
+		log.Error("failed to update settings", "error", err)
+		handleUsecaseError(w, err)
+		return
+	}
+
+	response.Success(w, http.StatusOK, settings)
+}
@@ -0,0 +1,61 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/singl3focus/uniflow/internal/adapters/http/middleware"
+	"github.com/singl3focus/uniflow/internal/adapters/http/response"
+	"github.com/singl3focus/uniflow/internal/core/usecase"
+	"github.com/singl3focus/uniflow/pkg/logger"
+)
+
+type StatsHandler struct {
+	uc  *usecase.Usecase
+	log logger.Logger
+}
+
+func NewStatsHandler(uc *usecase.Usecase, log logger.Logger) *StatsHandler {
+	return &StatsHandler{uc: uc, log: log}
+}
+
+// GetStats godoc
+// @Summary      Получить статистику продуктивности
+// @Description  Возвращает статистику за последние N дней: выполненные задачи по дням и неделям, разбивку по контекстам, просроченные задачи, среднее время выполнения и минуты фокуса
+// @Tags         stats
+// @Param        days query int false "Длина периода в днях (по умолчанию 7, максимум 366)"
+// @Success      200 {object} models.Stats
+// @Failure      400 {object} response.ErrorResponse
+// @Failure      401 {object} response.ErrorResponse
+// @Failure      500 {object} response.ErrorResponse
+// @Router       /stats [get]
+// @Security     BearerAuth
+func (h *StatsHandler) GetStats(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	log := h.log.WithContext(ctx)
+
+	userIDStr, ok := middleware.GetUserIDFromContext(ctx)
+	if !ok {
+		response.Error(w, http.StatusUnauthorized, "unauthorized")
+		return
+	}
+
+	days := usecase.DefaultStatsDays
+	if daysStr := r.URL.Query().Get("days"); daysStr != "" {
+		d, err := strconv.Atoi(daysStr)
+		if err != nil {
+			response.Error(w, http.StatusBadRequest, "invalid days")
+			return
+		}
+		days = d
+	}
+
+	stats, err := h.uc.GetStats(ctx, userIDStr, days)
+	if err != nil {
+		log.Error("failed to get stats", "error", err)
+		handleUsecaseError(w, err)
+		return
+	}
+
+	response.Success(w, http.StatusOK, stats)
+}
@@ -0,0 +1,181 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+
+	"github.com/singl3focus/uniflow/internal/adapters/http/middleware"
+	"github.com/singl3focus/uniflow/internal/adapters/http/response"
+	"github.com/singl3focus/uniflow/internal/core/models"
+	"github.com/singl3focus/uniflow/internal/core/usecase"
+	"github.com/singl3focus/uniflow/pkg/logger"
+)
+
+type SubtaskHandler struct {
+	uc  *usecase.Usecase
+	log logger.Logger
+}
+
+func NewSubtaskHandler(uc *usecase.Usecase, log logger.Logger) *SubtaskHandler {
+	return &SubtaskHandler{uc: uc, log: log}
+}
+
+type CreateSubtaskRequest struct {
+	Title string `json:"title"`
+}
+
+type UpdateSubtaskRequest struct {
+	Title *string `json:"title"`
+	Done  *bool   `json:"done"`
+}
+
+// GetSubtasks godoc
+// @Summary      Получить чек-лист задачи
+// @Description  Возвращает пункты чек-листа задачи по порядку и прогресс выполнения
+// @Tags         subtasks
+// @Param        id path string true "Task ID"
+// @Success      200 {object} map[string]interface{} "subtasks: array of Subtask objects, done, total"
+// @Failure      400 {object} response.ErrorResponse
+// @Failure      401 {object} response.ErrorResponse
+// @Failure      404 {object} response.ErrorResponse
+// @Failure      500 {object} response.ErrorResponse
+// @Router       /tasks/{id}/subtasks [get]
+// @Security     BearerAuth
+func (h *SubtaskHandler) GetSubtasks(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	log := h.log.WithContext(ctx)
+
+	userIDStr, ok := middleware.GetUserIDFromContext(ctx)
+	if !ok {
+		response.Error(w, http.StatusUnauthorized, "unauthorized")
+		return
+	}
+
+	subtasks, err := h.uc.GetSubtasks(ctx, userIDStr, chi.URLParam(r, "id"))
+	if err != nil {
+		log.Error("failed to get subtasks", "error", err)
+		handleUsecaseError(w, err)
+		return
+	}
+
+	if subtasks == nil {
+		subtasks = []models.Subtask{}
+	}
+	done, total := models.SubtaskProgress(subtasks)
+
+	response.Success(w, http.StatusOK, map[string]interface{}{
+		"subtasks": subtasks,
+		"done":     done,
+		"total":    total,
+	})
+}
+
+// CreateSubtask godoc
+// @Summary      Добавить пункт чек-листа
+// @Description  Добавляет пункт в конец чек-листа задачи
+// @Tags         subtasks
+// @Param        id path string true "Task ID"
+// @Param        request body CreateSubtaskRequest true "Данные пункта"
+// @Success      201 {object} models.Subtask
+// @Failure      400 {object} response.ErrorResponse
+// @Failure      401 {object} response.ErrorResponse
+// @Failure      404 {object} response.ErrorResponse
+// @Failure      500 {object} response.ErrorResponse
+// @Router       /tasks/{id}/subtasks [post]
+// @Security     BearerAuth
+func (h *SubtaskHandler) CreateSubtask(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	_ = h.log.WithContext(ctx)
+
+	userIDStr, ok := middleware.GetUserIDFromContext(ctx)
+	if !ok {
+		response.Error(w, http.StatusUnauthorized, "unauthorized")
+		return
+	}
+
+	var req CreateSubtaskRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		response.Error(w, http.StatusBadRequest, "invalid request body")
+		return
+	}
+
+	subtask, err := h.uc.CreateSubtask(ctx, userIDStr, chi.URLParam(r, "id"), req.Title)
+	if err != nil {
+		handleUsecaseError(w, err)
+		return
+	}
+
+	response.Success(w, http.StatusCreated, subtask)
+}
+
+// UpdateSubtask godoc
+// @Summary      Обновить пункт чек-листа
+// @Description  Переименовывает пункт и/или отмечает его выполненным. Все поля опциональны
+// @Tags         subtasks
+// @Param        id path string true "Task ID"
+// @Param        subtaskID path string true "Subtask ID"
+// @Param        request body UpdateSubtaskRequest true "Данные для обновления"
+// @Success      200 {object} models.Subtask
+// @Failure      400 {object} response.ErrorResponse
+// @Failure      401 {object} response.ErrorResponse
+// @Failure      404 {object} response.ErrorResponse
+// @Failure      500 {object} response.ErrorResponse
+// @Router       /tasks/{id}/subtasks/{subtaskID} [patch]
+// @Security     BearerAuth
+func (h *SubtaskHandler) UpdateSubtask(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	_ = h.log.WithContext(ctx)
+
+	userIDStr, ok := middleware.GetUserIDFromContext(ctx)
+	if !ok {
+		response.Error(w, http.StatusUnauthorized, "unauthorized")
+		return
+	}
+
+	var req UpdateSubtaskRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		response.Error(w, http.StatusBadRequest, "invalid request body")
+		return
+	}
+
+	subtask, err := h.uc.UpdateSubtask(ctx, userIDStr, chi.URLParam(r, "id"), chi.URLParam(r, "subtaskID"), req.Title, req.Done)
+	if err != nil {
+		handleUsecaseError(w, err)
+		return
+	}
+
+	response.Success(w, http.StatusOK, subtask)
+}
+
+// DeleteSubtask godoc
+// @Summary      Удалить пункт чек-листа
+// @Description  Удаляет пункт из чек-листа задачи
+// @Tags         subtasks
+// @Param        id path string true "Task ID"
+// @Param        subtaskID path string true "Subtask ID"
+// @Success      200 {object} map[string]string
+// @Failure      400 {object} response.ErrorResponse
+// @Failure      401 {object} response.ErrorResponse
+// @Failure      404 {object} response.ErrorResponse
+// @Failure      500 {object} response.ErrorResponse
+// @Router       /tasks/{id}/subtasks/{subtaskID} [delete]
+// @Security     BearerAuth
+func (h *SubtaskHandler) DeleteSubtask(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	_ = h.log.WithContext(ctx)
+
+	userIDStr, ok := middleware.GetUserIDFromContext(ctx)
+	if !ok {
+		response.Error(w, http.StatusUnauthorized, "unauthorized")
+		return
+	}
+
+	if err := h.uc.DeleteSubtask(ctx, userIDStr, chi.URLParam(r, "id"), chi.URLParam(r, "subtaskID")); err != nil {
+		handleUsecaseError(w, err)
+		return
+	}
+
+	response.Success(w, http.StatusOK, map[string]string{"status": "deleted"})
+}
@@ -0,0 +1,194 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+
+	"github.com/singl3focus/uniflow/internal/adapters/http/middleware"
+	"github.com/singl3focus/uniflow/internal/adapters/http/response"
+	"github.com/singl3focus/uniflow/internal/core/usecase"
+	"github.com/singl3focus/uniflow/pkg/logger"
+)
+
+type TagHandler struct {
+	uc  *usecase.Usecase
+	log logger.Logger
+}
+
+func NewTagHandler(uc *usecase.Usecase, log logger.Logger) *TagHandler {
+	return &TagHandler{uc: uc, log: log}
+}
+
+type CreateTagRequest struct {
+	Name string `json:"name"` // Буквы, цифры, '_' и '-'; ведущий '#' отбрасывается
+}
+
+type AddTaskTagsRequest struct {
+	Tags []string `json:"tags"` // Имена тегов, отсутствующие создаются
+}
+
+// GetTags godoc
+// @Summary      Получить теги пользователя
+// @Description  Возвращает все теги текущего пользователя, отсортированные по имени
+// @Tags         tags
+// @Success      200 {object} map[string]interface{} "tags: array of Tag objects"
+// @Failure      401 {object} response.ErrorResponse
+// @Failure      500 {object} response.ErrorResponse
+// @Router       /tags [get]
+// @Security     BearerAuth
+func (h *TagHandler) GetTags(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	log := h.log.WithContext(ctx)
+
+	userIDStr, ok := middleware.GetUserIDFromContext(ctx)
+	if !ok {
+		response.Error(w, http.StatusUnauthorized, "unauthorized")
+		return
+	}
+
+	tags, err := h.uc.GetTags(ctx, userIDStr)
+	if err != nil {
+		log.Error("failed to get tags", "error", err)
+		handleUsecaseError(w, err)
+		return
+	}
+
+	response.Success(w, http.StatusOK, map[string]interface{}{
+		"tags": tags,
+	})
+}
+
+// CreateTag godoc
+// @Summary      Создать тег
+// @Description  Создает тег. Имя приводится к нижнему регистру; тег с таким именем уже существовать не должен
+// @Tags         tags
+// @Param        request body CreateTagRequest true "Имя тега"
+// @Success      201 {object} models.Tag
+// @Failure      400 {object} response.ErrorResponse
+// @Failure      401 {object} response.ErrorResponse
+// @Failure      500 {object} response.ErrorResponse
+// @Router       /tags [post]
+// @Security     BearerAuth
+func (h *TagHandler) CreateTag(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	_ = h.log.WithContext(ctx)
+
+	userIDStr, ok := middleware.GetUserIDFromContext(ctx)
+	if !ok {
+		response.Error(w, http.StatusUnauthorized, "unauthorized")
+		return
+	}
+
+	var req CreateTagRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		response.Error(w, http.StatusBadRequest, "invalid request body")
+		return
+	}
+
+	tag, err := h.uc.CreateTag(ctx, userIDStr, req.Name)
+	if err != nil {
+		handleUsecaseError(w, err)
+		return
+	}
+
+	response.Success(w, http.StatusCreated, tag)
+}
+
+// DeleteTag godoc
+// @Summary      Удалить тег
+// @Description  Удаляет тег и снимает его со всех задач
+// @Tags         tags
+// @Param        id path string true "Tag ID"
+// @Success      200 {object} map[string]string
+// @Failure      400 {object} response.ErrorResponse
+// @Failure      404 {object} response.ErrorResponse
+// @Failure      500 {object} response.ErrorResponse
+// @Router       /tags/{id} [delete]
+// @Security     BearerAuth
+func (h *TagHandler) DeleteTag(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	_ = h.log.WithContext(ctx)
+
+	userIDStr, ok := middleware.GetUserIDFromContext(ctx)
+	if !ok {
+		response.Error(w, http.StatusUnauthorized, "unauthorized")
+		return
+	}
+
+	if err := h.uc.DeleteTag(ctx, userIDStr, chi.URLParam(r, "id")); err != nil {
+		handleUsecaseError(w, err)
+		return
+	}
+
+	response.Success(w, http.StatusOK, map[string]string{"status": "deleted"})
+}
+
+// AddTaskTags godoc
+// @Summary      Добавить теги задаче
+// @Description  Привязывает теги к задаче по именам, отсутствующие теги создаются. Возвращает обновленную задачу
+// @Tags         tags
+// @Param        id path string true "Task ID"
+// @Param        request body AddTaskTagsRequest true "Имена тегов"
+// @Success      200 {object} models.Task
+// @Failure      400 {object} response.ErrorResponse
+// @Failure      404 {object} response.ErrorResponse
+// @Failure      500 {object} response.ErrorResponse
+// @Router       /tasks/{id}/tags [post]
+// @Security     BearerAuth
+func (h *TagHandler) AddTaskTags(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	_ = h.log.WithContext(ctx)
+
+	userIDStr, ok := middleware.GetUserIDFromContext(ctx)
+	if !ok {
+		response.Error(w, http.StatusUnauthorized, "unauthorized")
+		return
+	}
+
+	var req AddTaskTagsRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		response.Error(w, http.StatusBadRequest, "invalid request body")
+		return
+	}
+
+	task, err := h.uc.AddTaskTags(ctx, userIDStr, chi.URLParam(r, "id"), req.Tags)
+	if err != nil {
+		handleUsecaseError(w, err)
+		return
+	}
+
+	response.Success(w, http.StatusOK, task)
+}
+
+// RemoveTaskTag godoc
+// @Summary      Снять тег с задачи
+// @Description  Отвязывает тег от задачи. Сам тег остается в списке тегов пользователя
+// @Tags         tags
+// @Param        id path string true "Task ID"
+// @Param        name path string true "Имя тега"
+// @Success      200 {object} models.Task
+// @Failure      400 {object} response.ErrorResponse
+// @Failure      404 {object} response.ErrorResponse
+// @Failure      500 {object} response.ErrorResponse
+// @Router       /tasks/{id}/tags/{name} [delete]
+// @Security     BearerAuth
+func (h *TagHandler) RemoveTaskTag(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	_ = h.log.WithContext(ctx)
+
+	userIDStr, ok := middleware.GetUserIDFromContext(ctx)
+	if !ok {
+		response.Error(w, http.StatusUnauthorized, "unauthorized")
+		return
+	}
+
+	task, err := h.uc.RemoveTaskTag(ctx, userIDStr, chi.URLParam(r, "id"), chi.URLParam(r, "name"))
+	if err != nil {
+		handleUsecaseError(w, err)
+		return
+	}
+
+	response.Success(w, http.StatusOK, task)
+}
@@ -2,7 +2,12 @@ package handlers
 
 import (
 	"encoding/json"
+	"errors"
 	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
 
 	"github.com/go-chi/chi/v5"
 
@@ -23,17 +28,22 @@ func NewTaskHandler(uc *usecase.Usecase, log logger.Logger) *TaskHandler {
 }
 
 type CreateTaskRequest struct {
-	ContextID   *string `json:"context_id"`
-	Title       string  `json:"title"`
-	Description string  `json:"description"`
-	DueAt       *string `json:"due_at"` // ISO 8601 format
+	ContextID   *string            `json:"context_id"`
+	Title       string             `json:"title"`
+	Description string             `json:"description"`
+	DueAt       *string            `json:"due_at"`     // ISO 8601 format или дата YYYY-MM-DD - срок на весь день
+	Recurrence  *models.Recurrence `json:"recurrence"` // Требует due_at
+	Priority    string             `json:"priority"`   // low, normal (по умолчанию), high, urgent
+	Tags        []string           `json:"tags"`       // Имена тегов, отсутствующие создаются
 }
 
 type UpdateTaskRequest struct {
-	ContextID   *string `json:"context_id"`
-	Title       *string `json:"title"`
-	Description *string `json:"description"`
-	DueAt       *string `json:"due_at"` // ISO 8601 format
+	ContextID   *string            `json:"context_id"` // "" убирает задачу из контекста
+	Title       *string            `json:"title"`
+	Description *string            `json:"description"`
+	DueAt       *string            `json:"due_at"`     // ISO 8601 format или дата YYYY-MM-DD - срок на весь день, "" снимает дедлайн
+	Recurrence  *models.Recurrence `json:"recurrence"` // {"frequency": ""} снимает повторение
+	Priority    *string            `json:"priority"`   // low, normal, high, urgent
 }
 
 type UpdateTaskStatusRequest struct {
@@ -41,10 +51,23 @@ type UpdateTaskStatusRequest struct {
 }
 
 // GetTasks godoc
-// @Summary      Получить все задачи пользователя
-// @Description  Возвращает список всех задач текущего пользователя
+// @Summary      Получить задачи пользователя
+// @Description  Возвращает задачи текущего пользователя с фильтрами и сортировкой. Фильтрация выполняется в БД.
+// @Description  При limit выдача постраничная: next_cursor передается в cursor для получения следующей страницы.
 // @Tags         tasks
-// @Success      200 {object} map[string]interface{} "tasks: array of Task objects"
+// @Param        status query string false "Статусы через запятую (todo, in_progress, completed, cancelled)"
+// @Param        context_id query string false "ID контекста"
+// @Param        inbox query bool false "Только задачи без контекста"
+// @Param        priority query string false "Приоритеты через запятую (low, normal, high, urgent)"
+// @Param        tag query string false "Имя тега (с '#' или без)"
+// @Param        due_from query string false "Дедлайн не раньше (RFC3339)"
+// @Param        due_to query string false "Дедлайн раньше (RFC3339)"
+// @Param        q query string false "Подстрока в названии или описании"
+// @Param        sort query string false "Сортировка: created (по умолчанию), priority, due"
+// @Param        cursor query string false "Курсор следующей страницы из next_cursor"
+// @Param        limit query int false "Размер страницы (1-100), по умолчанию - все задачи"
+// @Success      200 {object} models.TaskPage
+// @Failure      400 {object} response.ErrorResponse
 // @Failure      401 {object} response.ErrorResponse
 // @Failure      500 {object} response.ErrorResponse
 // @Router       /tasks [get]
@@ -59,16 +82,102 @@ func (h *TaskHandler) GetTasks(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	tasks, err := h.uc.GetTasksByUserID(ctx, userIDStr)
+	filter, err := parseTaskFilter(r.URL.Query())
+	if err != nil {
+		response.Error(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	page, err := h.uc.ListTasks(ctx, userIDStr, filter)
 	if err != nil {
 		log.Error("failed to get tasks", "error", err)
 		handleUsecaseError(w, err)
 		return
 	}
 
-	response.Success(w, http.StatusOK, map[string]interface{}{
-		"tasks": tasks,
-	})
+	response.Success(w, http.StatusOK, page)
+}
+
+// parseTaskFilter собирает фильтр списка задач из query-параметров.
+// Проверка значений (допустимые статусы, лимит и т.д.) выполняется в usecase.
+func parseTaskFilter(query url.Values) (models.TaskFilter, error) {
+	var filter models.TaskFilter
+
+	for _, s := range splitList(query.Get("status")) {
+		filter.Statuses = append(filter.Statuses, models.TaskStatus(s))
+	}
+	for _, p := range splitList(query.Get("priority")) {
+		filter.Priorities = append(filter.Priorities, models.TaskPriority(p))
+	}
+
+	if contextIDStr := query.Get("context_id"); contextIDStr != "" {
+		contextID, err := models.ParseContextID(contextIDStr)
+		if err != nil {
+			return models.TaskFilter{}, errors.New("invalid context_id")
+		}
+		filter.ContextID = &contextID
+	}
+
+	if inboxStr := query.Get("inbox"); inboxStr != "" {
+		inbox, err := strconv.ParseBool(inboxStr)
+		if err != nil {
+			return models.TaskFilter{}, errors.New("invalid inbox")
+		}
+		filter.InboxOnly = inbox
+	}
+
+	var err error
+	if filter.DueFrom, err = parseOptionalTime(query.Get("due_from")); err != nil {
+		return models.TaskFilter{}, errors.New("invalid due_from: expected RFC3339")
+	}
+	if filter.DueTo, err = parseOptionalTime(query.Get("due_to")); err != nil {
+		return models.TaskFilter{}, errors.New("invalid due_to: expected RFC3339")
+	}
+
+	if cursorStr := query.Get("cursor"); cursorStr != "" {
+		cursor, err := models.ParseTaskCursor(cursorStr)
+		if err != nil {
+			return models.TaskFilter{}, errors.New("invalid cursor")
+		}
+		filter.Cursor = &cursor
+	}
+
+	if limitStr := query.Get("limit"); limitStr != "" {
+		limit, err := strconv.Atoi(limitStr)
+		if err != nil {
+			return models.TaskFilter{}, errors.New("invalid limit")
+		}
+		filter.Limit = limit
+	}
+
+	filter.Tag = query.Get("tag")
+	filter.Text = strings.TrimSpace(query.Get("q"))
+	filter.Sort = models.TaskSort(query.Get("sort"))
+
+	return filter, nil
+}
+
+// parseOptionalTime разбирает время в формате RFC3339; пустая строка - nil
+func parseOptionalTime(value string) (*time.Time, error) {
+	if value == "" {
+		return nil, nil
+	}
+	t, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		return nil, err
+	}
+	return &t, nil
+}
+
+// splitList разбирает список значений через запятую, пропуская пустые
+func splitList(value string) []string {
+	var result []string
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			result = append(result, item)
+		}
+	}
+	return result
 }
 
 // GetTasksToday godoc
@@ -103,7 +212,7 @@ func (h *TaskHandler) GetTasksToday(w http.ResponseWriter, r *http.Request) {
 
 // CreateTask godoc
 // @Summary      Создать новую задачу
-// @Description  Создает новую задачу с привязкой к контексту (опционально)
+// @Description  Создает новую задачу с привязкой к контексту (опционально). Повторяющаяся задача (recurrence: daily, weekly, monthly) при завершении создает следующее вхождение
 // @Tags         tasks
 // @Param        request body CreateTaskRequest true "Данные задачи"
 // @Success      201 {object} models.Task
@@ -128,7 +237,7 @@ func (h *TaskHandler) CreateTask(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	task, err := h.uc.CreateTask(ctx, userIDStr, req.ContextID, req.Title, req.Description, req.DueAt)
+	task, err := h.uc.CreateTask(ctx, userIDStr, req.ContextID, req.Title, req.Description, req.DueAt, req.Recurrence, models.TaskPriority(req.Priority), req.Tags)
 	if err != nil {
 		handleUsecaseError(w, err)
 		return
@@ -154,9 +263,15 @@ func (h *TaskHandler) GetTask(w http.ResponseWriter, r *http.Request) {
 	ctx := r.Context()
 	log := h.log.WithContext(ctx)
 
+	userIDStr, ok := middleware.GetUserIDFromContext(ctx)
+	if !ok {
+		response.Error(w, http.StatusUnauthorized, "unauthorized")
+		return
+	}
+
 	taskIDStr := chi.URLParam(r, "id")
 
-	task, err := h.uc.GetTaskByID(ctx, taskIDStr)
+	task, err := h.uc.GetTaskByID(ctx, userIDStr, taskIDStr)
 	if err != nil {
 		log.Error("failed to get task", "error", err)
 		handleUsecaseError(w, err)
@@ -184,6 +299,12 @@ func (h *TaskHandler) UpdateTask(w http.ResponseWriter, r *http.Request) {
 	ctx := r.Context()
 	_ = h.log.WithContext(ctx)
 
+	userIDStr, ok := middleware.GetUserIDFromContext(ctx)
+	if !ok {
+		response.Error(w, http.StatusUnauthorized, "unauthorized")
+		return
+	}
+
 	taskIDStr := chi.URLParam(r, "id")
 
 	var req UpdateTaskRequest
@@ -192,7 +313,13 @@ func (h *TaskHandler) UpdateTask(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	task, err := h.uc.UpdateTask(ctx, taskIDStr, req.ContextID, req.Title, req.Description, req.DueAt, nil)
+	var priority *models.TaskPriority
+	if req.Priority != nil {
+		p := models.TaskPriority(*req.Priority)
+		priority = &p
+	}
+
+	task, err := h.uc.UpdateTask(ctx, userIDStr, taskIDStr, req.ContextID, req.Title, req.Description, req.DueAt, nil, req.Recurrence, priority)
 	if err != nil {
 		handleUsecaseError(w, err)
 		return
@@ -219,6 +346,12 @@ func (h *TaskHandler) UpdateTaskStatus(w http.ResponseWriter, r *http.Request) {
 	ctx := r.Context()
 	_ = h.log.WithContext(ctx)
 
+	userIDStr, ok := middleware.GetUserIDFromContext(ctx)
+	if !ok {
+		response.Error(w, http.StatusUnauthorized, "unauthorized")
+		return
+	}
+
 	taskIDStr := chi.URLParam(r, "id")
 
 	var req UpdateTaskStatusRequest
@@ -227,7 +360,7 @@ func (h *TaskHandler) UpdateTaskStatus(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	if err := h.uc.UpdateTaskStatus(ctx, taskIDStr, models.TaskStatus(req.Status)); err != nil {
+	if err := h.uc.UpdateTaskStatus(ctx, userIDStr, taskIDStr, models.TaskStatus(req.Status)); err != nil {
 		handleUsecaseError(w, err)
 		return
 	}
@@ -252,9 +385,15 @@ func (h *TaskHandler) DeleteTask(w http.ResponseWriter, r *http.Request) {
 	ctx := r.Context()
 	_ = h.log.WithContext(ctx)
 
+	userIDStr, ok := middleware.GetUserIDFromContext(ctx)
+	if !ok {
+		response.Error(w, http.StatusUnauthorized, "unauthorized")
+		return
+	}
+
 	taskIDStr := chi.URLParam(r, "id")
 
-	if err := h.uc.DeleteTask(ctx, taskIDStr); err != nil {
+	if err := h.uc.DeleteTask(ctx, userIDStr, taskIDStr); err != nil {
 		handleUsecaseError(w, err)
 		return
 	}
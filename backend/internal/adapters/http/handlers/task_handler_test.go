@@ -0,0 +1,35 @@
+package handlers
+
+import (
+	"net/url"
+	"testing"
+
+	"github.com/singl3focus/uniflow/internal/core/models"
+)
+
+func TestParseTaskFilter(t *testing.T) {
+	query, _ := url.ParseQuery("status=todo,in_progress&inbox=true&priority=high&tag=%23matan" +
+		"&due_from=2025-03-10T00:00:00Z&due_to=2025-03-11T00:00:00Z&q=+лаба+&sort=due&limit=20")
+
+	filter, err := parseTaskFilter(query)
+	if err != nil {
+		t.Fatalf("parseTaskFilter() error = %v", err)
+	}
+
+	if len(filter.Statuses) != 2 || filter.Statuses[1] != models.TaskStatusInProgress {
+		t.Errorf("Statuses = %v, want [todo in_progress]", filter.Statuses)
+	}
+	if !filter.InboxOnly || filter.Tag != "#matan" || filter.Text != "лаба" || filter.Sort != models.TaskSortDue || filter.Limit != 20 {
+		t.Errorf("parseTaskFilter() = %+v", filter)
+	}
+	if filter.DueFrom == nil || filter.DueTo == nil || !filter.DueFrom.Before(*filter.DueTo) {
+		t.Errorf("due range = %v - %v", filter.DueFrom, filter.DueTo)
+	}
+
+	for _, raw := range []string{"context_id=abc", "inbox=maybe", "due_from=10.03.2025", "limit=ten", "cursor=not-a-cursor"} {
+		query, _ := url.ParseQuery(raw)
+		if _, err := parseTaskFilter(query); err == nil {
+			t.Errorf("parseTaskFilter(%q) error = nil, want error", raw)
+		}
+	}
+}
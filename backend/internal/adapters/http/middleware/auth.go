@@ -13,8 +13,15 @@ type contextKey string
 
 const UserIDKey contextKey = "user_id"
 
-// RequireAuth middleware для обязательной аутентификации (JWT или X-User-ID)
-func RequireAuth(jwtManager *jwtpkg.JWTManager) func(http.Handler) http.Handler {
+// TokenRevocationChecker проверяет, не отозван ли access-токен при выходе пользователя
+type TokenRevocationChecker interface {
+	IsAccessTokenRevoked(ctx context.Context, tokenID string) (bool, error)
+}
+
+// RequireAuth middleware для обязательной аутентификации.
+// JWT принимается, только если его jti не отозван.
+// Заголовок X-User-ID принимается вместо JWT, только если allowUserIDHeader включен (локальная разработка).
+func RequireAuth(jwtManager *jwtpkg.JWTManager, revocations TokenRevocationChecker, allowUserIDHeader bool) func(http.Handler) http.Handler {
 	return func(next http.Handler) http.Handler {
 		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 			// Проверяем JWT токен
@@ -23,9 +30,19 @@ func RequireAuth(jwtManager *jwtpkg.JWTManager) func(http.Handler) http.Handler
 				parts := strings.Split(authHeader, " ")
 				if len(parts) == 2 && parts[0] == "Bearer" {
 					tokenString := parts[1]
-					userID, err := jwtManager.ExtractUserID(tokenString)
-					if err == nil {
-						ctx := context.WithValue(r.Context(), UserIDKey, userID.String())
+					claims, err := jwtManager.ValidateToken(tokenString)
+					if err == nil && claims.ID != "" {
+						revoked, err := revocations.IsAccessTokenRevoked(r.Context(), claims.ID)
+						if err != nil {
+							response.Error(w, http.StatusInternalServerError, "internal server error")
+							return
+						}
+						if revoked {
+							response.Error(w, http.StatusUnauthorized, "token revoked")
+							return
+						}
+
+						ctx := context.WithValue(r.Context(), UserIDKey, claims.UserID)
 						next.ServeHTTP(w, r.WithContext(ctx))
 						return
 					}
@@ -34,7 +51,7 @@ func RequireAuth(jwtManager *jwtpkg.JWTManager) func(http.Handler) http.Handler
 
 			// Если нет JWT, пробуем X-User-ID (для обратной совместимости)
 			userIDStr := r.Header.Get("X-User-ID")
-			if userIDStr != "" {
+			if allowUserIDHeader && userIDStr != "" {
 				ctx := context.WithValue(r.Context(), UserIDKey, userIDStr)
 				next.ServeHTTP(w, r.WithContext(ctx))
 				return
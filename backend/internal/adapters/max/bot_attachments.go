@@ -0,0 +1,109 @@
+package max
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/max-messenger/max-bot-api-client-go/schemes"
+
+	"github.com/singl3focus/uniflow/internal/core/models"
+)
+
+// ============== Вложения как заметки ==============
+
+// noteAttachment - вложение сообщения, которое можно сохранить как заметку
+type noteAttachment struct {
+	Type     models.NoteType
+	URL      string
+	Filename string // Только для файлов
+}
+
+// parseNoteAttachments извлекает из сообщения фото, аудио (в том числе голосовые) и файлы.
+// Разбираются исходные JSON-вложения: они приходят одинаково и через long polling, и через webhook.
+// Остальные типы вложений (клавиатуры, стикеры, контакты и т.п.) пропускаются.
+func parseNoteAttachments(raw []json.RawMessage) []noteAttachment {
+	var result []noteAttachment
+
+	for _, data := range raw {
+		var base schemes.Attachment
+		if err := json.Unmarshal(data, &base); err != nil {
+			continue
+		}
+
+		switch base.GetAttachmentType() {
+		case schemes.AttachmentImage:
+			var a schemes.PhotoAttachment
+			if err := json.Unmarshal(data, &a); err == nil && a.Payload.Url != "" {
+				result = append(result, noteAttachment{Type: models.NoteTypeImage, URL: a.Payload.Url})
+			}
+		case schemes.AttachmentAudio:
+			var a schemes.AudioAttachment
+			if err := json.Unmarshal(data, &a); err == nil && a.Payload.Url != "" {
+				result = append(result, noteAttachment{Type: models.NoteTypeAudio, URL: a.Payload.Url})
+			}
+		case schemes.AttachmentFile:
+			var a schemes.FileAttachment
+			if err := json.Unmarshal(data, &a); err == nil && a.Payload.Url != "" {
+				result = append(result, noteAttachment{Type: models.NoteTypeFile, URL: a.Payload.Url, Filename: a.Filename})
+			}
+		}
+	}
+
+	return result
+}
+
+// handleAttachmentMessage сохраняет вложения сообщения как заметки.
+// Подпись к сообщению становится текстом заметки, для файлов без подписи - имя файла.
+func (h *UniFlowUpdateHandler) handleAttachmentMessage(ctx context.Context, userID int64, caption string, attachments []noteAttachment) {
+	maxUserID := fmt.Sprintf("%d", userID)
+	user, err := h.usecase.GetOrCreateUserByMaxID(ctx, maxUserID)
+	if err != nil {
+		h.logger.Error("failed to get user", "error", err, "max_user_id", maxUserID)
+		h.sendMessage(ctx, userID, "❌ Ошибка при получении данных пользователя.")
+		return
+	}
+
+	contexts, err := h.usecase.GetContextsByUserID(ctx, user.ID.String())
+	if err != nil {
+		h.logger.Error("failed to get contexts", "error", err, "user_id", user.ID)
+		contexts = nil
+	}
+
+	for _, attachment := range attachments {
+		text := caption
+		if text == "" {
+			text = attachment.Filename
+		}
+
+		note, err := h.usecase.CreateNote(ctx, user.ID.String(), nil, attachment.Type, attachment.URL, text)
+		if err != nil {
+			h.logger.Error("failed to create note from attachment", "error", err, "user_id", user.ID, "type", attachment.Type)
+			h.sendMessage(ctx, userID, "❌ Не удалось сохранить вложение.")
+			continue
+		}
+
+		response := fmt.Sprintf("%s Сохранено в заметки", noteTypeIcon(note.Type))
+		if note.Text != "" {
+			response += fmt.Sprintf(":\n%s", truncate(note.Text, 100))
+		}
+		if len(contexts) > 0 {
+			response += "\n\nВыбери контекст для заметки:"
+		}
+
+		h.sendMessageWithKeyboard(ctx, userID, response, h.buildNoteContextKeyboard(note.ID.String(), contexts))
+	}
+}
+
+func noteTypeIcon(t models.NoteType) string {
+	switch t {
+	case models.NoteTypeImage:
+		return "🖼"
+	case models.NoteTypeAudio:
+		return "🎙"
+	case models.NoteTypeFile:
+		return "📎"
+	default:
+		return "🗒"
+	}
+}
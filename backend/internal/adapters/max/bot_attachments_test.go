@@ -0,0 +1,35 @@
+package max
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/singl3focus/uniflow/internal/core/models"
+)
+
+func TestParseNoteAttachments(t *testing.T) {
+	raw := []json.RawMessage{
+		json.RawMessage(`{"type":"image","payload":{"photo_id":1,"token":"t","url":"https://i.example/photo.jpg"}}`),
+		json.RawMessage(`{"type":"audio","payload":{"url":"https://i.example/voice.ogg","token":"t"}}`),
+		json.RawMessage(`{"type":"file","payload":{"url":"https://i.example/lecture.pdf","token":"t"},"filename":"lecture.pdf","size":1024}`),
+		json.RawMessage(`{"type":"sticker","payload":{"url":"https://i.example/sticker.webp","code":"x"}}`),
+		json.RawMessage(`{"type":"inline_keyboard","payload":{"buttons":[]}}`),
+	}
+
+	got := parseNoteAttachments(raw)
+
+	want := []noteAttachment{
+		{Type: models.NoteTypeImage, URL: "https://i.example/photo.jpg"},
+		{Type: models.NoteTypeAudio, URL: "https://i.example/voice.ogg"},
+		{Type: models.NoteTypeFile, URL: "https://i.example/lecture.pdf", Filename: "lecture.pdf"},
+	}
+
+	if len(got) != len(want) {
+		t.Fatalf("parseNoteAttachments() returned %d attachments, want %d: %+v", len(got), len(want), got)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("attachment[%d] = %+v, want %+v", i, got[i], want[i])
+		}
+	}
+}
@@ -0,0 +1,239 @@
+package max
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/singl3focus/uniflow/internal/core/models"
+)
+
+// Куда применяется дата, выбранная в календаре
+const (
+	calendarNewTask  = "new"   // Шаг дедлайна в /newtask
+	calendarQuickAdd = "quick" // Срок в карточке быстрого добавления
+	calendarTask     = "task"  // Срок существующей задачи: редактирование и "отложить" из напоминания
+	calendarContext  = "ctx"   // Дедлайн контекста
+)
+
+// Шаги выбора в callback'ах cal_<цель>_<шаг>_<значение>...
+const (
+	calendarStepMonth = "m" // Показать месяц: cal_<цель>_m_200601
+	calendarStepDay   = "d" // День выбран, показать часы: cal_<цель>_d_20060102
+	calendarStepHour  = "h" // Час выбран, показать минуты: cal_<цель>_h_20060102_15
+	calendarStepTime  = "t" // Выбор завершен: cal_<цель>_t_20060102_1504 или cal_<цель>_t_20060102_all
+	calendarAllDay    = "all"
+	calendarNoop      = "cal_noop" // Заголовки и пустые клетки календаря
+)
+
+// Форматы даты и времени в payload календаря
+const (
+	calendarMonthFmt = "200601"
+	calendarDayFmt   = "20060102"
+	calendarClockFmt = "1504"
+)
+
+var monthNames = [...]string{
+	"Январь", "Февраль", "Март", "Апрель", "Май", "Июнь",
+	"Июль", "Август", "Сентябрь", "Октябрь", "Ноябрь", "Декабрь",
+}
+
+// calendarTarget - цель выбора даты: вид и ID задачи или контекста.
+// В payload записывается как "<вид>" или "<вид>.<id>": "_" разделяет части payload.
+type calendarTarget struct {
+	kind string
+	id   string
+}
+
+func (t calendarTarget) String() string {
+	if t.id == "" {
+		return t.kind
+	}
+	return t.kind + "." + t.id
+}
+
+// payload собирает callback календаря для этой цели
+func (t calendarTarget) payload(parts ...string) string {
+	return strings.Join(append([]string{"cal", t.String()}, parts...), "_")
+}
+
+func parseCalendarTarget(s string) (calendarTarget, bool) {
+	kind, id, _ := strings.Cut(s, ".")
+	switch kind {
+	case calendarNewTask, calendarQuickAdd:
+		return calendarTarget{kind: kind}, id == ""
+	case calendarTask, calendarContext:
+		return calendarTarget{kind: kind, id: id}, id != ""
+	default:
+		return calendarTarget{}, false
+	}
+}
+
+// openCalendar отправляет календарь текущего месяца для выбора даты цели
+func (h *UniFlowUpdateHandler) openCalendar(ctx context.Context, userID int64, target calendarTarget) {
+	maxUserID := fmt.Sprintf("%d", userID)
+	user, err := h.usecase.GetOrCreateUserByMaxID(ctx, maxUserID)
+	if err != nil {
+		h.logger.Error("failed to get user", "error", err)
+		h.sendMessage(ctx, userID, "❌ Ошибка при получении данных пользователя.")
+		return
+	}
+
+	now := time.Now().In(user.Settings.Location())
+	h.sendMessageWithKeyboard(ctx, userID, calendarPrompt(target),
+		buildCalendarKeyboard(target, now, now, user.Settings.FirstWeekday))
+}
+
+// showTimePicker отправляет выбор времени для дня, выбранного кнопкой-пресетом
+func (h *UniFlowUpdateHandler) showTimePicker(ctx context.Context, userID int64, target calendarTarget, day time.Time) {
+	now := time.Now().In(day.Location())
+	h.sendMessageWithKeyboard(ctx, userID, timePickerPrompt(day), buildTimePickerKeyboard(target, day, now))
+}
+
+// handleCalendarCallback обрабатывает календарь и выбор времени.
+// Переходы между месяцами и шагами заменяют текущее сообщение, чтобы не засорять чат.
+func (h *UniFlowUpdateHandler) handleCalendarCallback(ctx context.Context, userID int64, callbackID string, parts []string) {
+	if parts[1] == "noop" {
+		h.answerCallback(ctx, callbackID, "")
+		return
+	}
+
+	target, ok := parseCalendarTarget(parts[1])
+	if !ok {
+		h.answerCallback(ctx, callbackID, "❌ Неизвестное действие")
+		return
+	}
+
+	maxUserID := fmt.Sprintf("%d", userID)
+	user, err := h.usecase.GetOrCreateUserByMaxID(ctx, maxUserID)
+	if err != nil {
+		h.logger.Error("failed to get user", "error", err)
+		h.answerCallback(ctx, callbackID, "❌ Ошибка при получении пользователя")
+		return
+	}
+	loc := user.Settings.Location()
+	now := time.Now().In(loc)
+
+	if len(parts) < 4 {
+		h.answerCallback(ctx, callbackID, "")
+		h.openCalendar(ctx, userID, target)
+		return
+	}
+
+	step := parts[2]
+	value := parts[3]
+
+	switch step {
+	case calendarStepMonth:
+		month, err := time.ParseInLocation(calendarMonthFmt, value, loc)
+		if err != nil {
+			h.answerCallback(ctx, callbackID, "❌ Неверный месяц")
+			return
+		}
+		h.updateCallbackMessage(ctx, callbackID, calendarPrompt(target),
+			buildCalendarKeyboard(target, month, now, user.Settings.FirstWeekday))
+	case calendarStepDay:
+		day, err := time.ParseInLocation(calendarDayFmt, value, loc)
+		if err != nil {
+			h.answerCallback(ctx, callbackID, "❌ Неверная дата")
+			return
+		}
+		h.updateCallbackMessage(ctx, callbackID, timePickerPrompt(day), buildTimePickerKeyboard(target, day, now))
+	case calendarStepHour:
+		if len(parts) < 5 {
+			return
+		}
+		day, err := time.ParseInLocation(calendarDayFmt, value, loc)
+		hour, hourErr := strconv.Atoi(parts[4])
+		if err != nil || hourErr != nil || hour < 0 || hour > 23 {
+			h.answerCallback(ctx, callbackID, "❌ Неверное время")
+			return
+		}
+		h.updateCallbackMessage(ctx, callbackID, timePickerPrompt(day), buildMinutePickerKeyboard(target, day, hour))
+	case calendarStepTime:
+		if len(parts) < 5 {
+			return
+		}
+		due, allDay, ok := parseCalendarTime(value, parts[4], loc)
+		if !ok {
+			h.answerCallback(ctx, callbackID, "❌ Неверное время")
+			return
+		}
+		if due.Before(now) {
+			h.answerCallback(ctx, callbackID, "⚠️ Это время уже прошло, выбери другое")
+			return
+		}
+		h.applyCalendarDate(ctx, userID, callbackID, user.ID.String(), target, due, allDay)
+	}
+}
+
+// applyCalendarDate передает выбранный срок цели календаря; allDay - выбран весь день
+func (h *UniFlowUpdateHandler) applyCalendarDate(ctx context.Context, userID int64, callbackID, userIDStr string, target calendarTarget, due time.Time, allDay bool) {
+	dueAt := dueValue(due, allDay)
+
+	switch target.kind {
+	case calendarTask:
+		h.applyTaskEdit(ctx, userID, callbackID, target.id, userIDStr, taskEdit{dueAt: &dueAt})
+	case calendarContext:
+		// У дедлайна контекста нет отдельного признака "весь день": он хранится как 23:59
+		deadlineAt := due.Format(time.RFC3339)
+		h.applyContextEdit(ctx, userID, callbackID, target.id, userIDStr, contextEdit{deadlineAt: &deadlineAt})
+	case calendarNewTask:
+		state, exists := h.getState(ctx, userID)
+		if !exists || state.State != stateCreatingTask || state.Data.Step != 4 {
+			h.answerCallback(ctx, callbackID, "❌ Не найден процесс создания задачи")
+			return
+		}
+		h.answerCallback(ctx, callbackID, "")
+		state.Data.DueAt = &dueAt
+		h.askTaskRecurrence(ctx, userID, state)
+	case calendarQuickAdd:
+		state, exists := h.getState(ctx, userID)
+		if !exists || state.State != stateQuickAdd {
+			h.answerCallback(ctx, callbackID, "⌛ Черновик устарел, отправь задачу заново")
+			return
+		}
+		h.answerCallback(ctx, callbackID, "")
+		state.Data.DueAt = &dueAt
+		state.Data.Field = ""
+		h.saveState(ctx, userID, state)
+		h.showQuickAddCard(ctx, userID, state, "")
+	}
+}
+
+// parseCalendarTime собирает срок из дня и времени "1504"; "all" - весь день, срок до 23:59
+func parseCalendarTime(day, clock string, loc *time.Location) (due time.Time, allDay bool, ok bool) {
+	d, err := time.ParseInLocation(calendarDayFmt, day, loc)
+	if err != nil {
+		return time.Time{}, false, false
+	}
+
+	if clock == calendarAllDay {
+		return models.AllDayDue(d, loc), true, true
+	}
+
+	c, err := time.Parse(calendarClockFmt, clock)
+	if err != nil {
+		return time.Time{}, false, false
+	}
+	return time.Date(d.Year(), d.Month(), d.Day(), c.Hour(), c.Minute(), 0, 0, loc), false, true
+}
+
+func calendarPrompt(target calendarTarget) string {
+	if target.kind == calendarContext {
+		return "📅 Выбери дедлайн контекста:"
+	}
+	return "📅 Выбери срок задачи:"
+}
+
+func timePickerPrompt(day time.Time) string {
+	return fmt.Sprintf("🕐 %s, %s\n\nВыбери время или весь день:",
+		day.Format("02.01.2006"), models.WeekdayOf(day).ShortName())
+}
+
+// calendarMonthTitle возвращает заголовок месяца: "Октябрь 2026"
+func calendarMonthTitle(month time.Time) string {
+	return fmt.Sprintf("%s %d", monthNames[month.Month()-1], month.Year())
+}
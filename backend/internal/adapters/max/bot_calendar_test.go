@@ -0,0 +1,120 @@
+package max
+
+import (
+	"testing"
+	"time"
+
+	"github.com/max-messenger/max-bot-api-client-go/schemes"
+
+	"github.com/singl3focus/uniflow/internal/core/models"
+)
+
+func TestParseCalendarTarget(t *testing.T) {
+	tests := []struct {
+		s    string
+		want calendarTarget
+		ok   bool
+	}{
+		{"new", calendarTarget{kind: calendarNewTask}, true},
+		{"quick", calendarTarget{kind: calendarQuickAdd}, true},
+		{"task.0f8fad5b-d9cb-469f-a165-70867728950e", calendarTarget{kind: calendarTask, id: "0f8fad5b-d9cb-469f-a165-70867728950e"}, true},
+		{"ctx.abc", calendarTarget{kind: calendarContext, id: "abc"}, true},
+		{"task", calendarTarget{}, false},
+		{"new.abc", calendarTarget{}, false},
+		{"snooze.abc", calendarTarget{}, false},
+	}
+
+	for _, tt := range tests {
+		got, ok := parseCalendarTarget(tt.s)
+		if ok != tt.ok || ok && got != tt.want {
+			t.Errorf("parseCalendarTarget(%q) = %+v, %v; want %+v, %v", tt.s, got, ok, tt.want, tt.ok)
+		}
+		if ok && got.String() != tt.s {
+			t.Errorf("calendarTarget.String() = %q, want %q", got.String(), tt.s)
+		}
+	}
+}
+
+func TestBuildCalendarKeyboard(t *testing.T) {
+	// Суббота 17 октября 2026; 1 октября - четверг
+	today := time.Date(2026, 10, 17, 12, 0, 0, 0, time.UTC)
+	target := calendarTarget{kind: calendarTask, id: "42"}
+
+	tests := []struct {
+		name         string
+		firstWeekday models.Weekday
+		firstRow     []string // Первая неделя месяца
+		weeks        int
+	}{
+		{"monday first", models.Monday, []string{" ", " ", " ", "·", "·", "·", "·"}, 5},
+		{"sunday first", models.Sunday, []string{" ", " ", " ", " ", "·", "·", "·"}, 5},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rows := buildCalendarKeyboard(target, today, today, tt.firstWeekday).Build().Buttons
+
+			// Навигация, дни недели и недели месяца
+			if len(rows) != 2+tt.weeks {
+				t.Fatalf("rows = %d, want %d", len(rows), 2+tt.weeks)
+			}
+			if got := buttonTexts(rows[1])[0]; got != tt.firstWeekday.ShortName() {
+				t.Errorf("first weekday = %q, want %q", got, tt.firstWeekday.ShortName())
+			}
+			for i, want := range tt.firstRow {
+				if got := buttonTexts(rows[2])[i]; got != want {
+					t.Errorf("first week cell %d = %q, want %q", i, got, want)
+				}
+			}
+			for _, row := range rows[2:] {
+				if len(row) != 7 {
+					t.Errorf("week row has %d cells, want 7", len(row))
+				}
+			}
+		})
+	}
+
+	rows := buildCalendarKeyboard(target, today, today, models.Monday).Build().Buttons
+	// В текущем месяце назад не листаем
+	if p := rows[0][0].(schemes.CallbackButton).Payload; p != calendarNoop {
+		t.Errorf("prev month payload = %q, want noop", p)
+	}
+	if p := rows[0][2].(schemes.CallbackButton).Payload; p != "cal_task.42_m_202611" {
+		t.Errorf("next month payload = %q", p)
+	}
+	// 17 октября - суббота третьей недели
+	if b := rows[4][5].(schemes.CallbackButton); b.Text != "[17]" || b.Payload != "cal_task.42_d_20261017" {
+		t.Errorf("today button = %q %q", b.Text, b.Payload)
+	}
+}
+
+func TestParseCalendarTime(t *testing.T) {
+	loc := time.UTC
+
+	tests := []struct {
+		day, clock string
+		want       time.Time
+		allDay     bool
+		ok         bool
+	}{
+		{"20261020", "1830", time.Date(2026, 10, 20, 18, 30, 0, 0, loc), false, true},
+		{"20261020", calendarAllDay, time.Date(2026, 10, 20, 23, 59, 0, 0, loc), true, true},
+		{"20261020", "2560", time.Time{}, false, false},
+		{"2026102", "1830", time.Time{}, false, false},
+	}
+
+	for _, tt := range tests {
+		got, allDay, ok := parseCalendarTime(tt.day, tt.clock, loc)
+		if ok != tt.ok || allDay != tt.allDay || !got.Equal(tt.want) {
+			t.Errorf("parseCalendarTime(%q, %q) = %v, %v, %v; want %v, %v, %v", tt.day, tt.clock, got, allDay, ok, tt.want, tt.allDay, tt.ok)
+		}
+	}
+}
+
+func buttonTexts(row []schemes.ButtonInterface) []string {
+	texts := make([]string, 0, len(row))
+	for _, b := range row {
+		texts = append(texts, b.(schemes.CallbackButton).Text)
+	}
+	return texts
+}
@@ -2,12 +2,13 @@ package max
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"strconv"
-	"strings"
 	"time"
 
 	"github.com/singl3focus/uniflow/internal/core/models"
+	"github.com/singl3focus/uniflow/internal/core/usecase"
 )
 
 // ============== Обработчики callback'ов ==============
@@ -17,8 +18,8 @@ func (h *UniFlowUpdateHandler) handleTaskCallback(ctx context.Context, userID in
 		return
 	}
 
-	action := parts[1] // complete, view, edit, delete, reopen, due
-	taskID := parts[2] // ID задачи
+	action := parts[1] // complete, view, edit, due, delete, reopen, check, additem, priority
+	taskID := parts[2] // ID задачи (для check - ID пункта чек-листа)
 
 	// Получаем пользователя
 	maxUserID := fmt.Sprintf("%d", userID)
@@ -35,11 +36,20 @@ func (h *UniFlowUpdateHandler) handleTaskCallback(ctx context.Context, userID in
 	case "view":
 		h.handleViewTask(ctx, userID, callbackID, taskID, user.ID.String())
 	case "edit":
-		h.handleEditTask(ctx, userID, callbackID, taskID)
+		h.handleEditTask(ctx, userID, callbackID, taskID, user.ID.String())
+	case "due":
+		h.showEditDue(ctx, userID, callbackID, taskID, user.ID.String())
 	case "delete":
 		h.handleDeleteTask(ctx, userID, callbackID, taskID, user.ID.String())
 	case "reopen":
 		h.handleReopenTask(ctx, userID, callbackID, taskID, user.ID.String())
+	case "check":
+		// task_check_<subtask_id> - переключить пункт чек-листа
+		h.handleToggleSubtask(ctx, userID, callbackID, taskID, user.ID.String())
+	case "additem":
+		h.handleAddSubtasks(ctx, userID, callbackID, taskID, user.ID.String())
+	case "priority":
+		h.handleCycleTaskPriority(ctx, userID, callbackID, taskID, user.ID.String())
 	case "confirm":
 		h.handleConfirmAction(ctx, userID, callbackID, "task", taskID)
 	case "cancel":
@@ -71,7 +81,7 @@ func (h *UniFlowUpdateHandler) handleContextCallback(ctx context.Context, userID
 	case "tasks":
 		h.handleContextTasks(ctx, userID, callbackID, contextID, user.ID.String())
 	case "edit":
-		h.handleEditContext(ctx, userID, callbackID, contextID)
+		h.handleEditContext(ctx, userID, callbackID, contextID, user.ID.String())
 	case "delete":
 		h.handleDeleteContext(ctx, userID, callbackID, contextID, user.ID.String())
 	case "confirm":
@@ -82,6 +92,89 @@ func (h *UniFlowUpdateHandler) handleContextCallback(ctx context.Context, userID
 	}
 }
 
+func (h *UniFlowUpdateHandler) handleNoteCallback(ctx context.Context, userID int64, callbackID string, parts []string) {
+	if len(parts) < 3 {
+		return
+	}
+
+	action := parts[1] // ctx, keep, delete
+	noteID := parts[2] // ID заметки
+
+	// Получаем пользователя
+	maxUserID := fmt.Sprintf("%d", userID)
+	user, err := h.usecase.GetOrCreateUserByMaxID(ctx, maxUserID)
+	if err != nil {
+		h.logger.Error("failed to get user", "error", err)
+		h.answerCallback(ctx, callbackID, "❌ Ошибка при получении пользователя")
+		return
+	}
+
+	switch action {
+	case "ctx":
+		if len(parts) < 4 {
+			return
+		}
+		contextID := parts[3]
+
+		note, err := h.usecase.UpdateNote(ctx, user.ID.String(), noteID, &contextID, nil, nil)
+		if err != nil {
+			h.logger.Error("failed to assign note context", "error", err, "note_id", noteID)
+			h.answerCallback(ctx, callbackID, "❌ Не удалось привязать заметку")
+			return
+		}
+
+		title := "контекст"
+		if c, err := h.usecase.GetContextByID(ctx, user.ID.String(), contextID); err == nil {
+			title = c.Title
+		}
+
+		h.answerCallback(ctx, callbackID, "✅ Заметка привязана")
+		h.sendMessageWithKeyboard(ctx, userID,
+			fmt.Sprintf("%s Заметка добавлена в «%s»", noteTypeIcon(note.Type), title),
+			h.buildMainMenuKeyboard())
+	case "keep":
+		h.answerCallback(ctx, callbackID, "✅ Заметка сохранена")
+	case "delete":
+		if err := h.usecase.DeleteNote(ctx, user.ID.String(), noteID); err != nil {
+			h.logger.Error("failed to delete note", "error", err, "note_id", noteID)
+			h.answerCallback(ctx, callbackID, "❌ Ошибка при удалении заметки")
+			return
+		}
+		h.answerCallback(ctx, callbackID, "🗑 Заметка удалена")
+	}
+}
+
+func (h *UniFlowUpdateHandler) handleFocusCallback(ctx context.Context, userID int64, callbackID string, parts []string) {
+	if len(parts) < 3 {
+		return
+	}
+
+	action := parts[1] // start, stop
+	value := parts[2]  // длительность в минутах или ID сессии
+
+	// Получаем пользователя
+	maxUserID := fmt.Sprintf("%d", userID)
+	user, err := h.usecase.GetOrCreateUserByMaxID(ctx, maxUserID)
+	if err != nil {
+		h.logger.Error("failed to get user", "error", err)
+		h.answerCallback(ctx, callbackID, "❌ Ошибка при получении пользователя")
+		return
+	}
+
+	h.answerCallback(ctx, callbackID, "")
+
+	switch action {
+	case "start":
+		minutes, err := strconv.Atoi(value)
+		if err != nil {
+			return
+		}
+		h.startFocusSession(ctx, userID, user.ID.String(), minutes, nil, "")
+	case "stop":
+		h.stopFocusSession(ctx, userID, user.ID.String(), value)
+	}
+}
+
 func (h *UniFlowUpdateHandler) handleMenuCallback(ctx context.Context, userID int64, callbackID string, parts []string) {
 	if len(parts) < 2 {
 		return
@@ -97,7 +190,8 @@ func (h *UniFlowUpdateHandler) handleMenuCallback(ctx context.Context, userID in
 	case "today":
 		h.handleTodayCommand(ctx, userID)
 	case "tasks":
-		h.handleTasksCommand(ctx, userID)
+		// menu_tasks_important - только важные задачи
+		h.handleTasksCommand(ctx, userID, len(parts) > 2 && parts[2] == "important", "")
 	case "newtask":
 		h.handleNewTaskCommand(ctx, userID)
 	case "contexts":
@@ -106,27 +200,31 @@ func (h *UniFlowUpdateHandler) handleMenuCallback(ctx context.Context, userID in
 		h.handleNewContextCommand(ctx, userID)
 	case "search":
 		h.sendMessage(ctx, userID, "🔍 Введи запрос для поиска:\n\nНапример: математика")
-		h.userStates[userID] = &UserState{
-			State: "searching",
-			Data:  make(map[string]interface{}),
-		}
+		h.saveState(ctx, userID, &UserState{State: stateSearching})
 	case "inbox":
 		h.handleInboxCommand(ctx, userID)
+	case "timetable":
+		h.handleTimetableCommand(ctx, userID)
+	case "focus":
+		h.handleFocusCommand(ctx, userID, []string{"/focus"})
+	case "stats":
+		// menu_stats_N, где N - длина периода в днях
+		days := usecase.DefaultStatsDays
+		if len(parts) > 2 {
+			if parsedDays, err := strconv.Atoi(parts[2]); err == nil {
+				days = parsedDays
+			}
+		}
+		h.showStats(ctx, userID, days)
 	case "schedule":
-		// Расписание на сегодня (без offset)
-		h.handleScheduleCommand(ctx, userID, 0)
-	default:
-		// Обработка schedule_N (где N - offset)
-		if strings.HasPrefix(action, "schedule_") {
-			offsetStr := strings.TrimPrefix(action, "schedule_")
-			offset := 0
-			if offsetStr != "" {
-				if parsedOffset, err := strconv.Atoi(offsetStr); err == nil {
-					offset = parsedOffset
-				}
+		// menu_schedule_N, где N - смещение в днях от сегодня (может быть отрицательным)
+		offset := 0
+		if len(parts) > 2 {
+			if parsedOffset, err := strconv.Atoi(parts[2]); err == nil {
+				offset = parsedOffset
 			}
-			h.handleScheduleCommand(ctx, userID, offset)
 		}
+		h.handleScheduleCommand(ctx, userID, offset)
 	}
 }
 
@@ -134,22 +232,16 @@ func (h *UniFlowUpdateHandler) handleMenuCallback(ctx context.Context, userID in
 
 func (h *UniFlowUpdateHandler) handleCompleteTask(ctx context.Context, userID int64, callbackID, taskID, userIDStr string) {
 	// Получаем задачу
-	task, err := h.usecase.GetTaskByID(ctx, taskID)
+	task, err := h.usecase.GetTaskByID(ctx, userIDStr, taskID)
 	if err != nil {
 		h.logger.Error("failed to get task", "error", err)
 		h.answerCallback(ctx, callbackID, "❌ Задача не найдена")
 		return
 	}
 
-	// Проверяем права доступа
-	if task.UserID.String() != userIDStr {
-		h.answerCallback(ctx, callbackID, "❌ Нет доступа к этой задаче")
-		return
-	}
-
 	// Обновляем статус
 	completedStatus := models.TaskStatusCompleted
-	if err := h.usecase.UpdateTaskStatus(ctx, taskID, completedStatus); err != nil {
+	if err := h.usecase.UpdateTaskStatus(ctx, userIDStr, taskID, completedStatus); err != nil {
 		h.logger.Error("failed to update task", "error", err)
 		h.answerCallback(ctx, callbackID, "❌ Ошибка при обновлении задачи")
 		return
@@ -158,80 +250,148 @@ func (h *UniFlowUpdateHandler) handleCompleteTask(ctx context.Context, userID in
 	h.answerCallback(ctx, callbackID, "✅ Задача завершена!")
 
 	response := fmt.Sprintf("✅ Задача завершена!\n\n📝 %s", task.Title)
+	loc := h.userLocation(ctx, userID)
+	if next, ok := task.NextOccurrence(time.Now().In(loc)); ok && task.Status != models.TaskStatusCompleted {
+		response += fmt.Sprintf("\n\n🔁 Следующее повторение: %s", formatTaskDue(next, loc))
+	}
 	h.sendMessageWithKeyboard(ctx, userID, response, h.buildMainMenuKeyboard())
 }
 
 func (h *UniFlowUpdateHandler) handleViewTask(ctx context.Context, userID int64, callbackID, taskID, userIDStr string) {
 	// Получаем задачу
-	task, err := h.usecase.GetTaskByID(ctx, taskID)
+	task, err := h.usecase.GetTaskByID(ctx, userIDStr, taskID)
 	if err != nil {
 		h.logger.Error("failed to get task", "error", err)
 		h.answerCallback(ctx, callbackID, "❌ Задача не найдена")
 		return
 	}
 
-	// Проверяем права доступа
-	if task.UserID.String() != userIDStr {
-		h.answerCallback(ctx, callbackID, "❌ Нет доступа к этой задаче")
-		return
-	}
-
 	h.answerCallback(ctx, callbackID, "")
 
-	// Формируем детали задачи
-	status := "⭕ В работе"
-	if task.Status == "completed" {
-		status = "✅ Завершена"
-	}
+	h.showTaskDetails(ctx, userID, task)
+}
 
+// showTaskDetails отправляет карточку задачи с чек-листом и кнопками действий
+func (h *UniFlowUpdateHandler) showTaskDetails(ctx context.Context, userID int64, task models.Task) {
+	// Формируем детали задачи
 	response := fmt.Sprintf("📝 *%s*\n\n", task.Title)
-	response += fmt.Sprintf("Статус: %s\n", status)
+	response += fmt.Sprintf("Статус: %s\n", taskStatusName(task.Status))
+	response += fmt.Sprintf("Приоритет: %s%s\n", priorityIcon(task.Priority), priorityName(task.Priority))
 
 	if task.Description != "" {
 		response += fmt.Sprintf("\n📄 Описание:\n%s\n", task.Description)
 	}
 
 	if task.DueAt != nil {
-		response += fmt.Sprintf("\n⏰ Срок: %s\n", task.DueAt.Format("02.01.2006 15:04"))
+		response += fmt.Sprintf("\n⏰ Срок: %s\n", formatTaskDue(task, h.userLocation(ctx, userID)))
+	}
+
+	if task.Recurrence != nil {
+		response += fmt.Sprintf("🔁 Повторение: %s\n", formatRecurrence(task.Recurrence))
+	}
+
+	if len(task.Tags) > 0 {
+		response += fmt.Sprintf("🏷 Теги: %s\n", formatTags(task.Tags))
 	}
 
 	if task.ContextID != nil {
 		contextIDStr := task.ContextID.String()
-		context, err := h.usecase.GetContextByID(ctx, contextIDStr)
+		context, err := h.usecase.GetContextByID(ctx, task.UserID.String(), contextIDStr)
 		if err == nil {
 			response += fmt.Sprintf("\n📂 Контекст: %s\n", context.Title)
 		}
 	}
 
-	h.sendMessageWithKeyboard(ctx, userID, response, h.buildTaskDetailKeyboard(&task))
+	subtasks, err := h.usecase.GetSubtasks(ctx, task.UserID.String(), task.ID.String())
+	if err != nil {
+		h.logger.Error("failed to get subtasks", "error", err, "task_id", task.ID)
+	}
+
+	if len(subtasks) > 0 {
+		done, total := models.SubtaskProgress(subtasks)
+		response += fmt.Sprintf("\n☑️ Чек-лист: %d/%d %s\n", done, total, progressBar(done, total))
+		for _, s := range subtasks {
+			mark := "⬜"
+			if s.Done {
+				mark = "✅"
+			}
+			response += fmt.Sprintf("%s %s\n", mark, s.Title)
+		}
+	}
+
+	h.sendMessageWithKeyboard(ctx, userID, response, h.buildTaskDetailKeyboard(&task, subtasks))
+}
+
+// handleToggleSubtask отмечает пункт чек-листа и обновляет карточку задачи
+func (h *UniFlowUpdateHandler) handleToggleSubtask(ctx context.Context, userID int64, callbackID, subtaskID, userIDStr string) {
+	subtask, err := h.usecase.ToggleSubtask(ctx, userIDStr, subtaskID)
+	if err != nil {
+		h.logger.Error("failed to toggle subtask", "error", err, "subtask_id", subtaskID)
+		h.answerCallback(ctx, callbackID, "❌ Пункт не найден")
+		return
+	}
+
+	task, err := h.usecase.GetTaskByID(ctx, userIDStr, subtask.TaskID.String())
+	if err != nil {
+		h.logger.Error("failed to get task", "error", err)
+		h.answerCallback(ctx, callbackID, "❌ Задача не найдена")
+		return
+	}
+
+	if subtask.Done {
+		h.answerCallback(ctx, callbackID, "✅ Готово")
+	} else {
+		h.answerCallback(ctx, callbackID, "⬜ Отметка снята")
+	}
+
+	h.showTaskDetails(ctx, userID, task)
 }
 
-func (h *UniFlowUpdateHandler) handleEditTask(ctx context.Context, userID int64, callbackID, taskID string) {
-	h.answerCallback(ctx, callbackID, "✏️ Редактирование задачи")
+// handleCycleTaskPriority переключает приоритет задачи на следующий и обновляет карточку
+func (h *UniFlowUpdateHandler) handleCycleTaskPriority(ctx context.Context, userID int64, callbackID, taskID, userIDStr string) {
+	task, err := h.usecase.GetTaskByID(ctx, userIDStr, taskID)
+	if err != nil {
+		h.answerCallback(ctx, callbackID, "❌ Задача не найдена")
+		return
+	}
 
-	// Устанавливаем состояние редактирования
-	h.userStates[userID] = &UserState{
-		State: "editing_task",
-		Data: map[string]interface{}{
-			"task_id": taskID,
-			"step":    1,
-		},
+	priority := nextPriority(task.Priority)
+	task, err = h.usecase.UpdateTask(ctx, userIDStr, taskID, nil, nil, nil, nil, nil, nil, &priority)
+	if err != nil {
+		h.logger.Error("failed to update task priority", "error", err, "task_id", taskID)
+		h.answerCallback(ctx, callbackID, "❌ Ошибка при обновлении задачи")
+		return
 	}
 
-	h.sendMessage(ctx, userID, "✏️ Редактирование задачи\n\nШаг 1/3: Введи новое название\n\nИли /cancel для отмены")
+	h.answerCallback(ctx, callbackID, "Приоритет: "+priorityName(priority))
+	h.showTaskDetails(ctx, userID, task)
 }
 
-func (h *UniFlowUpdateHandler) handleDeleteTask(ctx context.Context, userID int64, callbackID, taskID, userIDStr string) {
-	// Получаем задачу для проверки прав
-	task, err := h.usecase.GetTaskByID(ctx, taskID)
+// handleAddSubtasks переводит пользователя в режим добавления пунктов чек-листа
+func (h *UniFlowUpdateHandler) handleAddSubtasks(ctx context.Context, userID int64, callbackID, taskID, userIDStr string) {
+	task, err := h.usecase.GetTaskByID(ctx, userIDStr, taskID)
 	if err != nil {
-		h.logger.Error("failed to get task", "error", err)
 		h.answerCallback(ctx, callbackID, "❌ Задача не найдена")
 		return
 	}
 
-	if task.UserID.String() != userIDStr {
-		h.answerCallback(ctx, callbackID, "❌ Нет доступа к этой задаче")
+	h.answerCallback(ctx, callbackID, "")
+
+	h.saveState(ctx, userID, &UserState{
+		State: stateAddingSubtasks,
+		Data:  DialogData{TaskID: taskID},
+	})
+
+	h.sendMessage(ctx, userID, fmt.Sprintf("☑️ Чек-лист задачи «%s»\n\n", task.Title)+
+		"Введи пункты, каждый с новой строки.\n\nДля отмены: /cancel")
+}
+
+func (h *UniFlowUpdateHandler) handleDeleteTask(ctx context.Context, userID int64, callbackID, taskID, userIDStr string) {
+	// Получаем задачу; чужая задача не будет найдена
+	task, err := h.usecase.GetTaskByID(ctx, userIDStr, taskID)
+	if err != nil {
+		h.logger.Error("failed to get task", "error", err)
+		h.answerCallback(ctx, callbackID, "❌ Задача не найдена")
 		return
 	}
 
@@ -244,21 +404,16 @@ func (h *UniFlowUpdateHandler) handleDeleteTask(ctx context.Context, userID int6
 
 func (h *UniFlowUpdateHandler) handleReopenTask(ctx context.Context, userID int64, callbackID, taskID, userIDStr string) {
 	// Получаем задачу
-	task, err := h.usecase.GetTaskByID(ctx, taskID)
+	task, err := h.usecase.GetTaskByID(ctx, userIDStr, taskID)
 	if err != nil {
 		h.logger.Error("failed to get task", "error", err)
 		h.answerCallback(ctx, callbackID, "❌ Задача не найдена")
 		return
 	}
 
-	if task.UserID.String() != userIDStr {
-		h.answerCallback(ctx, callbackID, "❌ Нет доступа к этой задаче")
-		return
-	}
-
 	// Обновляем статус
 	todoStatus := models.TaskStatusTodo
-	if err := h.usecase.UpdateTaskStatus(ctx, taskID, todoStatus); err != nil {
+	if err := h.usecase.UpdateTaskStatus(ctx, userIDStr, taskID, todoStatus); err != nil {
 		h.logger.Error("failed to update task", "error", err)
 		h.answerCallback(ctx, callbackID, "❌ Ошибка при обновлении задачи")
 		return
@@ -274,35 +429,41 @@ func (h *UniFlowUpdateHandler) handleReopenTask(ctx context.Context, userID int6
 
 func (h *UniFlowUpdateHandler) handleViewContext(ctx context.Context, userID int64, callbackID, contextID, userIDStr string) {
 	// Получаем контекст
-	context, err := h.usecase.GetContextByID(ctx, contextID)
+	context, err := h.usecase.GetContextByID(ctx, userIDStr, contextID)
 	if err != nil {
 		h.logger.Error("failed to get context", "error", err)
 		h.answerCallback(ctx, callbackID, "❌ Контекст не найден")
 		return
 	}
 
-	if context.UserID.String() != userIDStr {
-		h.answerCallback(ctx, callbackID, "❌ Нет доступа к этому контексту")
-		return
-	}
-
 	h.answerCallback(ctx, callbackID, "")
 
+	h.showContextDetails(ctx, userID, context, userIDStr)
+}
+
+// showContextDetails отправляет карточку контекста со счетчиками задач и заметок
+func (h *UniFlowUpdateHandler) showContextDetails(ctx context.Context, userID int64, context models.Context, userIDStr string) {
+	contextID := context.ID.String()
+	typeOpt, _ := findContextTypeOption(context.Type)
+
 	response := fmt.Sprintf("📂 *%s*\n\n", context.Title)
+	response += fmt.Sprintf("🏷 Тип: %s\n", typeOpt.Name)
+	if context.Color != "" {
+		response += fmt.Sprintf("🎨 Цвет: %s\n", contextColorName(context.Color))
+	}
+	if context.DeadlineAt != nil {
+		response += fmt.Sprintf("📅 Дедлайн: %s\n", context.DeadlineAt.In(h.userLocation(ctx, userID)).Format("02.01.2006 15:04"))
+	}
+	response += "\n"
 
 	if context.Description != "" {
 		response += fmt.Sprintf("📄 %s\n\n", context.Description)
 	}
 
-	// Получаем все задачи пользователя и фильтруем по контексту
-	allTasks, err := h.usecase.GetTasksByUserID(ctx, userIDStr)
+	// Получаем задачи контекста
+	page, err := h.usecase.ListTasks(ctx, userIDStr, models.TaskFilter{ContextID: &context.ID})
 	if err == nil {
-		var tasks []models.Task
-		for _, task := range allTasks {
-			if task.ContextID != nil && task.ContextID.String() == contextID {
-				tasks = append(tasks, task)
-			}
-		}
+		tasks := page.Tasks
 		active := 0
 		completed := 0
 		for _, task := range tasks {
@@ -315,40 +476,36 @@ func (h *UniFlowUpdateHandler) handleViewContext(ctx context.Context, userID int
 		response += fmt.Sprintf("📊 Задач: %d (активных: %d, завершено: %d)", len(tasks), active, completed)
 	}
 
+	notes, err := h.usecase.GetNotesByContextID(ctx, userIDStr, contextID)
+	if err == nil && len(notes) > 0 {
+		response += fmt.Sprintf("\n🗒 Заметок: %d", len(notes))
+	}
+
 	h.sendMessageWithKeyboard(ctx, userID, response, h.buildContextDetailKeyboard(&context))
 }
 
 func (h *UniFlowUpdateHandler) handleContextTasks(ctx context.Context, userID int64, callbackID, contextID, userIDStr string) {
 	// Получаем контекст
-	context, err := h.usecase.GetContextByID(ctx, contextID)
+	context, err := h.usecase.GetContextByID(ctx, userIDStr, contextID)
 	if err != nil {
 		h.logger.Error("failed to get context", "error", err)
 		h.answerCallback(ctx, callbackID, "❌ Контекст не найден")
 		return
 	}
 
-	if context.UserID.String() != userIDStr {
-		h.answerCallback(ctx, callbackID, "❌ Нет доступа к этому контексту")
-		return
-	}
-
 	h.answerCallback(ctx, callbackID, "")
 
-	// Получаем все задачи пользователя и фильтруем по контексту
-	allTasks, err := h.usecase.GetTasksByUserID(ctx, userIDStr)
+	// Получаем задачи контекста, сначала важные
+	page, err := h.usecase.ListTasks(ctx, userIDStr, models.TaskFilter{
+		ContextID: &context.ID,
+		Sort:      models.TaskSortPriority,
+	})
 	if err != nil {
 		h.logger.Error("failed to get tasks", "error", err)
 		h.sendMessage(ctx, userID, "❌ Ошибка при получении задач")
 		return
 	}
-
-	// Фильтруем задачи по контексту
-	var tasks []models.Task
-	for _, task := range allTasks {
-		if task.ContextID != nil && task.ContextID.String() == contextID {
-			tasks = append(tasks, task)
-		}
-	}
+	tasks := page.Tasks
 
 	if len(tasks) == 0 {
 		response := fmt.Sprintf("📂 Контекст: %s\n\n📝 В этом контексте пока нет задач", context.Title)
@@ -363,7 +520,7 @@ func (h *UniFlowUpdateHandler) handleContextTasks(ctx context.Context, userID in
 		if task.Status == models.TaskStatusCompleted {
 			status = "✅"
 		}
-		response += fmt.Sprintf("%d. %s %s\n", i+1, status, task.Title)
+		response += fmt.Sprintf("%d. %s %s%s\n", i+1, status, priorityIcon(task.Priority), task.Title)
 	}
 
 	if len(tasks) > 10 {
@@ -373,26 +530,15 @@ func (h *UniFlowUpdateHandler) handleContextTasks(ctx context.Context, userID in
 	h.sendMessageWithKeyboard(ctx, userID, response, h.buildTaskListKeyboard(tasks[:min(5, len(tasks))]))
 }
 
-func (h *UniFlowUpdateHandler) handleEditContext(ctx context.Context, userID int64, callbackID, contextID string) {
-	h.answerCallback(ctx, callbackID, "✏️ Редактирование контекста")
-	h.sendMessage(ctx, userID, "✏️ Функция редактирования контекста будет добавлена позже")
-	h.handleContextsCommand(ctx, userID)
-}
-
 func (h *UniFlowUpdateHandler) handleDeleteContext(ctx context.Context, userID int64, callbackID, contextID, userIDStr string) {
-	// Получаем контекст для проверки прав
-	context, err := h.usecase.GetContextByID(ctx, contextID)
+	// Получаем контекст; чужой контекст не будет найден
+	context, err := h.usecase.GetContextByID(ctx, userIDStr, contextID)
 	if err != nil {
 		h.logger.Error("failed to get context", "error", err)
 		h.answerCallback(ctx, callbackID, "❌ Контекст не найден")
 		return
 	}
 
-	if context.UserID.String() != userIDStr {
-		h.answerCallback(ctx, callbackID, "❌ Нет доступа к этому контексту")
-		return
-	}
-
 	h.answerCallback(ctx, callbackID, "")
 
 	// Запрашиваем подтверждение
@@ -410,38 +556,77 @@ func (h *UniFlowUpdateHandler) handleDateCallback(ctx context.Context, userID in
 	h.answerCallback(ctx, callbackID, "")
 
 	// Проверяем, что пользователь в состоянии создания задачи
-	state, exists := h.userStates[userID]
-	if !exists || state.State != "creating_task" {
+	state, exists := h.getState(ctx, userID)
+	if !exists || state.State != stateCreatingTask {
 		h.sendMessage(ctx, userID, "❌ Ошибка: не найден процесс создания задачи")
 		return
 	}
 
 	days := parts[1]
 
-	if days == "skip" {
-		// Пропускаем установку даты
-		state.Data["due_at"] = nil
-	} else {
-		// Парсим количество дней
+	if days != "skip" {
+		// День выбран пресетом; срок задается после выбора времени в календаре
 		daysInt, err := strconv.Atoi(days)
 		if err != nil {
 			h.sendMessage(ctx, userID, "❌ Неверный формат даты")
 			return
 		}
 
-		// Вычисляем дату
-		dueDate := time.Now().AddDate(0, 0, daysInt)
-		dueDateStr := dueDate.Format("2006-01-02T15:04:05Z07:00")
-		state.Data["due_at"] = dueDateStr
+		day := startOfDay(time.Now().In(h.userLocation(ctx, userID))).AddDate(0, 0, daysInt)
+		h.showTimePicker(ctx, userID, calendarTarget{kind: calendarNewTask}, day)
+		return
 	}
 
-	// Переводим в шаг 4 для создания задачи
-	state.Data["step"] = 4
+	// Пропускаем установку даты
+	state.Data.DueAt = nil
+
+	// Переводим в последний шаг для создания задачи
+	state.Data.Step = 6
 
 	// Вызываем обработчик с пустым текстом для создания задачи
 	h.handleCreatingTaskState(ctx, userID, "", state)
 }
 
+// handleRepeatCallback обрабатывает выбор повторения на последнем шаге создания задачи
+func (h *UniFlowUpdateHandler) handleRepeatCallback(ctx context.Context, userID int64, callbackID string, parts []string) {
+	if len(parts) < 2 {
+		return
+	}
+
+	h.answerCallback(ctx, callbackID, "")
+
+	state, exists := h.getState(ctx, userID)
+	if !exists || state.State != stateCreatingTask {
+		h.sendMessage(ctx, userID, "❌ Ошибка: не найден процесс создания задачи")
+		return
+	}
+
+	var recurrence *models.Recurrence
+	switch parts[1] {
+	case "none":
+	case "daily":
+		recurrence = &models.Recurrence{Frequency: models.RecurrenceDaily, Interval: 1}
+	case "workdays":
+		recurrence = &models.Recurrence{
+			Frequency: models.RecurrenceWeekly,
+			Interval:  1,
+			Weekdays:  []models.Weekday{models.Monday, models.Tuesday, models.Wednesday, models.Thursday, models.Friday},
+		}
+	case "weekly":
+		// Без списка дней задача повторяется в тот же день недели, что и дедлайн
+		recurrence = &models.Recurrence{Frequency: models.RecurrenceWeekly, Interval: 1}
+	case "monthly":
+		recurrence = &models.Recurrence{Frequency: models.RecurrenceMonthly, Interval: 1}
+	default:
+		return
+	}
+
+	state.Data.Recurrence = recurrence
+	state.Data.Step = 6
+
+	h.handleCreatingTaskState(ctx, userID, "", state)
+}
+
 func (h *UniFlowUpdateHandler) handleConfirmAction(ctx context.Context, userID int64, callbackID, itemType, itemID string) {
 	// Получаем пользователя
 	maxUserID := fmt.Sprintf("%d", userID)
@@ -454,14 +639,12 @@ func (h *UniFlowUpdateHandler) handleConfirmAction(ctx context.Context, userID i
 
 	switch itemType {
 	case "task":
-		// Удаляем задачу
-		task, err := h.usecase.GetTaskByID(ctx, itemID)
-		if err != nil || task.UserID.String() != user.ID.String() {
-			h.answerCallback(ctx, callbackID, "❌ Задача не найдена")
-			return
-		}
-
-		if err := h.usecase.DeleteTask(ctx, itemID); err != nil {
+		// Удаляем задачу; чужая задача не будет найдена
+		if err := h.usecase.DeleteTask(ctx, user.ID.String(), itemID); err != nil {
+			if errors.Is(err, usecase.ErrNotFound) {
+				h.answerCallback(ctx, callbackID, "❌ Задача не найдена")
+				return
+			}
 			h.logger.Error("failed to delete task", "error", err)
 			h.answerCallback(ctx, callbackID, "❌ Ошибка при удалении")
 			return
@@ -469,17 +652,15 @@ func (h *UniFlowUpdateHandler) handleConfirmAction(ctx context.Context, userID i
 
 		h.answerCallback(ctx, callbackID, "✅ Задача удалена")
 		h.sendMessage(ctx, userID, "🗑 Задача удалена")
-		h.handleTasksCommand(ctx, userID)
+		h.handleTasksCommand(ctx, userID, false, "")
 
 	case "context":
-		// Удаляем контекст
-		context, err := h.usecase.GetContextByID(ctx, itemID)
-		if err != nil || context.UserID.String() != user.ID.String() {
-			h.answerCallback(ctx, callbackID, "❌ Контекст не найден")
-			return
-		}
-
-		if err := h.usecase.DeleteContext(ctx, itemID); err != nil {
+		// Удаляем контекст; чужой контекст не будет найден
+		if err := h.usecase.DeleteContext(ctx, user.ID.String(), itemID); err != nil {
+			if errors.Is(err, usecase.ErrNotFound) {
+				h.answerCallback(ctx, callbackID, "❌ Контекст не найден")
+				return
+			}
 			h.logger.Error("failed to delete context", "error", err)
 			h.answerCallback(ctx, callbackID, "❌ Ошибка при удалении")
 			return
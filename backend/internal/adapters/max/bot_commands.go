@@ -2,7 +2,10 @@ package max
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"regexp"
+	"strconv"
 	"strings"
 	"time"
 
@@ -10,6 +13,7 @@ import (
 	"github.com/max-messenger/max-bot-api-client-go/schemes"
 
 	"github.com/singl3focus/uniflow/internal/core/models"
+	"github.com/singl3focus/uniflow/internal/core/usecase"
 )
 
 // ============== Команды ==============
@@ -22,6 +26,7 @@ func (h *UniFlowUpdateHandler) handleStartCommand(ctx context.Context, userID in
 		"📅 Отслеживание дедлайнов\n" +
 		"🔔 Напоминания\n" +
 		"📊 Прогресс выполнения\n\n" +
+		"Чтобы добавить задачу, просто напиши ее, например: «сдать лабу завтра в 18:00»\n\n" +
 		"Используй кнопки ниже для навигации!"
 
 	h.sendMessageWithKeyboard(ctx, userID, response, h.buildMainMenuKeyboard())
@@ -46,38 +51,54 @@ func (h *UniFlowUpdateHandler) handleScheduleCommand(ctx context.Context, userID
 		return
 	}
 
-	// Вычисляем целевую дату
-	targetDate := time.Now().AddDate(0, 0, dayOffset)
-	dateStr := targetDate.Format("02.01.2006")
-
-	// Получаем все задачи пользователя
-	allTasks, err := h.usecase.GetTasksByUserID(ctx, user.ID.String())
+	// Вычисляем целевую дату и границы дня в часовом поясе пользователя
+	targetDate := time.Now().In(user.Settings.Location()).AddDate(0, 0, dayOffset)
+	dayStart := user.Settings.StartOfDay(targetDate)
+	dayEnd := dayStart.AddDate(0, 0, 1)
+
+	// Получаем задачи с дедлайном в этот день
+	page, err := h.usecase.ListTasks(ctx, user.ID.String(), models.TaskFilter{
+		DueFrom: &dayStart,
+		DueTo:   &dayEnd,
+		Sort:    models.TaskSortDue,
+	})
 	if err != nil {
 		h.logger.Error("failed to get tasks", "error", err, "user_id", user.ID)
 		h.sendMessage(ctx, userID, "❌ Ошибка при получении задач.")
 		return
 	}
+	tasks := page.Tasks
 
-	// Фильтруем задачи по дате
-	var tasks []models.Task
-	for _, task := range allTasks {
-		if task.DueAt != nil && task.DueAt.Format("02.01.2006") == dateStr {
-			tasks = append(tasks, task)
-		}
+	// Занятия из недельного расписания на этот день недели
+	classes, err := h.usecase.GetScheduleForDate(ctx, user.ID.String(), targetDate)
+	if err != nil {
+		h.logger.Error("failed to get schedule", "error", err, "user_id", user.ID)
+		h.sendMessage(ctx, userID, "❌ Ошибка при получении расписания.")
+		return
 	}
 
-	if len(tasks) == 0 {
-		dayLabel := "Сегодня"
-		if dayOffset > 0 {
-			dayLabel = targetDate.Format("02.01")
-		} else if dayOffset < 0 {
-			dayLabel = targetDate.Format("02.01")
-		}
-		response := fmt.Sprintf("📅 %s задач нет!\n\nОтличный день для отдыха 😊", dayLabel)
+	dayLabel := "Сегодня"
+	if dayOffset != 0 {
+		dayLabel = targetDate.Format("02.01")
+	}
+	weekday := models.WeekdayOf(targetDate)
+
+	if len(tasks) == 0 && len(classes) == 0 {
+		response := fmt.Sprintf("📅 %s (%s) задач и занятий нет!\n\nОтличный день для отдыха 😊", dayLabel, weekday.ShortName())
 		h.sendMessageWithKeyboard(ctx, userID, response, h.buildScheduleKeyboard(dayOffset))
 		return
 	}
 
+	response := fmt.Sprintf("📅 %s, %s\n\n", dayLabel, weekday.Name())
+
+	if len(classes) > 0 {
+		response += fmt.Sprintf("🎓 Занятия (%d):\n", len(classes))
+		for _, entry := range classes {
+			response += formatScheduleEntry(entry)
+		}
+		response += "\n"
+	}
+
 	// Группируем задачи по статусу
 	var active, completed []models.Task
 	for _, task := range tasks {
@@ -88,17 +109,14 @@ func (h *UniFlowUpdateHandler) handleScheduleCommand(ctx context.Context, userID
 		}
 	}
 
-	dayLabel := "Сегодня"
-	if dayOffset != 0 {
-		dayLabel = targetDate.Format("02.01")
+	if len(tasks) == 0 {
+		response += "✨ Задач на этот день нет\n"
 	}
 
-	response := fmt.Sprintf("📅 Задачи на %s:\n\n", dayLabel)
-
 	if len(active) > 0 {
 		response += fmt.Sprintf("⭕ Активные (%d):\n", len(active))
 		for _, task := range active {
-			response += fmt.Sprintf("• %s\n", task.Title)
+			response += fmt.Sprintf("• %s%s\n", formatDayTime(task, user.Settings.Location()), task.Title)
 		}
 		response += "\n"
 	}
@@ -113,7 +131,7 @@ func (h *UniFlowUpdateHandler) handleScheduleCommand(ctx context.Context, userID
 	h.sendMessageWithKeyboard(ctx, userID, response, h.buildScheduleKeyboard(dayOffset))
 }
 
-func (h *UniFlowUpdateHandler) handleInboxCommand(ctx context.Context, userID int64) {
+func (h *UniFlowUpdateHandler) handleTimetableCommand(ctx context.Context, userID int64) {
 	// Получаем или создаем пользователя по MAX ID
 	maxUserID := fmt.Sprintf("%d", userID)
 	user, err := h.usecase.GetOrCreateUserByMaxID(ctx, maxUserID)
@@ -123,22 +141,70 @@ func (h *UniFlowUpdateHandler) handleInboxCommand(ctx context.Context, userID in
 		return
 	}
 
-	// Получаем все задачи пользователя
-	allTasks, err := h.usecase.GetTasksByUserID(ctx, user.ID.String())
+	entries, err := h.usecase.GetScheduleEntries(ctx, user.ID.String())
 	if err != nil {
-		h.logger.Error("failed to get tasks", "error", err, "user_id", user.ID)
-		h.sendMessage(ctx, userID, "❌ Ошибка при получении задач.")
+		h.logger.Error("failed to get schedule", "error", err, "user_id", user.ID)
+		h.sendMessage(ctx, userID, "❌ Ошибка при получении расписания.")
 		return
 	}
 
-	// Фильтруем задачи без контекста
-	var inboxTasks []models.Task
-	for _, task := range allTasks {
-		if task.ContextID == nil {
-			inboxTasks = append(inboxTasks, task)
+	if len(entries) == 0 {
+		response := "🗓 Расписание занятий пусто.\n\nДобавь занятия в приложении UniFlow — они появятся здесь и в разделе «Расписание»."
+		h.sendMessageWithKeyboard(ctx, userID, response, h.buildMainMenuKeyboard())
+		return
+	}
+
+	// Записи приходят отсортированными по дню недели и времени начала
+	response := "🗓 Расписание на неделю:\n"
+	today := models.WeekdayOf(time.Now().In(user.Settings.Location()))
+	current := models.Weekday(-1)
+	for _, entry := range entries {
+		if entry.Weekday != current {
+			current = entry.Weekday
+			marker := ""
+			if current == today {
+				marker = " 👈 сегодня"
+			}
+			response += fmt.Sprintf("\n📌 %s%s:\n", current.Name(), marker)
 		}
+		response += formatScheduleEntry(entry)
 	}
 
+	h.sendMessageWithKeyboard(ctx, userID, response, h.buildScheduleKeyboard(0))
+}
+
+// formatScheduleEntry форматирует занятие одной строкой: время, название и место
+func formatScheduleEntry(entry models.ScheduleEntry) string {
+	line := fmt.Sprintf("• %s–%s %s",
+		entry.StartAt.Format(models.ScheduleTimeLayout),
+		entry.EndAt.Format(models.ScheduleTimeLayout),
+		entry.Title,
+	)
+	if entry.Location != "" {
+		line += fmt.Sprintf(" 📍 %s", entry.Location)
+	}
+	return line + "\n"
+}
+
+func (h *UniFlowUpdateHandler) handleInboxCommand(ctx context.Context, userID int64) {
+	// Получаем или создаем пользователя по MAX ID
+	maxUserID := fmt.Sprintf("%d", userID)
+	user, err := h.usecase.GetOrCreateUserByMaxID(ctx, maxUserID)
+	if err != nil {
+		h.logger.Error("failed to get user", "error", err, "max_user_id", maxUserID)
+		h.sendMessage(ctx, userID, "❌ Ошибка при получении данных пользователя.")
+		return
+	}
+
+	// Получаем задачи без контекста
+	page, err := h.usecase.ListTasks(ctx, user.ID.String(), models.TaskFilter{InboxOnly: true})
+	if err != nil {
+		h.logger.Error("failed to get tasks", "error", err, "user_id", user.ID)
+		h.sendMessage(ctx, userID, "❌ Ошибка при получении задач.")
+		return
+	}
+	inboxTasks := page.Tasks
+
 	if len(inboxTasks) == 0 {
 		response := "📥 Входящие пусты!\n\nВсе задачи распределены по контекстам 👍"
 		h.sendMessageWithKeyboard(ctx, userID, response, h.buildMainMenuKeyboard())
@@ -166,7 +232,7 @@ func (h *UniFlowUpdateHandler) handleInboxCommand(ctx context.Context, userID in
 			}
 			dueStr := ""
 			if task.DueAt != nil {
-				dueStr = fmt.Sprintf(" (до %s)", task.DueAt.Format("02.01"))
+				dueStr = fmt.Sprintf(" (до %s)", task.DueAt.In(user.Settings.Location()).Format("02.01"))
 			}
 			response += fmt.Sprintf("%d. %s%s\n", i+1, task.Title, dueStr)
 		}
@@ -182,7 +248,10 @@ func (h *UniFlowUpdateHandler) handleInboxCommand(ctx context.Context, userID in
 	h.sendMessageWithKeyboard(ctx, userID, response, h.buildInboxKeyboard(active))
 }
 
-func (h *UniFlowUpdateHandler) handleTasksCommand(ctx context.Context, userID int64) {
+// handleTasksCommand показывает задачи, сначала самые важные.
+// При onlyImportant показываются только задачи с высоким и срочным приоритетом,
+// при непустом tag - только задачи с этим тегом.
+func (h *UniFlowUpdateHandler) handleTasksCommand(ctx context.Context, userID int64, onlyImportant bool, tag string) {
 	// Получаем или создаем пользователя по MAX ID
 	maxUserID := fmt.Sprintf("%d", userID)
 	user, err := h.usecase.GetOrCreateUserByMaxID(ctx, maxUserID)
@@ -192,17 +261,33 @@ func (h *UniFlowUpdateHandler) handleTasksCommand(ctx context.Context, userID in
 		return
 	}
 
-	// Получаем все задачи
-	tasks, err := h.usecase.GetTasksByUserID(ctx, user.ID.String())
+	var priorities []models.TaskPriority
+	if onlyImportant {
+		priorities = []models.TaskPriority{models.TaskPriorityUrgent, models.TaskPriorityHigh}
+	}
+
+	// Получаем задачи, отсортированные по приоритету
+	page, err := h.usecase.ListTasks(ctx, user.ID.String(), models.TaskFilter{
+		Priorities: priorities,
+		Tag:        tag,
+		Sort:       models.TaskSortPriority,
+	})
 	if err != nil {
 		h.logger.Error("failed to get tasks", "error", err, "user_id", user.ID)
 		h.sendMessage(ctx, userID, "❌ Ошибка при получении задач.")
 		return
 	}
+	tasks := page.Tasks
 
 	// Формируем ответ
 	if len(tasks) == 0 {
 		response := "📝 У тебя пока нет задач!\n\nСоздай первую задачу с помощью /newtask"
+		if onlyImportant {
+			response = "🔥 Важных задач нет!\n\nВсе задачи: /tasks"
+		}
+		if tag != "" {
+			response = fmt.Sprintf("🏷 Задач с тегом %s нет!\n\nВсе задачи: /tasks", tag)
+		}
 		h.sendMessageWithKeyboard(ctx, userID, response, h.buildMainMenuKeyboard())
 		return
 	}
@@ -218,6 +303,12 @@ func (h *UniFlowUpdateHandler) handleTasksCommand(ctx context.Context, userID in
 	}
 
 	response := fmt.Sprintf("📝 Всего задач: %d\n\n", len(tasks))
+	if onlyImportant {
+		response = fmt.Sprintf("🔥 Важные задачи: %d\n\n", len(tasks))
+	}
+	if tag != "" {
+		response = fmt.Sprintf("🏷 Задачи с тегом %s: %d\n\n", tag, len(tasks))
+	}
 
 	if len(active) > 0 {
 		response += fmt.Sprintf("⭕ Активные (%d):\n", len(active))
@@ -229,9 +320,9 @@ func (h *UniFlowUpdateHandler) handleTasksCommand(ctx context.Context, userID in
 			}
 			dueStr := ""
 			if task.DueAt != nil {
-				dueStr = fmt.Sprintf(" (до %s)", task.DueAt.Format("02.01"))
+				dueStr = fmt.Sprintf(" (до %s)", task.DueAt.In(user.Settings.Location()).Format("02.01"))
 			}
-			response += fmt.Sprintf("• %s%s\n", task.Title, dueStr)
+			response += fmt.Sprintf("• %s%s%s\n", priorityIcon(task.Priority), task.Title, dueStr)
 		}
 		response += "\n"
 	}
@@ -242,19 +333,21 @@ func (h *UniFlowUpdateHandler) handleTasksCommand(ctx context.Context, userID in
 
 	// Показываем кнопки для первых 5 активных задач
 	displayTasks := active[:min(5, len(active))]
-	h.sendMessageWithKeyboard(ctx, userID, response, h.buildTaskListKeyboard(displayTasks))
+	kb := h.buildTaskListKeyboard(displayTasks)
+	if onlyImportant {
+		kb.AddRow().AddCallback("📋 Все задачи", schemes.DEFAULT, "menu_tasks")
+	} else {
+		kb.AddRow().AddCallback("🔥 Только важные", schemes.DEFAULT, "menu_tasks_important")
+	}
+	h.sendMessageWithKeyboard(ctx, userID, response, kb)
 }
 
 func (h *UniFlowUpdateHandler) handleNewTaskCommand(ctx context.Context, userID int64) {
 	// Устанавливаем состояние создания задачи
-	h.userStates[userID] = &UserState{
-		State:      "creating_task",
-		Data:       make(map[string]interface{}),
-		LastUpdate: time.Now(),
-	}
+	h.saveState(ctx, userID, &UserState{State: stateCreatingTask})
 
 	response := "📝 Создание новой задачи\n\n" +
-		"Шаг 1/3: Введи название задачи\n\n" +
+		"Шаг 1/5: Введи название задачи\n\n" +
 		"Или /cancel для отмены"
 
 	h.sendMessage(ctx, userID, response)
@@ -298,14 +391,10 @@ func (h *UniFlowUpdateHandler) handleContextsCommand(ctx context.Context, userID
 
 func (h *UniFlowUpdateHandler) handleNewContextCommand(ctx context.Context, userID int64) {
 	// Устанавливаем состояние создания контекста
-	h.userStates[userID] = &UserState{
-		State:      "creating_context",
-		Data:       make(map[string]interface{}),
-		LastUpdate: time.Now(),
-	}
+	h.saveState(ctx, userID, &UserState{State: stateCreatingContext})
 
 	response := "📁 Создание нового контекста\n\n" +
-		"Шаг 1/2: Введи название контекста\n\n" +
+		"Шаг 1/3: Введи название контекста\n\n" +
 		"Например: Учеба, Работа, Проекты\n\n" +
 		"Или /cancel для отмены"
 
@@ -337,9 +426,10 @@ func (h *UniFlowUpdateHandler) handleSearchCommand(ctx context.Context, userID i
 		return
 	}
 
-	// Извлекаем задачи и контексты из результатов
+	// Извлекаем задачи, контексты и заметки из результатов
 	var tasks []models.Task
 	var contexts []models.Context
+	var notes []models.Note
 
 	if tasksData, ok := results["tasks"].([]models.Task); ok {
 		tasks = tasksData
@@ -349,7 +439,11 @@ func (h *UniFlowUpdateHandler) handleSearchCommand(ctx context.Context, userID i
 		contexts = contextsData
 	}
 
-	if len(tasks) == 0 && len(contexts) == 0 {
+	if notesData, ok := results["notes"].([]models.Note); ok {
+		notes = notesData
+	}
+
+	if len(tasks) == 0 && len(contexts) == 0 && len(notes) == 0 {
 		h.sendMessage(ctx, userID, fmt.Sprintf("🔍 По запросу '%s' ничего не найдено", query))
 		return
 	}
@@ -381,11 +475,265 @@ func (h *UniFlowUpdateHandler) handleSearchCommand(ctx context.Context, userID i
 		if len(tasks) > 10 {
 			response += fmt.Sprintf("...и ещё %d\n", len(tasks)-10)
 		}
+		response += "\n"
+	}
+
+	// Показываем заметки
+	if len(notes) > 0 {
+		response += fmt.Sprintf("🗒 Заметки (%d):\n", len(notes))
+		for i, note := range notes[:min(5, len(notes))] {
+			response += fmt.Sprintf("%d. %s\n", i+1, truncate(note.Text, 60))
+		}
+		if len(notes) > 5 {
+			response += fmt.Sprintf("...и ещё %d\n", len(notes)-5)
+		}
+	}
+
+	h.sendMessageWithKeyboard(ctx, userID, response, h.buildMainMenuKeyboard())
+}
+
+func (h *UniFlowUpdateHandler) handleFocusCommand(ctx context.Context, userID int64, parts []string) {
+	// Получаем или создаем пользователя по MAX ID
+	maxUserID := fmt.Sprintf("%d", userID)
+	user, err := h.usecase.GetOrCreateUserByMaxID(ctx, maxUserID)
+	if err != nil {
+		h.logger.Error("failed to get user", "error", err, "max_user_id", maxUserID)
+		h.sendMessage(ctx, userID, "❌ Ошибка при получении данных пользователя.")
+		return
+	}
+
+	args := parts[1:]
+
+	// /focus stop - остановить текущую сессию
+	if len(args) > 0 && (strings.EqualFold(args[0], "stop") || strings.EqualFold(args[0], "стоп")) {
+		session, err := h.usecase.GetActiveFocusSession(ctx, user.ID.String())
+		if err != nil {
+			h.sendMessage(ctx, userID, "ℹ️ Сейчас нет активной фокус-сессии.")
+			return
+		}
+		h.stopFocusSession(ctx, userID, user.ID.String(), session.ID.String())
+		return
+	}
+
+	// /focus без аргументов - статус или выбор длительности
+	if len(args) == 0 {
+		h.showFocusStatus(ctx, userID, user.ID.String())
+		return
+	}
+
+	minutes := models.DefaultFocusMinutes
+	if n, err := strconv.Atoi(args[0]); err == nil {
+		minutes = n
+		args = args[1:]
+	}
+
+	var contextID *string
+	contextTitle := ""
+	if len(args) > 0 {
+		name := strings.Join(args, " ")
+
+		contexts, err := h.usecase.GetContextsByUserID(ctx, user.ID.String())
+		if err != nil {
+			h.logger.Error("failed to get contexts", "error", err, "user_id", user.ID)
+			h.sendMessage(ctx, userID, "❌ Ошибка при получении контекстов.")
+			return
+		}
+
+		c, ok := findContextByName(contexts, name)
+		if !ok {
+			h.sendMessage(ctx, userID, fmt.Sprintf("❌ Контекст «%s» не найден.\n\nСписок контекстов: /contexts", name))
+			return
+		}
+
+		id := c.ID.String()
+		contextID = &id
+		contextTitle = c.Title
 	}
 
+	h.startFocusSession(ctx, userID, user.ID.String(), minutes, contextID, contextTitle)
+}
+
+func (h *UniFlowUpdateHandler) showFocusStatus(ctx context.Context, userID int64, userIDStr string) {
+	session, err := h.usecase.GetActiveFocusSession(ctx, userIDStr)
+	if err != nil {
+		response := "🎯 Фокус-сессия\n\n" +
+			"Выбери длительность или используй /focus <минуты> [контекст]\n" +
+			"Например: /focus 50 Математика"
+		h.sendMessageWithKeyboard(ctx, userID, response, h.buildFocusKeyboard(nil))
+		return
+	}
+
+	left := time.Until(session.PlannedEndAt()).Round(time.Minute)
+	response := fmt.Sprintf("🎯 Идёт фокус-сессия\n\n⏱ Осталось: %d мин из %d\n🏁 Окончание в %s",
+		int(left.Minutes()), session.DurationMinutes, session.PlannedEndAt().In(h.userLocation(ctx, userID)).Format("15:04"))
+	h.sendMessageWithKeyboard(ctx, userID, response, h.buildFocusKeyboard(&session))
+}
+
+func (h *UniFlowUpdateHandler) startFocusSession(ctx context.Context, userID int64, userIDStr string, minutes int, contextID *string, contextTitle string) {
+	session, err := h.usecase.StartFocusSession(ctx, userIDStr, contextID, minutes)
+	if err != nil {
+		switch {
+		case errors.Is(err, models.ErrFocusSessionActive):
+			h.sendMessage(ctx, userID, "⚠️ Фокус-сессия уже идёт. Останови её: /focus stop")
+		case errors.Is(err, models.ErrInvalidFocusDuration):
+			h.sendMessage(ctx, userID, fmt.Sprintf("❌ Длительность должна быть от 1 до %d минут.", models.MaxFocusMinutes))
+		default:
+			h.logger.Error("failed to start focus session", "error", err)
+			h.sendMessage(ctx, userID, "❌ Не удалось начать фокус-сессию.")
+		}
+		return
+	}
+
+	response := fmt.Sprintf("🎯 Начинается фокус-сессия!\n\n⏱ Длительность: %d минут\n", session.DurationMinutes)
+	if contextTitle != "" {
+		response += fmt.Sprintf("📂 Контекст: %s\n", contextTitle)
+	}
+	response += fmt.Sprintf("🏁 Окончание в %s\n\nСконцентрируйся на задаче, я напишу, когда время выйдет. Удачи!",
+		session.PlannedEndAt().In(h.userLocation(ctx, userID)).Format("15:04"))
+
+	h.sendMessageWithKeyboard(ctx, userID, response, h.buildFocusKeyboard(&session))
+}
+
+func (h *UniFlowUpdateHandler) stopFocusSession(ctx context.Context, userID int64, userIDStr, sessionID string) {
+	session, err := h.usecase.StopFocusSession(ctx, userIDStr, sessionID)
+	if err != nil {
+		if errors.Is(err, models.ErrFocusSessionEnded) {
+			h.sendMessage(ctx, userID, "ℹ️ Эта фокус-сессия уже завершена.")
+			return
+		}
+		h.logger.Error("failed to stop focus session", "error", err, "session_id", sessionID)
+		h.sendMessage(ctx, userID, "❌ Не удалось остановить фокус-сессию.")
+		return
+	}
+
+	focused := int(session.FocusedDuration(time.Now()).Minutes())
+	response := fmt.Sprintf("⏹ Фокус-сессия остановлена\n\n⏱ Время работы: %d из %d минут", focused, session.DurationMinutes)
 	h.sendMessageWithKeyboard(ctx, userID, response, h.buildMainMenuKeyboard())
 }
 
+// findContextByName ищет контекст по названию без учета регистра:
+// сначала точное совпадение, затем по началу названия
+func findContextByName(contexts []models.Context, name string) (models.Context, bool) {
+	name = strings.ToLower(strings.TrimSpace(name))
+	if name == "" {
+		return models.Context{}, false
+	}
+
+	for _, c := range contexts {
+		if strings.ToLower(c.Title) == name {
+			return c, true
+		}
+	}
+
+	for _, c := range contexts {
+		if strings.HasPrefix(strings.ToLower(c.Title), name) {
+			return c, true
+		}
+	}
+
+	return models.Context{}, false
+}
+
+func (h *UniFlowUpdateHandler) handleStatsCommand(ctx context.Context, userID int64, parts []string) {
+	days := usecase.DefaultStatsDays
+	if len(parts) > 1 {
+		n, err := strconv.Atoi(parts[1])
+		if err != nil || n < 1 || n > usecase.MaxStatsDays {
+			h.sendMessage(ctx, userID, fmt.Sprintf("❌ Период должен быть числом дней от 1 до %d.\n\nНапример: /stats 30", usecase.MaxStatsDays))
+			return
+		}
+		days = n
+	}
+
+	h.showStats(ctx, userID, days)
+}
+
+func (h *UniFlowUpdateHandler) showStats(ctx context.Context, userID int64, days int) {
+	// Получаем или создаем пользователя по MAX ID
+	maxUserID := fmt.Sprintf("%d", userID)
+	user, err := h.usecase.GetOrCreateUserByMaxID(ctx, maxUserID)
+	if err != nil {
+		h.logger.Error("failed to get user", "error", err, "max_user_id", maxUserID)
+		h.sendMessage(ctx, userID, "❌ Ошибка при получении данных пользователя.")
+		return
+	}
+
+	stats, err := h.usecase.GetStats(ctx, user.ID.String(), days)
+	if err != nil {
+		h.logger.Error("failed to get stats", "error", err, "user_id", user.ID)
+		h.sendMessage(ctx, userID, "❌ Ошибка при подсчете статистики.")
+		return
+	}
+
+	h.sendMessageWithKeyboard(ctx, userID, formatStats(stats, days), h.buildStatsKeyboard(days))
+}
+
+// formatStats форматирует сводку статистики для сообщения бота
+func formatStats(stats models.Stats, days int) string {
+	response := fmt.Sprintf("📊 Статистика за %d дн. (%s — %s)\n\n",
+		days, stats.From.Format("02.01"), stats.To.Format("02.01"))
+
+	response += fmt.Sprintf("✅ Выполнено: %d\n", stats.CompletedTotal)
+	response += fmt.Sprintf("➕ Создано: %d\n", stats.CreatedTotal)
+	response += fmt.Sprintf("📋 Активных: %d\n", stats.ActiveTotal)
+	if stats.OverdueTotal > 0 {
+		response += fmt.Sprintf("⚠️ Просрочено: %d\n", stats.OverdueTotal)
+	}
+	if stats.CompletedTotal > 0 {
+		response += fmt.Sprintf("⏳ Среднее время выполнения: %s\n", formatLeadTime(stats.AvgLeadTimeHours))
+	}
+	response += fmt.Sprintf("🎯 Фокус: %d мин (%d сессий)\n", stats.FocusMinutes, stats.FocusSessions)
+
+	// По дням показываем только короткие периоды, для длинных - по неделям
+	if days <= 7 {
+		response += "\n📅 По дням:\n"
+		for _, d := range stats.CompletedByDay {
+			date, err := time.Parse("2006-01-02", d.Date)
+			if err != nil {
+				continue
+			}
+			response += fmt.Sprintf("%s %s — %d\n", models.WeekdayOf(date).ShortName(), date.Format("02.01"), d.Count)
+		}
+	} else {
+		response += "\n📅 По неделям:\n"
+		for _, w := range stats.CompletedByWeek {
+			weekStart, err := time.Parse("2006-01-02", w.WeekStart)
+			if err != nil {
+				continue
+			}
+			response += fmt.Sprintf("с %s — %d\n", weekStart.Format("02.01"), w.Count)
+		}
+	}
+
+	if len(stats.ByContext) > 0 {
+		response += "\n📁 По контекстам:\n"
+		for i, c := range stats.ByContext {
+			if i >= 5 {
+				response += fmt.Sprintf("...и ещё %d\n", len(stats.ByContext)-5)
+				break
+			}
+			response += fmt.Sprintf("• %s: ✅ %d, 📋 %d", truncate(c.Title, 30), c.Completed, c.Active)
+			if c.Overdue > 0 {
+				response += fmt.Sprintf(", ⚠️ %d", c.Overdue)
+			}
+			if c.FocusMinutes > 0 {
+				response += fmt.Sprintf(", 🎯 %d мин", c.FocusMinutes)
+			}
+			response += "\n"
+		}
+	}
+
+	return response
+}
+
+// formatLeadTime форматирует длительность в часах: до суток - в часах, дальше - в днях
+func formatLeadTime(hours float64) string {
+	if hours < 24 {
+		return fmt.Sprintf("%.1f ч", hours)
+	}
+	return fmt.Sprintf("%.1f дн", hours/24)
+}
+
 func (h *UniFlowUpdateHandler) handleHelpCommand(ctx context.Context, userID int64) {
 	response := "📖 Справка по командам:\n\n" +
 		"🏠 Основное:\n" +
@@ -393,14 +741,25 @@ func (h *UniFlowUpdateHandler) handleHelpCommand(ctx context.Context, userID int
 		"/menu — главное меню\n" +
 		"/help — эта справка\n\n" +
 		"✅ Задачи:\n" +
-		"/today — задачи на сегодня\n" +
-		"/tasks — все задачи\n" +
+		"/today — задачи и занятия на сегодня\n" +
+		"/tasks — все задачи (сначала важные)\n" +
+		"/tasks важные — только задачи с высоким и срочным приоритетом\n" +
+		"/tasks #тег — задачи с тегом\n" +
 		"/newtask — создать задачу\n" +
-		"/search <запрос> — поиск задач\n\n" +
+		"Или просто напиши задачу: «сдать лабу по физике завтра в 18:00 #физика !высокий»\n" +
+		"/search <запрос> — поиск задач и заметок\n\n" +
+		"🎓 Расписание:\n" +
+		"/timetable — расписание занятий на неделю\n\n" +
 		"📁 Контексты:\n" +
 		"/contexts — все контексты\n" +
 		"/newcontext — создать контекст\n\n" +
+		"🎯 Фокус:\n" +
+		"/focus [минуты] [контекст] — начать фокус-сессию\n" +
+		"/focus stop — остановить фокус-сессию\n\n" +
+		"📊 Статистика:\n" +
+		"/stats [дни] — статистика за период (по умолчанию 7 дней)\n\n" +
 		"⚙️ Другое:\n" +
+		"/settings — часовой пояс, язык и напоминания\n" +
 		"/cancel — отменить текущее действие"
 
 	h.sendMessageWithKeyboard(ctx, userID, response, h.buildMainMenuKeyboard())
@@ -410,18 +769,26 @@ func (h *UniFlowUpdateHandler) handleHelpCommand(ctx context.Context, userID int
 
 func (h *UniFlowUpdateHandler) handleStateMessage(ctx context.Context, userID int64, text string, state *UserState) {
 	switch state.State {
-	case "creating_task":
+	case stateCreatingTask:
 		h.handleCreatingTaskState(ctx, userID, text, state)
-	case "creating_context":
+	case stateCreatingContext:
 		h.handleCreatingContextState(ctx, userID, text, state)
-	case "editing_task":
+	case stateEditingTask:
 		h.handleEditingTaskState(ctx, userID, text, state)
-	case "searching":
+	case stateEditingContext:
+		h.handleEditingContextState(ctx, userID, text, state)
+	case stateEditingSettings:
+		h.handleEditingSettingsState(ctx, userID, text, state)
+	case stateAddingSubtasks:
+		h.handleAddingSubtasksState(ctx, userID, text, state)
+	case stateQuickAdd:
+		h.handleQuickAddState(ctx, userID, text, state)
+	case stateSearching:
 		// Выполняем поиск по введенному запросу
-		delete(h.userStates, userID)
+		h.clearState(ctx, userID)
 		h.handleSearchCommand(ctx, userID, []string{"search", text})
 	default:
-		delete(h.userStates, userID)
+		h.clearState(ctx, userID)
 		h.showMainMenu(ctx, userID)
 	}
 }
@@ -433,36 +800,45 @@ func (h *UniFlowUpdateHandler) handleCreatingTaskState(ctx context.Context, user
 	if err != nil {
 		h.logger.Error("failed to get user", "error", err, "max_user_id", maxUserID)
 		h.sendMessage(ctx, userID, "❌ Ошибка при получении данных пользователя.")
-		delete(h.userStates, userID)
+		h.clearState(ctx, userID)
 		return
 	}
 
-	step := state.Data["step"]
-	if step == nil {
+	step := state.Data.Step
+	if step == 0 {
 		step = 1
 	}
 
-	switch step.(int) {
+	switch step {
 	case 1:
-		// Сохраняем название
-		state.Data["title"] = text
-		state.Data["step"] = 2
-		state.LastUpdate = time.Now()
+		// Сохраняем название, вынося из него #теги
+		title, tags := parseHashtags(text)
+		if title == "" {
+			title = text
+			tags = nil
+		}
+		state.Data.Title = title
+		state.Data.Tags = tags
+		state.Data.Step = 2
+		h.saveState(ctx, userID, state)
 
 		response := "📝 Создание новой задачи\n\n" +
-			fmt.Sprintf("Название: %s ✓\n\n", text) +
-			"Шаг 2/3: Введи описание задачи\n\n" +
+			fmt.Sprintf("Название: %s ✓\n\n", title) +
+			"Шаг 2/5: Введи описание задачи\n\n" +
 			"Или напиши '-' чтобы пропустить"
 
 		h.sendMessage(ctx, userID, response)
 
 	case 2:
-		// Сохраняем описание
+		// Сохраняем описание; #теги из него добавляются к тегам названия
 		if text != "-" {
-			state.Data["description"] = text
+			description, tags := parseHashtags(text)
+			if description != "" {
+				state.Data.Description = description
+			}
+			state.Data.Tags = append(state.Data.Tags, tags...)
 		}
-		state.Data["step"] = 3
-		state.LastUpdate = time.Now()
+		state.Data.Step = 3
 
 		// Получаем контексты для выбора
 		contexts, err := h.usecase.GetContextsByUserID(ctx, user.ID.String())
@@ -471,9 +847,9 @@ func (h *UniFlowUpdateHandler) handleCreatingTaskState(ctx context.Context, user
 		}
 
 		response := "📝 Создание новой задачи\n\n" +
-			fmt.Sprintf("Название: %s ✓\n", state.Data["title"]) +
+			fmt.Sprintf("Название: %s ✓\n", state.Data.Title) +
 			"Описание: ✓\n\n" +
-			"Шаг 3/4: Выбери контекст или введи '-' чтобы пропустить\n\n"
+			"Шаг 3/5: Выбери контекст или введи '-' чтобы пропустить\n\n"
 
 		if len(contexts) > 0 {
 			response += "Доступные контексты:\n"
@@ -483,7 +859,12 @@ func (h *UniFlowUpdateHandler) handleCreatingTaskState(ctx context.Context, user
 			response += "\nВведи номер контекста или '-'"
 		}
 
-		state.Data["contexts"] = contexts
+		// Запоминаем только ID: номер, введенный пользователем, указывает на позицию в списке
+		state.Data.ContextIDs = make([]string, 0, len(contexts))
+		for _, c := range contexts {
+			state.Data.ContextIDs = append(state.Data.ContextIDs, c.ID.String())
+		}
+		h.saveState(ctx, userID, state)
 		h.sendMessage(ctx, userID, response)
 
 	case 3:
@@ -493,51 +874,49 @@ func (h *UniFlowUpdateHandler) handleCreatingTaskState(ctx context.Context, user
 			// Пытаемся распарсить номер контекста
 			var contextIdx int
 			if _, err := fmt.Sscanf(text, "%d", &contextIdx); err == nil {
-				contexts := state.Data["contexts"].([]models.Context)
-				if contextIdx > 0 && contextIdx <= len(contexts) {
-					ctxID := contexts[contextIdx-1].ID.String()
+				if contextIdx > 0 && contextIdx <= len(state.Data.ContextIDs) {
+					ctxID := state.Data.ContextIDs[contextIdx-1]
 					contextID = &ctxID
 				}
 			}
 		}
 
-		state.Data["context_id"] = contextID
-		state.Data["step"] = 4
-		state.LastUpdate = time.Now()
+		state.Data.ContextID = contextID
+		state.Data.ContextIDs = nil
+		state.Data.Step = 4
+		h.saveState(ctx, userID, state)
 
 		response := "📝 Создание новой задачи\n\n" +
-			fmt.Sprintf("Название: %s ✓\n", state.Data["title"]) +
+			fmt.Sprintf("Название: %s ✓\n", state.Data.Title) +
 			"Описание: ✓\n" +
 			"Контекст: ✓\n\n" +
-			"Шаг 4/4: Выбери дедлайн"
+			"Шаг 4/5: Выбери дедлайн"
 
 		h.sendMessageWithKeyboard(ctx, userID, response, h.buildDateSelectionKeyboard())
 
-	case 4:
-		// Создаем задачу с выбранной датой
-		title := state.Data["title"].(string)
-		description := ""
-		if desc, ok := state.Data["description"]; ok {
-			description = desc.(string)
+	case 5:
+		// Повторение введено текстом: число дней или дни недели
+		recurrence, ok := parseRecurrenceInput(text)
+		if !ok {
+			h.sendMessage(ctx, userID, "❌ Не понял правило повторения.\n\n"+
+				"Введи число дней (например, 3) или дни недели через пробел (например, пн ср пт), либо выбери вариант на клавиатуре.")
+			return
 		}
 
-		var contextID *string
-		if ctxID, ok := state.Data["context_id"].(*string); ok {
-			contextID = ctxID
-		}
+		state.Data.Recurrence = recurrence
+		state.Data.Step = 6
+		h.handleCreatingTaskState(ctx, userID, "", state)
 
-		var dueAt *string
-		if dueDateStr, ok := state.Data["due_at"]; ok && dueDateStr != nil {
-			dateStr := dueDateStr.(string)
-			dueAt = &dateStr
-		}
+	case 4, 6:
+		// Создаем задачу с выбранной датой и повторением
+		data := state.Data
 
 		// Создаем задачу
-		createdTask, err := h.usecase.CreateTask(ctx, user.ID.String(), contextID, title, description, dueAt)
+		createdTask, err := h.usecase.CreateTask(ctx, user.ID.String(), data.ContextID, data.Title, data.Description, data.DueAt, data.Recurrence, models.TaskPriorityNormal, data.Tags)
 		if err != nil {
 			h.logger.Error("failed to create task", "error", err)
 			h.sendMessage(ctx, userID, "❌ Ошибка при создании задачи: "+err.Error())
-			delete(h.userStates, userID)
+			h.clearState(ctx, userID)
 			return
 		}
 
@@ -547,78 +926,318 @@ func (h *UniFlowUpdateHandler) handleCreatingTaskState(ctx context.Context, user
 			response += fmt.Sprintf("📄 %s\n", createdTask.Description)
 		}
 		if createdTask.DueAt != nil {
-			response += fmt.Sprintf("⏰ До %s\n", createdTask.DueAt.Format("02.01.2006"))
+			response += fmt.Sprintf("⏰ До %s\n", formatTaskDue(createdTask, user.Settings.Location()))
+		}
+		if createdTask.Recurrence != nil {
+			response += fmt.Sprintf("🔁 %s\n", formatRecurrence(createdTask.Recurrence))
+		}
+		if len(createdTask.Tags) > 0 {
+			response += fmt.Sprintf("🏷 %s\n", formatTags(createdTask.Tags))
 		}
 
-		delete(h.userStates, userID)
+		h.clearState(ctx, userID)
 		h.sendMessageWithKeyboard(ctx, userID, response, h.buildMainMenuKeyboard())
 	}
 }
 
-func (h *UniFlowUpdateHandler) handleCreatingContextState(ctx context.Context, userID int64, text string, state *UserState) {
-	// Получаем пользователя
-	maxUserID := fmt.Sprintf("%d", userID)
-	user, err := h.usecase.GetOrCreateUserByMaxID(ctx, maxUserID)
-	if err != nil {
-		h.logger.Error("failed to get user", "error", err, "max_user_id", maxUserID)
-		h.sendMessage(ctx, userID, "❌ Ошибка при получении данных пользователя.")
-		delete(h.userStates, userID)
-		return
+// askTaskRecurrence предлагает выбрать правило повторения для задачи с дедлайном
+func (h *UniFlowUpdateHandler) askTaskRecurrence(ctx context.Context, userID int64, state *UserState) {
+	state.Data.Step = 5
+	h.saveState(ctx, userID, state)
+
+	response := "📝 Создание новой задачи\n\n" +
+		fmt.Sprintf("Название: %s ✓\n", state.Data.Title) +
+		"Описание: ✓\n" +
+		"Контекст: ✓\n" +
+		"Дедлайн: ✓\n\n" +
+		"Шаг 5/5: Повторять задачу?\n\n" +
+		"Выбери вариант или введи число дней (например, 3) или дни недели (например, пн ср пт)"
+
+	h.sendMessageWithKeyboard(ctx, userID, response, h.buildRecurrenceKeyboard())
+}
+
+// parseRecurrenceInput разбирает правило повторения из текста:
+// "-" - без повторения, число N - каждые N дней, "пн ср пт" - еженедельно по этим дням
+func parseRecurrenceInput(text string) (*models.Recurrence, bool) {
+	text = strings.ToLower(strings.TrimSpace(text))
+	if text == "-" {
+		return nil, true
+	}
+
+	if n, err := strconv.Atoi(text); err == nil {
+		if n < 1 || n > models.MaxRecurrenceInterval {
+			return nil, false
+		}
+		return &models.Recurrence{Frequency: models.RecurrenceDaily, Interval: n}, true
+	}
+
+	fields := strings.FieldsFunc(text, func(r rune) bool {
+		return r == ' ' || r == ',' || r == ';'
+	})
+	if len(fields) == 0 {
+		return nil, false
+	}
+
+	weekdays := make([]models.Weekday, 0, len(fields))
+	for _, field := range fields {
+		weekday, ok := parseWeekdayShortName(field)
+		if !ok {
+			return nil, false
+		}
+		weekdays = append(weekdays, weekday)
+	}
+
+	return &models.Recurrence{Frequency: models.RecurrenceWeekly, Interval: 1, Weekdays: weekdays}, true
+}
+
+func parseWeekdayShortName(s string) (models.Weekday, bool) {
+	for w := models.Monday; w <= models.Sunday; w++ {
+		if strings.ToLower(w.ShortName()) == s {
+			return w, true
+		}
+	}
+	return 0, false
+}
+
+// formatRecurrence описывает правило повторения для пользователя
+func formatRecurrence(r *models.Recurrence) string {
+	var text string
+	switch r.Frequency {
+	case models.RecurrenceDaily:
+		if r.Interval > 1 {
+			text = fmt.Sprintf("Каждые %d дн.", r.Interval)
+		} else {
+			text = "Каждый день"
+		}
+	case models.RecurrenceWeekly:
+		if r.Interval > 1 {
+			text = fmt.Sprintf("Раз в %d нед.", r.Interval)
+		} else {
+			text = "Каждую неделю"
+		}
+		if len(r.Weekdays) > 0 {
+			names := make([]string, 0, len(r.Weekdays))
+			for _, w := range r.Weekdays {
+				names = append(names, w.ShortName())
+			}
+			text += ": " + strings.Join(names, ", ")
+		}
+	case models.RecurrenceMonthly:
+		if r.Interval > 1 {
+			text = fmt.Sprintf("Раз в %d мес.", r.Interval)
+		} else {
+			text = "Каждый месяц"
+		}
+	}
+
+	if r.Until != nil {
+		text += fmt.Sprintf(" до %s", r.Until.Format("02.01.2006"))
 	}
 
-	step := state.Data["step"]
-	if step == nil {
+	return text
+}
+
+func (h *UniFlowUpdateHandler) handleCreatingContextState(ctx context.Context, userID int64, text string, state *UserState) {
+	step := state.Data.Step
+	if step == 0 {
 		step = 1
 	}
 
-	switch step.(int) {
+	switch step {
 	case 1:
 		// Сохраняем название
-		state.Data["name"] = text
-		state.Data["step"] = 2
-		state.LastUpdate = time.Now()
+		state.Data.Title = text
+		state.Data.Step = 2
+		h.saveState(ctx, userID, state)
 
 		response := "📁 Создание нового контекста\n\n" +
 			fmt.Sprintf("Название: %s ✓\n\n", text) +
-			"Шаг 2/2: Введи описание контекста\n\n" +
+			"Шаг 2/3: Введи описание контекста\n\n" +
 			"Или напиши '-' чтобы пропустить"
 
 		h.sendMessage(ctx, userID, response)
 
 	case 2:
-		// Создаем контекст
-		title := state.Data["name"].(string)
-		description := ""
+		// Сохраняем описание и предлагаем выбрать тип
+		state.Data.Description = ""
 		if text != "-" {
-			description = text
+			state.Data.Description = text
 		}
+		state.Data.Step = 3
+		h.saveState(ctx, userID, state)
 
-		createdContext, err := h.usecase.CreateContext(ctx, user.ID.String(), models.ContextTypeOther, title, description, "#3B82F6", nil, nil)
-		if err != nil {
-			h.logger.Error("failed to create context", "error", err)
-			h.sendMessage(ctx, userID, "❌ Ошибка при создании контекста: "+err.Error())
-			delete(h.userStates, userID)
+		h.askNewContextType(ctx, userID, state)
+
+	case 3:
+		// Тип выбирается кнопкой, текст не подходит
+		h.askNewContextType(ctx, userID, state)
+	}
+}
+
+// askNewContextType запрашивает тип создаваемого контекста
+func (h *UniFlowUpdateHandler) askNewContextType(ctx context.Context, userID int64, state *UserState) {
+	response := "📁 Создание нового контекста\n\n" +
+		fmt.Sprintf("Название: %s ✓\n\n", state.Data.Title) +
+		"Шаг 3/3: Выбери тип контекста"
+
+	h.sendMessageWithKeyboard(ctx, userID, response, h.buildContextTypeKeyboard("ctxtype_", ""))
+}
+
+// handleAddingSubtasksState добавляет пункты чек-листа: каждая строка сообщения - отдельный пункт
+func (h *UniFlowUpdateHandler) handleAddingSubtasksState(ctx context.Context, userID int64, text string, state *UserState) {
+	h.clearState(ctx, userID)
+
+	maxUserID := fmt.Sprintf("%d", userID)
+	user, err := h.usecase.GetOrCreateUserByMaxID(ctx, maxUserID)
+	if err != nil {
+		h.logger.Error("failed to get user", "error", err, "max_user_id", maxUserID)
+		h.sendMessage(ctx, userID, "❌ Ошибка при получении данных пользователя.")
+		return
+	}
+
+	taskID := state.Data.TaskID
+
+	added := 0
+	for _, line := range strings.Split(text, "\n") {
+		title := strings.TrimSpace(strings.TrimLeft(line, "-•*"))
+		if title == "" {
+			continue
+		}
+		if _, err := h.usecase.CreateSubtask(ctx, user.ID.String(), taskID, title); err != nil {
+			if errors.Is(err, models.ErrTooManySubtasks) {
+				h.sendMessage(ctx, userID, fmt.Sprintf("⚠️ В чек-листе может быть не больше %d пунктов.", models.MaxSubtasksPerTask))
+				break
+			}
+			h.logger.Error("failed to create subtask", "error", err, "task_id", taskID)
+			h.sendMessage(ctx, userID, "❌ Не удалось добавить пункт чек-листа.")
 			return
 		}
+		added++
+	}
+
+	if added == 0 {
+		h.sendMessage(ctx, userID, "ℹ️ Пункты не добавлены.")
+	}
 
-		response := "✅ Контекст создан!\n\n" +
-			fmt.Sprintf("📁 %s\n", createdContext.Title)
-		if createdContext.Description != "" {
-			response += fmt.Sprintf("📄 %s\n", createdContext.Description)
+	task, err := h.usecase.GetTaskByID(ctx, user.ID.String(), taskID)
+	if err != nil {
+		h.logger.Error("failed to get task", "error", err)
+		h.showMainMenu(ctx, userID)
+		return
+	}
+
+	h.showTaskDetails(ctx, userID, task)
+}
+
+// ============== Вспомогательные функции ==============
+
+// priorityIcon возвращает значок приоритета для списков; у обычного приоритета значка нет
+func priorityIcon(p models.TaskPriority) string {
+	switch p {
+	case models.TaskPriorityUrgent:
+		return "‼️ "
+	case models.TaskPriorityHigh:
+		return "❗ "
+	case models.TaskPriorityLow:
+		return "🔽 "
+	default:
+		return ""
+	}
+}
+
+// isImportantFilter распознает аргумент /tasks, включающий показ только важных задач
+// hashtagPattern - #тег в тексте сообщения: буквы, цифры, '_' и '-'
+var hashtagPattern = regexp.MustCompile(`#[\p{L}\p{N}_-]+`)
+
+// parseHashtags выносит #теги из текста: "Лаба #матан #срочно" -> "Лаба", [матан срочно].
+// Теги нормализуются, повторы и слишком длинные теги отбрасываются.
+func parseHashtags(text string) (string, []string) {
+	var tags []string
+	seen := make(map[string]bool)
+	for _, match := range hashtagPattern.FindAllString(text, -1) {
+		name, err := models.NormalizeTagName(match)
+		if err != nil || seen[name] {
+			continue
 		}
+		seen[name] = true
+		tags = append(tags, name)
+	}
 
-		delete(h.userStates, userID)
-		h.sendMessageWithKeyboard(ctx, userID, response, h.buildMainMenuKeyboard())
+	clean := strings.Join(strings.Fields(hashtagPattern.ReplaceAllString(text, "")), " ")
+	return clean, tags
+}
+
+// formatTags форматирует теги для вывода: "#матан #срочно"
+func formatTags(tags []string) string {
+	parts := make([]string, 0, len(tags))
+	for _, tag := range tags {
+		parts = append(parts, "#"+tag)
 	}
+	return strings.Join(parts, " ")
 }
 
-func (h *UniFlowUpdateHandler) handleEditingTaskState(ctx context.Context, userID int64, text string, state *UserState) {
-	// TODO: Реализовать редактирование задачи
-	delete(h.userStates, userID)
-	h.showMainMenu(ctx, userID)
+func isImportantFilter(arg string) bool {
+	switch strings.ToLower(arg) {
+	case "важные", "важное", "important", "!":
+		return true
+	default:
+		return false
+	}
 }
 
-// ============== Вспомогательные функции ==============
+// formatDayTime возвращает время задачи в списке дня: "18:00 " или пустую строку для задачи на весь день
+func formatDayTime(task models.Task, loc *time.Location) string {
+	if task.DueAt == nil || task.AllDay {
+		return ""
+	}
+	return task.DueAt.In(loc).Format("15:04") + " "
+}
+
+// formatTaskDue форматирует срок задачи в поясе loc; у задачи на весь день время не показывается
+func formatTaskDue(task models.Task, loc *time.Location) string {
+	due := task.DueAt.In(loc)
+	if task.AllDay {
+		return due.Format("02.01.2006") + ", весь день"
+	}
+	return due.Format("02.01.2006 15:04")
+}
+
+// priorityName возвращает название приоритета
+func priorityName(p models.TaskPriority) string {
+	switch p {
+	case models.TaskPriorityUrgent:
+		return "Срочный"
+	case models.TaskPriorityHigh:
+		return "Высокий"
+	case models.TaskPriorityLow:
+		return "Низкий"
+	default:
+		return "Обычный"
+	}
+}
+
+// nextPriority возвращает следующий приоритет по кругу: низкий → обычный → высокий → срочный → низкий
+func nextPriority(p models.TaskPriority) models.TaskPriority {
+	switch p {
+	case models.TaskPriorityLow:
+		return models.TaskPriorityNormal
+	case models.TaskPriorityHigh:
+		return models.TaskPriorityUrgent
+	case models.TaskPriorityUrgent:
+		return models.TaskPriorityLow
+	default:
+		return models.TaskPriorityHigh
+	}
+}
+
+// progressBar рисует полосу прогресса из 10 делений
+func progressBar(done, total int) string {
+	if total == 0 {
+		return ""
+	}
+	filled := done * 10 / total
+	return strings.Repeat("▰", filled) + strings.Repeat("▱", 10-filled)
+}
 
 func min(a, b int) int {
 	if a < b {
@@ -654,3 +1273,17 @@ func (h *UniFlowUpdateHandler) answerCallback(ctx context.Context, callbackID st
 		h.logger.Error("failed to answer callback", "error", err)
 	}
 }
+
+// updateCallbackMessage отвечает на callback, заменяя текст и клавиатуру сообщения с нажатой кнопкой
+func (h *UniFlowUpdateHandler) updateCallbackMessage(ctx context.Context, callbackID, text string, keyboard *maxbot.Keyboard) {
+	api := h.client.GetAPI()
+	answer := &schemes.CallbackAnswer{
+		Message: &schemes.NewMessageBody{
+			Text:        text,
+			Attachments: []interface{}{schemes.NewInlineKeyboardAttachmentRequest(keyboard.Build())},
+		},
+	}
+	if _, err := api.Messages.AnswerOnCallback(ctx, callbackID, answer); err != nil {
+		h.logger.Error("failed to update callback message", "error", err)
+	}
+}
@@ -0,0 +1,25 @@
+package max
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestParseHashtags(t *testing.T) {
+	tests := []struct {
+		text      string
+		wantClean string
+		wantTags  []string
+	}{
+		{"Сдать лабу #Матан #срочно", "Сдать лабу", []string{"матан", "срочно"}},
+		{"#диплом глава 2 #диплом", "глава 2", []string{"диплом"}},
+		{"Купить хлеб", "Купить хлеб", nil},
+	}
+
+	for _, tt := range tests {
+		clean, tags := parseHashtags(tt.text)
+		if clean != tt.wantClean || !slices.Equal(tags, tt.wantTags) {
+			t.Errorf("parseHashtags(%q) = %q, %v; want %q, %v", tt.text, clean, tags, tt.wantClean, tt.wantTags)
+		}
+	}
+}
@@ -0,0 +1,330 @@
+package max
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/singl3focus/uniflow/internal/core/models"
+)
+
+// Редактируемые поля контекста в callback'ах ctxedit_<поле>_<context_id>[_<значение>]
+const (
+	ctxEditFieldTitle    = "title"
+	ctxEditFieldDesc     = "desc"
+	ctxEditFieldType     = "type"
+	ctxEditFieldColor    = "color"
+	ctxEditFieldDeadline = "deadline"
+)
+
+// contextTypeOption - тип контекста, предлагаемый пользователю, и цвет по умолчанию для него
+type contextTypeOption struct {
+	Type  models.ContextType
+	Name  string
+	Color string
+}
+
+// contextTypeOptions перечислены в порядке кнопок
+var contextTypeOptions = []contextTypeOption{
+	{models.ContextTypeSubject, "🎓 Предмет", "#3B82F6"},
+	{models.ContextTypeProject, "🚀 Проект", "#8B5CF6"},
+	{models.ContextTypeWork, "💼 Работа", "#F59E0B"},
+	{models.ContextTypePersonal, "🏠 Личное", "#10B981"},
+	{models.ContextTypeOther, "📁 Другое", "#6B7280"},
+}
+
+// contextColorOption - цвет контекста из палитры бота; в callback передается HEX без "#"
+type contextColorOption struct {
+	Name string
+	Hex  string
+}
+
+var contextColorOptions = []contextColorOption{
+	{"🔵 Синий", "3B82F6"},
+	{"🟢 Зеленый", "10B981"},
+	{"🟡 Желтый", "F59E0B"},
+	{"🔴 Красный", "EF4444"},
+	{"🟣 Фиолетовый", "8B5CF6"},
+	{"⚪ Серый", "6B7280"},
+}
+
+// findContextTypeOption возвращает описание типа контекста; неизвестный тип считается "Другое"
+func findContextTypeOption(t models.ContextType) (contextTypeOption, bool) {
+	for _, opt := range contextTypeOptions {
+		if opt.Type == t {
+			return opt, true
+		}
+	}
+	return contextTypeOptions[len(contextTypeOptions)-1], false
+}
+
+// contextColorName возвращает название цвета из палитры или сам HEX, если цвета в палитре нет
+func contextColorName(hex string) string {
+	for _, opt := range contextColorOptions {
+		if strings.EqualFold("#"+opt.Hex, hex) {
+			return opt.Name
+		}
+	}
+	return hex
+}
+
+// handleEditContext показывает выбор поля для редактирования контекста
+func (h *UniFlowUpdateHandler) handleEditContext(ctx context.Context, userID int64, callbackID, contextID, userIDStr string) {
+	c, err := h.usecase.GetContextByID(ctx, userIDStr, contextID)
+	if err != nil {
+		h.answerCallback(ctx, callbackID, "❌ Контекст не найден")
+		return
+	}
+
+	h.answerCallback(ctx, callbackID, "")
+
+	// Незаконченный ввод другого поля отменяется
+	h.clearState(ctx, userID)
+
+	h.sendMessageWithKeyboard(ctx, userID,
+		fmt.Sprintf("✏️ Редактирование контекста «%s»\n\nЧто изменить?", c.Title),
+		h.buildContextEditKeyboard(contextID))
+}
+
+// handleContextEditCallback обрабатывает выбор поля и значения в диалоге редактирования контекста
+func (h *UniFlowUpdateHandler) handleContextEditCallback(ctx context.Context, userID int64, callbackID string, parts []string) {
+	if len(parts) < 3 {
+		return
+	}
+
+	field := parts[1]     // title, desc, type, color, deadline
+	contextID := parts[2] // ID контекста
+	value := ""           // Выбранное значение, если оно пришло кнопкой
+	if len(parts) > 3 {
+		value = parts[3]
+	}
+
+	maxUserID := fmt.Sprintf("%d", userID)
+	user, err := h.usecase.GetOrCreateUserByMaxID(ctx, maxUserID)
+	if err != nil {
+		h.logger.Error("failed to get user", "error", err)
+		h.answerCallback(ctx, callbackID, "❌ Ошибка при получении пользователя")
+		return
+	}
+	userIDStr := user.ID.String()
+
+	switch field {
+	case ctxEditFieldTitle, ctxEditFieldDesc:
+		h.askContextEditText(ctx, userID, callbackID, contextID, userIDStr, field)
+	case ctxEditFieldType:
+		if value == "" {
+			h.answerCallback(ctx, callbackID, "")
+			h.sendMessageWithKeyboard(ctx, userID, "🏷 Выбери тип контекста:",
+				h.buildContextTypeKeyboard("ctxedit_type_"+contextID+"_", "ctxedit_menu_"+contextID))
+			return
+		}
+		h.applyContextEdit(ctx, userID, callbackID, contextID, userIDStr, contextEdit{contextType: value})
+	case ctxEditFieldColor:
+		if value == "" {
+			h.answerCallback(ctx, callbackID, "")
+			h.sendMessageWithKeyboard(ctx, userID, "🎨 Выбери цвет контекста:", h.buildContextColorKeyboard(contextID))
+			return
+		}
+		color := "#" + value
+		h.applyContextEdit(ctx, userID, callbackID, contextID, userIDStr, contextEdit{color: &color})
+	case ctxEditFieldDeadline:
+		switch value {
+		case "":
+			h.showContextDeadline(ctx, userID, callbackID, contextID, userIDStr)
+		case "input":
+			h.askContextEditText(ctx, userID, callbackID, contextID, userIDStr, ctxEditFieldDeadline)
+		case "none":
+			deadlineAt := ""
+			h.applyContextEdit(ctx, userID, callbackID, contextID, userIDStr, contextEdit{deadlineAt: &deadlineAt})
+		default:
+			days, err := strconv.Atoi(value)
+			if err != nil {
+				h.answerCallback(ctx, callbackID, "❌ Неверный формат даты")
+				return
+			}
+			deadline := time.Now().In(h.userLocation(ctx, userID)).AddDate(0, 0, days)
+			deadlineAt := time.Date(deadline.Year(), deadline.Month(), deadline.Day(), 23, 59, 0, 0, deadline.Location()).Format(time.RFC3339)
+			h.applyContextEdit(ctx, userID, callbackID, contextID, userIDStr, contextEdit{deadlineAt: &deadlineAt})
+		}
+	case "menu":
+		h.handleEditContext(ctx, userID, callbackID, contextID, userIDStr)
+	}
+}
+
+// askContextEditText просит ввести новое значение текстового поля контекста
+func (h *UniFlowUpdateHandler) askContextEditText(ctx context.Context, userID int64, callbackID, contextID, userIDStr, field string) {
+	c, err := h.usecase.GetContextByID(ctx, userIDStr, contextID)
+	if err != nil {
+		h.answerCallback(ctx, callbackID, "❌ Контекст не найден")
+		return
+	}
+
+	h.answerCallback(ctx, callbackID, "")
+
+	h.saveState(ctx, userID, &UserState{
+		State: stateEditingContext,
+		Data:  DialogData{ContextID: &contextID, Field: field},
+	})
+
+	var prompt string
+	switch field {
+	case ctxEditFieldTitle:
+		prompt = fmt.Sprintf("✏️ Текущее название: %s\n\nВведи новое название:", c.Title)
+	case ctxEditFieldDesc:
+		current := c.Description
+		if current == "" {
+			current = "нет"
+		}
+		prompt = fmt.Sprintf("📄 Текущее описание: %s\n\nВведи новое описание (или \"-\", чтобы удалить):", current)
+	case ctxEditFieldDeadline:
+		prompt = "📅 Введи дедлайн в формате ДД.ММ.ГГГГ ЧЧ:ММ\n\nГод и время можно опустить: 25.12 или 25.12 18:00"
+	}
+
+	h.sendMessage(ctx, userID, prompt+"\n\nДля отмены: /cancel")
+}
+
+// showContextDeadline предлагает выбрать дедлайн контекста
+func (h *UniFlowUpdateHandler) showContextDeadline(ctx context.Context, userID int64, callbackID, contextID, userIDStr string) {
+	c, err := h.usecase.GetContextByID(ctx, userIDStr, contextID)
+	if err != nil {
+		h.answerCallback(ctx, callbackID, "❌ Контекст не найден")
+		return
+	}
+
+	h.answerCallback(ctx, callbackID, "")
+
+	response := "📅 Дедлайн не установлен\n\nВыбери дедлайн:"
+	if c.DeadlineAt != nil {
+		response = fmt.Sprintf("📅 Текущий дедлайн: %s\n\nВыбери новый дедлайн:", c.DeadlineAt.In(h.userLocation(ctx, userID)).Format("02.01.2006 15:04"))
+	}
+	h.sendMessageWithKeyboard(ctx, userID, response, h.buildContextDeadlineKeyboard(contextID, c.DeadlineAt != nil))
+}
+
+// contextEdit - изменяемые поля контекста; nil и пустой тип означают, что поле не меняется
+type contextEdit struct {
+	contextType string
+	title       *string
+	description *string
+	color       *string
+	deadlineAt  *string
+}
+
+// applyContextEdit сохраняет изменение контекста и показывает обновленную карточку.
+// callbackID пуст, если значение введено сообщением.
+func (h *UniFlowUpdateHandler) applyContextEdit(ctx context.Context, userID int64, callbackID, contextID, userIDStr string, edit contextEdit) {
+	updated, err := h.usecase.UpdateContext(ctx, userIDStr, contextID, edit.contextType, edit.title, edit.description, edit.color, nil, edit.deadlineAt)
+
+	msg := "✅ Контекст обновлен"
+	if err != nil {
+		h.logger.Error("failed to update context", "error", err, "context_id", contextID)
+		msg = "❌ Ошибка при обновлении контекста"
+	}
+
+	if callbackID != "" {
+		h.answerCallback(ctx, callbackID, msg)
+	} else {
+		h.sendMessage(ctx, userID, msg)
+	}
+	if err != nil {
+		return
+	}
+
+	h.showContextDetails(ctx, userID, updated, userIDStr)
+}
+
+// handleEditingContextState принимает введенное значение редактируемого поля контекста
+func (h *UniFlowUpdateHandler) handleEditingContextState(ctx context.Context, userID int64, text string, state *UserState) {
+	maxUserID := fmt.Sprintf("%d", userID)
+	user, err := h.usecase.GetOrCreateUserByMaxID(ctx, maxUserID)
+	if err != nil {
+		h.logger.Error("failed to get user", "error", err, "max_user_id", maxUserID)
+		h.sendMessage(ctx, userID, "❌ Ошибка при получении данных пользователя.")
+		h.clearState(ctx, userID)
+		return
+	}
+
+	if state.Data.ContextID == nil {
+		h.clearState(ctx, userID)
+		h.showMainMenu(ctx, userID)
+		return
+	}
+
+	var edit contextEdit
+	switch state.Data.Field {
+	case ctxEditFieldTitle:
+		if text == "" {
+			h.sendMessage(ctx, userID, "❌ Название не может быть пустым. Попробуй еще раз:")
+			return
+		}
+		edit.title = &text
+	case ctxEditFieldDesc:
+		if text == "-" {
+			text = ""
+		}
+		edit.description = &text
+	case ctxEditFieldDeadline:
+		deadline, _, ok := parseDueInput(text, time.Now().In(h.userLocation(ctx, userID)))
+		if !ok {
+			h.sendMessage(ctx, userID, "❌ Не понял дату. Пример: 25.12.2026 18:00 или 25.12")
+			return
+		}
+		deadlineAt := deadline.Format(time.RFC3339)
+		edit.deadlineAt = &deadlineAt
+	default:
+		h.clearState(ctx, userID)
+		h.showMainMenu(ctx, userID)
+		return
+	}
+
+	h.clearState(ctx, userID)
+	h.applyContextEdit(ctx, userID, "", *state.Data.ContextID, user.ID.String(), edit)
+}
+
+// handleNewContextTypeCallback завершает создание контекста выбранным типом
+func (h *UniFlowUpdateHandler) handleNewContextTypeCallback(ctx context.Context, userID int64, callbackID string, parts []string) {
+	if len(parts) < 2 {
+		return
+	}
+
+	state, exists := h.getState(ctx, userID)
+	if !exists || state.State != stateCreatingContext || state.Data.Step != 3 {
+		h.answerCallback(ctx, callbackID, "❌ Не найден процесс создания контекста")
+		return
+	}
+
+	opt, ok := findContextTypeOption(models.ContextType(parts[1]))
+	if !ok {
+		h.answerCallback(ctx, callbackID, "❌ Неизвестный тип контекста")
+		return
+	}
+
+	maxUserID := fmt.Sprintf("%d", userID)
+	user, err := h.usecase.GetOrCreateUserByMaxID(ctx, maxUserID)
+	if err != nil {
+		h.logger.Error("failed to get user", "error", err, "max_user_id", maxUserID)
+		h.answerCallback(ctx, callbackID, "❌ Ошибка при получении пользователя")
+		return
+	}
+
+	h.clearState(ctx, userID)
+
+	createdContext, err := h.usecase.CreateContext(ctx, user.ID.String(), opt.Type, state.Data.Title, state.Data.Description, opt.Color, nil, nil)
+	if err != nil {
+		h.logger.Error("failed to create context", "error", err)
+		h.answerCallback(ctx, callbackID, "❌ Ошибка при создании контекста")
+		return
+	}
+
+	h.answerCallback(ctx, callbackID, "✅ Контекст создан")
+
+	response := "✅ Контекст создан!\n\n" +
+		fmt.Sprintf("📁 %s\n", createdContext.Title) +
+		fmt.Sprintf("🏷 Тип: %s\n", opt.Name)
+	if createdContext.Description != "" {
+		response += fmt.Sprintf("📄 %s\n", createdContext.Description)
+	}
+
+	h.sendMessageWithKeyboard(ctx, userID, response, h.buildContextDetailKeyboard(&createdContext))
+}
@@ -12,28 +12,28 @@ import (
 	"github.com/singl3focus/uniflow/pkg/logger"
 )
 
-// UserState представляет состояние диалога пользователя
-type UserState struct {
-	State      string // "creating_task", "editing_task", "creating_context", etc.
-	Data       map[string]interface{}
-	LastUpdate time.Time
-}
-
 // UniFlowUpdateHandler обработчик команд бота UniFlow
 type UniFlowUpdateHandler struct {
-	client     *Client
-	usecase    *usecase.Usecase
-	logger     logger.Logger
-	userStates map[int64]*UserState // Хранение состояний пользователей
+	client    *Client
+	usecase   *usecase.Usecase
+	logger    logger.Logger
+	states    StateStore    // Состояния диалогов пользователей
+	dialogTTL time.Duration // Через сколько без ответа диалог отменяется
 }
 
-// NewUniFlowUpdateHandler создает обработчик команд UniFlow
-func NewUniFlowUpdateHandler(client *Client, uc *usecase.Usecase, log logger.Logger) *UniFlowUpdateHandler {
+// NewUniFlowUpdateHandler создает обработчик команд UniFlow.
+// dialogTTL <= 0 означает DefaultDialogTTL.
+func NewUniFlowUpdateHandler(client *Client, uc *usecase.Usecase, states StateStore, dialogTTL time.Duration, log logger.Logger) *UniFlowUpdateHandler {
+	if dialogTTL <= 0 {
+		dialogTTL = DefaultDialogTTL
+	}
+
 	return &UniFlowUpdateHandler{
-		client:     client,
-		usecase:    uc,
-		logger:     log,
-		userStates: make(map[int64]*UserState),
+		client:    client,
+		usecase:   uc,
+		logger:    log,
+		states:    states,
+		dialogTTL: dialogTTL,
 	}
 }
 
@@ -53,7 +53,7 @@ func (h *UniFlowUpdateHandler) HandleUpdate(update schemes.UpdateInterface) {
 	}
 }
 
-// handleMessage обрабатывает входящие текстовые сообщения
+// handleMessage обрабатывает входящие сообщения: команды, вложения и ответы в диалогах
 func (h *UniFlowUpdateHandler) handleMessage(ctx context.Context, upd *schemes.MessageCreatedUpdate) {
 	userID := upd.Message.Sender.UserId
 	text := strings.TrimSpace(upd.Message.Body.Text)
@@ -66,14 +66,24 @@ func (h *UniFlowUpdateHandler) handleMessage(ctx context.Context, upd *schemes.M
 		return
 	}
 
+	// Фото, голосовые и документы сохраняем как заметки, не прерывая текущий диалог
+	if attachments := parseNoteAttachments(upd.Message.Body.RawAttachments); len(attachments) > 0 {
+		h.handleAttachmentMessage(ctx, userID, text, attachments)
+		return
+	}
+
 	// Проверяем состояние пользователя
-	if state, exists := h.userStates[userID]; exists {
+	if state, exists := h.getState(ctx, userID); exists {
 		h.handleStateMessage(ctx, userID, text, state)
 		return
 	}
 
-	// Обычное сообщение - показываем главное меню
-	h.showMainMenu(ctx, userID)
+	// Обычное сообщение - быстрое добавление задачи
+	if text == "" {
+		h.showMainMenu(ctx, userID)
+		return
+	}
+	h.handleQuickAdd(ctx, userID, text)
 }
 
 // handleCommand обрабатывает команды бота
@@ -86,7 +96,7 @@ func (h *UniFlowUpdateHandler) handleCommand(ctx context.Context, userID int64,
 	cmd := strings.ToLower(parts[0])
 
 	// Сбрасываем состояние при любой команде
-	delete(h.userStates, userID)
+	h.clearState(ctx, userID)
 
 	switch cmd {
 	case "/start":
@@ -96,7 +106,19 @@ func (h *UniFlowUpdateHandler) handleCommand(ctx context.Context, userID int64,
 	case "/today":
 		h.handleTodayCommand(ctx, userID)
 	case "/tasks":
-		h.handleTasksCommand(ctx, userID)
+		// /tasks важные или /tasks #тег
+		var onlyImportant bool
+		var tag string
+		if len(parts) > 1 {
+			if strings.HasPrefix(parts[1], "#") {
+				tag = parts[1]
+			} else {
+				onlyImportant = isImportantFilter(parts[1])
+			}
+		}
+		h.handleTasksCommand(ctx, userID, onlyImportant, tag)
+	case "/timetable":
+		h.handleTimetableCommand(ctx, userID)
 	case "/newtask":
 		h.handleNewTaskCommand(ctx, userID)
 	case "/contexts":
@@ -105,8 +127,14 @@ func (h *UniFlowUpdateHandler) handleCommand(ctx context.Context, userID int64,
 		h.handleNewContextCommand(ctx, userID)
 	case "/search":
 		h.handleSearchCommand(ctx, userID, parts)
+	case "/focus":
+		h.handleFocusCommand(ctx, userID, parts)
+	case "/stats":
+		h.handleStatsCommand(ctx, userID, parts)
+	case "/settings":
+		h.handleSettingsCommand(ctx, userID)
 	case "/cancel":
-		delete(h.userStates, userID)
+		h.clearState(ctx, userID)
 		h.sendMessage(ctx, userID, "❌ Действие отменено")
 		h.showMainMenu(ctx, userID)
 	case "/help":
@@ -150,5 +178,25 @@ func (h *UniFlowUpdateHandler) handleCallback(ctx context.Context, upd *schemes.
 		h.handleMenuCallback(ctx, userID, callbackID, parts)
 	case "date":
 		h.handleDateCallback(ctx, userID, callbackID, parts)
+	case "repeat":
+		h.handleRepeatCallback(ctx, userID, callbackID, parts)
+	case "note":
+		h.handleNoteCallback(ctx, userID, callbackID, parts)
+	case "focus":
+		h.handleFocusCallback(ctx, userID, callbackID, parts)
+	case "edit":
+		h.handleEditCallback(ctx, userID, callbackID, parts)
+	case "ctxedit":
+		h.handleContextEditCallback(ctx, userID, callbackID, parts)
+	case "ctxtype":
+		h.handleNewContextTypeCallback(ctx, userID, callbackID, parts)
+	case "settings":
+		h.handleSettingsCallback(ctx, userID, callbackID, parts)
+	case "review":
+		h.handleReviewCallback(ctx, userID, callbackID, parts)
+	case "quick":
+		h.handleQuickAddCallback(ctx, userID, callbackID, parts)
+	case "cal":
+		h.handleCalendarCallback(ctx, userID, callbackID, parts)
 	}
 }
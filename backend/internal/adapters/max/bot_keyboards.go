@@ -2,6 +2,8 @@ package max
 
 import (
 	"fmt"
+	"strconv"
+	"time"
 
 	maxbot "github.com/max-messenger/max-bot-api-client-go"
 	"github.com/max-messenger/max-bot-api-client-go/schemes"
@@ -28,10 +30,16 @@ func (h *UniFlowUpdateHandler) buildMainMenuKeyboard() *maxbot.Keyboard {
 		AddCallback("➕ Новая задача", schemes.POSITIVE, "menu_newtask").
 		AddCallback("📂 Новый контекст", schemes.POSITIVE, "menu_newcontext")
 
-	// Четвертая строка - поиск (большая кнопка)
+	// Четвертая строка - фокус и поиск
 	kb.AddRow().
+		AddCallback("🎯 Фокус", schemes.DEFAULT, "menu_focus").
 		AddCallback("🔍 Поиск", schemes.DEFAULT, "menu_search")
 
+	// Пятая строка - статистика и настройки
+	kb.AddRow().
+		AddCallback("📊 Статистика", schemes.DEFAULT, "menu_stats_7").
+		AddCallback("⚙️ Настройки", schemes.DEFAULT, "settings_show")
+
 	return kb
 }
 
@@ -51,9 +59,13 @@ func (h *UniFlowUpdateHandler) buildTaskListKeyboard(tasks []models.Task) *maxbo
 			kb.AddRow().
 				AddCallback("✅ "+truncate(task.Title, 30), schemes.DEFAULT, "task_view_"+task.ID.String())
 		} else {
-			// Для активных - завершить или посмотреть
+			// Для активных - завершить или посмотреть. Важные задачи выделяются значком и названием.
+			completeLabel := "✓ Завершить"
+			if task.Priority.IsImportant() {
+				completeLabel = "✓ " + priorityIcon(task.Priority) + truncate(task.Title, 20)
+			}
 			kb.AddRow().
-				AddCallback("✓ Завершить", schemes.POSITIVE, "task_complete_"+task.ID.String()).
+				AddCallback(completeLabel, schemes.POSITIVE, "task_complete_"+task.ID.String()).
 				AddCallback("👁 Просмотр", schemes.DEFAULT, "task_view_"+task.ID.String())
 		}
 		count++
@@ -67,10 +79,27 @@ func (h *UniFlowUpdateHandler) buildTaskListKeyboard(tasks []models.Task) *maxbo
 }
 
 // buildTaskDetailKeyboard создает клавиатуру для деталей задачи
-func (h *UniFlowUpdateHandler) buildTaskDetailKeyboard(task *models.Task) *maxbot.Keyboard {
+func (h *UniFlowUpdateHandler) buildTaskDetailKeyboard(task *models.Task, subtasks []models.Subtask) *maxbot.Keyboard {
 	kb := &maxbot.Keyboard{}
 
 	if task.Status != "completed" {
+		// Пункты чек-листа переключаются нажатием (максимум 10 кнопок)
+		for i, s := range subtasks {
+			if i >= 10 {
+				break
+			}
+			mark := "⬜ "
+			if s.Done {
+				mark = "✅ "
+			}
+			kb.AddRow().
+				AddCallback(mark+truncate(s.Title, 30), schemes.DEFAULT, "task_check_"+s.ID.String())
+		}
+
+		kb.AddRow().
+			AddCallback("➕ Пункт чек-листа", schemes.DEFAULT, "task_additem_"+task.ID.String()).
+			AddCallback(priorityIcon(task.Priority)+"Приоритет: "+priorityName(task.Priority), schemes.DEFAULT, "task_priority_"+task.ID.String())
+
 		// Управление задачей
 		kb.AddRow().
 			AddCallback("✓ Завершить", schemes.POSITIVE, "task_complete_"+task.ID.String()).
@@ -141,6 +170,46 @@ func (h *UniFlowUpdateHandler) buildContextDetailKeyboard(context *models.Contex
 	return kb
 }
 
+// buildNoteContextKeyboard создает клавиатуру выбора контекста для новой заметки
+func (h *UniFlowUpdateHandler) buildNoteContextKeyboard(noteID string, contexts []models.Context) *maxbot.Keyboard {
+	kb := &maxbot.Keyboard{}
+
+	// Контексты по два в ряд (максимум 6)
+	for i := 0; i < len(contexts) && i < 6; i += 2 {
+		row := kb.AddRow()
+		row.AddCallback("📂 "+truncate(contexts[i].Title, 20), schemes.DEFAULT, "note_ctx_"+noteID+"_"+contexts[i].ID.String())
+		if i+1 < len(contexts) && i+1 < 6 {
+			row.AddCallback("📂 "+truncate(contexts[i+1].Title, 20), schemes.DEFAULT, "note_ctx_"+noteID+"_"+contexts[i+1].ID.String())
+		}
+	}
+
+	kb.AddRow().
+		AddCallback("✓ Без контекста", schemes.POSITIVE, "note_keep_"+noteID).
+		AddCallback("🗑 Удалить", schemes.NEGATIVE, "note_delete_"+noteID)
+
+	return kb
+}
+
+// buildFocusKeyboard создает клавиатуру фокус-сессии: остановка идущей или выбор длительности новой
+func (h *UniFlowUpdateHandler) buildFocusKeyboard(active *models.FocusSession) *maxbot.Keyboard {
+	kb := &maxbot.Keyboard{}
+
+	if active != nil {
+		kb.AddRow().
+			AddCallback("⏹ Остановить", schemes.NEGATIVE, "focus_stop_"+active.ID.String())
+	} else {
+		kb.AddRow().
+			AddCallback("15 мин", schemes.DEFAULT, "focus_start_15").
+			AddCallback("25 мин", schemes.POSITIVE, "focus_start_25").
+			AddCallback("50 мин", schemes.DEFAULT, "focus_start_50")
+	}
+
+	kb.AddRow().
+		AddCallback("🏠 Главное меню", schemes.DEFAULT, "menu_main")
+
+	return kb
+}
+
 // buildConfirmKeyboard создает клавиатуру подтверждения
 func (h *UniFlowUpdateHandler) buildConfirmKeyboard(actionType, itemID string) *maxbot.Keyboard {
 	kb := &maxbot.Keyboard{}
@@ -165,6 +234,9 @@ func (h *UniFlowUpdateHandler) buildScheduleKeyboard(dayOffset int) *maxbot.Keyb
 		AddCallback("Сегодня", schemes.POSITIVE, "menu_schedule_0").
 		AddCallback("Следующий ➡️", schemes.DEFAULT, fmt.Sprintf("menu_schedule_%d", nextOffset))
 
+	kb.AddRow().
+		AddCallback("🗓 Расписание на неделю", schemes.DEFAULT, "menu_timetable")
+
 	// Возврат в меню
 	kb.AddRow().
 		AddCallback("🏠 Главное меню", schemes.DEFAULT, "menu_main")
@@ -172,6 +244,28 @@ func (h *UniFlowUpdateHandler) buildScheduleKeyboard(dayOffset int) *maxbot.Keyb
 	return kb
 }
 
+// buildStatsKeyboard создает клавиатуру выбора периода статистики
+func (h *UniFlowUpdateHandler) buildStatsKeyboard(days int) *maxbot.Keyboard {
+	kb := &maxbot.Keyboard{}
+
+	row := kb.AddRow()
+	for _, period := range []struct {
+		days  int
+		label string
+	}{{7, "Неделя"}, {30, "Месяц"}, {90, "3 месяца"}} {
+		intent := schemes.DEFAULT
+		if period.days == days {
+			intent = schemes.POSITIVE
+		}
+		row.AddCallback(period.label, intent, fmt.Sprintf("menu_stats_%d", period.days))
+	}
+
+	kb.AddRow().
+		AddCallback("🏠 Главное меню", schemes.DEFAULT, "menu_main")
+
+	return kb
+}
+
 // buildDateSelectionKeyboard создает клавиатуру для выбора даты
 func (h *UniFlowUpdateHandler) buildDateSelectionKeyboard() *maxbot.Keyboard {
 	kb := &maxbot.Keyboard{}
@@ -187,11 +281,297 @@ func (h *UniFlowUpdateHandler) buildDateSelectionKeyboard() *maxbot.Keyboard {
 
 	kb.AddRow().
 		AddCallback("Через неделю", schemes.DEFAULT, "date_7").
+		AddCallback("📅 Календарь", schemes.DEFAULT, calendarTarget{kind: calendarNewTask}.payload())
+
+	kb.AddRow().
 		AddCallback("Пропустить", schemes.NEGATIVE, "date_skip")
 
 	return kb
 }
 
+// buildRecurrenceKeyboard создает клавиатуру выбора повторения задачи
+func (h *UniFlowUpdateHandler) buildRecurrenceKeyboard() *maxbot.Keyboard {
+	kb := &maxbot.Keyboard{}
+
+	kb.AddRow().
+		AddCallback("Каждый день", schemes.DEFAULT, "repeat_daily").
+		AddCallback("По будням", schemes.DEFAULT, "repeat_workdays")
+
+	kb.AddRow().
+		AddCallback("Каждую неделю", schemes.DEFAULT, "repeat_weekly").
+		AddCallback("Каждый месяц", schemes.DEFAULT, "repeat_monthly")
+
+	kb.AddRow().
+		AddCallback("Не повторять", schemes.NEGATIVE, "repeat_none")
+
+	return kb
+}
+
+// buildTaskEditKeyboard создает клавиатуру выбора редактируемого поля задачи
+func (h *UniFlowUpdateHandler) buildTaskEditKeyboard(taskID string) *maxbot.Keyboard {
+	kb := &maxbot.Keyboard{}
+
+	kb.AddRow().
+		AddCallback("📝 Название", schemes.DEFAULT, "edit_title_"+taskID).
+		AddCallback("📄 Описание", schemes.DEFAULT, "edit_desc_"+taskID)
+
+	kb.AddRow().
+		AddCallback("📂 Контекст", schemes.DEFAULT, "edit_ctx_"+taskID).
+		AddCallback("⏰ Срок", schemes.DEFAULT, "edit_due_"+taskID)
+
+	kb.AddRow().
+		AddCallback("📌 Статус", schemes.DEFAULT, "edit_status_"+taskID)
+
+	kb.AddRow().
+		AddCallback("◀️ Назад к задаче", schemes.DEFAULT, "task_view_"+taskID)
+
+	return kb
+}
+
+// buildEditContextKeyboard создает клавиатуру выбора контекста задачи
+func (h *UniFlowUpdateHandler) buildEditContextKeyboard(taskID string, contexts []models.Context) *maxbot.Keyboard {
+	kb := &maxbot.Keyboard{}
+
+	// Показываем максимум 10 контекстов
+	for i, c := range contexts {
+		if i >= 10 {
+			break
+		}
+		kb.AddRow().
+			AddCallback("📂 "+truncate(c.Title, 30), schemes.DEFAULT, "edit_ctx_"+taskID+"_"+c.ID.String())
+	}
+
+	kb.AddRow().
+		AddCallback("Без контекста", schemes.NEGATIVE, "edit_ctx_"+taskID+"_none")
+
+	kb.AddRow().
+		AddCallback("◀️ Назад", schemes.DEFAULT, "task_edit_"+taskID)
+
+	return kb
+}
+
+// buildEditDueKeyboard создает клавиатуру выбора нового срока задачи
+func (h *UniFlowUpdateHandler) buildEditDueKeyboard(taskID string, hasDue bool) *maxbot.Keyboard {
+	kb := &maxbot.Keyboard{}
+
+	kb.AddRow().
+		AddCallback("Сегодня", schemes.DEFAULT, "edit_due_"+taskID+"_0").
+		AddCallback("Завтра", schemes.DEFAULT, "edit_due_"+taskID+"_1")
+
+	kb.AddRow().
+		AddCallback("Через 3 дня", schemes.DEFAULT, "edit_due_"+taskID+"_3").
+		AddCallback("Через неделю", schemes.DEFAULT, "edit_due_"+taskID+"_7")
+
+	kb.AddRow().
+		AddCallback("📅 Календарь", schemes.DEFAULT, calendarTarget{kind: calendarTask, id: taskID}.payload()).
+		AddCallback("✍️ Ввести дату", schemes.DEFAULT, "edit_due_"+taskID+"_input")
+
+	if hasDue {
+		kb.AddRow().
+			AddCallback("Снять срок", schemes.NEGATIVE, "edit_due_"+taskID+"_none")
+	}
+
+	kb.AddRow().
+		AddCallback("◀️ Назад к задаче", schemes.DEFAULT, "task_view_"+taskID)
+
+	return kb
+}
+
+// buildEditStatusKeyboard создает клавиатуру выбора статуса задачи
+func (h *UniFlowUpdateHandler) buildEditStatusKeyboard(taskID string) *maxbot.Keyboard {
+	kb := &maxbot.Keyboard{}
+
+	kb.AddRow().
+		AddCallback(taskStatusName(models.TaskStatusTodo), schemes.DEFAULT, "edit_status_"+taskID+"_todo").
+		AddCallback(taskStatusName(models.TaskStatusInProgress), schemes.DEFAULT, "edit_status_"+taskID+"_progress")
+
+	kb.AddRow().
+		AddCallback(taskStatusName(models.TaskStatusCompleted), schemes.POSITIVE, "edit_status_"+taskID+"_done").
+		AddCallback(taskStatusName(models.TaskStatusCancelled), schemes.NEGATIVE, "edit_status_"+taskID+"_cancel")
+
+	kb.AddRow().
+		AddCallback("◀️ Назад", schemes.DEFAULT, "task_edit_"+taskID)
+
+	return kb
+}
+
+// buildContextEditKeyboard создает клавиатуру выбора редактируемого поля контекста
+func (h *UniFlowUpdateHandler) buildContextEditKeyboard(contextID string) *maxbot.Keyboard {
+	kb := &maxbot.Keyboard{}
+
+	kb.AddRow().
+		AddCallback("📝 Название", schemes.DEFAULT, "ctxedit_title_"+contextID).
+		AddCallback("📄 Описание", schemes.DEFAULT, "ctxedit_desc_"+contextID)
+
+	kb.AddRow().
+		AddCallback("🏷 Тип", schemes.DEFAULT, "ctxedit_type_"+contextID).
+		AddCallback("🎨 Цвет", schemes.DEFAULT, "ctxedit_color_"+contextID)
+
+	kb.AddRow().
+		AddCallback("📅 Дедлайн", schemes.DEFAULT, "ctxedit_deadline_"+contextID)
+
+	kb.AddRow().
+		AddCallback("◀️ Назад к контексту", schemes.DEFAULT, "context_view_"+contextID)
+
+	return kb
+}
+
+// buildContextTypeKeyboard создает клавиатуру выбора типа контекста. К prefix добавляется тип,
+// кнопка "Назад" добавляется, только если задан backPayload.
+func (h *UniFlowUpdateHandler) buildContextTypeKeyboard(prefix, backPayload string) *maxbot.Keyboard {
+	kb := &maxbot.Keyboard{}
+
+	for i := 0; i < len(contextTypeOptions); i += 2 {
+		row := kb.AddRow()
+		for _, opt := range contextTypeOptions[i:min(i+2, len(contextTypeOptions))] {
+			row.AddCallback(opt.Name, schemes.DEFAULT, prefix+string(opt.Type))
+		}
+	}
+
+	if backPayload != "" {
+		kb.AddRow().
+			AddCallback("◀️ Назад", schemes.DEFAULT, backPayload)
+	}
+
+	return kb
+}
+
+// buildContextColorKeyboard создает клавиатуру выбора цвета контекста
+func (h *UniFlowUpdateHandler) buildContextColorKeyboard(contextID string) *maxbot.Keyboard {
+	kb := &maxbot.Keyboard{}
+
+	for i := 0; i < len(contextColorOptions); i += 2 {
+		row := kb.AddRow()
+		for _, opt := range contextColorOptions[i:min(i+2, len(contextColorOptions))] {
+			row.AddCallback(opt.Name, schemes.DEFAULT, "ctxedit_color_"+contextID+"_"+opt.Hex)
+		}
+	}
+
+	kb.AddRow().
+		AddCallback("◀️ Назад", schemes.DEFAULT, "ctxedit_menu_"+contextID)
+
+	return kb
+}
+
+// buildContextDeadlineKeyboard создает клавиатуру выбора дедлайна контекста
+func (h *UniFlowUpdateHandler) buildContextDeadlineKeyboard(contextID string, hasDeadline bool) *maxbot.Keyboard {
+	kb := &maxbot.Keyboard{}
+
+	kb.AddRow().
+		AddCallback("Через неделю", schemes.DEFAULT, "ctxedit_deadline_"+contextID+"_7").
+		AddCallback("Через 2 недели", schemes.DEFAULT, "ctxedit_deadline_"+contextID+"_14")
+
+	kb.AddRow().
+		AddCallback("Через месяц", schemes.DEFAULT, "ctxedit_deadline_"+contextID+"_30").
+		AddCallback("📅 Календарь", schemes.DEFAULT, calendarTarget{kind: calendarContext, id: contextID}.payload())
+
+	kb.AddRow().
+		AddCallback("✍️ Ввести дату", schemes.DEFAULT, "ctxedit_deadline_"+contextID+"_input")
+
+	if hasDeadline {
+		kb.AddRow().
+			AddCallback("Снять дедлайн", schemes.NEGATIVE, "ctxedit_deadline_"+contextID+"_none")
+	}
+
+	kb.AddRow().
+		AddCallback("◀️ Назад", schemes.DEFAULT, "ctxedit_menu_"+contextID)
+
+	return kb
+}
+
+// buildSettingsKeyboard создает клавиатуру настроек пользователя
+func (h *UniFlowUpdateHandler) buildSettingsKeyboard(settings models.UserSettings) *maxbot.Keyboard {
+	kb := &maxbot.Keyboard{}
+
+	langLabel := "🇬🇧 English"
+	if settings.Language == "en" {
+		langLabel = "🇷🇺 Русский"
+	}
+	weekLabel := "Неделя с воскресенья"
+	if settings.FirstWeekday == models.Sunday {
+		weekLabel = "Неделя с понедельника"
+	}
+
+	kb.AddRow().
+		AddCallback("🌍 Часовой пояс", schemes.DEFAULT, "settings_tz").
+		AddCallback("⏰ Напоминания", schemes.DEFAULT, "settings_rem")
+
+	kb.AddRow().
+		AddCallback(langLabel, schemes.DEFAULT, "settings_lang").
+		AddCallback(weekLabel, schemes.DEFAULT, "settings_week")
+
+	kb.AddRow().
+		AddCallback("🌅 Утренняя сводка", schemes.DEFAULT, "settings_morning").
+		AddCallback("🌙 Вечерний обзор", schemes.DEFAULT, "settings_evening")
+
+	kb.AddRow().
+		AddCallback("🏠 Главное меню", schemes.DEFAULT, "menu_main")
+
+	return kb
+}
+
+// buildTimezoneKeyboard создает клавиатуру выбора часового пояса (по два в строке)
+func (h *UniFlowUpdateHandler) buildTimezoneKeyboard() *maxbot.Keyboard {
+	kb := &maxbot.Keyboard{}
+
+	for i := 0; i < len(timezoneOptions); i += 2 {
+		row := kb.AddRow()
+		for j := i; j < min(i+2, len(timezoneOptions)); j++ {
+			row.AddCallback(timezoneOptions[j].Name, schemes.DEFAULT, fmt.Sprintf("settings_tz_%d", j))
+		}
+	}
+
+	kb.AddRow().
+		AddCallback("✍️ Другой пояс", schemes.DEFAULT, "settings_tz_input")
+
+	kb.AddRow().
+		AddCallback("◀️ Назад", schemes.DEFAULT, "settings_show")
+
+	return kb
+}
+
+// buildDigestTimeKeyboard создает клавиатуру выбора времени сводки
+func (h *UniFlowUpdateHandler) buildDigestTimeKeyboard(kind models.DigestKind) *maxbot.Keyboard {
+	kb := &maxbot.Keyboard{}
+	prefix := "settings_" + string(kind) + "_"
+
+	presets := digestPresets[kind]
+	for i := 0; i < len(presets); i += 2 {
+		row := kb.AddRow()
+		for _, at := range presets[i:min(i+2, len(presets))] {
+			row.AddCallback(at, schemes.DEFAULT, prefix+at)
+		}
+	}
+
+	kb.AddRow().
+		AddCallback("✍️ Другое время", schemes.DEFAULT, prefix+"input").
+		AddCallback("🔕 Выключить", schemes.NEGATIVE, prefix+"off")
+
+	kb.AddRow().
+		AddCallback("◀️ Назад", schemes.DEFAULT, "settings_show")
+
+	return kb
+}
+
+// buildReminderPresetsKeyboard создает клавиатуру выбора напоминаний
+func (h *UniFlowUpdateHandler) buildReminderPresetsKeyboard() *maxbot.Keyboard {
+	kb := &maxbot.Keyboard{}
+
+	for _, preset := range reminderPresets {
+		intent := schemes.DEFAULT
+		if preset.Payload == "none" {
+			intent = schemes.NEGATIVE
+		}
+		kb.AddRow().
+			AddCallback(preset.Name, intent, "settings_rem_"+preset.Payload)
+	}
+
+	kb.AddRow().
+		AddCallback("◀️ Назад", schemes.DEFAULT, "settings_show")
+
+	return kb
+}
+
 // buildInboxKeyboard создает клавиатуру для входящих задач
 func (h *UniFlowUpdateHandler) buildInboxKeyboard(tasks []models.Task) *maxbot.Keyboard {
 	kb := &maxbot.Keyboard{}
@@ -222,10 +602,227 @@ func (h *UniFlowUpdateHandler) buildInboxKeyboard(tasks []models.Task) *maxbot.K
 	return kb
 }
 
-// truncate обрезает строку до указанной длины
+// buildReminderKeyboard создает клавиатуру напоминания о задаче: выполнить или отложить, выбрав новый срок
+func buildReminderKeyboard(taskID string) *maxbot.Keyboard {
+	kb := &maxbot.Keyboard{}
+
+	kb.AddRow().
+		AddCallback("✅ Выполнено", schemes.POSITIVE, "task_complete_"+taskID).
+		AddCallback("⏰ Отложить", schemes.DEFAULT, calendarTarget{kind: calendarTask, id: taskID}.payload())
+
+	return kb
+}
+
+// maxReviewTasks - сколько задач вечернего обзора получают кнопки
+const maxReviewTasks = 10
+
+// buildReviewKeyboard создает клавиатуру вечернего обзора: по строке кнопок на задачу
+func buildReviewKeyboard(tasks []models.Task) *maxbot.Keyboard {
+	kb := &maxbot.Keyboard{}
+
+	for _, task := range tasks[:min(len(tasks), maxReviewTasks)] {
+		id := task.ID.String()
+		kb.AddRow().
+			AddCallback("✓ "+truncate(task.Title, 20), schemes.POSITIVE, "review_done_"+id).
+			AddCallback("➡️", schemes.DEFAULT, "review_tomorrow_"+id).
+			AddCallback("✖", schemes.NEGATIVE, "review_drop_"+id)
+	}
+
+	return kb
+}
+
+// buildMorningDigestKeyboard создает клавиатуру утренней сводки
+func buildMorningDigestKeyboard() *maxbot.Keyboard {
+	kb := &maxbot.Keyboard{}
+
+	kb.AddRow().
+		AddCallback("📋 Открыть день", schemes.DEFAULT, "menu_today").
+		AddCallback("➕ Новая задача", schemes.POSITIVE, "menu_newtask")
+
+	return kb
+}
+
+// truncate обрезает строку до указанной длины в символах
 func truncate(s string, maxLen int) string {
-	if len(s) <= maxLen {
+	runes := []rune(s)
+	if len(runes) <= maxLen {
 		return s
 	}
-	return s[:maxLen-3] + "..."
+	return string(runes[:maxLen-3]) + "..."
+}
+
+// buildQuickAddKeyboard создает клавиатуру карточки быстрого добавления задачи
+func (h *UniFlowUpdateHandler) buildQuickAddKeyboard() *maxbot.Keyboard {
+	kb := &maxbot.Keyboard{}
+
+	kb.AddRow().
+		AddCallback("✅ Создать", schemes.POSITIVE, "quick_save").
+		AddCallback("❌ Отмена", schemes.NEGATIVE, "quick_cancel")
+
+	kb.AddRow().
+		AddCallback("📝 Название", schemes.DEFAULT, "quick_title").
+		AddCallback("⏰ Срок", schemes.DEFAULT, "quick_due")
+
+	kb.AddRow().
+		AddCallback("📂 Контекст", schemes.DEFAULT, "quick_ctx").
+		AddCallback("🔥 Приоритет", schemes.DEFAULT, "quick_prio")
+
+	return kb
+}
+
+// buildQuickAddDueKeyboard создает клавиатуру выбора срока в карточке быстрого добавления
+func (h *UniFlowUpdateHandler) buildQuickAddDueKeyboard(hasDue bool) *maxbot.Keyboard {
+	kb := &maxbot.Keyboard{}
+
+	kb.AddRow().
+		AddCallback("Сегодня", schemes.DEFAULT, "quick_due_0").
+		AddCallback("Завтра", schemes.DEFAULT, "quick_due_1")
+
+	kb.AddRow().
+		AddCallback("Через 3 дня", schemes.DEFAULT, "quick_due_3").
+		AddCallback("Через неделю", schemes.DEFAULT, "quick_due_7")
+
+	kb.AddRow().
+		AddCallback("📅 Календарь", schemes.DEFAULT, calendarTarget{kind: calendarQuickAdd}.payload()).
+		AddCallback("✍️ Ввести срок", schemes.DEFAULT, "quick_due_input")
+
+	if hasDue {
+		kb.AddRow().
+			AddCallback("Без срока", schemes.NEGATIVE, "quick_due_none")
+	}
+
+	kb.AddRow().
+		AddCallback("◀️ Назад", schemes.DEFAULT, "quick_show")
+
+	return kb
+}
+
+// buildQuickAddContextKeyboard создает клавиатуру выбора контекста в карточке быстрого добавления
+func (h *UniFlowUpdateHandler) buildQuickAddContextKeyboard(contexts []models.Context) *maxbot.Keyboard {
+	kb := &maxbot.Keyboard{}
+
+	// Показываем максимум 10 контекстов
+	for i, c := range contexts {
+		if i >= 10 {
+			break
+		}
+		kb.AddRow().
+			AddCallback("📂 "+truncate(c.Title, 30), schemes.DEFAULT, "quick_ctx_"+c.ID.String())
+	}
+
+	kb.AddRow().
+		AddCallback("📥 Входящие", schemes.NEGATIVE, "quick_ctx_none")
+
+	kb.AddRow().
+		AddCallback("◀️ Назад", schemes.DEFAULT, "quick_show")
+
+	return kb
+}
+
+// buildCalendarKeyboard создает календарь месяца month: навигация по месяцам, дни недели от firstWeekday
+// и сетка дней. Прошедшие дни недоступны; сегодняшний отмечен скобками.
+func buildCalendarKeyboard(target calendarTarget, month, today time.Time, firstWeekday models.Weekday) *maxbot.Keyboard {
+	kb := &maxbot.Keyboard{}
+
+	loc := today.Location()
+	first := time.Date(month.Year(), month.Month(), 1, 0, 0, 0, 0, loc)
+	thisMonth := time.Date(today.Year(), today.Month(), 1, 0, 0, 0, 0, loc)
+	todayStart := startOfDay(today)
+
+	// В прошлые месяцы не листаем: выбрать в них нечего
+	nav := kb.AddRow()
+	if first.After(thisMonth) {
+		nav.AddCallback("◀️", schemes.DEFAULT, target.payload(calendarStepMonth, first.AddDate(0, -1, 0).Format(calendarMonthFmt)))
+	} else {
+		nav.AddCallback(" ", schemes.DEFAULT, calendarNoop)
+	}
+	nav.AddCallback(calendarMonthTitle(first), schemes.DEFAULT, calendarNoop)
+	nav.AddCallback("▶️", schemes.DEFAULT, target.payload(calendarStepMonth, first.AddDate(0, 1, 0).Format(calendarMonthFmt)))
+
+	header := kb.AddRow()
+	for i := 0; i < 7; i++ {
+		header.AddCallback(models.Weekday((int(firstWeekday)+i)%7).ShortName(), schemes.DEFAULT, calendarNoop)
+	}
+
+	// Пустые клетки до первого числа
+	offset := (int(models.WeekdayOf(first)) - int(firstWeekday) + 7) % 7
+	days := first.AddDate(0, 1, -1).Day()
+
+	var row *maxbot.KeyboardRow
+	for cell := 0; cell < offset+days || cell%7 != 0; cell++ {
+		if cell%7 == 0 {
+			row = kb.AddRow()
+		}
+
+		day := cell - offset + 1
+		if day < 1 || day > days {
+			row.AddCallback(" ", schemes.DEFAULT, calendarNoop)
+			continue
+		}
+
+		date := time.Date(first.Year(), first.Month(), day, 0, 0, 0, 0, loc)
+		switch {
+		case date.Before(todayStart):
+			row.AddCallback("·", schemes.DEFAULT, calendarNoop)
+		case date.Equal(todayStart):
+			row.AddCallback(fmt.Sprintf("[%d]", day), schemes.POSITIVE, target.payload(calendarStepDay, date.Format(calendarDayFmt)))
+		default:
+			row.AddCallback(strconv.Itoa(day), schemes.DEFAULT, target.payload(calendarStepDay, date.Format(calendarDayFmt)))
+		}
+	}
+
+	return kb
+}
+
+// Часы, предлагаемые при выборе времени: с 06 до 23, по 6 в строке
+const (
+	timePickerFirstHour  = 6
+	timePickerHoursInRow = 6
+)
+
+// buildTimePickerKeyboard создает выбор часа для дня day; уже прошедшие сегодня часы недоступны
+func buildTimePickerKeyboard(target calendarTarget, day, now time.Time) *maxbot.Keyboard {
+	kb := &maxbot.Keyboard{}
+	dayStr := day.Format(calendarDayFmt)
+	lastHourPassed := -1
+	if startOfDay(day).Equal(startOfDay(now)) {
+		lastHourPassed = now.Hour() - 1 // В текущем часе еще можно выбрать минуты впереди
+	}
+
+	var row *maxbot.KeyboardRow
+	for hour := timePickerFirstHour; hour < 24; hour++ {
+		if (hour-timePickerFirstHour)%timePickerHoursInRow == 0 {
+			row = kb.AddRow()
+		}
+		if hour <= lastHourPassed {
+			row.AddCallback("·", schemes.DEFAULT, calendarNoop)
+			continue
+		}
+		row.AddCallback(fmt.Sprintf("%02d", hour), schemes.DEFAULT, target.payload(calendarStepHour, dayStr, fmt.Sprintf("%02d", hour)))
+	}
+
+	kb.AddRow().
+		AddCallback("🌙 Весь день", schemes.POSITIVE, target.payload(calendarStepTime, dayStr, calendarAllDay))
+
+	kb.AddRow().
+		AddCallback("◀️ К календарю", schemes.DEFAULT, target.payload(calendarStepMonth, day.Format(calendarMonthFmt)))
+
+	return kb
+}
+
+// buildMinutePickerKeyboard создает выбор минут для часа hour дня day
+func buildMinutePickerKeyboard(target calendarTarget, day time.Time, hour int) *maxbot.Keyboard {
+	kb := &maxbot.Keyboard{}
+	dayStr := day.Format(calendarDayFmt)
+
+	row := kb.AddRow()
+	for _, minute := range []int{0, 15, 30, 45} {
+		row.AddCallback(fmt.Sprintf("%02d:%02d", hour, minute), schemes.DEFAULT,
+			target.payload(calendarStepTime, dayStr, fmt.Sprintf("%02d%02d", hour, minute)))
+	}
+
+	kb.AddRow().
+		AddCallback("◀️ Другой час", schemes.DEFAULT, target.payload(calendarStepDay, dayStr))
+
+	return kb
 }
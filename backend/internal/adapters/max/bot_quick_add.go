@@ -0,0 +1,285 @@
+package max
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/singl3focus/uniflow/internal/core/models"
+)
+
+// handleQuickAdd разбирает обычное сообщение как задачу и показывает карточку для подтверждения.
+// Черновик хранится в состоянии stateQuickAdd, пока пользователь не создаст задачу или не отменит ее.
+func (h *UniFlowUpdateHandler) handleQuickAdd(ctx context.Context, userID int64, text string) {
+	maxUserID := fmt.Sprintf("%d", userID)
+	user, err := h.usecase.GetOrCreateUserByMaxID(ctx, maxUserID)
+	if err != nil {
+		h.logger.Error("failed to get user", "error", err, "max_user_id", maxUserID)
+		h.sendMessage(ctx, userID, "❌ Ошибка при получении данных пользователя.")
+		return
+	}
+
+	contexts, err := h.usecase.GetContextsByUserID(ctx, user.ID.String())
+	if err != nil {
+		h.logger.Error("failed to get contexts", "error", err)
+		contexts = nil // Задачу можно создать и без угадывания контекста
+	}
+
+	now := time.Now().In(user.Settings.Location())
+	draft := parseQuickAdd(text, now, contexts)
+
+	data := DialogData{
+		Title:    draft.Title,
+		Tags:     draft.Tags,
+		Priority: draft.Priority,
+	}
+	if draft.Context != nil {
+		id := draft.Context.ID.String()
+		data.ContextID = &id
+	}
+	if draft.DueAt != nil {
+		dueAt := dueValue(*draft.DueAt, draft.AllDay)
+		data.DueAt = &dueAt
+	}
+
+	state := &UserState{State: stateQuickAdd, Data: data}
+	h.saveState(ctx, userID, state)
+
+	var note string
+	if draft.ContextName != "" {
+		note = fmt.Sprintf("⚠️ Контекст «%s» не найден, задача попадет во входящие", draft.ContextName)
+	}
+	h.showQuickAddCard(ctx, userID, state, note)
+}
+
+// showQuickAddCard показывает разобранную задачу с кнопками исправления; note - необязательное предупреждение
+func (h *UniFlowUpdateHandler) showQuickAddCard(ctx context.Context, userID int64, state *UserState, note string) {
+	data := state.Data
+
+	response := "📝 Новая задача\n\n" +
+		fmt.Sprintf("Название: %s\n", data.Title)
+
+	loc := h.userLocation(ctx, userID)
+	if dueAt, allDay, ok := parseDialogDue(data.DueAt, loc); ok {
+		response += fmt.Sprintf("⏰ Срок: %s\n", formatTaskDue(models.Task{DueAt: &dueAt, AllDay: allDay}, loc))
+	} else {
+		response += "⏰ Срок: не указан\n"
+	}
+
+	contextName := "📥 Входящие"
+	if data.ContextID != nil {
+		maxUserID := fmt.Sprintf("%d", userID)
+		if user, err := h.usecase.GetOrCreateUserByMaxID(ctx, maxUserID); err == nil {
+			if c, err := h.usecase.GetContextByID(ctx, user.ID.String(), *data.ContextID); err == nil {
+				contextName = c.Title
+			}
+		}
+	}
+	response += fmt.Sprintf("📂 Контекст: %s\n", contextName)
+	response += fmt.Sprintf("🔥 Приоритет: %s%s\n", priorityIcon(data.Priority), priorityName(data.Priority))
+
+	if len(data.Tags) > 0 {
+		response += fmt.Sprintf("🏷 %s\n", formatTags(data.Tags))
+	}
+	if note != "" {
+		response += "\n" + note + "\n"
+	}
+
+	response += "\nВсё верно? Исправь поле кнопкой или отправь задачу заново."
+
+	h.sendMessageWithKeyboard(ctx, userID, response, h.buildQuickAddKeyboard())
+}
+
+// handleQuickAddCallback обрабатывает кнопки карточки быстрого добавления: quick_<действие>[_<значение>]
+func (h *UniFlowUpdateHandler) handleQuickAddCallback(ctx context.Context, userID int64, callbackID string, parts []string) {
+	action := parts[1] // save, cancel, show, title, due, ctx, prio
+	value := ""
+	if len(parts) > 2 {
+		value = parts[2]
+	}
+
+	state, exists := h.getState(ctx, userID)
+	if !exists || state.State != stateQuickAdd {
+		h.answerCallback(ctx, callbackID, "⌛ Черновик устарел, отправь задачу заново")
+		return
+	}
+
+	switch action {
+	case "save":
+		h.saveQuickAdd(ctx, userID, callbackID, state)
+	case "cancel":
+		h.clearState(ctx, userID)
+		h.answerCallback(ctx, callbackID, "Отменено")
+		h.sendMessageWithKeyboard(ctx, userID, "❌ Задача не создана", h.buildMainMenuKeyboard())
+	case "show":
+		state.Data.Field = ""
+		h.saveState(ctx, userID, state)
+		h.answerCallback(ctx, callbackID, "")
+		h.showQuickAddCard(ctx, userID, state, "")
+	case "title":
+		state.Data.Field = "title"
+		h.saveState(ctx, userID, state)
+		h.answerCallback(ctx, callbackID, "")
+		h.sendMessage(ctx, userID, "📝 Введи название задачи\n\nДля отмены: /cancel")
+	case "prio":
+		state.Data.Priority = nextPriority(state.Data.Priority)
+		h.saveState(ctx, userID, state)
+		h.answerCallback(ctx, callbackID, "Приоритет: "+priorityName(state.Data.Priority))
+		h.showQuickAddCard(ctx, userID, state, "")
+	case "due":
+		h.handleQuickAddDue(ctx, userID, callbackID, state, value)
+	case "ctx":
+		h.handleQuickAddContext(ctx, userID, callbackID, state, value)
+	}
+}
+
+// handleQuickAddDue меняет срок черновика: пресет в днях от сегодня, ввод текстом или снятие срока
+func (h *UniFlowUpdateHandler) handleQuickAddDue(ctx context.Context, userID int64, callbackID string, state *UserState, value string) {
+	switch value {
+	case "":
+		h.answerCallback(ctx, callbackID, "")
+		h.sendMessageWithKeyboard(ctx, userID, "⏰ Выбери срок:", h.buildQuickAddDueKeyboard(state.Data.DueAt != nil))
+		return
+	case "input":
+		state.Data.Field = "due"
+		h.saveState(ctx, userID, state)
+		h.answerCallback(ctx, callbackID, "")
+		h.sendMessage(ctx, userID, "⏰ Введи срок, например: завтра в 18:00, в пятницу, 25.12 9:30\n\nДля отмены: /cancel")
+		return
+	case "none":
+		state.Data.DueAt = nil
+	default:
+		days, err := strconv.Atoi(value)
+		if err != nil || days < 0 {
+			h.answerCallback(ctx, callbackID, "❌ Неизвестный вариант")
+			return
+		}
+
+		// Время дня сохраняется; без времени срок - на весь день
+		loc := h.userLocation(ctx, userID)
+		day := startOfDay(time.Now().In(loc)).AddDate(0, 0, days)
+		dueAtStr := dueValue(day, true)
+		if dueAt, allDay, ok := parseDialogDue(state.Data.DueAt, loc); ok && !allDay {
+			dueAt = dueAt.In(loc)
+			dueAtStr = dueValue(time.Date(day.Year(), day.Month(), day.Day(), dueAt.Hour(), dueAt.Minute(), 0, 0, loc), false)
+		}
+		state.Data.DueAt = &dueAtStr
+	}
+
+	h.saveState(ctx, userID, state)
+	h.answerCallback(ctx, callbackID, "")
+	h.showQuickAddCard(ctx, userID, state, "")
+}
+
+// handleQuickAddContext меняет контекст черновика: без значения показывает список контекстов
+func (h *UniFlowUpdateHandler) handleQuickAddContext(ctx context.Context, userID int64, callbackID string, state *UserState, value string) {
+	maxUserID := fmt.Sprintf("%d", userID)
+	user, err := h.usecase.GetOrCreateUserByMaxID(ctx, maxUserID)
+	if err != nil {
+		h.logger.Error("failed to get user", "error", err)
+		h.answerCallback(ctx, callbackID, "❌ Ошибка при получении пользователя")
+		return
+	}
+
+	switch value {
+	case "":
+		contexts, err := h.usecase.GetContextsByUserID(ctx, user.ID.String())
+		if err != nil {
+			h.logger.Error("failed to get contexts", "error", err)
+			h.answerCallback(ctx, callbackID, "❌ Не удалось загрузить контексты")
+			return
+		}
+		h.answerCallback(ctx, callbackID, "")
+		h.sendMessageWithKeyboard(ctx, userID, "📂 Выбери контекст:", h.buildQuickAddContextKeyboard(contexts))
+		return
+	case "none":
+		state.Data.ContextID = nil
+	default:
+		c, err := h.usecase.GetContextByID(ctx, user.ID.String(), value)
+		if err != nil {
+			h.answerCallback(ctx, callbackID, "❌ Контекст не найден")
+			return
+		}
+		id := c.ID.String()
+		state.Data.ContextID = &id
+	}
+
+	h.saveState(ctx, userID, state)
+	h.answerCallback(ctx, callbackID, "")
+	h.showQuickAddCard(ctx, userID, state, "")
+}
+
+// saveQuickAdd создает задачу из черновика и показывает ее
+func (h *UniFlowUpdateHandler) saveQuickAdd(ctx context.Context, userID int64, callbackID string, state *UserState) {
+	maxUserID := fmt.Sprintf("%d", userID)
+	user, err := h.usecase.GetOrCreateUserByMaxID(ctx, maxUserID)
+	if err != nil {
+		h.logger.Error("failed to get user", "error", err)
+		h.answerCallback(ctx, callbackID, "❌ Ошибка при получении пользователя")
+		return
+	}
+
+	data := state.Data
+	task, err := h.usecase.CreateTask(ctx, user.ID.String(), data.ContextID, data.Title, "", data.DueAt, nil, data.Priority, data.Tags)
+	if err != nil {
+		h.logger.Error("failed to create task", "error", err)
+		h.answerCallback(ctx, callbackID, "❌ Не удалось создать задачу")
+		return
+	}
+
+	h.clearState(ctx, userID)
+	h.answerCallback(ctx, callbackID, "✅ Задача создана")
+	h.showTaskDetails(ctx, userID, task)
+}
+
+// handleQuickAddState обрабатывает текст, пока открыта карточка быстрого добавления:
+// ввод названия или срока, а без выбранного поля - новую задачу вместо черновика
+func (h *UniFlowUpdateHandler) handleQuickAddState(ctx context.Context, userID int64, text string, state *UserState) {
+	switch state.Data.Field {
+	case "title":
+		title := strings.TrimSpace(text)
+		if title == "" {
+			h.sendMessage(ctx, userID, "❌ Название не может быть пустым. Введи название задачи")
+			return
+		}
+		state.Data.Title = title
+	case "due":
+		now := time.Now().In(h.userLocation(ctx, userID))
+		draft := parseQuickAdd(text, now, nil)
+		if draft.DueAt == nil {
+			due, allDay, ok := parseDueInput(text, now)
+			if !ok {
+				h.sendMessage(ctx, userID, "❌ Не понял срок. Пример: завтра в 18:00, в пятницу, 25.12 9:30")
+				return
+			}
+			draft.DueAt, draft.AllDay = &due, allDay
+		}
+		dueAtStr := dueValue(*draft.DueAt, draft.AllDay)
+		state.Data.DueAt = &dueAtStr
+	default:
+		h.handleQuickAdd(ctx, userID, text)
+		return
+	}
+
+	state.Data.Field = ""
+	h.saveState(ctx, userID, state)
+	h.showQuickAddCard(ctx, userID, state, "")
+}
+
+// parseDialogDue разбирает срок, сохраненный в состоянии диалога (см. dueValue).
+// Срок на весь день возвращается как 23:59 этого дня в поясе loc.
+func parseDialogDue(dueAt *string, loc *time.Location) (due time.Time, allDay bool, ok bool) {
+	if dueAt == nil {
+		return time.Time{}, false, false
+	}
+	if t, err := time.Parse(time.RFC3339, *dueAt); err == nil {
+		return t, false, true
+	}
+	date, err := time.Parse(models.DueDateLayout, *dueAt)
+	if err != nil {
+		return time.Time{}, false, false
+	}
+	return models.AllDayDue(date, loc), true, true
+}
@@ -0,0 +1,360 @@
+package max
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/singl3focus/uniflow/internal/core/models"
+)
+
+// quickAddDraft - задача, разобранная из свободного текста сообщения
+type quickAddDraft struct {
+	Title       string
+	Tags        []string
+	Priority    models.TaskPriority
+	Context     *models.Context
+	ContextName string // Название после @, если такой контекст не найден
+	DueAt       *time.Time
+	AllDay      bool // Срок указан днем без времени
+}
+
+// quickDue - найденные в тексте части срока; собираются в дату после разбора всего сообщения
+type quickDue struct {
+	day     *time.Time // Полночь выбранного дня
+	exact   *time.Time // Точный момент: "через 2 часа"
+	hour    int
+	minute  int
+	hasTime bool
+	dayPart bool // Время задано словом "утром", "вечером" и т.п.
+}
+
+var (
+	quickTimePattern = regexp.MustCompile(`^([01]?\d|2[0-3]):([0-5]\d)$`)
+	quickHourPattern = regexp.MustCompile(`^([01]?\d|2[0-3])$`)
+	quickDatePattern = regexp.MustCompile(`^\d{1,2}\.\d{1,2}(\.\d{4})?$`)
+)
+
+// quickPrepositions - предлоги перед датой или временем, которые убираются из названия вместе с ними
+var quickPrepositions = map[string]bool{"в": true, "во": true, "до": true, "к": true, "ко": true, "на": true}
+
+var quickRelativeDays = map[string]int{"сегодня": 0, "завтра": 1, "послезавтра": 2}
+
+// quickWeekdays - формы дней недели после предлога: "в пятницу", "до пятницы", "к пятнице"
+var quickWeekdays = map[string]models.Weekday{
+	"пн": models.Monday, "понедельник": models.Monday, "понедельника": models.Monday, "понедельнику": models.Monday,
+	"вт": models.Tuesday, "вторник": models.Tuesday, "вторника": models.Tuesday, "вторнику": models.Tuesday,
+	"ср": models.Wednesday, "среду": models.Wednesday, "среда": models.Wednesday, "среды": models.Wednesday, "среде": models.Wednesday,
+	"чт": models.Thursday, "четверг": models.Thursday, "четверга": models.Thursday, "четвергу": models.Thursday,
+	"пт": models.Friday, "пятницу": models.Friday, "пятница": models.Friday, "пятницы": models.Friday, "пятнице": models.Friday,
+	"сб": models.Saturday, "субботу": models.Saturday, "суббота": models.Saturday, "субботы": models.Saturday, "субботе": models.Saturday,
+	"вс": models.Sunday, "воскресенье": models.Sunday, "воскресенья": models.Sunday, "воскресенью": models.Sunday,
+}
+
+// quickDayParts - время по умолчанию для слов "утром", "днем", "вечером"
+var quickDayParts = map[string]int{"утром": 9, "днем": 13, "днём": 13, "вечером": 19}
+
+var quickNumbers = map[string]int{
+	"один": 1, "одну": 1, "пару": 2, "два": 2, "две": 2, "три": 3, "четыре": 4,
+	"пять": 5, "шесть": 6, "семь": 7, "десять": 10,
+}
+
+// quickHourSuffixes - слова после часа: "в 7 вечера", "в 10 часов"
+var quickHourSuffixes = map[string]bool{
+	"ч": true, "час": true, "часа": true, "часов": true, "утра": true, "дня": true, "вечера": true, "ночи": true,
+}
+
+var quickPriorities = map[string]models.TaskPriority{
+	"низкий": models.TaskPriorityLow, "low": models.TaskPriorityLow,
+	"обычный": models.TaskPriorityNormal, "normal": models.TaskPriorityNormal,
+	"высокий": models.TaskPriorityHigh, "важно": models.TaskPriorityHigh, "важный": models.TaskPriorityHigh, "high": models.TaskPriorityHigh,
+	"срочно": models.TaskPriorityUrgent, "срочный": models.TaskPriorityUrgent, "urgent": models.TaskPriorityUrgent,
+}
+
+// parseQuickAdd разбирает сообщение вида "сдать лабу по физике завтра в 18:00 #физика !высокий":
+// #теги, !приоритет, @контекст, дату и время ("завтра", "в пятницу", "через 2 недели", "25.12 в 9:30").
+// Контекст без @ угадывается по упоминанию его названия в тексте. Все, что не распознано, остается в названии.
+// now задает текущий момент в часовом поясе пользователя.
+func parseQuickAdd(text string, now time.Time, contexts []models.Context) quickAddDraft {
+	draft := quickAddDraft{Priority: models.TaskPriorityNormal}
+
+	withoutTags, tags := parseHashtags(text)
+	draft.Tags = tags
+
+	tokens := strings.Fields(withoutTags)
+	lower := make([]string, len(tokens))
+	for i, tok := range tokens {
+		lower[i] = quickToken(tok)
+	}
+
+	var due quickDue
+	var priorityFound bool
+	var title []string
+
+	for i := 0; i < len(tokens); {
+		w := lower[i]
+
+		switch {
+		case strings.HasPrefix(w, "!") && !priorityFound:
+			if p, ok := parseQuickPriority(w); ok {
+				draft.Priority = p
+				priorityFound = true
+				i++
+				continue
+			}
+		case strings.HasPrefix(w, "@") && len(w) > 1 && draft.Context == nil && draft.ContextName == "":
+			name := strings.TrimPrefix(tokens[i], "@")
+			if c, ok := findContextByName(contexts, name); ok {
+				draft.Context = &c
+			} else {
+				draft.ContextName = name
+			}
+			i++
+			continue
+		}
+
+		if n := due.match(lower, i, now); n > 0 {
+			i += n
+			continue
+		}
+
+		title = append(title, tokens[i])
+		i++
+	}
+
+	draft.Title = strings.Join(title, " ")
+	if draft.Title == "" {
+		draft.Title = strings.TrimSpace(withoutTags)
+	}
+
+	if draft.Context == nil && draft.ContextName == "" {
+		if c, ok := findMentionedContext(contexts, draft.Title); ok {
+			draft.Context = &c
+		}
+	}
+
+	draft.DueAt, draft.AllDay = due.resolve(now)
+
+	return draft
+}
+
+// quickToken приводит слово к виду для распознавания: нижний регистр без знаков препинания в конце,
+// чтобы "завтра." в конце предложения оставалось датой. У !приоритета восклицательные знаки - часть слова.
+func quickToken(tok string) string {
+	tok = strings.ToLower(tok)
+	if strings.HasPrefix(tok, "!") {
+		return strings.TrimRight(tok, ",;.?")
+	}
+	return strings.TrimRight(tok, ",;.!?")
+}
+
+// match пробует распознать срок, начиная с токена i; возвращает количество использованных токенов
+func (d *quickDue) match(w []string, i int, now time.Time) int {
+	// "через N единиц" - без предлога
+	if w[i] == "через" {
+		return d.matchRelative(w, i, now)
+	}
+
+	if hour, ok := quickDayParts[w[i]]; ok && !d.hasTime {
+		d.hour, d.minute, d.hasTime, d.dayPart = hour, 0, true, true
+		return 1
+	}
+
+	j := i
+	if quickPrepositions[w[i]] && i+1 < len(w) {
+		j = i + 1
+	}
+	withPrep := j > i
+	used := j - i + 1
+
+	if n, ok := quickRelativeDays[w[j]]; ok && d.day == nil {
+		day := startOfDay(now).AddDate(0, 0, n)
+		d.day = &day
+		return used
+	}
+
+	if weekday, ok := quickWeekdays[w[j]]; ok && withPrep && d.day == nil {
+		today := startOfDay(now)
+		shift := (int(weekday)-int(models.WeekdayOf(today))+6)%7 + 1 // Ближайший такой день после сегодняшнего
+		day := today.AddDate(0, 0, shift)
+		d.day = &day
+		return used
+	}
+
+	if quickDatePattern.MatchString(w[j]) && d.day == nil {
+		if due, _, ok := parseDueInput(padDateParts(w[j]), now); ok {
+			day := startOfDay(due)
+			d.day = &day
+			return used
+		}
+	}
+
+	if m := quickTimePattern.FindStringSubmatch(w[j]); m != nil && (!d.hasTime || d.dayPart) {
+		d.hour, _ = strconv.Atoi(m[1])
+		d.minute, _ = strconv.Atoi(m[2])
+		d.hasTime, d.dayPart = true, false
+		return used
+	}
+
+	// Одно число - час, только после "в"/"к": "в 18", "к 9 утра"
+	if m := quickHourPattern.FindStringSubmatch(w[j]); m != nil && withPrep && (w[i] == "в" || w[i] == "к") && (!d.hasTime || d.dayPart) {
+		suffix := ""
+		if j+1 < len(w) {
+			suffix = w[j+1]
+		}
+		if suffix != "" && !quickHourSuffixes[suffix] {
+			return 0
+		}
+
+		hour, _ := strconv.Atoi(m[1])
+		if (suffix == "вечера" || suffix == "дня") && hour < 12 {
+			hour += 12
+		}
+		d.hour, d.minute, d.hasTime, d.dayPart = hour, 0, true, false
+		if suffix != "" {
+			used++
+		}
+		return used
+	}
+
+	return 0
+}
+
+// matchRelative разбирает "через 2 недели", "через час", "через три дня"
+func (d *quickDue) matchRelative(w []string, i int, now time.Time) int {
+	if d.day != nil || d.exact != nil {
+		return 0
+	}
+
+	j := i + 1
+	amount := 1
+	if j < len(w) {
+		if n, err := strconv.Atoi(w[j]); err == nil && n > 0 {
+			amount = n
+			j++
+		} else if n, ok := quickNumbers[w[j]]; ok {
+			amount = n
+			j++
+		}
+	}
+	if j >= len(w) {
+		return 0
+	}
+
+	today := startOfDay(now)
+	var day, exact time.Time
+	switch w[j] {
+	case "минуту", "минуты", "минут", "мин":
+		exact = now.Add(time.Duration(amount) * time.Minute)
+	case "час", "часа", "часов":
+		exact = now.Add(time.Duration(amount) * time.Hour)
+	case "день", "дня", "дней":
+		day = today.AddDate(0, 0, amount)
+	case "неделю", "недели", "недель":
+		day = today.AddDate(0, 0, 7*amount)
+	case "месяц", "месяца", "месяцев":
+		day = today.AddDate(0, amount, 0)
+	default:
+		return 0
+	}
+
+	if !exact.IsZero() {
+		exact = exact.Truncate(time.Minute)
+		d.exact = &exact
+	} else {
+		d.day = &day
+	}
+	return j - i + 1
+}
+
+// resolve собирает срок: день без времени - на весь день (до 23:59), время без дня - ближайшее такое время
+func (d *quickDue) resolve(now time.Time) (due *time.Time, allDay bool) {
+	if d.exact != nil {
+		return d.exact, false
+	}
+	if d.day == nil && !d.hasTime {
+		return nil, false
+	}
+
+	hour, minute := 23, 59
+	if d.hasTime {
+		hour, minute = d.hour, d.minute
+	}
+
+	day := startOfDay(now)
+	if d.day != nil {
+		day = *d.day
+	}
+
+	at := time.Date(day.Year(), day.Month(), day.Day(), hour, minute, 0, 0, now.Location())
+	if d.day == nil && !at.After(now) {
+		at = at.AddDate(0, 0, 1)
+	}
+	return &at, !d.hasTime
+}
+
+// padDateParts дополняет день и месяц нулем: "5.3" -> "05.03", как ожидает parseDueInput
+func padDateParts(date string) string {
+	parts := strings.Split(date, ".")
+	for i := 0; i < len(parts) && i < 2; i++ {
+		if len(parts[i]) == 1 {
+			parts[i] = "0" + parts[i]
+		}
+	}
+	return strings.Join(parts, ".")
+}
+
+// parseQuickPriority разбирает приоритет: "!высокий", "!срочно", "!!" - высокий, "!!!" - срочный
+func parseQuickPriority(w string) (models.TaskPriority, bool) {
+	switch w {
+	case "!!":
+		return models.TaskPriorityHigh, true
+	case "!!!":
+		return models.TaskPriorityUrgent, true
+	}
+
+	p, ok := quickPriorities[strings.TrimPrefix(w, "!")]
+	return p, ok
+}
+
+// findMentionedContext ищет контекст, название которого упомянуто в тексте.
+// Однословные названия сравниваются по основе, чтобы "по физике" находило контекст "Физика".
+func findMentionedContext(contexts []models.Context, text string) (models.Context, bool) {
+	text = strings.ToLower(text)
+	words := strings.FieldsFunc(text, func(r rune) bool {
+		return !(r == '-' || 'а' <= r && r <= 'я' || r == 'ё' || 'a' <= r && r <= 'z' || '0' <= r && r <= '9')
+	})
+
+	for _, c := range contexts {
+		title := strings.ToLower(strings.TrimSpace(c.Title))
+		if title == "" {
+			continue
+		}
+
+		if strings.Contains(title, " ") {
+			if strings.Contains(text, title) {
+				return c, true
+			}
+			continue
+		}
+
+		stem := wordStem(title)
+		for _, w := range words {
+			if strings.HasPrefix(w, stem) {
+				return c, true
+			}
+		}
+	}
+
+	return models.Context{}, false
+}
+
+// wordStem отрезает от слова окончание из гласных, "й" и "ь", оставляя не меньше 4 букв
+func wordStem(word string) string {
+	runes := []rune(word)
+	for i := 0; i < 2 && len(runes) > 4 && strings.ContainsRune("аеёиоуыэюяйь", runes[len(runes)-1]); i++ {
+		runes = runes[:len(runes)-1]
+	}
+	return string(runes)
+}
@@ -0,0 +1,94 @@
+package max
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/singl3focus/uniflow/internal/core/models"
+)
+
+func TestParseQuickAdd(t *testing.T) {
+	msk, err := time.LoadLocation("Europe/Moscow")
+	if err != nil {
+		t.Skip("tzdata is not available:", err)
+	}
+	// Суббота, полдень
+	now := time.Date(2026, 10, 17, 12, 0, 0, 0, msk)
+	at := func(month time.Month, day, hour, minute int) *time.Time {
+		t := time.Date(2026, month, day, hour, minute, 0, 0, msk)
+		return &t
+	}
+
+	physics := models.Context{ID: uuid.New(), Title: "Физика"}
+	english := models.Context{ID: uuid.New(), Title: "Английский язык"}
+	contexts := []models.Context{physics, english}
+
+	tests := []struct {
+		text     string
+		title    string
+		due      *time.Time
+		priority models.TaskPriority
+		context  *models.Context
+		tags     []string
+	}{
+		{
+			text:     "сдать лабу по физике завтра в 18:00 #физика !высокий",
+			title:    "сдать лабу по физике",
+			due:      at(10, 18, 18, 0),
+			priority: models.TaskPriorityHigh,
+			context:  &physics,
+			tags:     []string{"физика"},
+		},
+		{
+			text:  "купить молоко",
+			title: "купить молоко",
+		},
+		// День без времени - до конца дня
+		{text: "эссе в пятницу", title: "эссе", due: at(10, 23, 23, 59)},
+		// Тот же день недели - следующая неделя
+		{text: "созвон в субботу в 10", title: "созвон", due: at(10, 24, 10, 0)},
+		{text: "курсовая через 2 недели", title: "курсовая", due: at(10, 31, 23, 59)},
+		{text: "позвонить маме через час", title: "позвонить маме", due: at(10, 17, 13, 0)},
+		// Прошедшее сегодня время - завтра
+		{text: "зарядка в 7:30", title: "зарядка", due: at(10, 18, 7, 30)},
+		{text: "отчет к 7 вечера !!!", title: "отчет", due: at(10, 17, 19, 0), priority: models.TaskPriorityUrgent},
+		// Знаки препинания в конце предложения не мешают распознать дату
+		{text: "эссе в пятницу.", title: "эссе", due: at(10, 23, 23, 59)},
+		{text: "сдать отчет завтра!", title: "сдать отчет", due: at(10, 18, 23, 59)},
+		{text: "позвонить в 18:00?", title: "позвонить", due: at(10, 17, 18, 0)},
+		{text: "отчет завтра, !срочно.", title: "отчет", due: at(10, 18, 23, 59), priority: models.TaskPriorityUrgent},
+		{text: "экзамен 5.1 утром", title: "экзамен", due: func() *time.Time { t := time.Date(2027, 1, 5, 9, 0, 0, 0, msk); return &t }()},
+		{text: "слова @англ", title: "слова", context: &english},
+		// Неизвестный контекст и непохожие на дату слова остаются в тексте
+		{text: "встреча в кафе @работа", title: "встреча в кафе"},
+		{text: "завтра", title: "завтра", due: at(10, 18, 23, 59)},
+	}
+
+	for _, tt := range tests {
+		got := parseQuickAdd(tt.text, now, contexts)
+
+		wantPriority := tt.priority
+		if wantPriority == "" {
+			wantPriority = models.TaskPriorityNormal
+		}
+
+		if got.Title != tt.title {
+			t.Errorf("parseQuickAdd(%q).Title = %q, want %q", tt.text, got.Title, tt.title)
+		}
+		if (got.DueAt == nil) != (tt.due == nil) || got.DueAt != nil && !got.DueAt.Equal(*tt.due) {
+			t.Errorf("parseQuickAdd(%q).DueAt = %v, want %v", tt.text, got.DueAt, tt.due)
+		}
+		if got.Priority != wantPriority {
+			t.Errorf("parseQuickAdd(%q).Priority = %q, want %q", tt.text, got.Priority, wantPriority)
+		}
+		if (got.Context == nil) != (tt.context == nil) || got.Context != nil && got.Context.ID != tt.context.ID {
+			t.Errorf("parseQuickAdd(%q).Context = %v, want %v", tt.text, got.Context, tt.context)
+		}
+		if !reflect.DeepEqual(got.Tags, tt.tags) {
+			t.Errorf("parseQuickAdd(%q).Tags = %v, want %v", tt.text, got.Tags, tt.tags)
+		}
+	}
+}
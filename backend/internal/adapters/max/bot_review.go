@@ -0,0 +1,66 @@
+package max
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/singl3focus/uniflow/internal/core/models"
+)
+
+// handleReviewCallback обрабатывает кнопки вечернего обзора: review_<действие>_<taskID>.
+// Результат показывается всплывающим уведомлением, чтобы не засорять чат при разборе списка.
+func (h *UniFlowUpdateHandler) handleReviewCallback(ctx context.Context, userID int64, callbackID string, parts []string) {
+	if len(parts) < 3 {
+		return
+	}
+
+	action := parts[1] // done, tomorrow, drop
+	taskID := parts[2]
+
+	maxUserID := fmt.Sprintf("%d", userID)
+	user, err := h.usecase.GetOrCreateUserByMaxID(ctx, maxUserID)
+	if err != nil {
+		h.logger.Error("failed to get user", "error", err)
+		h.answerCallback(ctx, callbackID, "❌ Ошибка при получении пользователя")
+		return
+	}
+	userIDStr := user.ID.String()
+
+	task, err := h.usecase.GetTaskByID(ctx, userIDStr, taskID)
+	if err != nil {
+		h.logger.Error("failed to get task", "error", err)
+		h.answerCallback(ctx, callbackID, "❌ Задача не найдена")
+		return
+	}
+	title := truncate(task.Title, 30)
+
+	switch action {
+	case "done":
+		if err = h.usecase.UpdateTaskStatus(ctx, userIDStr, taskID, models.TaskStatusCompleted); err != nil {
+			h.logger.Error("failed to complete task", "error", err)
+			h.answerCallback(ctx, callbackID, "❌ Не удалось завершить задачу")
+			return
+		}
+		h.answerCallback(ctx, callbackID, fmt.Sprintf("✅ «%s» выполнена", title))
+	case "tomorrow":
+		postponed, err := h.usecase.PostponeTaskToTomorrow(ctx, userIDStr, taskID)
+		if err != nil {
+			h.logger.Error("failed to postpone task", "error", err)
+			h.answerCallback(ctx, callbackID, "❌ Не удалось перенести задачу")
+			return
+		}
+		layout := "02.01 15:04"
+		if postponed.AllDay {
+			layout = "02.01"
+		}
+		h.answerCallback(ctx, callbackID, fmt.Sprintf("➡️ «%s» перенесена на %s", title,
+			postponed.DueAt.In(user.Settings.Location()).Format(layout)))
+	case "drop":
+		if err = h.usecase.UpdateTaskStatus(ctx, userIDStr, taskID, models.TaskStatusCancelled); err != nil {
+			h.logger.Error("failed to cancel task", "error", err)
+			h.answerCallback(ctx, callbackID, "❌ Не удалось отменить задачу")
+			return
+		}
+		h.answerCallback(ctx, callbackID, fmt.Sprintf("✖ «%s» отменена", title))
+	}
+}
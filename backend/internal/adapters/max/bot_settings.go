@@ -0,0 +1,340 @@
+package max
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/singl3focus/uniflow/internal/core/models"
+)
+
+// timezoneOption - часовой пояс, предлагаемый кнопкой; в callback передается индекс,
+// потому что в названиях IANA встречается "_"
+type timezoneOption struct {
+	Name     string
+	Location string
+}
+
+var timezoneOptions = []timezoneOption{
+	{"Калининград (UTC+2)", "Europe/Kaliningrad"},
+	{"Москва (UTC+3)", "Europe/Moscow"},
+	{"Самара (UTC+4)", "Europe/Samara"},
+	{"Екатеринбург (UTC+5)", "Asia/Yekaterinburg"},
+	{"Омск (UTC+6)", "Asia/Omsk"},
+	{"Новосибирск (UTC+7)", "Asia/Novosibirsk"},
+	{"Красноярск (UTC+7)", "Asia/Krasnoyarsk"},
+	{"Иркутск (UTC+8)", "Asia/Irkutsk"},
+	{"Якутск (UTC+9)", "Asia/Yakutsk"},
+	{"Владивосток (UTC+10)", "Asia/Vladivostok"},
+	{"Магадан (UTC+11)", "Asia/Magadan"},
+	{"Камчатка (UTC+12)", "Asia/Kamchatka"},
+}
+
+// reminderPreset - готовый набор напоминаний; в callback передаются минуты через точку
+type reminderPreset struct {
+	Name    string
+	Payload string // default - по умолчанию, none - без напоминаний
+}
+
+var reminderPresets = []reminderPreset{
+	{"По умолчанию", "default"},
+	{"За сутки и за час", "1440.60"},
+	{"За 3 часа и за 15 минут", "180.15"},
+	{"За час", "60"},
+	{"За 15 минут", "15"},
+	{"Без напоминаний", "none"},
+}
+
+// digestPresets - время сводок, предлагаемое кнопками
+var digestPresets = map[models.DigestKind][]string{
+	models.DigestKindMorning: {"07:00", "08:00", "09:00", "10:00"},
+	models.DigestKindEvening: {"20:00", "21:00", "22:00", "23:00"},
+}
+
+var languageNames = map[string]string{
+	"ru": "🇷🇺 Русский",
+	"en": "🇬🇧 English",
+}
+
+// userLocation возвращает часовой пояс пользователя MAX; при ошибке - пояс по умолчанию
+func (h *UniFlowUpdateHandler) userLocation(ctx context.Context, userID int64) *time.Location {
+	user, err := h.usecase.GetOrCreateUserByMaxID(ctx, fmt.Sprintf("%d", userID))
+	if err != nil {
+		h.logger.Error("failed to get user timezone", "error", err, "max_user_id", userID)
+		return models.DefaultUserSettings().Location()
+	}
+	return user.Settings.Location()
+}
+
+func (h *UniFlowUpdateHandler) handleSettingsCommand(ctx context.Context, userID int64) {
+	maxUserID := fmt.Sprintf("%d", userID)
+	user, err := h.usecase.GetOrCreateUserByMaxID(ctx, maxUserID)
+	if err != nil {
+		h.logger.Error("failed to get user", "error", err, "max_user_id", maxUserID)
+		h.sendMessage(ctx, userID, "❌ Ошибка при получении данных пользователя.")
+		return
+	}
+
+	h.showSettings(ctx, userID, user.Settings)
+}
+
+// showSettings отправляет текущие настройки с кнопками изменения
+func (h *UniFlowUpdateHandler) showSettings(ctx context.Context, userID int64, settings models.UserSettings) {
+	now := time.Now().In(settings.Location())
+
+	response := "⚙️ Настройки\n\n" +
+		fmt.Sprintf("🌍 Часовой пояс: %s (сейчас %s)\n", timezoneName(settings.Timezone), now.Format("15:04")) +
+		fmt.Sprintf("🗣 Язык: %s\n", languageNames[settings.Language]) +
+		fmt.Sprintf("📅 Неделя начинается с: %s\n", firstWeekdayName(settings.FirstWeekday)) +
+		fmt.Sprintf("⏰ Напоминания: %s\n", formatReminderOffsets(settings.ReminderOffsets)) +
+		fmt.Sprintf("🌅 Утренняя сводка: %s\n", formatDigestAt(settings, models.DigestKindMorning)) +
+		fmt.Sprintf("🌙 Вечерний обзор: %s", formatDigestAt(settings, models.DigestKindEvening))
+
+	h.sendMessageWithKeyboard(ctx, userID, response, h.buildSettingsKeyboard(settings))
+}
+
+// handleSettingsCallback обрабатывает кнопки настроек: settings_<настройка>[_<значение>]
+func (h *UniFlowUpdateHandler) handleSettingsCallback(ctx context.Context, userID int64, callbackID string, parts []string) {
+	field := parts[1] // tz, lang, week, rem, morning, evening, show
+	value := ""
+	if len(parts) > 2 {
+		value = parts[2]
+	}
+
+	maxUserID := fmt.Sprintf("%d", userID)
+	user, err := h.usecase.GetOrCreateUserByMaxID(ctx, maxUserID)
+	if err != nil {
+		h.logger.Error("failed to get user", "error", err)
+		h.answerCallback(ctx, callbackID, "❌ Ошибка при получении пользователя")
+		return
+	}
+	userIDStr := user.ID.String()
+
+	switch field {
+	case "show":
+		h.answerCallback(ctx, callbackID, "")
+		h.showSettings(ctx, userID, user.Settings)
+	case "tz":
+		switch value {
+		case "":
+			h.answerCallback(ctx, callbackID, "")
+			h.sendMessageWithKeyboard(ctx, userID, "🌍 Выбери часовой пояс:", h.buildTimezoneKeyboard())
+		case "input":
+			h.answerCallback(ctx, callbackID, "")
+			h.saveState(ctx, userID, &UserState{State: stateEditingSettings, Data: DialogData{Field: "tz"}})
+			h.sendMessage(ctx, userID, "🌍 Введи часовой пояс в формате IANA, например Europe/Berlin или Asia/Almaty\n\nДля отмены: /cancel")
+		default:
+			i, err := strconv.Atoi(value)
+			if err != nil || i < 0 || i >= len(timezoneOptions) {
+				h.answerCallback(ctx, callbackID, "❌ Неизвестный часовой пояс")
+				return
+			}
+			tz := timezoneOptions[i].Location
+			h.applySettings(ctx, userID, callbackID, userIDStr, settingsEdit{timezone: &tz})
+		}
+	case "lang":
+		lang := "en"
+		if user.Settings.Language == "en" {
+			lang = "ru"
+		}
+		h.applySettings(ctx, userID, callbackID, userIDStr, settingsEdit{language: &lang})
+	case "week":
+		weekday := models.Sunday
+		if user.Settings.FirstWeekday == models.Sunday {
+			weekday = models.Monday
+		}
+		h.applySettings(ctx, userID, callbackID, userIDStr, settingsEdit{firstWeekday: &weekday})
+	case "rem":
+		if value == "" {
+			h.answerCallback(ctx, callbackID, "")
+			h.sendMessageWithKeyboard(ctx, userID, "⏰ Когда напоминать о задачах с дедлайном?", h.buildReminderPresetsKeyboard())
+			return
+		}
+		offsets, ok := parseReminderPreset(value)
+		if !ok {
+			h.answerCallback(ctx, callbackID, "❌ Неизвестный вариант")
+			return
+		}
+		h.applySettings(ctx, userID, callbackID, userIDStr, settingsEdit{reminderOffsets: &offsets})
+	case "morning", "evening":
+		kind := models.DigestKind(field)
+		switch value {
+		case "":
+			h.answerCallback(ctx, callbackID, "")
+			h.sendMessageWithKeyboard(ctx, userID, digestQuestion(kind), h.buildDigestTimeKeyboard(kind))
+		case "input":
+			h.answerCallback(ctx, callbackID, "")
+			h.saveState(ctx, userID, &UserState{State: stateEditingSettings, Data: DialogData{Field: field}})
+			h.sendMessage(ctx, userID, "🕐 Введи время в формате ЧЧ:ММ, например 07:30\n\nДля отмены: /cancel")
+		default:
+			at := value
+			if value == "off" {
+				at = ""
+			}
+			h.applySettings(ctx, userID, callbackID, userIDStr, digestEdit(kind, at))
+		}
+	}
+}
+
+// settingsEdit - изменяемые настройки; nil означает, что настройка не меняется
+type settingsEdit struct {
+	timezone        *string
+	language        *string
+	firstWeekday    *models.Weekday
+	reminderOffsets *[]int
+	morningDigestAt *string
+	eveningReviewAt *string
+}
+
+// digestEdit задает время сводки вида kind; пустая строка выключает ее
+func digestEdit(kind models.DigestKind, at string) settingsEdit {
+	if kind == models.DigestKindEvening {
+		return settingsEdit{eveningReviewAt: &at}
+	}
+	return settingsEdit{morningDigestAt: &at}
+}
+
+// applySettings сохраняет изменение настроек и показывает их заново.
+// callbackID пуст, если значение введено сообщением.
+func (h *UniFlowUpdateHandler) applySettings(ctx context.Context, userID int64, callbackID, userIDStr string, edit settingsEdit) {
+	settings, err := h.usecase.UpdateUserSettings(ctx, userIDStr, edit.timezone, edit.language, edit.firstWeekday,
+		edit.reminderOffsets, edit.morningDigestAt, edit.eveningReviewAt)
+
+	msg := "✅ Настройки сохранены"
+	if err != nil {
+		h.logger.Error("failed to update settings", "error", err)
+		msg = "❌ Не удалось сохранить настройки"
+	}
+
+	if callbackID != "" {
+		h.answerCallback(ctx, callbackID, msg)
+	} else {
+		h.sendMessage(ctx, userID, msg)
+	}
+	if err != nil {
+		return
+	}
+
+	h.showSettings(ctx, userID, settings)
+}
+
+// handleEditingSettingsState принимает введенные вручную часовой пояс или время сводки
+func (h *UniFlowUpdateHandler) handleEditingSettingsState(ctx context.Context, userID int64, text string, state *UserState) {
+	field := state.Data.Field
+	if field != "tz" && field != string(models.DigestKindMorning) && field != string(models.DigestKindEvening) {
+		h.clearState(ctx, userID)
+		h.showMainMenu(ctx, userID)
+		return
+	}
+
+	maxUserID := fmt.Sprintf("%d", userID)
+	user, err := h.usecase.GetOrCreateUserByMaxID(ctx, maxUserID)
+	if err != nil {
+		h.logger.Error("failed to get user", "error", err, "max_user_id", maxUserID)
+		h.sendMessage(ctx, userID, "❌ Ошибка при получении данных пользователя.")
+		h.clearState(ctx, userID)
+		return
+	}
+
+	value := strings.TrimSpace(text)
+
+	if field != "tz" {
+		if _, err = time.Parse(models.DigestTimeLayout, value); err != nil {
+			h.sendMessage(ctx, userID, "❌ Не понял время. Пример: 07:30. Попробуй еще раз:")
+			return
+		}
+
+		h.clearState(ctx, userID)
+		h.applySettings(ctx, userID, "", user.ID.String(), digestEdit(models.DigestKind(field), value))
+		return
+	}
+
+	if err = (models.UserSettings{Timezone: value, Language: models.DefaultLanguage}).Validate(); err != nil {
+		h.sendMessage(ctx, userID, "❌ Не знаю такой часовой пояс. Пример: Europe/Berlin. Попробуй еще раз:")
+		return
+	}
+
+	h.clearState(ctx, userID)
+	h.applySettings(ctx, userID, "", user.ID.String(), settingsEdit{timezone: &value})
+}
+
+// parseReminderPreset разбирает вариант напоминаний из callback: default, none или минуты через точку
+func parseReminderPreset(value string) ([]int, bool) {
+	switch value {
+	case "default":
+		return nil, true
+	case "none":
+		return []int{}, true
+	}
+
+	var offsets []int
+	for _, s := range strings.Split(value, ".") {
+		m, err := strconv.Atoi(s)
+		if err != nil || m <= 0 {
+			return nil, false
+		}
+		offsets = append(offsets, m)
+	}
+	return offsets, true
+}
+
+// formatReminderOffsets описывает напоминания словами: "за 1 д, за 1 ч"
+func formatReminderOffsets(offsets []int) string {
+	if offsets == nil {
+		return "по умолчанию"
+	}
+	if len(offsets) == 0 {
+		return "выключены"
+	}
+
+	parts := make([]string, 0, len(offsets))
+	// Сначала самые ранние
+	for i := len(offsets) - 1; i >= 0; i-- {
+		m := offsets[i]
+		switch {
+		case m%(24*60) == 0:
+			parts = append(parts, fmt.Sprintf("за %d д", m/(24*60)))
+		case m%60 == 0:
+			parts = append(parts, fmt.Sprintf("за %d ч", m/60))
+		default:
+			parts = append(parts, fmt.Sprintf("за %d мин", m))
+		}
+	}
+	return strings.Join(parts, ", ")
+}
+
+// formatDigestAt описывает время сводки или сообщает, что она выключена
+func formatDigestAt(settings models.UserSettings, kind models.DigestKind) string {
+	if at, ok := settings.DigestAt(kind); ok {
+		return "в " + at
+	}
+	if kind == models.DigestKindEvening {
+		return "выключен"
+	}
+	return "выключена"
+}
+
+func digestQuestion(kind models.DigestKind) string {
+	if kind == models.DigestKindEvening {
+		return "🌙 Во сколько присылать вечерний обзор незавершенных задач?"
+	}
+	return "🌅 Во сколько присылать утреннюю сводку задач и занятий?"
+}
+
+func timezoneName(tz string) string {
+	for _, opt := range timezoneOptions {
+		if opt.Location == tz {
+			return opt.Name
+		}
+	}
+	return tz
+}
+
+func firstWeekdayName(w models.Weekday) string {
+	if w == models.Sunday {
+		return "воскресенья"
+	}
+	return "понедельника"
+}
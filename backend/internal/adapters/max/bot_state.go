@@ -0,0 +1,90 @@
+package max
+
+import (
+	"context"
+	"time"
+)
+
+// stateExpiryInterval - как часто искать брошенные диалоги
+const stateExpiryInterval = time.Minute
+
+// getState возвращает текущий диалог пользователя. Ошибка хранилища
+// логируется и трактуется как отсутствие диалога.
+func (h *UniFlowUpdateHandler) getState(ctx context.Context, userID int64) (*UserState, bool) {
+	state, ok, err := h.states.Get(ctx, userID)
+	if err != nil {
+		h.logger.Error("failed to get dialog state", "error", err, "user_id", userID)
+		return nil, false
+	}
+
+	return state, ok
+}
+
+// saveState сохраняет диалог и продлевает его жизнь. Вызывается после каждого
+// изменения state: хранилище не видит изменений, сделанных по указателю.
+func (h *UniFlowUpdateHandler) saveState(ctx context.Context, userID int64, state *UserState) {
+	state.LastUpdate = time.Now()
+	if err := h.states.Set(ctx, userID, state); err != nil {
+		h.logger.Error("failed to save dialog state", "error", err, "user_id", userID, "state", state.State)
+	}
+}
+
+func (h *UniFlowUpdateHandler) clearState(ctx context.Context, userID int64) {
+	if err := h.states.Delete(ctx, userID); err != nil {
+		h.logger.Error("failed to delete dialog state", "error", err, "user_id", userID)
+	}
+}
+
+// RunStateExpiry отменяет диалоги, в которых пользователь не отвечал дольше dialogTTL,
+// и сообщает ему об этом. Работает до отмены ctx.
+func (h *UniFlowUpdateHandler) RunStateExpiry(ctx context.Context) error {
+	ticker := time.NewTicker(stateExpiryInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+			h.expireStates(ctx)
+		}
+	}
+}
+
+func (h *UniFlowUpdateHandler) expireStates(ctx context.Context) {
+	expired, err := h.states.TakeExpired(ctx, time.Now().Add(-h.dialogTTL))
+	if err != nil {
+		h.logger.Error("failed to take expired dialog states", "error", err)
+	}
+
+	for userID, state := range expired {
+		h.logger.Info("dialog expired", "user_id", userID, "state", state.State)
+		h.sendMessageWithKeyboard(ctx, userID, dialogTimeoutMessage(state.State), h.buildMainMenuKeyboard())
+	}
+}
+
+// dialogTimeoutMessage объясняет пользователю, какой диалог отменен и как начать заново
+func dialogTimeoutMessage(state string) string {
+	const suffix = ": время ожидания ответа истекло, действие отменено."
+
+	switch state {
+	case stateCreatingTask:
+		return "⌛ Создание задачи" + suffix + "\n\nЧтобы начать заново, используй /newtask"
+	case stateCreatingContext:
+		return "⌛ Создание контекста" + suffix + "\n\nЧтобы начать заново, используй /newcontext"
+	case stateEditingTask:
+		return "⌛ Редактирование задачи" + suffix + "\n\nОткрой задачу еще раз, чтобы продолжить"
+	case stateEditingContext:
+		return "⌛ Редактирование контекста" + suffix + "\n\nОткрой контекст еще раз, чтобы продолжить"
+	case stateEditingSettings:
+		return "⌛ Изменение настроек" + suffix + "\n\nЧтобы продолжить, используй /settings"
+	case stateAddingSubtasks:
+		return "⌛ Добавление пунктов чек-листа" + suffix + "\n\nОткрой задачу еще раз, чтобы продолжить"
+	case stateSearching:
+		return "⌛ Поиск" + suffix + "\n\nЧтобы поискать снова, используй /search"
+	case stateQuickAdd:
+		return "⌛ Черновик задачи" + suffix + "\n\nЧтобы создать задачу, отправь ее текст еще раз"
+	default:
+		return "⌛ Время ожидания ответа истекло, действие отменено."
+	}
+}
@@ -0,0 +1,345 @@
+package max
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/singl3focus/uniflow/internal/core/models"
+)
+
+// Редактируемые поля задачи в callback'ах edit_<поле>_<task_id>[_<значение>]
+const (
+	editFieldTitle   = "title"
+	editFieldDesc    = "desc"
+	editFieldContext = "ctx"
+	editFieldDue     = "due"
+	editFieldStatus  = "status"
+)
+
+// Короткие коды статусов для callback'ов: в in_progress есть "_", который разделяет части payload
+var editStatusCodes = map[string]models.TaskStatus{
+	"todo":     models.TaskStatusTodo,
+	"progress": models.TaskStatusInProgress,
+	"done":     models.TaskStatusCompleted,
+	"cancel":   models.TaskStatusCancelled,
+}
+
+// Форматы ручного ввода срока, от более точного к менее точному
+var dueInputLayouts = []struct {
+	layout   string
+	withYear bool
+	withTime bool
+}{
+	{"02.01.2006 15:04", true, true},
+	{"02.01.2006", true, false},
+	{"02.01 15:04", false, true},
+	{"02.01", false, false},
+}
+
+// handleEditTask показывает выбор поля для редактирования задачи
+func (h *UniFlowUpdateHandler) handleEditTask(ctx context.Context, userID int64, callbackID, taskID, userIDStr string) {
+	task, err := h.usecase.GetTaskByID(ctx, userIDStr, taskID)
+	if err != nil {
+		h.answerCallback(ctx, callbackID, "❌ Задача не найдена")
+		return
+	}
+
+	h.answerCallback(ctx, callbackID, "")
+
+	// Незаконченный ввод другого поля отменяется
+	h.clearState(ctx, userID)
+
+	h.sendMessageWithKeyboard(ctx, userID,
+		fmt.Sprintf("✏️ Редактирование задачи «%s»\n\nЧто изменить?", task.Title),
+		h.buildTaskEditKeyboard(taskID))
+}
+
+// handleEditCallback обрабатывает выбор поля и значения в диалоге редактирования задачи
+func (h *UniFlowUpdateHandler) handleEditCallback(ctx context.Context, userID int64, callbackID string, parts []string) {
+	if len(parts) < 3 {
+		return
+	}
+
+	field := parts[1]  // title, desc, ctx, due, status
+	taskID := parts[2] // ID задачи
+	value := ""        // Выбранное значение, если оно пришло кнопкой
+	if len(parts) > 3 {
+		value = parts[3]
+	}
+
+	maxUserID := fmt.Sprintf("%d", userID)
+	user, err := h.usecase.GetOrCreateUserByMaxID(ctx, maxUserID)
+	if err != nil {
+		h.logger.Error("failed to get user", "error", err)
+		h.answerCallback(ctx, callbackID, "❌ Ошибка при получении пользователя")
+		return
+	}
+	userIDStr := user.ID.String()
+
+	switch field {
+	case editFieldTitle:
+		h.askEditText(ctx, userID, callbackID, taskID, userIDStr, editFieldTitle)
+	case editFieldDesc:
+		h.askEditText(ctx, userID, callbackID, taskID, userIDStr, editFieldDesc)
+	case editFieldContext:
+		if value == "" {
+			h.showEditContext(ctx, userID, callbackID, taskID, userIDStr)
+			return
+		}
+		contextID := value
+		if value == "none" {
+			contextID = ""
+		}
+		h.applyTaskEdit(ctx, userID, callbackID, taskID, userIDStr, taskEdit{contextID: &contextID})
+	case editFieldDue:
+		switch value {
+		case "":
+			h.showEditDue(ctx, userID, callbackID, taskID, userIDStr)
+		case "input":
+			h.askEditText(ctx, userID, callbackID, taskID, userIDStr, editFieldDue)
+		case "none":
+			dueAt := ""
+			h.applyTaskEdit(ctx, userID, callbackID, taskID, userIDStr, taskEdit{dueAt: &dueAt})
+		default:
+			days, err := strconv.Atoi(value)
+			if err != nil {
+				h.answerCallback(ctx, callbackID, "❌ Неверный формат даты")
+				return
+			}
+			// День выбран пресетом, время - следующим шагом
+			h.answerCallback(ctx, callbackID, "")
+			day := startOfDay(time.Now().In(h.userLocation(ctx, userID))).AddDate(0, 0, days)
+			h.showTimePicker(ctx, userID, calendarTarget{kind: calendarTask, id: taskID}, day)
+		}
+	case editFieldStatus:
+		if value == "" {
+			h.showEditStatus(ctx, userID, callbackID, taskID, userIDStr)
+			return
+		}
+		status, ok := editStatusCodes[value]
+		if !ok {
+			h.answerCallback(ctx, callbackID, "❌ Неизвестный статус")
+			return
+		}
+		h.applyTaskEdit(ctx, userID, callbackID, taskID, userIDStr, taskEdit{status: &status})
+	}
+}
+
+// askEditText просит ввести новое значение текстового поля задачи
+func (h *UniFlowUpdateHandler) askEditText(ctx context.Context, userID int64, callbackID, taskID, userIDStr, field string) {
+	task, err := h.usecase.GetTaskByID(ctx, userIDStr, taskID)
+	if err != nil {
+		h.answerCallback(ctx, callbackID, "❌ Задача не найдена")
+		return
+	}
+
+	h.answerCallback(ctx, callbackID, "")
+
+	h.saveState(ctx, userID, &UserState{
+		State: stateEditingTask,
+		Data:  DialogData{TaskID: taskID, Field: field},
+	})
+
+	var prompt string
+	switch field {
+	case editFieldTitle:
+		prompt = fmt.Sprintf("✏️ Текущее название: %s\n\nВведи новое название:", task.Title)
+	case editFieldDesc:
+		current := task.Description
+		if current == "" {
+			current = "нет"
+		}
+		prompt = fmt.Sprintf("📄 Текущее описание: %s\n\nВведи новое описание (или \"-\", чтобы удалить):", current)
+	case editFieldDue:
+		prompt = "⏰ Введи срок в формате ДД.ММ.ГГГГ ЧЧ:ММ\n\nГод и время можно опустить: 25.12 или 25.12 18:00"
+	}
+
+	h.sendMessage(ctx, userID, prompt+"\n\nДля отмены: /cancel")
+}
+
+// showEditContext предлагает выбрать контекст задачи
+func (h *UniFlowUpdateHandler) showEditContext(ctx context.Context, userID int64, callbackID, taskID, userIDStr string) {
+	contexts, err := h.usecase.GetContextsByUserID(ctx, userIDStr)
+	if err != nil {
+		h.logger.Error("failed to get contexts", "error", err)
+		h.answerCallback(ctx, callbackID, "❌ Ошибка при получении контекстов")
+		return
+	}
+
+	h.answerCallback(ctx, callbackID, "")
+	h.sendMessageWithKeyboard(ctx, userID, "📂 Выбери контекст задачи:", h.buildEditContextKeyboard(taskID, contexts))
+}
+
+// showEditDue предлагает выбрать новый срок задачи
+func (h *UniFlowUpdateHandler) showEditDue(ctx context.Context, userID int64, callbackID, taskID, userIDStr string) {
+	task, err := h.usecase.GetTaskByID(ctx, userIDStr, taskID)
+	if err != nil {
+		h.answerCallback(ctx, callbackID, "❌ Задача не найдена")
+		return
+	}
+
+	h.answerCallback(ctx, callbackID, "")
+
+	response := "⏰ Срок не установлен\n\nВыбери новый срок:"
+	if task.DueAt != nil {
+		response = fmt.Sprintf("⏰ Текущий срок: %s\n\nВыбери новый срок:", formatTaskDue(task, h.userLocation(ctx, userID)))
+	}
+	h.sendMessageWithKeyboard(ctx, userID, response, h.buildEditDueKeyboard(taskID, task.DueAt != nil))
+}
+
+// showEditStatus предлагает выбрать статус задачи
+func (h *UniFlowUpdateHandler) showEditStatus(ctx context.Context, userID int64, callbackID, taskID, userIDStr string) {
+	task, err := h.usecase.GetTaskByID(ctx, userIDStr, taskID)
+	if err != nil {
+		h.answerCallback(ctx, callbackID, "❌ Задача не найдена")
+		return
+	}
+
+	h.answerCallback(ctx, callbackID, "")
+	h.sendMessageWithKeyboard(ctx, userID,
+		fmt.Sprintf("📌 Текущий статус: %s\n\nВыбери новый статус:", taskStatusName(task.Status)),
+		h.buildEditStatusKeyboard(taskID))
+}
+
+// taskEdit - изменяемые поля задачи; nil означает, что поле не меняется
+type taskEdit struct {
+	contextID   *string
+	title       *string
+	description *string
+	dueAt       *string
+	status      *models.TaskStatus
+}
+
+// applyTaskEdit сохраняет изменение задачи и показывает обновленную карточку.
+// callbackID пуст, если значение введено сообщением.
+func (h *UniFlowUpdateHandler) applyTaskEdit(ctx context.Context, userID int64, callbackID, taskID, userIDStr string, edit taskEdit) {
+	task, err := h.usecase.UpdateTask(ctx, userIDStr, taskID, edit.contextID, edit.title, edit.description, edit.dueAt, edit.status, nil, nil)
+	if err != nil {
+		msg := "❌ Ошибка при обновлении задачи"
+		if errors.Is(err, models.ErrRecurrenceWithoutDueDate) {
+			msg = "⚠️ У повторяющейся задачи должен быть срок"
+		} else {
+			h.logger.Error("failed to update task", "error", err, "task_id", taskID)
+		}
+
+		if callbackID != "" {
+			h.answerCallback(ctx, callbackID, msg)
+		} else {
+			h.sendMessage(ctx, userID, msg)
+		}
+		return
+	}
+
+	if callbackID != "" {
+		h.answerCallback(ctx, callbackID, "✅ Задача обновлена")
+	} else {
+		h.sendMessage(ctx, userID, "✅ Задача обновлена")
+	}
+	h.showTaskDetails(ctx, userID, task)
+}
+
+// handleEditingTaskState принимает введенное значение редактируемого поля задачи
+func (h *UniFlowUpdateHandler) handleEditingTaskState(ctx context.Context, userID int64, text string, state *UserState) {
+	maxUserID := fmt.Sprintf("%d", userID)
+	user, err := h.usecase.GetOrCreateUserByMaxID(ctx, maxUserID)
+	if err != nil {
+		h.logger.Error("failed to get user", "error", err, "max_user_id", maxUserID)
+		h.sendMessage(ctx, userID, "❌ Ошибка при получении данных пользователя.")
+		h.clearState(ctx, userID)
+		return
+	}
+
+	var edit taskEdit
+	switch state.Data.Field {
+	case editFieldTitle:
+		if text == "" {
+			h.sendMessage(ctx, userID, "❌ Название не может быть пустым. Попробуй еще раз:")
+			return
+		}
+		edit.title = &text
+	case editFieldDesc:
+		if text == "-" {
+			text = ""
+		}
+		edit.description = &text
+	case editFieldDue:
+		dueAt, allDay, ok := parseDueInput(text, time.Now().In(h.userLocation(ctx, userID)))
+		if !ok {
+			h.sendMessage(ctx, userID, "❌ Не понял дату. Пример: 25.12.2026 18:00 или 25.12")
+			return
+		}
+		dueAtStr := dueValue(dueAt, allDay)
+		edit.dueAt = &dueAtStr
+	default:
+		h.clearState(ctx, userID)
+		h.showMainMenu(ctx, userID)
+		return
+	}
+
+	h.clearState(ctx, userID)
+	h.applyTaskEdit(ctx, userID, "", state.Data.TaskID, user.ID.String(), edit)
+}
+
+// parseDueInput разбирает срок, введенный вручную. Без времени срок - на весь день (allDay, до 23:59),
+// без года - ближайшая такая дата, начиная с сегодняшней.
+func parseDueInput(text string, now time.Time) (due time.Time, allDay bool, ok bool) {
+	text = strings.TrimSpace(text)
+
+	for _, f := range dueInputLayouts {
+		t, err := time.ParseInLocation(f.layout, text, now.Location())
+		if err != nil {
+			continue
+		}
+
+		year := t.Year()
+		if !f.withYear {
+			year = now.Year()
+		}
+		hour, minute := t.Hour(), t.Minute()
+		if !f.withTime {
+			hour, minute = 23, 59
+		}
+
+		due = time.Date(year, t.Month(), t.Day(), hour, minute, 0, 0, now.Location())
+		if !f.withYear && due.Before(startOfDay(now)) {
+			due = due.AddDate(1, 0, 0)
+		}
+		// Без года дата разбирается в високосном году 0: 29.02 в обычном году time.Date превращает в 01.03
+		if due.Month() != t.Month() || due.Day() != t.Day() {
+			return time.Time{}, false, false
+		}
+		return due, !f.withTime, true
+	}
+
+	return time.Time{}, false, false
+}
+
+// dueValue возвращает срок задачи в виде для usecase: дату для срока на весь день, иначе RFC 3339
+func dueValue(due time.Time, allDay bool) string {
+	if allDay {
+		return due.Format(models.DueDateLayout)
+	}
+	return due.Format(time.RFC3339)
+}
+
+func startOfDay(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, t.Location())
+}
+
+// taskStatusName возвращает название статуса задачи для пользователя
+func taskStatusName(s models.TaskStatus) string {
+	switch s {
+	case models.TaskStatusInProgress:
+		return "🔄 В процессе"
+	case models.TaskStatusCompleted:
+		return "✅ Завершена"
+	case models.TaskStatusCancelled:
+		return "🚫 Отменена"
+	default:
+		return "⭕ К выполнению"
+	}
+}
@@ -0,0 +1,37 @@
+package max
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParseDueInput(t *testing.T) {
+	now := time.Date(2026, 10, 17, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		text   string
+		want   time.Time
+		allDay bool
+		ok     bool
+	}{
+		{"25.12.2026 18:00", time.Date(2026, 12, 25, 18, 0, 0, 0, time.UTC), false, true},
+		{"25.12.2026", time.Date(2026, 12, 25, 23, 59, 0, 0, time.UTC), true, true},
+		{"20.10 9:30", time.Date(2026, 10, 20, 9, 30, 0, 0, time.UTC), false, true},
+		{"17.10", time.Date(2026, 10, 17, 23, 59, 0, 0, time.UTC), true, true},
+		// Прошедшая дата без года переносится на следующий год
+		{"01.02", time.Date(2027, 2, 1, 23, 59, 0, 0, time.UTC), true, true},
+		{"завтра", time.Time{}, false, false},
+		{"32.01", time.Time{}, false, false},
+		// 29 февраля нет ни в 2026, ни в 2027 году
+		{"29.02", time.Time{}, false, false},
+		{"29.02 10:00", time.Time{}, false, false},
+		{"29.02.2028", time.Date(2028, 2, 29, 23, 59, 0, 0, time.UTC), true, true},
+	}
+
+	for _, tt := range tests {
+		got, allDay, ok := parseDueInput(tt.text, now)
+		if ok != tt.ok || allDay != tt.allDay || !got.Equal(tt.want) {
+			t.Errorf("parseDueInput(%q) = %v, %v, %v; want %v, %v, %v", tt.text, got, allDay, ok, tt.want, tt.allDay, tt.ok)
+		}
+	}
+}
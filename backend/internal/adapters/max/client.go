@@ -1,18 +1,32 @@
 package max
 
 import (
+	"bytes"
 	"context"
+	"encoding/json"
 	"fmt"
 	"log"
+	"net/http"
+	"net/url"
+	"time"
 
 	maxbot "github.com/max-messenger/max-bot-api-client-go"
 	"github.com/max-messenger/max-bot-api-client-go/schemes"
 )
 
+// maxAPIURL - адрес MAX Bot API и версия протокола, с которыми работает maxbot.Api
+const (
+	maxAPIURL     = "https://botapi.max.ru/"
+	maxAPIVersion = "1.2.5"
+	// maxAPITimeout ограничивает запросы, которые Client отправляет в обход maxbot.Api
+	maxAPITimeout = 30 * time.Second
+)
+
 // Client обертка над MAX Bot API
 type Client struct {
-	api   *maxbot.Api
-	token string
+	api        *maxbot.Api
+	httpClient *http.Client
+	token      string
 }
 
 // NewClient создает новый MAX клиент
@@ -27,8 +41,9 @@ func NewClient(token string) (*Client, error) {
 	}
 
 	return &Client{
-		api:   api,
-		token: token,
+		api:        api,
+		httpClient: &http.Client{Timeout: maxAPITimeout},
+		token:      token,
 	}, nil
 }
 
@@ -41,6 +56,15 @@ func (c *Client) SendMessage(ctx context.Context, userID int64, text string) err
 	return nil
 }
 
+// SendMessageWithKeyboard отправляет пользователю сообщение с inline-клавиатурой
+func (c *Client) SendMessageWithKeyboard(ctx context.Context, userID int64, text string, keyboard *maxbot.Keyboard) error {
+	msg := maxbot.NewMessage().SetUser(userID).SetText(text).AddKeyboard(keyboard)
+	if _, err := c.api.Messages.Send(ctx, msg); err != nil {
+		return fmt.Errorf("failed to send message: %w", err)
+	}
+	return nil
+}
+
 // SendMessageToChat отправляет сообщение в чат
 func (c *Client) SendMessageToChat(ctx context.Context, chatID int64, text string) error {
 	msg := maxbot.NewMessage().SetChat(chatID).SetText(text)
@@ -55,8 +79,9 @@ func (c *Client) GetUpdates(ctx context.Context) <-chan schemes.UpdateInterface
 	return c.api.GetUpdates(ctx)
 }
 
-// SetWebhook устанавливает webhook для получения обновлений
-func (c *Client) SetWebhook(ctx context.Context, webhookURL string) error {
+// SetWebhook устанавливает webhook для получения обновлений. Непустой secret MAX
+// будет присылать в заголовке WebhookSecretHeader каждого запроса.
+func (c *Client) SetWebhook(ctx context.Context, webhookURL, secret string) error {
 	// Получаем список существующих подписок
 	subs, err := c.api.Subscriptions.GetSubscriptions(ctx)
 	if err != nil {
@@ -70,9 +95,12 @@ func (c *Client) SetWebhook(ctx context.Context, webhookURL string) error {
 		}
 	}
 
-	// Создаем новую подписку
-	updateTypes := []string{} // Пустой массив означает подписку на все типы обновлений
-	if _, err := c.api.Subscriptions.Subscribe(ctx, webhookURL, updateTypes); err != nil {
+	// Создаем новую подписку; без списка типов MAX присылает обновления всех типов
+	if err := c.subscribe(ctx, schemes.SubscriptionRequestBody{
+		Url:     webhookURL,
+		Secret:  secret,
+		Version: maxAPIVersion,
+	}); err != nil {
 		return fmt.Errorf("failed to subscribe to webhook: %w", err)
 	}
 
@@ -80,6 +108,40 @@ func (c *Client) SetWebhook(ctx context.Context, webhookURL string) error {
 	return nil
 }
 
+// subscribe создает подписку напрямую: Subscriptions.Subscribe из maxbot не передает secret
+func (c *Client) subscribe(ctx context.Context, subscription schemes.SubscriptionRequestBody) error {
+	body, err := json.Marshal(subscription)
+	if err != nil {
+		return err
+	}
+
+	query := url.Values{}
+	query.Set("access_token", c.token)
+	query.Set("v", maxAPIVersion)
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, maxAPIURL+"subscriptions?"+query.Encode(), bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	var result schemes.SimpleQueryResult
+	if err = json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return fmt.Errorf("HTTP %d: %w", resp.StatusCode, err)
+	}
+	if resp.StatusCode != http.StatusOK || !result.Success {
+		return fmt.Errorf("HTTP %d: %s", resp.StatusCode, result.Message)
+	}
+
+	return nil
+}
+
 // GetMe возвращает информацию о боте
 func (c *Client) GetMe(ctx context.Context) (*schemes.BotInfo, error) {
 	return c.api.Bots.GetBot(ctx)
@@ -3,9 +3,11 @@ package max
 import (
 	"context"
 	"fmt"
+	"strconv"
 	"time"
 
 	"github.com/singl3focus/uniflow/internal/core/models"
+	"github.com/singl3focus/uniflow/internal/core/ports/notifier"
 )
 
 // NotificationService сервис для отправки уведомлений через MAX
@@ -13,6 +15,11 @@ type NotificationService struct {
 	client *Client
 }
 
+var (
+	_ notifier.Sender       = (*NotificationService)(nil)
+	_ notifier.DigestSender = (*NotificationService)(nil)
+)
+
 // NewNotificationService создает новый сервис уведомлений
 func NewNotificationService(client *Client) *NotificationService {
 	return &NotificationService{
@@ -46,23 +53,90 @@ func (s *NotificationService) SendTaskReminder(ctx context.Context, userID int64
 	return s.client.SendMessage(ctx, userID, text)
 }
 
-// SendDailySummary отправляет ежедневную сводку
-func (s *NotificationService) SendDailySummary(ctx context.Context, userID int64, tasks []*models.Task) error {
-	text := "📊 Ваши задачи на сегодня:\n\n"
+// SendDailySummary отправляет утреннюю сводку или вечерний обзор.
+// К вечернему обзору прикладываются кнопки завершить, перенести на завтра или отменить задачу.
+func (s *NotificationService) SendDailySummary(ctx context.Context, recipient models.User, digest models.DailyDigest) error {
+	userID, err := parseMaxUserID(recipient)
+	if err != nil {
+		return err
+	}
 
-	if len(tasks) == 0 {
-		text += "✅ Нет запланированных задач"
-	} else {
-		for i, task := range tasks {
+	loc := recipient.Settings.Location()
+	if digest.Kind == models.DigestKindEvening {
+		unfinished := append(append([]models.Task{}, digest.Overdue...), digest.Today...)
+		if len(unfinished) == 0 {
+			return s.client.SendMessage(ctx, userID, "🌙 Вечерний обзор\n\n🎉 Все задачи на сегодня выполнены. Отличная работа!")
+		}
+		return s.client.SendMessageWithKeyboard(ctx, userID, formatEveningReview(digest, loc), buildReviewKeyboard(unfinished))
+	}
+
+	return s.client.SendMessageWithKeyboard(ctx, userID, formatMorningDigest(digest, loc), buildMorningDigestKeyboard())
+}
+
+// formatMorningDigest формирует текст утренней сводки: занятия, просроченные задачи и задачи на сегодня
+func formatMorningDigest(digest models.DailyDigest, loc *time.Location) string {
+	text := fmt.Sprintf("🌅 Доброе утро! Сводка на %s, %s\n\n",
+		models.WeekdayOf(digest.Date).Name(), digest.Date.Format("02.01"))
+
+	if digest.IsEmpty() {
+		return text + "✨ Задач и занятий на сегодня нет. Отличный день для отдыха!"
+	}
+
+	if len(digest.Classes) > 0 {
+		text += fmt.Sprintf("🎓 Занятия (%d):\n", len(digest.Classes))
+		for _, entry := range digest.Classes {
+			text += formatScheduleEntry(entry)
+		}
+		text += "\n"
+	}
+
+	if len(digest.Overdue) > 0 {
+		text += fmt.Sprintf("⚠️ Просрочено (%d):\n", len(digest.Overdue))
+		for _, task := range digest.Overdue {
+			text += fmt.Sprintf("• %s%s (до %s)\n", priorityIcon(task.Priority), task.Title, task.DueAt.In(loc).Format("02.01"))
+		}
+		text += "\n"
+	}
+
+	if len(digest.Today) > 0 {
+		text += fmt.Sprintf("📋 Задачи на сегодня (%d):\n", len(digest.Today))
+		for _, task := range digest.Today {
 			status := "⬜"
 			if task.Status == models.TaskStatusCompleted {
 				status = "✅"
 			}
-			text += fmt.Sprintf("%d. %s %s\n", i+1, status, task.Title)
+			due := task.DueAt.In(loc).Format("15:04")
+			if task.AllDay {
+				due = "весь день"
+			}
+			text += fmt.Sprintf("%s %s%s — %s\n", status, priorityIcon(task.Priority), task.Title, due)
 		}
 	}
 
-	return s.client.SendMessage(ctx, userID, text)
+	return text
+}
+
+// formatEveningReview формирует текст вечернего обзора незавершенных задач
+func formatEveningReview(digest models.DailyDigest, loc *time.Location) string {
+	text := "🌙 Вечерний обзор\n\n"
+
+	if len(digest.Today) > 0 {
+		text += fmt.Sprintf("⭕ Не завершено сегодня (%d):\n", len(digest.Today))
+		for _, task := range digest.Today {
+			text += fmt.Sprintf("• %s%s\n", priorityIcon(task.Priority), task.Title)
+		}
+		text += "\n"
+	}
+
+	if len(digest.Overdue) > 0 {
+		text += fmt.Sprintf("⚠️ Просрочено (%d):\n", len(digest.Overdue))
+		for _, task := range digest.Overdue {
+			text += fmt.Sprintf("• %s%s (до %s)\n", priorityIcon(task.Priority), task.Title, task.DueAt.In(loc).Format("02.01"))
+		}
+		text += "\n"
+	}
+
+	return text + "✓ — выполнено, ➡️ — перенести на завтра, ✖ — отменить"
 }
 
 // SendFocusSessionStart отправляет уведомление о начале фокус-сессии
@@ -111,3 +185,26 @@ func (s *NotificationService) SendCustomNotification(ctx context.Context, userID
 
 	return s.client.SendMessage(ctx, userID, text)
 }
+
+// Send доставляет запланированное уведомление пользователю в MAX.
+// К напоминанию о задаче прикладываются кнопки выполнить и отложить.
+func (s *NotificationService) Send(ctx context.Context, recipient models.User, notification models.Notification) error {
+	userID, err := parseMaxUserID(recipient)
+	if err != nil {
+		return err
+	}
+
+	if notification.TaskID != nil {
+		return s.client.SendMessageWithKeyboard(ctx, userID, notification.Message, buildReminderKeyboard(notification.TaskID.String()))
+	}
+	return s.client.SendMessage(ctx, userID, notification.Message)
+}
+
+// parseMaxUserID возвращает числовой ID пользователя в MAX
+func parseMaxUserID(user models.User) (int64, error) {
+	userID, err := strconv.ParseInt(user.MaxUserID, 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("invalid MAX user id %q: %w", user.MaxUserID, err)
+	}
+	return userID, nil
+}
@@ -0,0 +1,115 @@
+package max
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/singl3focus/uniflow/internal/core/models"
+)
+
+// Диалоги бота
+const (
+	stateCreatingTask    = "creating_task"
+	stateCreatingContext = "creating_context"
+	stateEditingTask     = "editing_task"
+	stateEditingContext  = "editing_context"
+	stateEditingSettings = "editing_settings"
+	stateAddingSubtasks  = "adding_subtasks"
+	stateSearching       = "searching"
+	stateQuickAdd        = "quick_add"
+)
+
+// DefaultDialogTTL - через сколько без ответа пользователя диалог считается брошенным
+const DefaultDialogTTL = 30 * time.Minute
+
+// UserState представляет состояние диалога пользователя
+type UserState struct {
+	State      string     `json:"state"` // Один из state* выше
+	Data       DialogData `json:"data"`
+	LastUpdate time.Time  `json:"last_update"`
+}
+
+// DialogData - данные, накопленные в ходе диалога. Набор полей общий для всех диалогов,
+// каждый диалог использует свою часть.
+type DialogData struct {
+	Step        int                 `json:"step,omitempty"` // 0 - диалог только начат
+	TaskID      string              `json:"task_id,omitempty"`
+	Field       string              `json:"field,omitempty"` // Редактируемое поле задачи или контекста
+	Title       string              `json:"title,omitempty"` // Название задачи или контекста
+	Description string              `json:"description,omitempty"`
+	Tags        []string            `json:"tags,omitempty"`
+	ContextIDs  []string            `json:"context_ids,omitempty"` // Контексты в порядке номеров, предложенных пользователю
+	ContextID   *string             `json:"context_id,omitempty"`
+	DueAt       *string             `json:"due_at,omitempty"` // RFC 3339 или дата 2006-01-02 - срок на весь день
+	Recurrence  *models.Recurrence  `json:"recurrence,omitempty"`
+	Priority    models.TaskPriority `json:"priority,omitempty"`
+}
+
+// StateStore хранит состояния диалогов пользователей бота.
+// Реализации должны быть безопасны для конкурентного использования.
+type StateStore interface {
+	// Get возвращает состояние пользователя; ok == false, если диалога нет
+	Get(ctx context.Context, userID int64) (state *UserState, ok bool, err error)
+	Set(ctx context.Context, userID int64, state *UserState) error
+	Delete(ctx context.Context, userID int64) error
+	// TakeExpired удаляет и возвращает состояния, не обновлявшиеся с момента before.
+	// Каждое состояние возвращается ровно одному вызывающему, даже если хранилище
+	// разделяют несколько реплик.
+	TakeExpired(ctx context.Context, before time.Time) (map[int64]*UserState, error)
+}
+
+// MemoryStateStore хранит состояния в памяти процесса. Подходит для одной реплики;
+// при перезапуске состояния теряются.
+type MemoryStateStore struct {
+	mu     sync.Mutex
+	states map[int64]UserState
+}
+
+func NewMemoryStateStore() *MemoryStateStore {
+	return &MemoryStateStore{states: make(map[int64]UserState)}
+}
+
+// Get возвращает копию состояния: изменения видны другим только после Set
+func (s *MemoryStateStore) Get(_ context.Context, userID int64) (*UserState, bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	state, ok := s.states[userID]
+	if !ok {
+		return nil, false, nil
+	}
+
+	return &state, true, nil
+}
+
+func (s *MemoryStateStore) Set(_ context.Context, userID int64, state *UserState) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.states[userID] = *state
+	return nil
+}
+
+func (s *MemoryStateStore) Delete(_ context.Context, userID int64) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	delete(s.states, userID)
+	return nil
+}
+
+func (s *MemoryStateStore) TakeExpired(_ context.Context, before time.Time) (map[int64]*UserState, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	expired := make(map[int64]*UserState)
+	for userID, state := range s.states {
+		if state.LastUpdate.Before(before) {
+			expired[userID] = &state
+			delete(s.states, userID)
+		}
+	}
+
+	return expired, nil
+}
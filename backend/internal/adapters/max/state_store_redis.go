@@ -0,0 +1,148 @@
+package max
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"strconv"
+	"time"
+
+	"github.com/singl3focus/uniflow/internal/core/ports/cache"
+)
+
+const (
+	redisStateKeyPrefix = "uniflow:bot:state:"
+	// redisStateIndexKey - упорядоченное множество userID со score = LastUpdate (unix),
+	// по нему находятся брошенные диалоги
+	redisStateIndexKey = "uniflow:bot:states"
+)
+
+// RedisStateStore хранит состояния в Redis, поэтому они переживают перезапуск
+// и доступны всем репликам бота
+type RedisStateStore struct {
+	cache cache.Cache
+	// retention - страховочный TTL ключей на случай, если TakeExpired долго не вызывался
+	retention time.Duration
+}
+
+func NewRedisStateStore(c cache.Cache, dialogTTL time.Duration) *RedisStateStore {
+	return &RedisStateStore{
+		cache:     c,
+		retention: 2 * dialogTTL,
+	}
+}
+
+func (s *RedisStateStore) Get(ctx context.Context, userID int64) (*UserState, bool, error) {
+	raw, err := s.cache.Get(ctx, redisStateKey(userID))
+	if err != nil {
+		if errors.Is(err, cache.ErrCacheMiss) {
+			return nil, false, nil
+		}
+		return nil, false, err
+	}
+
+	var state UserState
+	if err = json.Unmarshal([]byte(raw), &state); err != nil {
+		return nil, false, fmt.Errorf("decode state of user %d: %w", userID, err)
+	}
+
+	return &state, true, nil
+}
+
+func (s *RedisStateStore) Set(ctx context.Context, userID int64, state *UserState) error {
+	raw, err := json.Marshal(state)
+	if err != nil {
+		return fmt.Errorf("encode state of user %d: %w", userID, err)
+	}
+
+	if err = s.cache.Set(ctx, redisStateKey(userID), raw, s.retention); err != nil {
+		return err
+	}
+
+	return s.cache.ZAdd(ctx, redisStateIndexKey, float64(state.LastUpdate.Unix()), strconv.FormatInt(userID, 10))
+}
+
+func (s *RedisStateStore) Delete(ctx context.Context, userID int64) error {
+	if err := s.cache.Del(ctx, redisStateKey(userID)); err != nil {
+		return err
+	}
+
+	_, err := s.cache.ZRem(ctx, redisStateIndexKey, strconv.FormatInt(userID, 10))
+	return err
+}
+
+func (s *RedisStateStore) TakeExpired(ctx context.Context, before time.Time) (map[int64]*UserState, error) {
+	members, err := s.cache.ZRangeByScore(ctx, redisStateIndexKey, math.Inf(-1), float64(before.Unix()-1))
+	if err != nil {
+		return nil, err
+	}
+
+	expired := make(map[int64]*UserState)
+	for _, member := range members {
+		// Состояние достается той реплике, которая успела удалить его из индекса
+		removed, err := s.cache.ZRem(ctx, redisStateIndexKey, member)
+		if err != nil {
+			return expired, err
+		}
+		if removed == 0 {
+			continue
+		}
+
+		userID, err := strconv.ParseInt(member, 10, 64)
+		if err != nil {
+			continue
+		}
+
+		raw, err := s.cache.Get(ctx, redisStateKey(userID))
+		if err != nil {
+			if errors.Is(err, cache.ErrCacheMiss) {
+				continue
+			}
+			return expired, err
+		}
+
+		var state UserState
+		if err = json.Unmarshal([]byte(raw), &state); err != nil {
+			return expired, fmt.Errorf("decode state of user %d: %w", userID, err)
+		}
+
+		// Пользователь ответил, пока мы разбирали индекс: возвращаем диалог в индекс
+		if !state.LastUpdate.Before(before) {
+			if err = s.cache.ZAdd(ctx, redisStateIndexKey, float64(state.LastUpdate.Unix()), member); err != nil {
+				return expired, err
+			}
+			continue
+		}
+
+		// Удаляем только прочитанное состояние: новое, записанное Set после Get, остается
+		deleted, err := s.cache.DelIfEqual(ctx, redisStateKey(userID), raw)
+		if err != nil {
+			return expired, err
+		}
+		if !deleted {
+			if err = s.reindex(ctx, userID); err != nil {
+				return expired, err
+			}
+			continue
+		}
+		expired[userID] = &state
+	}
+
+	return expired, nil
+}
+
+// reindex возвращает в индекс диалог, обновленный во время TakeExpired:
+// Set мог добавить его в индекс раньше, чем TakeExpired удалил оттуда старую запись
+func (s *RedisStateStore) reindex(ctx context.Context, userID int64) error {
+	state, ok, err := s.Get(ctx, userID)
+	if err != nil || !ok {
+		return err
+	}
+	return s.cache.ZAdd(ctx, redisStateIndexKey, float64(state.LastUpdate.Unix()), strconv.FormatInt(userID, 10))
+}
+
+func redisStateKey(userID int64) string {
+	return redisStateKeyPrefix + strconv.FormatInt(userID, 10)
+}
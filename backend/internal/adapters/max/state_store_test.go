@@ -0,0 +1,192 @@
+package max
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/singl3focus/uniflow/internal/core/models"
+	"github.com/singl3focus/uniflow/internal/core/ports/cache"
+)
+
+// fakeCache реализует часть cache.Cache, нужную RedisStateStore
+type fakeCache struct {
+	cache.Cache
+	values map[string]string
+	zsets  map[string]map[string]float64
+	// afterGet вызывается после чтения ключа и имитирует запись конкурирующей репликой
+	afterGet func(key string)
+}
+
+func newFakeCache() *fakeCache {
+	return &fakeCache{values: map[string]string{}, zsets: map[string]map[string]float64{}}
+}
+
+func (c *fakeCache) Set(_ context.Context, key string, value interface{}, _ time.Duration) error {
+	if b, ok := value.([]byte); ok {
+		value = string(b)
+	}
+	c.values[key] = fmt.Sprint(value)
+	return nil
+}
+
+func (c *fakeCache) Get(_ context.Context, key string) (string, error) {
+	v, ok := c.values[key]
+	if c.afterGet != nil {
+		c.afterGet(key)
+	}
+	if !ok {
+		return "", cache.ErrCacheMiss
+	}
+	return v, nil
+}
+
+func (c *fakeCache) DelIfEqual(_ context.Context, key, value string) (bool, error) {
+	if v, ok := c.values[key]; !ok || v != value {
+		return false, nil
+	}
+	delete(c.values, key)
+	return true, nil
+}
+
+func (c *fakeCache) Del(_ context.Context, keys ...string) error {
+	for _, key := range keys {
+		delete(c.values, key)
+	}
+	return nil
+}
+
+func (c *fakeCache) ZAdd(_ context.Context, key string, score float64, member string) error {
+	if c.zsets[key] == nil {
+		c.zsets[key] = map[string]float64{}
+	}
+	c.zsets[key][member] = score
+	return nil
+}
+
+func (c *fakeCache) ZRangeByScore(_ context.Context, key string, min, max float64) ([]string, error) {
+	var members []string
+	for member, score := range c.zsets[key] {
+		if score >= min && score <= max {
+			members = append(members, member)
+		}
+	}
+	return members, nil
+}
+
+func (c *fakeCache) ZRem(_ context.Context, key string, members ...string) (int64, error) {
+	var removed int64
+	for _, member := range members {
+		if _, ok := c.zsets[key][member]; ok {
+			delete(c.zsets[key], member)
+			removed++
+		}
+	}
+	return removed, nil
+}
+
+func TestStateStores(t *testing.T) {
+	stores := map[string]StateStore{
+		"memory": NewMemoryStateStore(),
+		"redis":  NewRedisStateStore(newFakeCache(), DefaultDialogTTL),
+	}
+
+	for name, store := range stores {
+		t.Run(name, func(t *testing.T) {
+			ctx := context.Background()
+			now := time.Now()
+			contextID := "ctx-1"
+			dueAt := "2026-01-02T15:04:05Z"
+
+			fresh := &UserState{
+				State: stateCreatingTask,
+				Data: DialogData{
+					Step:       6,
+					Title:      "Сдать лабу",
+					Tags:       []string{"матан"},
+					ContextID:  &contextID,
+					DueAt:      &dueAt,
+					Recurrence: &models.Recurrence{Frequency: models.RecurrenceWeekly, Interval: 1, Weekdays: []models.Weekday{models.Monday}},
+				},
+				LastUpdate: now,
+			}
+			stale := &UserState{State: stateSearching, LastUpdate: now.Add(-time.Hour)}
+
+			if err := store.Set(ctx, 1, fresh); err != nil {
+				t.Fatalf("Set() error = %v", err)
+			}
+			if err := store.Set(ctx, 2, stale); err != nil {
+				t.Fatalf("Set() error = %v", err)
+			}
+
+			got, ok, err := store.Get(ctx, 1)
+			if err != nil || !ok {
+				t.Fatalf("Get() = %v, %v", ok, err)
+			}
+			if got.Data.Title != fresh.Data.Title || *got.Data.ContextID != contextID || *got.Data.DueAt != dueAt ||
+				got.Data.Recurrence == nil || got.Data.Recurrence.Weekdays[0] != models.Monday || got.Data.Tags[0] != "матан" {
+				t.Errorf("Get() data = %+v, want %+v", got.Data, fresh.Data)
+			}
+
+			expired, err := store.TakeExpired(ctx, now.Add(-DefaultDialogTTL))
+			if err != nil {
+				t.Fatalf("TakeExpired() error = %v", err)
+			}
+			if len(expired) != 1 || expired[2] == nil || expired[2].State != stateSearching {
+				t.Fatalf("TakeExpired() = %v, want only user 2", expired)
+			}
+
+			// Истекший диалог удален и второй раз не возвращается
+			if _, ok, _ = store.Get(ctx, 2); ok {
+				t.Errorf("expired state is still stored")
+			}
+			if expired, _ = store.TakeExpired(ctx, now.Add(-DefaultDialogTTL)); len(expired) != 0 {
+				t.Errorf("second TakeExpired() = %v, want empty", expired)
+			}
+
+			if err = store.Delete(ctx, 1); err != nil {
+				t.Fatalf("Delete() error = %v", err)
+			}
+			if _, ok, _ = store.Get(ctx, 1); ok {
+				t.Errorf("state is stored after Delete()")
+			}
+		})
+	}
+}
+
+func TestRedisStateStoreTakeExpiredKeepsAnsweredDialog(t *testing.T) {
+	ctx := context.Background()
+	now := time.Now()
+	c := newFakeCache()
+	store := NewRedisStateStore(c, DefaultDialogTTL)
+
+	if err := store.Set(ctx, 1, &UserState{State: stateSearching, LastUpdate: now.Add(-time.Hour)}); err != nil {
+		t.Fatalf("Set() error = %v", err)
+	}
+
+	// Пользователь отвечает между чтением состояния и его удалением
+	answered := &UserState{State: stateCreatingTask, LastUpdate: now}
+	c.afterGet = func(string) {
+		c.afterGet = nil
+		if err := store.Set(ctx, 1, answered); err != nil {
+			t.Fatalf("Set() error = %v", err)
+		}
+	}
+
+	expired, err := store.TakeExpired(ctx, now.Add(-DefaultDialogTTL))
+	if err != nil {
+		t.Fatalf("TakeExpired() error = %v", err)
+	}
+	if len(expired) != 0 {
+		t.Errorf("TakeExpired() = %v, want empty", expired)
+	}
+
+	got, ok, err := store.Get(ctx, 1)
+	if err != nil || !ok || got.State != stateCreatingTask {
+		t.Fatalf("Get() = %+v, %v, %v; want answered dialog", got, ok, err)
+	}
+	if _, ok := c.zsets[redisStateIndexKey]["1"]; !ok {
+		t.Error("answered dialog is missing from the index")
+	}
+}
@@ -0,0 +1,150 @@
+package max
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"runtime/debug"
+	"sync"
+
+	"github.com/max-messenger/max-bot-api-client-go/schemes"
+
+	"github.com/singl3focus/uniflow/pkg/logger"
+)
+
+// ErrDispatcherStopped возвращается Dispatch после остановки диспетчера
+var ErrDispatcherStopped = errors.New("update dispatcher stopped")
+
+// DispatcherConfig - параметры диспетчера обновлений бота
+type DispatcherConfig struct {
+	Workers   int // Число воркеров; обновления одного пользователя всегда обрабатывает один воркер
+	QueueSize int // Емкость очереди каждого воркера
+}
+
+func DefaultDispatcherConfig() DispatcherConfig {
+	return DispatcherConfig{
+		Workers:   8,
+		QueueSize: 64,
+	}
+}
+
+// UpdateDispatcher обрабатывает обновления бота пулом воркеров. Обновления
+// распределяются по воркерам по ID пользователя, поэтому обновления одного
+// пользователя обрабатываются строго по очереди, а разных - параллельно.
+type UpdateDispatcher struct {
+	handler UpdateHandler
+	log     logger.Logger
+	shards  []chan schemes.UpdateInterface
+
+	mu      sync.RWMutex // Защищает stopped и закрытие shards от параллельного Dispatch
+	stopped bool
+}
+
+func NewUpdateDispatcher(handler UpdateHandler, log logger.Logger, cfg DispatcherConfig) *UpdateDispatcher {
+	def := DefaultDispatcherConfig()
+	if cfg.Workers <= 0 {
+		cfg.Workers = def.Workers
+	}
+	if cfg.QueueSize <= 0 {
+		cfg.QueueSize = def.QueueSize
+	}
+
+	shards := make([]chan schemes.UpdateInterface, cfg.Workers)
+	for i := range shards {
+		shards[i] = make(chan schemes.UpdateInterface, cfg.QueueSize)
+	}
+
+	return &UpdateDispatcher{
+		handler: handler,
+		log:     log,
+		shards:  shards,
+	}
+}
+
+// Run запускает воркеры и работает до отмены ctx. После отмены новые обновления
+// не принимаются, а уже поставленные в очередь обрабатываются до конца.
+func (d *UpdateDispatcher) Run(ctx context.Context) error {
+	d.mu.RLock()
+	stopped := d.stopped
+	d.mu.RUnlock()
+	if stopped {
+		return ErrDispatcherStopped
+	}
+
+	var wg sync.WaitGroup
+	for _, shard := range d.shards {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for update := range shard {
+				d.handle(update)
+			}
+		}()
+	}
+
+	<-ctx.Done()
+
+	d.mu.Lock()
+	d.stopped = true
+	for _, shard := range d.shards {
+		close(shard)
+	}
+	d.mu.Unlock()
+
+	d.log.Info("draining bot update queues")
+	wg.Wait()
+
+	return ctx.Err()
+}
+
+// Dispatch ставит обновление в очередь воркера его пользователя. Если очередь
+// заполнена, ждет освобождения места или отмены ctx.
+func (d *UpdateDispatcher) Dispatch(ctx context.Context, update schemes.UpdateInterface) error {
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+
+	if d.stopped {
+		return ErrDispatcherStopped
+	}
+
+	select {
+	case d.shardFor(update.GetUserID()) <- update:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+func (d *UpdateDispatcher) shardFor(userID int64) chan schemes.UpdateInterface {
+	return d.shards[uint64(userID)%uint64(len(d.shards))]
+}
+
+// handle обрабатывает одно обновление; паника не должна останавливать воркер
+func (d *UpdateDispatcher) handle(update schemes.UpdateInterface) {
+	defer func() {
+		if rec := recover(); rec != nil {
+			d.log.Error("panic while handling bot update",
+				"panic", fmt.Sprint(rec),
+				"type", update.GetUpdateType(),
+				"user_id", update.GetUserID(),
+				"stack", string(debug.Stack()),
+			)
+		}
+	}()
+
+	d.handler.HandleUpdate(update)
+}
+
+// Poll получает обновления long polling и передает их диспетчеру до отмены ctx
+func (d *UpdateDispatcher) Poll(ctx context.Context, client *Client) error {
+	for update := range client.GetUpdates(ctx) {
+		if err := d.Dispatch(ctx, update); err != nil {
+			return err
+		}
+	}
+
+	if ctx.Err() != nil {
+		return ctx.Err()
+	}
+	return errors.New("updates channel closed")
+}
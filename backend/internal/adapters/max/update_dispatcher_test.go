@@ -0,0 +1,116 @@
+package max
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/max-messenger/max-bot-api-client-go/schemes"
+
+	"github.com/singl3focus/uniflow/pkg/logger"
+)
+
+type nopLogger struct{}
+
+func (nopLogger) Debug(string, ...interface{})                {}
+func (nopLogger) Info(string, ...interface{})                 {}
+func (nopLogger) Warn(string, ...interface{})                 {}
+func (nopLogger) Error(string, ...interface{})                {}
+func (nopLogger) Fatal(string, ...interface{})                {}
+func (nopLogger) Log(string, string, ...interface{})          {}
+func (nopLogger) SetLevel(string) error                       { return nil }
+func (nopLogger) Shutdown() error                             { return nil }
+func (nopLogger) Flush() error                                { return nil }
+func (l nopLogger) With(...interface{}) logger.Logger         { return l }
+func (l nopLogger) WithContext(context.Context) logger.Logger { return l }
+
+// recordingHandler запоминает тексты сообщений по пользователям; текст "panic" вызывает панику
+type recordingHandler struct {
+	mu   sync.Mutex
+	seen map[int64][]string
+}
+
+func (h *recordingHandler) HandleUpdate(update schemes.UpdateInterface) {
+	upd := update.(*schemes.MessageCreatedUpdate)
+	if upd.Message.Body.Text == "panic" {
+		panic("boom")
+	}
+
+	// Небольшая пауза, чтобы обновления разных пользователей перемешивались
+	time.Sleep(time.Millisecond)
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.seen[upd.GetUserID()] = append(h.seen[upd.GetUserID()], upd.Message.Body.Text)
+}
+
+func newTextUpdate(userID int64, text string) *schemes.MessageCreatedUpdate {
+	return &schemes.MessageCreatedUpdate{
+		Message: schemes.Message{
+			Sender: schemes.User{UserId: userID},
+			Body:   schemes.MessageBody{Text: text},
+		},
+	}
+}
+
+func TestUpdateDispatcher_OrderingAndDrain(t *testing.T) {
+	handler := &recordingHandler{seen: map[int64][]string{}}
+	d := NewUpdateDispatcher(handler, nopLogger{}, DispatcherConfig{Workers: 3, QueueSize: 100})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+	go func() { done <- d.Run(ctx) }()
+
+	const users, perUser = 5, 20
+	var want []string
+	for i := 0; i < perUser; i++ {
+		want = append(want, string(rune('a'+i)))
+	}
+	for i := 0; i < perUser; i++ {
+		for userID := int64(1); userID <= users; userID++ {
+			if err := d.Dispatch(context.Background(), newTextUpdate(userID, want[i])); err != nil {
+				t.Fatalf("Dispatch() error = %v", err)
+			}
+			// Паника при обработке не должна ломать воркер и очередь пользователя
+			if userID == 2 && i == perUser/2 {
+				if err := d.Dispatch(context.Background(), newTextUpdate(userID, "panic")); err != nil {
+					t.Fatalf("Dispatch() error = %v", err)
+				}
+			}
+		}
+	}
+
+	// Остановка сразу после постановки: все принятые обновления должны быть обработаны
+	cancel()
+	if err := <-done; !errors.Is(err, context.Canceled) {
+		t.Fatalf("Run() error = %v, want context.Canceled", err)
+	}
+
+	for userID := int64(1); userID <= users; userID++ {
+		if got := handler.seen[userID]; !slices.Equal(got, want) {
+			t.Errorf("user %d updates = %v, want %v", userID, got, want)
+		}
+	}
+
+	if err := d.Dispatch(context.Background(), newTextUpdate(1, "late")); !errors.Is(err, ErrDispatcherStopped) {
+		t.Errorf("Dispatch() after stop error = %v, want ErrDispatcherStopped", err)
+	}
+}
+
+func TestUpdateDispatcher_QueueFull(t *testing.T) {
+	// Диспетчер не запущен: очередь из одного элемента заполняется первым же обновлением
+	d := NewUpdateDispatcher(&recordingHandler{seen: map[int64][]string{}}, nopLogger{}, DispatcherConfig{Workers: 1, QueueSize: 1})
+
+	if err := d.Dispatch(context.Background(), newTextUpdate(1, "a")); err != nil {
+		t.Fatalf("Dispatch() error = %v", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	if err := d.Dispatch(ctx, newTextUpdate(1, "b")); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Dispatch() into full queue error = %v, want context.DeadlineExceeded", err)
+	}
+}
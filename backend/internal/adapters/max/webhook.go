@@ -1,34 +1,63 @@
 package max
 
 import (
-	"log"
+	"container/list"
+	"context"
+	"crypto/subtle"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
 	"net/http"
+	"sync"
+	"time"
 
 	"github.com/max-messenger/max-bot-api-client-go/schemes"
+
+	"github.com/singl3focus/uniflow/pkg/logger"
 )
 
-// WebhookHandler обработчик webhook от MAX
-type WebhookHandler struct {
-	client        *Client
-	updateChannel chan schemes.UpdateInterface
-	updateHandler UpdateHandler
-}
+const (
+	// WebhookSecretHeader - заголовок, в котором MAX присылает секрет подписки
+	WebhookSecretHeader = "X-Max-Bot-Api-Secret"
+
+	webhookMaxBodySize = 1 << 20
+	// webhookEnqueueTimeout - сколько ждать места в очереди, прежде чем попросить MAX повторить запрос
+	webhookEnqueueTimeout = 2 * time.Second
+	// webhookDedupTTL - сколько помнить обработанные обновления; MAX повторяет доставку в пределах минут
+	webhookDedupTTL = 10 * time.Minute
+	// webhookDedupLimit - сколько ключей помнить самое большее; при всплеске трафика вытесняются самые старые
+	webhookDedupLimit = 50_000
+)
+
+var errUnknownUpdateType = errors.New("unknown update type")
 
 // UpdateHandler интерфейс для обработки обновлений
 type UpdateHandler interface {
 	HandleUpdate(update schemes.UpdateInterface)
 }
 
-// NewWebhookHandler создает новый обработчик webhook
-func NewWebhookHandler(client *Client, handler UpdateHandler) *WebhookHandler {
-	if handler == nil {
-		handler = &DefaultUpdateHandler{}
-	}
+// UpdateQueue принимает обновления на асинхронную обработку
+type UpdateQueue interface {
+	Dispatch(ctx context.Context, update schemes.UpdateInterface) error
+}
 
+// WebhookHandler принимает обновления от MAX по webhook: проверяет секрет,
+// отбрасывает повторные доставки и ставит обновление в очередь, не дожидаясь обработки
+type WebhookHandler struct {
+	queue  UpdateQueue
+	secret string
+	seen   *updateDeduplicator
+	log    logger.Logger
+}
+
+// NewWebhookHandler создает обработчик webhook. Пустой secret отключает проверку заголовка.
+func NewWebhookHandler(queue UpdateQueue, secret string, log logger.Logger) *WebhookHandler {
 	return &WebhookHandler{
-		client:        client,
-		updateChannel: make(chan schemes.UpdateInterface, 100),
-		updateHandler: handler,
+		queue:  queue,
+		secret: secret,
+		seen:   newUpdateDeduplicator(webhookDedupTTL, webhookDedupLimit),
+		log:    log,
 	}
 }
 
@@ -39,40 +68,173 @@ func (h *WebhookHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	// Используем встроенный парсер MAX API
-	updates := make(chan schemes.UpdateInterface)
-	http.HandleFunc(r.URL.Path, h.client.api.GetHandler(updates))
+	if h.secret != "" && subtle.ConstantTimeCompare([]byte(r.Header.Get(WebhookSecretHeader)), []byte(h.secret)) != 1 {
+		h.log.Warn("MAX webhook request with invalid secret", "remote_addr", r.RemoteAddr)
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
 
-	// Читаем обновление из канала
-	select {
-	case update := <-updates:
-		// Обрабатываем обновление
-		h.updateHandler.HandleUpdate(update)
-		w.WriteHeader(http.StatusOK)
-	default:
+	body, err := io.ReadAll(http.MaxBytesReader(w, r.Body, webhookMaxBodySize))
+	if err != nil {
+		http.Error(w, "Failed to read request body", http.StatusBadRequest)
+		return
+	}
+
+	update, timestamp, err := decodeUpdate(body)
+	if err != nil {
+		// Неизвестные типы подтверждаем, иначе MAX будет бесконечно повторять доставку
+		if errors.Is(err, errUnknownUpdateType) {
+			h.log.Debug("MAX webhook update ignored", "error", err)
+			w.WriteHeader(http.StatusOK)
+			return
+		}
+		h.log.Warn("failed to decode MAX webhook update", "error", err)
+		http.Error(w, "Failed to parse update", http.StatusBadRequest)
+		return
+	}
+
+	key := updateKey(update, timestamp)
+	if !h.seen.Add(key) {
+		h.log.Debug("duplicate MAX webhook update skipped", "key", key)
 		w.WriteHeader(http.StatusOK)
+		return
 	}
-}
 
-// GetUpdates возвращает канал с обновлениями
-func (h *WebhookHandler) GetUpdates() <-chan schemes.UpdateInterface {
-	return h.updateChannel
+	ctx, cancel := context.WithTimeout(r.Context(), webhookEnqueueTimeout)
+	defer cancel()
+
+	if err = h.queue.Dispatch(ctx, update); err != nil {
+		// Обновление не принято: забываем его, чтобы повторная доставка не считалась дублем
+		h.seen.Remove(key)
+		h.log.Warn("MAX webhook update not queued", "error", err, "type", update.GetUpdateType())
+		http.Error(w, "Service unavailable", http.StatusServiceUnavailable)
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
 }
 
-// DefaultUpdateHandler стандартный обработчик обновлений
-type DefaultUpdateHandler struct{}
+// decodeUpdate разбирает тело webhook в конкретный тип обновления по полю update_type.
+// Также возвращает время события в миллисекундах.
+func decodeUpdate(body []byte) (schemes.UpdateInterface, int, error) {
+	var base schemes.Update
+	if err := json.Unmarshal(body, &base); err != nil {
+		return nil, 0, err
+	}
+
+	var update schemes.UpdateInterface
+	switch base.UpdateType {
+	case schemes.TypeMessageCreated:
+		update = &schemes.MessageCreatedUpdate{}
+	case schemes.TypeMessageCallback:
+		update = &schemes.MessageCallbackUpdate{}
+	case schemes.TypeMessageEdited:
+		update = &schemes.MessageEditedUpdate{}
+	case schemes.TypeMessageRemoved:
+		update = &schemes.MessageRemovedUpdate{}
+	case schemes.TypeBotStarted:
+		update = &schemes.BotStartedUpdate{}
+	case schemes.TypeBotAdded:
+		update = &schemes.BotAddedToChatUpdate{}
+	case schemes.TypeBotRemoved:
+		update = &schemes.BotRemovedFromChatUpdate{}
+	case schemes.TypeUserAdded:
+		update = &schemes.UserAddedToChatUpdate{}
+	case schemes.TypeUserRemoved:
+		update = &schemes.UserRemovedFromChatUpdate{}
+	case schemes.TypeChatTitleChanged:
+		update = &schemes.ChatTitleChangedUpdate{}
+	default:
+		return nil, 0, fmt.Errorf("%w: %q", errUnknownUpdateType, base.UpdateType)
+	}
+
+	if err := json.Unmarshal(body, update); err != nil {
+		return nil, 0, fmt.Errorf("decode %s update: %w", base.UpdateType, err)
+	}
+
+	return update, base.Timestamp, nil
+}
 
-// HandleUpdate обрабатывает обновление (базовая реализация)
-func (h *DefaultUpdateHandler) HandleUpdate(update schemes.UpdateInterface) {
-	// Определяем тип обновления
+// updateKey возвращает идентификатор обновления для дедупликации. У обновлений MAX
+// нет общего ID, поэтому используется ID сообщения или callback, а для остальных
+// типов - тип, время и участники события.
+func updateKey(update schemes.UpdateInterface, timestamp int) string {
 	switch upd := update.(type) {
 	case *schemes.MessageCreatedUpdate:
-		log.Printf("New message from user %d: %s", upd.Message.Sender.UserId, upd.Message.Body.Text)
-	case *schemes.BotStartedUpdate:
-		log.Printf("Bot started by user %d", upd.User.UserId)
+		if upd.Message.Body.Mid != "" {
+			return "message:" + upd.Message.Body.Mid
+		}
 	case *schemes.MessageCallbackUpdate:
-		log.Printf("Callback from user %d with payload: %s", upd.Callback.User.UserId, upd.Callback.Payload)
-	default:
-		log.Printf("Received update: %+v", update)
+		if upd.Callback.CallbackID != "" {
+			return "callback:" + upd.Callback.CallbackID
+		}
+	case *schemes.MessageEditedUpdate:
+		if upd.Message.Body.Mid != "" {
+			return fmt.Sprintf("edited:%s:%d", upd.Message.Body.Mid, timestamp)
+		}
+	}
+
+	return fmt.Sprintf("%s:%d:%d:%d", update.GetUpdateType(), timestamp, update.GetUserID(), update.GetChatID())
+}
+
+// updateDeduplicator помнит ключи недавно принятых обновлений, но не больше limit
+type updateDeduplicator struct {
+	mu    sync.Mutex
+	ttl   time.Duration
+	limit int
+	order *list.List // dedupEntry в порядке добавления, самые старые в начале
+	seen  map[string]*list.Element
+	now   func() time.Time
+}
+
+type dedupEntry struct {
+	key string
+	at  time.Time
+}
+
+func newUpdateDeduplicator(ttl time.Duration, limit int) *updateDeduplicator {
+	return &updateDeduplicator{
+		ttl:   ttl,
+		limit: limit,
+		order: list.New(),
+		seen:  make(map[string]*list.Element),
+		now:   time.Now,
+	}
+}
+
+// Add запоминает ключ и возвращает false, если он уже встречался в пределах ttl
+func (d *updateDeduplicator) Add(key string) bool {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	now := d.now()
+
+	// Ключи добавляются по порядку времени, поэтому устаревшие всегда в начале списка
+	for e := d.order.Front(); e != nil && now.Sub(e.Value.(dedupEntry).at) > d.ttl; e = d.order.Front() {
+		d.remove(e)
+	}
+
+	if _, ok := d.seen[key]; ok {
+		return false
+	}
+
+	for d.order.Len() >= d.limit {
+		d.remove(d.order.Front())
 	}
+
+	d.seen[key] = d.order.PushBack(dedupEntry{key: key, at: now})
+	return true
+}
+
+func (d *updateDeduplicator) Remove(key string) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	if e, ok := d.seen[key]; ok {
+		d.remove(e)
+	}
+}
+
+func (d *updateDeduplicator) remove(e *list.Element) {
+	delete(d.seen, d.order.Remove(e).(dedupEntry).key)
 }
@@ -0,0 +1,163 @@
+package max
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/max-messenger/max-bot-api-client-go/schemes"
+)
+
+const testWebhookSecret = "test_webhook-secret"
+
+// queueFunc позволяет подменить очередь функцией
+type queueFunc func(ctx context.Context, update schemes.UpdateInterface) error
+
+func (f queueFunc) Dispatch(ctx context.Context, update schemes.UpdateInterface) error {
+	return f(ctx, update)
+}
+
+type recordingQueue struct {
+	mu      sync.Mutex
+	updates []schemes.UpdateInterface
+}
+
+func (q *recordingQueue) Dispatch(_ context.Context, update schemes.UpdateInterface) error {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	q.updates = append(q.updates, update)
+	return nil
+}
+
+func readWebhookPayload(t *testing.T, name string) []byte {
+	t.Helper()
+	body, err := os.ReadFile(filepath.Join("testdata", "webhook", name))
+	if err != nil {
+		t.Fatalf("read payload: %v", err)
+	}
+	return body
+}
+
+func postWebhook(h http.Handler, body []byte, secret string) int {
+	req := httptest.NewRequest(http.MethodPost, "/max/webhook", bytes.NewReader(body))
+	if secret != "" {
+		req.Header.Set(WebhookSecretHeader, secret)
+	}
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, req)
+	return rec.Code
+}
+
+func TestWebhookHandler_ReplayPayloads(t *testing.T) {
+	queue := &recordingQueue{}
+	h := NewWebhookHandler(queue, testWebhookSecret, nopLogger{})
+
+	payloads := []string{"bot_started.json", "message_created.json", "message_callback.json"}
+	for _, name := range payloads {
+		body := readWebhookPayload(t, name)
+		// Повторная доставка того же обновления подтверждается, но не обрабатывается
+		for i := 0; i < 2; i++ {
+			if code := postWebhook(h, body, testWebhookSecret); code != http.StatusOK {
+				t.Fatalf("%s: status = %d, want 200", name, code)
+			}
+		}
+	}
+
+	if len(queue.updates) != len(payloads) {
+		t.Fatalf("queued %d updates, want %d", len(queue.updates), len(payloads))
+	}
+
+	started, ok := queue.updates[0].(*schemes.BotStartedUpdate)
+	if !ok || started.User.UserId != 4242 {
+		t.Errorf("update 0 = %#v, want bot_started from 4242", queue.updates[0])
+	}
+	msg, ok := queue.updates[1].(*schemes.MessageCreatedUpdate)
+	if !ok || msg.Message.Sender.UserId != 4242 || msg.Message.Body.Text != "/today" {
+		t.Errorf("update 1 = %#v, want message_created \"/today\" from 4242", queue.updates[1])
+	}
+	cb, ok := queue.updates[2].(*schemes.MessageCallbackUpdate)
+	if !ok || cb.GetUserID() != 4242 || cb.Callback.Payload != "task_complete_6f1c1a3e-3b7a-4c55-9d5e-2f6d1a7c9b10" {
+		t.Errorf("update 2 = %#v, want message_callback from 4242", queue.updates[2])
+	}
+}
+
+func TestWebhookHandler_Rejects(t *testing.T) {
+	queue := &recordingQueue{}
+	h := NewWebhookHandler(queue, testWebhookSecret, nopLogger{})
+	body := readWebhookPayload(t, "message_created.json")
+
+	if code := postWebhook(h, body, ""); code != http.StatusUnauthorized {
+		t.Errorf("without secret: status = %d, want 401", code)
+	}
+	if code := postWebhook(h, body, "wrong-secret"); code != http.StatusUnauthorized {
+		t.Errorf("wrong secret: status = %d, want 401", code)
+	}
+	if code := postWebhook(h, []byte(`{"update_type":`), testWebhookSecret); code != http.StatusBadRequest {
+		t.Errorf("malformed body: status = %d, want 400", code)
+	}
+	// Неизвестный тип подтверждается, чтобы MAX не повторял доставку
+	if code := postWebhook(h, readWebhookPayload(t, "dialog_muted.json"), testWebhookSecret); code != http.StatusOK {
+		t.Errorf("unknown type: status = %d, want 200", code)
+	}
+
+	if len(queue.updates) != 0 {
+		t.Errorf("queued %d updates, want 0", len(queue.updates))
+	}
+}
+
+func TestWebhookHandler_QueueUnavailable(t *testing.T) {
+	fail := true
+	queue := &recordingQueue{}
+	h := NewWebhookHandler(queueFunc(func(ctx context.Context, update schemes.UpdateInterface) error {
+		if fail {
+			return ErrDispatcherStopped
+		}
+		return queue.Dispatch(ctx, update)
+	}), "", nopLogger{})
+	body := readWebhookPayload(t, "message_created.json")
+
+	if code := postWebhook(h, body, ""); code != http.StatusServiceUnavailable {
+		t.Fatalf("stopped queue: status = %d, want 503", code)
+	}
+
+	// Непринятое обновление не считается дублем при повторной доставке
+	fail = false
+	if code := postWebhook(h, body, ""); code != http.StatusOK {
+		t.Fatalf("retry: status = %d, want 200", code)
+	}
+	if len(queue.updates) != 1 {
+		t.Errorf("queued %d updates, want 1", len(queue.updates))
+	}
+}
+
+func TestUpdateDeduplicator(t *testing.T) {
+	now := time.Date(2026, 10, 17, 12, 0, 0, 0, time.UTC)
+	d := newUpdateDeduplicator(time.Minute, 2)
+	d.now = func() time.Time { return now }
+
+	if !d.Add("a") || d.Add("a") {
+		t.Fatal("repeated key within ttl was not rejected")
+	}
+
+	// При переполнении вытесняется самый старый ключ
+	d.Add("b")
+	d.Add("c")
+	if len(d.seen) != 2 || !d.Add("a") {
+		t.Errorf("oldest key was not evicted: %d keys", len(d.seen))
+	}
+
+	// По истечении ttl ключ снова принимается
+	now = now.Add(2 * time.Minute)
+	if !d.Add("c") {
+		t.Error("expired key was rejected")
+	}
+	if len(d.seen) != 1 || d.order.Len() != 1 {
+		t.Errorf("expired keys were not purged: %d keys, %d in order", len(d.seen), d.order.Len())
+	}
+}
@@ -0,0 +1,194 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	sq "github.com/Masterminds/squirrel"
+	"github.com/jackc/pgx/v5"
+
+	"github.com/singl3focus/uniflow/internal/core/models"
+	"github.com/singl3focus/uniflow/internal/core/ports/repository"
+)
+
+var refreshTokenColumns = []string{
+	"id", "user_id", "token_hash", "access_token_id", "access_expires_at",
+	"expires_at", "revoked_at", "created_at",
+}
+
+func (d *Database) CreateRefreshToken(ctx context.Context, token models.RefreshToken) error {
+	const op = "postgres.CreateRefreshToken"
+
+	query, args, err := sqBuilder.
+		Insert(tblRefreshTokens).
+		Columns(refreshTokenColumns...).
+		Values(
+			token.ID, token.UserID, token.TokenHash, token.AccessTokenID, token.AccessExpiresAt,
+			token.ExpiresAt, token.RevokedAt, token.CreatedAt,
+		).
+		ToSql()
+
+	if err != nil {
+		return repository.ErrBuildQuery.SetPlace(op).SetCause(err)
+	}
+
+	_, err = d.conn(ctx).Exec(ctx, query, args...)
+	if err != nil {
+		return repository.ErrQueryFailed.SetPlace(op).SetCause(err)
+	}
+
+	return nil
+}
+
+func (d *Database) GetRefreshTokenByHash(ctx context.Context, tokenHash string) (models.RefreshToken, error) {
+	const op = "postgres.GetRefreshTokenByHash"
+
+	query, args, err := sqBuilder.
+		Select(refreshTokenColumns...).
+		From(tblRefreshTokens).
+		Where(sq.Eq{"token_hash": tokenHash}).
+		ToSql()
+
+	if err != nil {
+		return models.RefreshToken{}, repository.ErrBuildQuery.SetPlace(op).SetCause(err)
+	}
+
+	token, err := scanRefreshToken(d.conn(ctx).QueryRow(ctx, query, args...))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return models.RefreshToken{}, repository.ErrNotFound.SetPlace(op).SetCause(err)
+		}
+		return models.RefreshToken{}, repository.ErrQueryFailed.SetPlace(op).SetCause(err)
+	}
+
+	return token, nil
+}
+
+func (d *Database) RevokeRefreshToken(ctx context.Context, id models.RefreshTokenID, revokedAt time.Time) error {
+	const op = "postgres.RevokeRefreshToken"
+
+	// Условие revoked_at IS NULL не дает двум параллельным ротациям использовать один токен
+	query, args, err := sqBuilder.
+		Update(tblRefreshTokens).
+		Set("revoked_at", revokedAt).
+		Where(sq.Eq{"id": id, "revoked_at": nil}).
+		ToSql()
+
+	if err != nil {
+		return repository.ErrBuildQuery.SetPlace(op).SetCause(err)
+	}
+
+	tag, err := d.conn(ctx).Exec(ctx, query, args...)
+	if err != nil {
+		return repository.ErrQueryFailed.SetPlace(op).SetCause(err)
+	}
+	if tag.RowsAffected() == 0 {
+		return repository.ErrNotFound.SetPlace(op)
+	}
+
+	return nil
+}
+
+func (d *Database) RevokeUserRefreshTokens(ctx context.Context, userID models.UserID, revokedAt time.Time) error {
+	const op = "postgres.RevokeUserRefreshTokens"
+
+	query, args, err := sqBuilder.
+		Update(tblRefreshTokens).
+		Set("revoked_at", revokedAt).
+		Where(sq.Eq{"user_id": userID, "revoked_at": nil}).
+		ToSql()
+
+	if err != nil {
+		return repository.ErrBuildQuery.SetPlace(op).SetCause(err)
+	}
+
+	_, err = d.conn(ctx).Exec(ctx, query, args...)
+	if err != nil {
+		return repository.ErrQueryFailed.SetPlace(op).SetCause(err)
+	}
+
+	return nil
+}
+
+func (d *Database) RevokeAccessToken(ctx context.Context, tokenID string, expiresAt time.Time) error {
+	const op = "postgres.RevokeAccessToken"
+
+	query, args, err := sqBuilder.
+		Insert(tblRevokedAccessTokens).
+		Columns("token_id", "expires_at").
+		Values(tokenID, expiresAt).
+		Suffix("ON CONFLICT DO NOTHING").
+		ToSql()
+
+	if err != nil {
+		return repository.ErrBuildQuery.SetPlace(op).SetCause(err)
+	}
+
+	_, err = d.conn(ctx).Exec(ctx, query, args...)
+	if err != nil {
+		return repository.ErrQueryFailed.SetPlace(op).SetCause(err)
+	}
+
+	return nil
+}
+
+func (d *Database) IsAccessTokenRevoked(ctx context.Context, tokenID string) (bool, error) {
+	const op = "postgres.IsAccessTokenRevoked"
+
+	query, args, err := sqBuilder.
+		Select("1").
+		Prefix("SELECT EXISTS (").
+		From(tblRevokedAccessTokens).
+		Where(sq.Eq{"token_id": tokenID}).
+		Suffix(")").
+		ToSql()
+
+	if err != nil {
+		return false, repository.ErrBuildQuery.SetPlace(op).SetCause(err)
+	}
+
+	var revoked bool
+	if err = d.conn(ctx).QueryRow(ctx, query, args...).Scan(&revoked); err != nil {
+		return false, repository.ErrQueryFailed.SetPlace(op).SetCause(err)
+	}
+
+	return revoked, nil
+}
+
+func (d *Database) DeleteExpiredAuthTokens(ctx context.Context, now time.Time) error {
+	const op = "postgres.DeleteExpiredAuthTokens"
+
+	for _, table := range []string{tblRefreshTokens, tblRevokedAccessTokens} {
+		query, args, err := sqBuilder.
+			Delete(table).
+			Where(sq.Lt{"expires_at": now}).
+			ToSql()
+
+		if err != nil {
+			return repository.ErrBuildQuery.SetPlace(op).SetCause(err)
+		}
+
+		if _, err = d.conn(ctx).Exec(ctx, query, args...); err != nil {
+			return repository.ErrQueryFailed.SetPlace(op).SetCause(err)
+		}
+	}
+
+	return nil
+}
+
+func scanRefreshToken(row pgx.Row) (models.RefreshToken, error) {
+	var token models.RefreshToken
+	err := row.Scan(
+		&token.ID,
+		&token.UserID,
+		&token.TokenHash,
+		&token.AccessTokenID,
+		&token.AccessExpiresAt,
+		&token.ExpiresAt,
+		&token.RevokedAt,
+		&token.CreatedAt,
+	)
+
+	return token, err
+}
@@ -24,7 +24,7 @@ func (d *Database) CreateContext(ctx context.Context, context models.Context) er
 		return repository.ErrBuildQuery.SetPlace(op).SetCause(err)
 	}
 
-	_, err = d.pool.Exec(ctx, query, args...)
+	_, err = d.conn(ctx).Exec(ctx, query, args...)
 	if err != nil {
 		return repository.ErrQueryFailed.SetPlace(op).SetCause(err)
 	}
@@ -46,7 +46,7 @@ func (d *Database) GetContextByID(ctx context.Context, id models.ContextID) (mod
 	}
 
 	var context models.Context
-	err = d.pool.QueryRow(ctx, query, args...).Scan(
+	err = d.conn(ctx).QueryRow(ctx, query, args...).Scan(
 		&context.ID,
 		&context.UserID,
 		&context.Type,
@@ -83,7 +83,7 @@ func (d *Database) GetContextsByUserID(ctx context.Context, userID models.UserID
 		return nil, repository.ErrBuildQuery.SetPlace(op).SetCause(err)
 	}
 
-	rows, err := d.pool.Query(ctx, query, args...)
+	rows, err := d.conn(ctx).Query(ctx, query, args...)
 	if err != nil {
 		return nil, repository.ErrQueryFailed.SetPlace(op).SetCause(err)
 	}
@@ -135,7 +135,7 @@ func (d *Database) SearchContexts(ctx context.Context, userID models.UserID, que
 		return nil, repository.ErrBuildQuery.SetPlace(op).SetCause(err)
 	}
 
-	rows, err := d.pool.Query(ctx, sqlQuery, args...)
+	rows, err := d.conn(ctx).Query(ctx, sqlQuery, args...)
 	if err != nil {
 		return nil, repository.ErrQueryFailed.SetPlace(op).SetCause(err)
 	}
@@ -177,37 +177,43 @@ func (d *Database) UpdateContext(ctx context.Context, context models.Context) er
 		Set("color", context.Color).
 		Set("deadline_at", context.DeadlineAt).
 		Set("updated_at", context.UpdatedAt).
-		Where(sq.Eq{"id": context.ID}).
+		Where(sq.Eq{"id": context.ID, "user_id": context.UserID}).
 		ToSql()
 
 	if err != nil {
 		return repository.ErrBuildQuery.SetPlace(op).SetCause(err)
 	}
 
-	_, err = d.pool.Exec(ctx, query, args...)
+	tag, err := d.conn(ctx).Exec(ctx, query, args...)
 	if err != nil {
 		return repository.ErrQueryFailed.SetPlace(op).SetCause(err)
 	}
+	if tag.RowsAffected() == 0 {
+		return repository.ErrNotFound.SetPlace(op)
+	}
 
 	return nil
 }
 
-func (d *Database) DeleteContext(ctx context.Context, id models.ContextID) error {
+func (d *Database) DeleteContext(ctx context.Context, userID models.UserID, id models.ContextID) error {
 	const op = "postgres.DeleteContext"
 
 	query, args, err := sqBuilder.
 		Delete(tblContexts).
-		Where(sq.Eq{"id": id}).
+		Where(sq.Eq{"id": id, "user_id": userID}).
 		ToSql()
 
 	if err != nil {
 		return repository.ErrBuildQuery.SetPlace(op).SetCause(err)
 	}
 
-	_, err = d.pool.Exec(ctx, query, args...)
+	tag, err := d.conn(ctx).Exec(ctx, query, args...)
 	if err != nil {
 		return repository.ErrQueryFailed.SetPlace(op).SetCause(err)
 	}
+	if tag.RowsAffected() == 0 {
+		return repository.ErrNotFound.SetPlace(op)
+	}
 
 	return nil
 }
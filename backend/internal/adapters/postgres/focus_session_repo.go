@@ -0,0 +1,203 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	sq "github.com/Masterminds/squirrel"
+	"github.com/jackc/pgx/v5"
+
+	"github.com/singl3focus/uniflow/internal/core/models"
+	"github.com/singl3focus/uniflow/internal/core/ports/repository"
+)
+
+var focusSessionColumns = []string{
+	"id", "user_id", "context_id", "duration_minutes", "started_at", "ended_at", "created_at", "updated_at",
+}
+
+func (d *Database) CreateFocusSession(ctx context.Context, session models.FocusSession) error {
+	const op = "postgres.CreateFocusSession"
+
+	query, args, err := sqBuilder.
+		Insert(tblFocusSessions).
+		Columns(focusSessionColumns...).
+		Values(
+			session.ID, session.UserID, session.ContextID, session.DurationMinutes,
+			session.StartedAt, session.EndedAt, session.CreatedAt, session.UpdatedAt,
+		).
+		ToSql()
+
+	if err != nil {
+		return repository.ErrBuildQuery.SetPlace(op).SetCause(err)
+	}
+
+	_, err = d.conn(ctx).Exec(ctx, query, args...)
+	if err != nil {
+		return repository.ErrQueryFailed.SetPlace(op).SetCause(err)
+	}
+
+	return nil
+}
+
+func (d *Database) GetFocusSessionByID(ctx context.Context, id models.FocusSessionID) (models.FocusSession, error) {
+	const op = "postgres.GetFocusSessionByID"
+
+	query, args, err := sqBuilder.
+		Select(focusSessionColumns...).
+		From(tblFocusSessions).
+		Where(sq.Eq{"id": id}).
+		ToSql()
+
+	if err != nil {
+		return models.FocusSession{}, repository.ErrBuildQuery.SetPlace(op).SetCause(err)
+	}
+
+	session, err := scanFocusSession(d.conn(ctx).QueryRow(ctx, query, args...))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return models.FocusSession{}, repository.ErrNotFound.SetPlace(op).SetCause(err)
+		}
+		return models.FocusSession{}, repository.ErrQueryFailed.SetPlace(op).SetCause(err)
+	}
+
+	return session, nil
+}
+
+func (d *Database) GetFocusSessionsByUserID(ctx context.Context, userID models.UserID) ([]models.FocusSession, error) {
+	const op = "postgres.GetFocusSessionsByUserID"
+
+	query, args, err := sqBuilder.
+		Select(focusSessionColumns...).
+		From(tblFocusSessions).
+		Where(sq.Eq{"user_id": userID}).
+		OrderBy("started_at DESC").
+		ToSql()
+
+	if err != nil {
+		return nil, repository.ErrBuildQuery.SetPlace(op).SetCause(err)
+	}
+
+	rows, err := d.conn(ctx).Query(ctx, query, args...)
+	if err != nil {
+		return nil, repository.ErrQueryFailed.SetPlace(op).SetCause(err)
+	}
+	defer rows.Close()
+
+	var sessions []models.FocusSession
+	for rows.Next() {
+		session, err := scanFocusSession(rows)
+		if err != nil {
+			return nil, repository.ErrQueryFailed.SetPlace(op).SetCause(err)
+		}
+		sessions = append(sessions, session)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, repository.ErrQueryFailed.SetPlace(op).SetCause(err)
+	}
+
+	return sessions, nil
+}
+
+func (d *Database) GetActiveFocusSession(ctx context.Context, userID models.UserID, now time.Time) (models.FocusSession, error) {
+	const op = "postgres.GetActiveFocusSession"
+
+	query, args, err := sqBuilder.
+		Select(focusSessionColumns...).
+		From(tblFocusSessions).
+		Where(sq.And{
+			sq.Eq{"user_id": userID},
+			sq.Eq{"ended_at": nil},
+			sq.Expr("started_at + duration_minutes * INTERVAL '1 minute' > ?", now),
+		}).
+		OrderBy("started_at DESC").
+		Limit(1).
+		ToSql()
+
+	if err != nil {
+		return models.FocusSession{}, repository.ErrBuildQuery.SetPlace(op).SetCause(err)
+	}
+
+	session, err := scanFocusSession(d.conn(ctx).QueryRow(ctx, query, args...))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return models.FocusSession{}, repository.ErrNotFound.SetPlace(op).SetCause(err)
+		}
+		return models.FocusSession{}, repository.ErrQueryFailed.SetPlace(op).SetCause(err)
+	}
+
+	return session, nil
+}
+
+func (d *Database) LockUserFocusSessions(ctx context.Context, userID models.UserID) error {
+	const op = "postgres.LockUserFocusSessions"
+
+	// Блокируется строка пользователя: у каждой сессии она есть по внешнему ключу
+	query, args, err := sqBuilder.
+		Select("id").
+		From(tblUsers).
+		Where(sq.Eq{"id": userID}).
+		Suffix("FOR UPDATE").
+		ToSql()
+
+	if err != nil {
+		return repository.ErrBuildQuery.SetPlace(op).SetCause(err)
+	}
+
+	rows, err := d.conn(ctx).Query(ctx, query, args...)
+	if err != nil {
+		return repository.ErrQueryFailed.SetPlace(op).SetCause(err)
+	}
+	rows.Close()
+
+	if err = rows.Err(); err != nil {
+		return repository.ErrQueryFailed.SetPlace(op).SetCause(err)
+	}
+
+	return nil
+}
+
+func (d *Database) UpdateFocusSession(ctx context.Context, session models.FocusSession) error {
+	const op = "postgres.UpdateFocusSession"
+
+	query, args, err := sqBuilder.
+		Update(tblFocusSessions).
+		Set("context_id", session.ContextID).
+		Set("duration_minutes", session.DurationMinutes).
+		Set("ended_at", session.EndedAt).
+		Set("updated_at", session.UpdatedAt).
+		Where(sq.Eq{"id": session.ID}).
+		ToSql()
+
+	if err != nil {
+		return repository.ErrBuildQuery.SetPlace(op).SetCause(err)
+	}
+
+	_, err = d.conn(ctx).Exec(ctx, query, args...)
+	if err != nil {
+		return repository.ErrQueryFailed.SetPlace(op).SetCause(err)
+	}
+
+	return nil
+}
+
+func scanFocusSession(row pgx.Row) (models.FocusSession, error) {
+	var session models.FocusSession
+
+	err := row.Scan(
+		&session.ID,
+		&session.UserID,
+		&session.ContextID,
+		&session.DurationMinutes,
+		&session.StartedAt,
+		&session.EndedAt,
+		&session.CreatedAt,
+		&session.UpdatedAt,
+	)
+	if err != nil {
+		return models.FocusSession{}, err
+	}
+
+	return session, nil
+}
@@ -48,4 +48,10 @@ const (
 	tblNotifications   = "uniflow.notifications"
 	tblNotes           = "uniflow.notes"
 	tblFocusSessions   = "uniflow.focus_sessions"
+	tblSubtasks        = "uniflow.subtasks"
+	tblTags            = "uniflow.tags"
+	tblTaskTags        = "uniflow.task_tags"
+
+	tblRefreshTokens       = "uniflow.refresh_tokens"
+	tblRevokedAccessTokens = "uniflow.revoked_access_tokens"
 )
@@ -0,0 +1,226 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+
+	sq "github.com/Masterminds/squirrel"
+	"github.com/jackc/pgx/v5"
+
+	"github.com/singl3focus/uniflow/internal/core/models"
+	"github.com/singl3focus/uniflow/internal/core/ports/repository"
+)
+
+var noteColumns = []string{
+	"id", "user_id", "context_id", "type", "content_url", "text", "created_at", "updated_at",
+}
+
+func (d *Database) CreateNote(ctx context.Context, note models.Note) error {
+	const op = "postgres.CreateNote"
+
+	query, args, err := sqBuilder.
+		Insert(tblNotes).
+		Columns(noteColumns...).
+		Values(note.ID, note.UserID, note.ContextID, note.Type, note.ContentURL, note.Text, note.CreatedAt, note.UpdatedAt).
+		ToSql()
+
+	if err != nil {
+		return repository.ErrBuildQuery.SetPlace(op).SetCause(err)
+	}
+
+	_, err = d.conn(ctx).Exec(ctx, query, args...)
+	if err != nil {
+		return repository.ErrQueryFailed.SetPlace(op).SetCause(err)
+	}
+
+	return nil
+}
+
+func (d *Database) GetNoteByID(ctx context.Context, id models.NoteID) (models.Note, error) {
+	const op = "postgres.GetNoteByID"
+
+	query, args, err := sqBuilder.
+		Select(noteColumns...).
+		From(tblNotes).
+		Where(sq.Eq{"id": id}).
+		ToSql()
+
+	if err != nil {
+		return models.Note{}, repository.ErrBuildQuery.SetPlace(op).SetCause(err)
+	}
+
+	note, err := scanNote(d.conn(ctx).QueryRow(ctx, query, args...))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return models.Note{}, repository.ErrNotFound.SetPlace(op).SetCause(err)
+		}
+		return models.Note{}, repository.ErrQueryFailed.SetPlace(op).SetCause(err)
+	}
+
+	return note, nil
+}
+
+func (d *Database) GetNotesByUserID(ctx context.Context, userID models.UserID) ([]models.Note, error) {
+	const op = "postgres.GetNotesByUserID"
+
+	query, args, err := sqBuilder.
+		Select(noteColumns...).
+		From(tblNotes).
+		Where(sq.Eq{"user_id": userID}).
+		OrderBy("created_at DESC").
+		ToSql()
+
+	if err != nil {
+		return nil, repository.ErrBuildQuery.SetPlace(op).SetCause(err)
+	}
+
+	rows, err := d.conn(ctx).Query(ctx, query, args...)
+	if err != nil {
+		return nil, repository.ErrQueryFailed.SetPlace(op).SetCause(err)
+	}
+	defer rows.Close()
+
+	return scanNotes(rows, op)
+}
+
+func (d *Database) GetNotesByContextID(ctx context.Context, contextID models.ContextID) ([]models.Note, error) {
+	const op = "postgres.GetNotesByContextID"
+
+	query, args, err := sqBuilder.
+		Select(noteColumns...).
+		From(tblNotes).
+		Where(sq.Eq{"context_id": contextID}).
+		OrderBy("created_at DESC").
+		ToSql()
+
+	if err != nil {
+		return nil, repository.ErrBuildQuery.SetPlace(op).SetCause(err)
+	}
+
+	rows, err := d.conn(ctx).Query(ctx, query, args...)
+	if err != nil {
+		return nil, repository.ErrQueryFailed.SetPlace(op).SetCause(err)
+	}
+	defer rows.Close()
+
+	return scanNotes(rows, op)
+}
+
+func (d *Database) SearchNotes(ctx context.Context, userID models.UserID, query string) ([]models.Note, error) {
+	const op = "postgres.SearchNotes"
+
+	searchQuery := "%" + query + "%"
+
+	sqlQuery, args, err := sqBuilder.
+		Select(noteColumns...).
+		From(tblNotes).
+		Where(sq.And{
+			sq.Eq{"user_id": userID},
+			sq.ILike{"text": searchQuery},
+		}).
+		OrderBy("created_at DESC").
+		ToSql()
+
+	if err != nil {
+		return nil, repository.ErrBuildQuery.SetPlace(op).SetCause(err)
+	}
+
+	rows, err := d.conn(ctx).Query(ctx, sqlQuery, args...)
+	if err != nil {
+		return nil, repository.ErrQueryFailed.SetPlace(op).SetCause(err)
+	}
+	defer rows.Close()
+
+	return scanNotes(rows, op)
+}
+
+func (d *Database) UpdateNote(ctx context.Context, note models.Note) error {
+	const op = "postgres.UpdateNote"
+
+	query, args, err := sqBuilder.
+		Update(tblNotes).
+		Set("context_id", note.ContextID).
+		Set("content_url", note.ContentURL).
+		Set("text", note.Text).
+		Set("updated_at", note.UpdatedAt).
+		Where(sq.Eq{"id": note.ID}).
+		ToSql()
+
+	if err != nil {
+		return repository.ErrBuildQuery.SetPlace(op).SetCause(err)
+	}
+
+	_, err = d.conn(ctx).Exec(ctx, query, args...)
+	if err != nil {
+		return repository.ErrQueryFailed.SetPlace(op).SetCause(err)
+	}
+
+	return nil
+}
+
+func (d *Database) DeleteNote(ctx context.Context, id models.NoteID) error {
+	const op = "postgres.DeleteNote"
+
+	query, args, err := sqBuilder.
+		Delete(tblNotes).
+		Where(sq.Eq{"id": id}).
+		ToSql()
+
+	if err != nil {
+		return repository.ErrBuildQuery.SetPlace(op).SetCause(err)
+	}
+
+	_, err = d.conn(ctx).Exec(ctx, query, args...)
+	if err != nil {
+		return repository.ErrQueryFailed.SetPlace(op).SetCause(err)
+	}
+
+	return nil
+}
+
+func scanNote(row pgx.Row) (models.Note, error) {
+	var (
+		note             models.Note
+		contentURL, text *string
+	)
+
+	err := row.Scan(
+		&note.ID,
+		&note.UserID,
+		&note.ContextID,
+		&note.Type,
+		&contentURL,
+		&text,
+		&note.CreatedAt,
+		&note.UpdatedAt,
+	)
+	if err != nil {
+		return models.Note{}, err
+	}
+
+	if contentURL != nil {
+		note.ContentURL = *contentURL
+	}
+	if text != nil {
+		note.Text = *text
+	}
+
+	return note, nil
+}
+
+func scanNotes(rows pgx.Rows, op string) ([]models.Note, error) {
+	var notes []models.Note
+	for rows.Next() {
+		note, err := scanNote(rows)
+		if err != nil {
+			return nil, repository.ErrQueryFailed.SetPlace(op).SetCause(err)
+		}
+		notes = append(notes, note)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, repository.ErrQueryFailed.SetPlace(op).SetCause(err)
+	}
+
+	return notes, nil
+}
@@ -0,0 +1,287 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"time"
+
+	sq "github.com/Masterminds/squirrel"
+	"github.com/jackc/pgx/v5"
+
+	"github.com/singl3focus/uniflow/internal/core/models"
+	"github.com/singl3focus/uniflow/internal/core/ports/repository"
+)
+
+var notificationColumns = []string{
+	"id", "user_id", "task_id", "focus_session_id", "notify_at", "channel", "status", "message",
+	"attempts", "last_error", "locked_until", "created_at", "updated_at", "sent_at",
+}
+
+// unsentNotificationStatuses - уведомления, которые еще могут быть доставлены: ждущие и захваченные воркером
+var unsentNotificationStatuses = []models.NotificationStatus{models.NotificationStatusPending, models.NotificationStatusProcessing}
+
+func (d *Database) CreateNotification(ctx context.Context, notification models.Notification) error {
+	const op = "postgres.CreateNotification"
+
+	query, args, err := sqBuilder.
+		Insert(tblNotifications).
+		Columns(notificationColumns...).
+		Values(
+			notification.ID, notification.UserID, notification.TaskID, notification.FocusSessionID, notification.NotifyAt, notification.Channel,
+			notification.Status, notification.Message, notification.Attempts, notification.LastError,
+			notification.LockedUntil, notification.CreatedAt, notification.UpdatedAt, notification.SentAt,
+		).
+		ToSql()
+
+	if err != nil {
+		return repository.ErrBuildQuery.SetPlace(op).SetCause(err)
+	}
+
+	_, err = d.conn(ctx).Exec(ctx, query, args...)
+	if err != nil {
+		return repository.ErrQueryFailed.SetPlace(op).SetCause(err)
+	}
+
+	return nil
+}
+
+func (d *Database) GetNotificationByID(ctx context.Context, id models.NotificationID) (models.Notification, error) {
+	const op = "postgres.GetNotificationByID"
+
+	query, args, err := sqBuilder.
+		Select(notificationColumns...).
+		From(tblNotifications).
+		Where(sq.Eq{"id": id}).
+		ToSql()
+
+	if err != nil {
+		return models.Notification{}, repository.ErrBuildQuery.SetPlace(op).SetCause(err)
+	}
+
+	n, err := scanNotification(d.conn(ctx).QueryRow(ctx, query, args...))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return models.Notification{}, repository.ErrNotFound.SetPlace(op).SetCause(err)
+		}
+		return models.Notification{}, repository.ErrQueryFailed.SetPlace(op).SetCause(err)
+	}
+
+	return n, nil
+}
+
+func (d *Database) GetPendingNotifications(ctx context.Context, before time.Time) ([]models.Notification, error) {
+	const op = "postgres.GetPendingNotifications"
+
+	query, args, err := sqBuilder.
+		Select(notificationColumns...).
+		From(tblNotifications).
+		Where(sq.And{
+			sq.Eq{"status": models.NotificationStatusPending},
+			sq.LtOrEq{"notify_at": before},
+		}).
+		OrderBy("notify_at ASC").
+		ToSql()
+
+	if err != nil {
+		return nil, repository.ErrBuildQuery.SetPlace(op).SetCause(err)
+	}
+
+	rows, err := d.conn(ctx).Query(ctx, query, args...)
+	if err != nil {
+		return nil, repository.ErrQueryFailed.SetPlace(op).SetCause(err)
+	}
+	defer rows.Close()
+
+	return scanNotifications(rows, op)
+}
+
+func (d *Database) ClaimDueNotifications(ctx context.Context, now, lockedUntil time.Time, limit int) ([]models.Notification, error) {
+	const op = "postgres.ClaimDueNotifications"
+
+	// Подзапрос строится без плейсхолдеров Dollar: внешний билдер пронумерует их сам.
+	// SKIP LOCKED позволяет нескольким репликам забирать разные пачки без ожидания друг друга,
+	// а просроченная аренда возвращает в работу уведомления упавшего воркера.
+	due := sq.
+		Select("id").
+		From(tblNotifications).
+		Where(sq.Or{
+			sq.And{
+				sq.Eq{"status": models.NotificationStatusPending},
+				sq.LtOrEq{"notify_at": now},
+			},
+			sq.And{
+				sq.Eq{"status": models.NotificationStatusProcessing},
+				sq.Lt{"locked_until": now},
+			},
+		}).
+		OrderBy("notify_at ASC").
+		Limit(uint64(limit)).
+		Suffix("FOR UPDATE SKIP LOCKED")
+
+	query, args, err := sqBuilder.
+		Update(tblNotifications).
+		Set("status", models.NotificationStatusProcessing).
+		Set("locked_until", lockedUntil).
+		Set("attempts", sq.Expr("attempts + 1")).
+		Set("updated_at", now).
+		Where(sq.Expr("id IN (?)", due)).
+		Suffix("RETURNING " + strings.Join(notificationColumns, ", ")).
+		ToSql()
+
+	if err != nil {
+		return nil, repository.ErrBuildQuery.SetPlace(op).SetCause(err)
+	}
+
+	rows, err := d.conn(ctx).Query(ctx, query, args...)
+	if err != nil {
+		return nil, repository.ErrQueryFailed.SetPlace(op).SetCause(err)
+	}
+	defer rows.Close()
+
+	return scanNotifications(rows, op)
+}
+
+func (d *Database) UpdateNotification(ctx context.Context, notification models.Notification) error {
+	const op = "postgres.UpdateNotification"
+
+	query, args, err := sqBuilder.
+		Update(tblNotifications).
+		Set("notify_at", notification.NotifyAt).
+		Set("status", notification.Status).
+		Set("message", notification.Message).
+		Set("attempts", notification.Attempts).
+		Set("last_error", notification.LastError).
+		Set("locked_until", notification.LockedUntil).
+		Set("updated_at", notification.UpdatedAt).
+		Set("sent_at", notification.SentAt).
+		Where(sq.Eq{"id": notification.ID}).
+		ToSql()
+
+	if err != nil {
+		return repository.ErrBuildQuery.SetPlace(op).SetCause(err)
+	}
+
+	_, err = d.conn(ctx).Exec(ctx, query, args...)
+	if err != nil {
+		return repository.ErrQueryFailed.SetPlace(op).SetCause(err)
+	}
+
+	return nil
+}
+
+func (d *Database) DeleteNotification(ctx context.Context, id models.NotificationID) error {
+	const op = "postgres.DeleteNotification"
+
+	query, args, err := sqBuilder.
+		Delete(tblNotifications).
+		Where(sq.Eq{"id": id}).
+		ToSql()
+
+	if err != nil {
+		return repository.ErrBuildQuery.SetPlace(op).SetCause(err)
+	}
+
+	_, err = d.conn(ctx).Exec(ctx, query, args...)
+	if err != nil {
+		return repository.ErrQueryFailed.SetPlace(op).SetCause(err)
+	}
+
+	return nil
+}
+
+func (d *Database) DeletePendingNotificationsByTaskID(ctx context.Context, taskID models.TaskID) error {
+	const op = "postgres.DeletePendingNotificationsByTaskID"
+
+	query, args, err := sqBuilder.
+		Delete(tblNotifications).
+		Where(sq.And{
+			sq.Eq{"task_id": taskID},
+			sq.Eq{"status": unsentNotificationStatuses},
+		}).
+		ToSql()
+
+	if err != nil {
+		return repository.ErrBuildQuery.SetPlace(op).SetCause(err)
+	}
+
+	_, err = d.conn(ctx).Exec(ctx, query, args...)
+	if err != nil {
+		return repository.ErrQueryFailed.SetPlace(op).SetCause(err)
+	}
+
+	return nil
+}
+
+func (d *Database) DeletePendingNotificationsByFocusSessionID(ctx context.Context, sessionID models.FocusSessionID) error {
+	const op = "postgres.DeletePendingNotificationsByFocusSessionID"
+
+	query, args, err := sqBuilder.
+		Delete(tblNotifications).
+		Where(sq.And{
+			sq.Eq{"focus_session_id": sessionID},
+			sq.Eq{"status": unsentNotificationStatuses},
+		}).
+		ToSql()
+
+	if err != nil {
+		return repository.ErrBuildQuery.SetPlace(op).SetCause(err)
+	}
+
+	_, err = d.conn(ctx).Exec(ctx, query, args...)
+	if err != nil {
+		return repository.ErrQueryFailed.SetPlace(op).SetCause(err)
+	}
+
+	return nil
+}
+
+func scanNotification(row pgx.Row) (models.Notification, error) {
+	var (
+		n         models.Notification
+		lastError *string
+	)
+
+	err := row.Scan(
+		&n.ID,
+		&n.UserID,
+		&n.TaskID,
+		&n.FocusSessionID,
+		&n.NotifyAt,
+		&n.Channel,
+		&n.Status,
+		&n.Message,
+		&n.Attempts,
+		&lastError,
+		&n.LockedUntil,
+		&n.CreatedAt,
+		&n.UpdatedAt,
+		&n.SentAt,
+	)
+	if err != nil {
+		return models.Notification{}, err
+	}
+
+	if lastError != nil {
+		n.LastError = *lastError
+	}
+
+	return n, nil
+}
+
+func scanNotifications(rows pgx.Rows, op string) ([]models.Notification, error) {
+	var notifications []models.Notification
+	for rows.Next() {
+		n, err := scanNotification(rows)
+		if err != nil {
+			return nil, repository.ErrQueryFailed.SetPlace(op).SetCause(err)
+		}
+		notifications = append(notifications, n)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, repository.ErrQueryFailed.SetPlace(op).SetCause(err)
+	}
+
+	return notifications, nil
+}
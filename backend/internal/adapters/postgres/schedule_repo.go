@@ -0,0 +1,225 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	sq "github.com/Masterminds/squirrel"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+
+	"github.com/singl3focus/uniflow/internal/core/models"
+	"github.com/singl3focus/uniflow/internal/core/ports/repository"
+)
+
+var scheduleEntryColumns = []string{
+	"id", "user_id", "context_id", "title", "weekday", "start_at", "end_at", "location", "created_at", "updated_at",
+}
+
+func (d *Database) CreateScheduleEntry(ctx context.Context, entry models.ScheduleEntry) error {
+	const op = "postgres.CreateScheduleEntry"
+
+	query, args, err := sqBuilder.
+		Insert(tblScheduleEntries).
+		Columns(scheduleEntryColumns...).
+		Values(
+			entry.ID, entry.UserID, entry.ContextID, entry.Title, entry.Weekday,
+			toPgTime(entry.StartAt), toPgTime(entry.EndAt), entry.Location, entry.CreatedAt, entry.UpdatedAt,
+		).
+		ToSql()
+
+	if err != nil {
+		return repository.ErrBuildQuery.SetPlace(op).SetCause(err)
+	}
+
+	_, err = d.conn(ctx).Exec(ctx, query, args...)
+	if err != nil {
+		return repository.ErrQueryFailed.SetPlace(op).SetCause(err)
+	}
+
+	return nil
+}
+
+func (d *Database) GetScheduleEntryByID(ctx context.Context, id models.ScheduleEntryID) (models.ScheduleEntry, error) {
+	const op = "postgres.GetScheduleEntryByID"
+
+	query, args, err := sqBuilder.
+		Select(scheduleEntryColumns...).
+		From(tblScheduleEntries).
+		Where(sq.Eq{"id": id}).
+		ToSql()
+
+	if err != nil {
+		return models.ScheduleEntry{}, repository.ErrBuildQuery.SetPlace(op).SetCause(err)
+	}
+
+	entry, err := scanScheduleEntry(d.conn(ctx).QueryRow(ctx, query, args...))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return models.ScheduleEntry{}, repository.ErrNotFound.SetPlace(op).SetCause(err)
+		}
+		return models.ScheduleEntry{}, repository.ErrQueryFailed.SetPlace(op).SetCause(err)
+	}
+
+	return entry, nil
+}
+
+func (d *Database) GetScheduleEntriesByUserID(ctx context.Context, userID models.UserID) ([]models.ScheduleEntry, error) {
+	const op = "postgres.GetScheduleEntriesByUserID"
+
+	query, args, err := sqBuilder.
+		Select(scheduleEntryColumns...).
+		From(tblScheduleEntries).
+		Where(sq.Eq{"user_id": userID}).
+		OrderBy("weekday ASC", "start_at ASC").
+		ToSql()
+
+	if err != nil {
+		return nil, repository.ErrBuildQuery.SetPlace(op).SetCause(err)
+	}
+
+	rows, err := d.conn(ctx).Query(ctx, query, args...)
+	if err != nil {
+		return nil, repository.ErrQueryFailed.SetPlace(op).SetCause(err)
+	}
+	defer rows.Close()
+
+	return scanScheduleEntries(rows, op)
+}
+
+func (d *Database) GetScheduleEntriesByWeekday(ctx context.Context, userID models.UserID, weekday models.Weekday) ([]models.ScheduleEntry, error) {
+	const op = "postgres.GetScheduleEntriesByWeekday"
+
+	query, args, err := sqBuilder.
+		Select(scheduleEntryColumns...).
+		From(tblScheduleEntries).
+		Where(sq.And{
+			sq.Eq{"user_id": userID},
+			sq.Eq{"weekday": weekday},
+		}).
+		OrderBy("start_at ASC").
+		ToSql()
+
+	if err != nil {
+		return nil, repository.ErrBuildQuery.SetPlace(op).SetCause(err)
+	}
+
+	rows, err := d.conn(ctx).Query(ctx, query, args...)
+	if err != nil {
+		return nil, repository.ErrQueryFailed.SetPlace(op).SetCause(err)
+	}
+	defer rows.Close()
+
+	return scanScheduleEntries(rows, op)
+}
+
+func (d *Database) UpdateScheduleEntry(ctx context.Context, entry models.ScheduleEntry) error {
+	const op = "postgres.UpdateScheduleEntry"
+
+	query, args, err := sqBuilder.
+		Update(tblScheduleEntries).
+		Set("context_id", entry.ContextID).
+		Set("title", entry.Title).
+		Set("weekday", entry.Weekday).
+		Set("start_at", toPgTime(entry.StartAt)).
+		Set("end_at", toPgTime(entry.EndAt)).
+		Set("location", entry.Location).
+		Set("updated_at", entry.UpdatedAt).
+		Where(sq.Eq{"id": entry.ID}).
+		ToSql()
+
+	if err != nil {
+		return repository.ErrBuildQuery.SetPlace(op).SetCause(err)
+	}
+
+	_, err = d.conn(ctx).Exec(ctx, query, args...)
+	if err != nil {
+		return repository.ErrQueryFailed.SetPlace(op).SetCause(err)
+	}
+
+	return nil
+}
+
+func (d *Database) DeleteScheduleEntry(ctx context.Context, id models.ScheduleEntryID) error {
+	const op = "postgres.DeleteScheduleEntry"
+
+	query, args, err := sqBuilder.
+		Delete(tblScheduleEntries).
+		Where(sq.Eq{"id": id}).
+		ToSql()
+
+	if err != nil {
+		return repository.ErrBuildQuery.SetPlace(op).SetCause(err)
+	}
+
+	_, err = d.conn(ctx).Exec(ctx, query, args...)
+	if err != nil {
+		return repository.ErrQueryFailed.SetPlace(op).SetCause(err)
+	}
+
+	return nil
+}
+
+func scanScheduleEntry(row pgx.Row) (models.ScheduleEntry, error) {
+	var (
+		entry          models.ScheduleEntry
+		startAt, endAt pgtype.Time
+		location       *string
+	)
+
+	err := row.Scan(
+		&entry.ID,
+		&entry.UserID,
+		&entry.ContextID,
+		&entry.Title,
+		&entry.Weekday,
+		&startAt,
+		&endAt,
+		&location,
+		&entry.CreatedAt,
+		&entry.UpdatedAt,
+	)
+	if err != nil {
+		return models.ScheduleEntry{}, err
+	}
+
+	entry.StartAt = fromPgTime(startAt)
+	entry.EndAt = fromPgTime(endAt)
+	if location != nil {
+		entry.Location = *location
+	}
+
+	return entry, nil
+}
+
+func scanScheduleEntries(rows pgx.Rows, op string) ([]models.ScheduleEntry, error) {
+	var entries []models.ScheduleEntry
+	for rows.Next() {
+		entry, err := scanScheduleEntry(rows)
+		if err != nil {
+			return nil, repository.ErrQueryFailed.SetPlace(op).SetCause(err)
+		}
+		entries = append(entries, entry)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, repository.ErrQueryFailed.SetPlace(op).SetCause(err)
+	}
+
+	return entries, nil
+}
+
+// toPgTime переводит время суток в значение колонки TIME
+func toPgTime(t time.Time) pgtype.Time {
+	micros := int64(t.Hour())*int64(time.Hour/time.Microsecond) +
+		int64(t.Minute())*int64(time.Minute/time.Microsecond) +
+		int64(t.Second())*int64(time.Second/time.Microsecond)
+
+	return pgtype.Time{Microseconds: micros, Valid: true}
+}
+
+// fromPgTime переводит значение колонки TIME во время суток (дата 0000-01-01, UTC)
+func fromPgTime(t pgtype.Time) time.Time {
+	return time.Date(0, 1, 1, 0, 0, 0, 0, time.UTC).Add(time.Duration(t.Microseconds) * time.Microsecond)
+}
@@ -0,0 +1,156 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+
+	sq "github.com/Masterminds/squirrel"
+	"github.com/jackc/pgx/v5"
+
+	"github.com/singl3focus/uniflow/internal/core/models"
+	"github.com/singl3focus/uniflow/internal/core/ports/repository"
+)
+
+var subtaskColumns = []string{
+	"id", "task_id", "title", "done", "position", "created_at", "updated_at",
+}
+
+func (d *Database) CreateSubtask(ctx context.Context, subtask models.Subtask) error {
+	const op = "postgres.CreateSubtask"
+
+	query, args, err := sqBuilder.
+		Insert(tblSubtasks).
+		Columns(subtaskColumns...).
+		Values(subtask.ID, subtask.TaskID, subtask.Title, subtask.Done, subtask.Position, subtask.CreatedAt, subtask.UpdatedAt).
+		ToSql()
+
+	if err != nil {
+		return repository.ErrBuildQuery.SetPlace(op).SetCause(err)
+	}
+
+	_, err = d.conn(ctx).Exec(ctx, query, args...)
+	if err != nil {
+		return repository.ErrQueryFailed.SetPlace(op).SetCause(err)
+	}
+
+	return nil
+}
+
+func (d *Database) GetSubtaskByID(ctx context.Context, id models.SubtaskID) (models.Subtask, error) {
+	const op = "postgres.GetSubtaskByID"
+
+	query, args, err := sqBuilder.
+		Select(subtaskColumns...).
+		From(tblSubtasks).
+		Where(sq.Eq{"id": id}).
+		ToSql()
+
+	if err != nil {
+		return models.Subtask{}, repository.ErrBuildQuery.SetPlace(op).SetCause(err)
+	}
+
+	subtask, err := scanSubtask(d.conn(ctx).QueryRow(ctx, query, args...))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return models.Subtask{}, repository.ErrNotFound.SetPlace(op).SetCause(err)
+		}
+		return models.Subtask{}, repository.ErrQueryFailed.SetPlace(op).SetCause(err)
+	}
+
+	return subtask, nil
+}
+
+func (d *Database) GetSubtasksByTaskID(ctx context.Context, taskID models.TaskID) ([]models.Subtask, error) {
+	const op = "postgres.GetSubtasksByTaskID"
+
+	query, args, err := sqBuilder.
+		Select(subtaskColumns...).
+		From(tblSubtasks).
+		Where(sq.Eq{"task_id": taskID}).
+		OrderBy("position ASC", "created_at ASC").
+		ToSql()
+
+	if err != nil {
+		return nil, repository.ErrBuildQuery.SetPlace(op).SetCause(err)
+	}
+
+	rows, err := d.conn(ctx).Query(ctx, query, args...)
+	if err != nil {
+		return nil, repository.ErrQueryFailed.SetPlace(op).SetCause(err)
+	}
+	defer rows.Close()
+
+	var subtasks []models.Subtask
+	for rows.Next() {
+		subtask, err := scanSubtask(rows)
+		if err != nil {
+			return nil, repository.ErrQueryFailed.SetPlace(op).SetCause(err)
+		}
+		subtasks = append(subtasks, subtask)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, repository.ErrQueryFailed.SetPlace(op).SetCause(err)
+	}
+
+	return subtasks, nil
+}
+
+func (d *Database) UpdateSubtask(ctx context.Context, subtask models.Subtask) error {
+	const op = "postgres.UpdateSubtask"
+
+	query, args, err := sqBuilder.
+		Update(tblSubtasks).
+		Set("title", subtask.Title).
+		Set("done", subtask.Done).
+		Set("position", subtask.Position).
+		Set("updated_at", subtask.UpdatedAt).
+		Where(sq.Eq{"id": subtask.ID}).
+		ToSql()
+
+	if err != nil {
+		return repository.ErrBuildQuery.SetPlace(op).SetCause(err)
+	}
+
+	_, err = d.conn(ctx).Exec(ctx, query, args...)
+	if err != nil {
+		return repository.ErrQueryFailed.SetPlace(op).SetCause(err)
+	}
+
+	return nil
+}
+
+func (d *Database) DeleteSubtask(ctx context.Context, id models.SubtaskID) error {
+	const op = "postgres.DeleteSubtask"
+
+	query, args, err := sqBuilder.
+		Delete(tblSubtasks).
+		Where(sq.Eq{"id": id}).
+		ToSql()
+
+	if err != nil {
+		return repository.ErrBuildQuery.SetPlace(op).SetCause(err)
+	}
+
+	_, err = d.conn(ctx).Exec(ctx, query, args...)
+	if err != nil {
+		return repository.ErrQueryFailed.SetPlace(op).SetCause(err)
+	}
+
+	return nil
+}
+
+func scanSubtask(row pgx.Row) (models.Subtask, error) {
+	var subtask models.Subtask
+	err := row.Scan(
+		&subtask.ID,
+		&subtask.TaskID,
+		&subtask.Title,
+		&subtask.Done,
+		&subtask.Position,
+		&subtask.CreatedAt,
+		&subtask.UpdatedAt,
+	)
+
+	return subtask, err
+}
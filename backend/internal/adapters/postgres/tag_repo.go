@@ -0,0 +1,200 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+
+	sq "github.com/Masterminds/squirrel"
+	"github.com/jackc/pgx/v5"
+
+	"github.com/singl3focus/uniflow/internal/core/models"
+	"github.com/singl3focus/uniflow/internal/core/ports/repository"
+)
+
+var tagColumns = []string{
+	"id", "user_id", "name", "created_at",
+}
+
+func (d *Database) CreateTag(ctx context.Context, tag models.Tag) error {
+	const op = "postgres.CreateTag"
+
+	query, args, err := sqBuilder.
+		Insert(tblTags).
+		Columns(tagColumns...).
+		Values(tag.ID, tag.UserID, tag.Name, tag.CreatedAt).
+		// Конфликт не прерывает транзакцию, в которой тег создается вместе с задачей
+		Suffix("ON CONFLICT (user_id, name) DO NOTHING").
+		ToSql()
+
+	if err != nil {
+		return repository.ErrBuildQuery.SetPlace(op).SetCause(err)
+	}
+
+	cmd, err := d.conn(ctx).Exec(ctx, query, args...)
+	if err != nil {
+		return repository.ErrQueryFailed.SetPlace(op).SetCause(err)
+	}
+	if cmd.RowsAffected() == 0 {
+		return repository.ErrAlreadyExists.SetPlace(op)
+	}
+
+	return nil
+}
+
+func (d *Database) GetTagByID(ctx context.Context, id models.TagID) (models.Tag, error) {
+	const op = "postgres.GetTagByID"
+
+	query, args, err := sqBuilder.
+		Select(tagColumns...).
+		From(tblTags).
+		Where(sq.Eq{"id": id}).
+		ToSql()
+
+	if err != nil {
+		return models.Tag{}, repository.ErrBuildQuery.SetPlace(op).SetCause(err)
+	}
+
+	tag, err := scanTag(d.conn(ctx).QueryRow(ctx, query, args...))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return models.Tag{}, repository.ErrNotFound.SetPlace(op).SetCause(err)
+		}
+		return models.Tag{}, repository.ErrQueryFailed.SetPlace(op).SetCause(err)
+	}
+
+	return tag, nil
+}
+
+func (d *Database) GetTagByName(ctx context.Context, userID models.UserID, name string) (models.Tag, error) {
+	const op = "postgres.GetTagByName"
+
+	query, args, err := sqBuilder.
+		Select(tagColumns...).
+		From(tblTags).
+		Where(sq.Eq{"user_id": userID, "name": name}).
+		ToSql()
+
+	if err != nil {
+		return models.Tag{}, repository.ErrBuildQuery.SetPlace(op).SetCause(err)
+	}
+
+	tag, err := scanTag(d.conn(ctx).QueryRow(ctx, query, args...))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return models.Tag{}, repository.ErrNotFound.SetPlace(op).SetCause(err)
+		}
+		return models.Tag{}, repository.ErrQueryFailed.SetPlace(op).SetCause(err)
+	}
+
+	return tag, nil
+}
+
+func (d *Database) GetTagsByUserID(ctx context.Context, userID models.UserID) ([]models.Tag, error) {
+	const op = "postgres.GetTagsByUserID"
+
+	query, args, err := sqBuilder.
+		Select(tagColumns...).
+		From(tblTags).
+		Where(sq.Eq{"user_id": userID}).
+		OrderBy("name ASC").
+		ToSql()
+
+	if err != nil {
+		return nil, repository.ErrBuildQuery.SetPlace(op).SetCause(err)
+	}
+
+	rows, err := d.conn(ctx).Query(ctx, query, args...)
+	if err != nil {
+		return nil, repository.ErrQueryFailed.SetPlace(op).SetCause(err)
+	}
+	defer rows.Close()
+
+	var tags []models.Tag
+	for rows.Next() {
+		tag, err := scanTag(rows)
+		if err != nil {
+			return nil, repository.ErrQueryFailed.SetPlace(op).SetCause(err)
+		}
+		tags = append(tags, tag)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, repository.ErrQueryFailed.SetPlace(op).SetCause(err)
+	}
+
+	return tags, nil
+}
+
+func (d *Database) DeleteTag(ctx context.Context, id models.TagID) error {
+	const op = "postgres.DeleteTag"
+
+	query, args, err := sqBuilder.
+		Delete(tblTags).
+		Where(sq.Eq{"id": id}).
+		ToSql()
+
+	if err != nil {
+		return repository.ErrBuildQuery.SetPlace(op).SetCause(err)
+	}
+
+	_, err = d.conn(ctx).Exec(ctx, query, args...)
+	if err != nil {
+		return repository.ErrQueryFailed.SetPlace(op).SetCause(err)
+	}
+
+	return nil
+}
+
+func (d *Database) AddTagToTask(ctx context.Context, taskID models.TaskID, tagID models.TagID) error {
+	const op = "postgres.AddTagToTask"
+
+	query, args, err := sqBuilder.
+		Insert(tblTaskTags).
+		Columns("task_id", "tag_id").
+		Values(taskID, tagID).
+		Suffix("ON CONFLICT DO NOTHING").
+		ToSql()
+
+	if err != nil {
+		return repository.ErrBuildQuery.SetPlace(op).SetCause(err)
+	}
+
+	_, err = d.conn(ctx).Exec(ctx, query, args...)
+	if err != nil {
+		return repository.ErrQueryFailed.SetPlace(op).SetCause(err)
+	}
+
+	return nil
+}
+
+func (d *Database) RemoveTagFromTask(ctx context.Context, taskID models.TaskID, tagID models.TagID) error {
+	const op = "postgres.RemoveTagFromTask"
+
+	query, args, err := sqBuilder.
+		Delete(tblTaskTags).
+		Where(sq.Eq{"task_id": taskID, "tag_id": tagID}).
+		ToSql()
+
+	if err != nil {
+		return repository.ErrBuildQuery.SetPlace(op).SetCause(err)
+	}
+
+	_, err = d.conn(ctx).Exec(ctx, query, args...)
+	if err != nil {
+		return repository.ErrQueryFailed.SetPlace(op).SetCause(err)
+	}
+
+	return nil
+}
+
+func scanTag(row pgx.Row) (models.Tag, error) {
+	var tag models.Tag
+	err := row.Scan(
+		&tag.ID,
+		&tag.UserID,
+		&tag.Name,
+		&tag.CreatedAt,
+	)
+
+	return tag, err
+}
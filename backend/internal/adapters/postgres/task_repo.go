@@ -12,20 +12,31 @@ import (
 	"github.com/singl3focus/uniflow/internal/core/ports/repository"
 )
 
+var taskColumns = []string{
+	"id", "user_id", "context_id", "title", "description", "status", "priority", "due_at", "due_all_day", "completed_at", "recurrence", "created_at", "updated_at",
+}
+
+// taskTagsColumn - имена тегов задачи, собранные подзапросом в массив
+const taskTagsColumn = "COALESCE((SELECT array_agg(tg.name ORDER BY tg.name) FROM " + tblTaskTags + " tt " +
+	"JOIN " + tblTags + " tg ON tg.id = tt.tag_id WHERE tt.task_id = tasks.id), '{}') AS tags"
+
+// taskSelectColumns - колонки для чтения задачи: хранимые поля и ее теги
+var taskSelectColumns = append(append([]string{}, taskColumns...), taskTagsColumn)
+
 func (d *Database) CreateTask(ctx context.Context, task models.Task) error {
 	const op = "postgres.CreateTask"
 
 	query, args, err := sqBuilder.
 		Insert(tblTasks).
-		Columns("id", "user_id", "context_id", "title", "description", "status", "due_at", "completed_at", "created_at", "updated_at").
-		Values(task.ID, task.UserID, task.ContextID, task.Title, task.Description, task.Status, task.DueAt, task.CompletedAt, task.CreatedAt, task.UpdatedAt).
+		Columns(taskColumns...).
+		Values(task.ID, task.UserID, task.ContextID, task.Title, task.Description, task.Status, task.Priority, task.DueAt, task.AllDay, task.CompletedAt, task.Recurrence, task.CreatedAt, task.UpdatedAt).
 		ToSql()
 
 	if err != nil {
 		return repository.ErrBuildQuery.SetPlace(op).SetCause(err)
 	}
 
-	_, err = d.pool.Exec(ctx, query, args...)
+	_, err = d.conn(ctx).Exec(ctx, query, args...)
 	if err != nil {
 		return repository.ErrQueryFailed.SetPlace(op).SetCause(err)
 	}
@@ -37,7 +48,7 @@ func (d *Database) GetTaskByID(ctx context.Context, id models.TaskID) (models.Ta
 	const op = "postgres.GetTaskByID"
 
 	query, args, err := sqBuilder.
-		Select("id", "user_id", "context_id", "title", "description", "status", "due_at", "completed_at", "created_at", "updated_at").
+		Select(taskSelectColumns...).
 		From(tblTasks).
 		Where(sq.Eq{"id": id}).
 		ToSql()
@@ -46,20 +57,7 @@ func (d *Database) GetTaskByID(ctx context.Context, id models.TaskID) (models.Ta
 		return models.Task{}, repository.ErrBuildQuery.SetPlace(op).SetCause(err)
 	}
 
-	var task models.Task
-	err = d.pool.QueryRow(ctx, query, args...).Scan(
-		&task.ID,
-		&task.UserID,
-		&task.ContextID,
-		&task.Title,
-		&task.Description,
-		&task.Status,
-		&task.DueAt,
-		&task.CompletedAt,
-		&task.CreatedAt,
-		&task.UpdatedAt,
-	)
-
+	task, err := scanTask(d.conn(ctx).QueryRow(ctx, query, args...))
 	if err != nil {
 		if errors.Is(err, pgx.ErrNoRows) {
 			return models.Task{}, repository.ErrNotFound.SetPlace(op).SetCause(
@@ -78,7 +76,7 @@ func (d *Database) GetTasksByUserID(ctx context.Context, userID models.UserID) (
 	const op = "postgres.GetTasksByUserID"
 
 	query, args, err := sqBuilder.
-		Select("id", "user_id", "context_id", "title", "description", "status", "due_at", "completed_at", "created_at", "updated_at").
+		Select(taskSelectColumns...).
 		From(tblTasks).
 		Where(sq.Eq{"user_id": userID}).
 		OrderBy("created_at DESC").
@@ -88,7 +86,7 @@ func (d *Database) GetTasksByUserID(ctx context.Context, userID models.UserID) (
 		return nil, repository.ErrBuildQuery.SetPlace(op).SetCause(err)
 	}
 
-	rows, err := d.pool.Query(ctx, query, args...)
+	rows, err := d.conn(ctx).Query(ctx, query, args...)
 	if err != nil {
 		return nil, repository.ErrQueryFailed.SetPlace(op).SetCause(err)
 	}
@@ -101,7 +99,7 @@ func (d *Database) GetTasksByContextID(ctx context.Context, contextID models.Con
 	const op = "postgres.GetTasksByContextID"
 
 	query, args, err := sqBuilder.
-		Select("id", "user_id", "context_id", "title", "description", "status", "due_at", "completed_at", "created_at", "updated_at").
+		Select(taskSelectColumns...).
 		From(tblTasks).
 		Where(sq.Eq{"context_id": contextID}).
 		OrderBy("created_at DESC").
@@ -111,7 +109,7 @@ func (d *Database) GetTasksByContextID(ctx context.Context, contextID models.Con
 		return nil, repository.ErrBuildQuery.SetPlace(op).SetCause(err)
 	}
 
-	rows, err := d.pool.Query(ctx, query, args...)
+	rows, err := d.conn(ctx).Query(ctx, query, args...)
 	if err != nil {
 		return nil, repository.ErrQueryFailed.SetPlace(op).SetCause(err)
 	}
@@ -120,29 +118,29 @@ func (d *Database) GetTasksByContextID(ctx context.Context, contextID models.Con
 	return d.scanTasks(rows, op)
 }
 
-func (d *Database) GetTasksDueToday(ctx context.Context, userID models.UserID) ([]models.Task, error) {
-	const op = "postgres.GetTasksDueToday"
+func (d *Database) SearchTasks(ctx context.Context, userID models.UserID, query string) ([]models.Task, error) {
+	const op = "postgres.SearchTasks"
 
-	now := time.Now()
-	startOfDay := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
-	endOfDay := startOfDay.Add(24 * time.Hour)
+	searchQuery := "%" + query + "%"
 
-	query, args, err := sqBuilder.
-		Select("id", "user_id", "context_id", "title", "description", "status", "due_at", "completed_at", "created_at", "updated_at").
+	sqlQuery, args, err := sqBuilder.
+		Select(taskSelectColumns...).
 		From(tblTasks).
 		Where(sq.And{
 			sq.Eq{"user_id": userID},
-			sq.GtOrEq{"due_at": startOfDay},
-			sq.Lt{"due_at": endOfDay},
+			sq.Or{
+				sq.ILike{"title": searchQuery},
+				sq.ILike{"description": searchQuery},
+			},
 		}).
-		OrderBy("due_at ASC").
+		OrderBy("created_at DESC").
 		ToSql()
 
 	if err != nil {
 		return nil, repository.ErrBuildQuery.SetPlace(op).SetCause(err)
 	}
 
-	rows, err := d.pool.Query(ctx, query, args...)
+	rows, err := d.conn(ctx).Query(ctx, sqlQuery, args...)
 	if err != nil {
 		return nil, repository.ErrQueryFailed.SetPlace(op).SetCause(err)
 	}
@@ -151,29 +149,72 @@ func (d *Database) GetTasksDueToday(ctx context.Context, userID models.UserID) (
 	return d.scanTasks(rows, op)
 }
 
-func (d *Database) SearchTasks(ctx context.Context, userID models.UserID, query string) ([]models.Task, error) {
-	const op = "postgres.SearchTasks"
+// ListTasks возвращает задачи пользователя, подходящие под filter, в порядке filter.Sort.
+// Фильтр должен быть предварительно проверен через TaskFilter.Validate.
+func (d *Database) ListTasks(ctx context.Context, userID models.UserID, filter models.TaskFilter) ([]models.Task, error) {
+	const op = "postgres.ListTasks"
 
-	searchQuery := "%" + query + "%"
+	where := sq.And{sq.Eq{"user_id": userID}}
 
-	sqlQuery, args, err := sqBuilder.
-		Select("id", "user_id", "context_id", "title", "description", "status", "due_at", "completed_at", "created_at", "updated_at").
+	if len(filter.Statuses) > 0 {
+		where = append(where, sq.Eq{"status": filter.Statuses})
+	}
+	if filter.ContextID != nil {
+		where = append(where, sq.Eq{"context_id": *filter.ContextID})
+	}
+	if filter.InboxOnly {
+		where = append(where, sq.Eq{"context_id": nil})
+	}
+	if len(filter.Priorities) > 0 {
+		where = append(where, sq.Eq{"priority": filter.Priorities})
+	}
+	if filter.Tag != "" {
+		where = append(where, sq.Expr("id IN (SELECT tt.task_id FROM "+tblTaskTags+" tt JOIN "+tblTags+
+			" tg ON tg.id = tt.tag_id WHERE tg.user_id = ? AND tg.name = ?)", userID, filter.Tag))
+	}
+	if filter.DueFrom != nil {
+		where = append(where, sq.GtOrEq{"due_at": *filter.DueFrom})
+	}
+	if filter.DueTo != nil {
+		where = append(where, sq.Lt{"due_at": *filter.DueTo})
+	}
+	if filter.Text != "" {
+		searchQuery := "%" + filter.Text + "%"
+		where = append(where, sq.Or{
+			sq.ILike{"title": searchQuery},
+			sq.ILike{"description": searchQuery},
+		})
+	}
+
+	keys := taskSortKeys(filter.Sort)
+	if filter.Cursor != nil {
+		where = append(where, keysetAfter(keys, *filter.Cursor))
+	}
+
+	orderBy := make([]string, 0, len(keys))
+	for _, key := range keys {
+		if key.desc {
+			orderBy = append(orderBy, key.expr+" DESC")
+		} else {
+			orderBy = append(orderBy, key.expr+" ASC")
+		}
+	}
+
+	builder := sqBuilder.
+		Select(taskSelectColumns...).
 		From(tblTasks).
-		Where(sq.And{
-			sq.Eq{"user_id": userID},
-			sq.Or{
-				sq.ILike{"title": searchQuery},
-				sq.ILike{"description": searchQuery},
-			},
-		}).
-		OrderBy("created_at DESC").
-		ToSql()
+		Where(where).
+		OrderBy(orderBy...)
+	if filter.Limit > 0 {
+		builder = builder.Limit(uint64(filter.Limit))
+	}
 
+	query, args, err := builder.ToSql()
 	if err != nil {
 		return nil, repository.ErrBuildQuery.SetPlace(op).SetCause(err)
 	}
 
-	rows, err := d.pool.Query(ctx, sqlQuery, args...)
+	rows, err := d.conn(ctx).Query(ctx, query, args...)
 	if err != nil {
 		return nil, repository.ErrQueryFailed.SetPlace(op).SetCause(err)
 	}
@@ -182,6 +223,79 @@ func (d *Database) SearchTasks(ctx context.Context, userID models.UserID, query
 	return d.scanTasks(rows, op)
 }
 
+// taskSortKey - выражение сортировки списка задач и соответствующее ему значение курсора
+type taskSortKey struct {
+	expr  string
+	desc  bool
+	value func(c models.TaskCursor) any
+}
+
+// noDueDate подставляется вместо отсутствующего дедлайна, чтобы такие задачи шли последними
+var noDueDate = time.Date(9999, 12, 31, 0, 0, 0, 0, time.UTC)
+
+var (
+	// Ранг приоритета, совпадает с models.TaskPriority.Rank
+	sortKeyPriority = taskSortKey{
+		expr:  "(CASE priority WHEN 'urgent' THEN 3 WHEN 'high' THEN 2 WHEN 'low' THEN 0 ELSE 1 END)",
+		desc:  true,
+		value: func(c models.TaskCursor) any { return c.PriorityRank },
+	}
+	sortKeyDue = taskSortKey{
+		expr: "COALESCE(due_at, '9999-12-31 00:00:00+00'::timestamptz)",
+		value: func(c models.TaskCursor) any {
+			if c.DueAt == nil {
+				return noDueDate
+			}
+			return *c.DueAt
+		},
+	}
+	sortKeyCreated = taskSortKey{
+		expr:  "created_at",
+		desc:  true,
+		value: func(c models.TaskCursor) any { return c.CreatedAt },
+	}
+	// id делает порядок строгим, иначе курсор может пропустить задачи с одинаковым created_at
+	sortKeyID = taskSortKey{
+		expr:  "id",
+		desc:  true,
+		value: func(c models.TaskCursor) any { return c.ID },
+	}
+)
+
+// taskSortKeys повторяет порядок models.SortTasks
+func taskSortKeys(by models.TaskSort) []taskSortKey {
+	switch by {
+	case models.TaskSortPriority:
+		return []taskSortKey{sortKeyPriority, sortKeyDue, sortKeyCreated, sortKeyID}
+	case models.TaskSortDue:
+		return []taskSortKey{sortKeyDue, sortKeyCreated, sortKeyID}
+	default:
+		return []taskSortKey{sortKeyCreated, sortKeyID}
+	}
+}
+
+// keysetAfter строит условие "строка идет после курсора":
+// (k1 после v1) OR (k1 = v1 AND k2 после v2) OR ...
+func keysetAfter(keys []taskSortKey, cursor models.TaskCursor) sq.Or {
+	cond := make(sq.Or, 0, len(keys))
+	for i, key := range keys {
+		and := make(sq.And, 0, i+1)
+		for _, prev := range keys[:i] {
+			and = append(and, sq.Expr(prev.expr+" = ?", prev.value(cursor)))
+		}
+
+		op := " > ?"
+		if key.desc {
+			op = " < ?"
+		}
+		and = append(and, sq.Expr(key.expr+op, key.value(cursor)))
+
+		cond = append(cond, and)
+	}
+
+	return cond
+}
+
 func (d *Database) UpdateTask(ctx context.Context, task models.Task) error {
 	const op = "postgres.UpdateTask"
 
@@ -191,40 +305,49 @@ func (d *Database) UpdateTask(ctx context.Context, task models.Task) error {
 		Set("title", task.Title).
 		Set("description", task.Description).
 		Set("status", task.Status).
+		Set("priority", task.Priority).
 		Set("due_at", task.DueAt).
+		Set("due_all_day", task.AllDay).
 		Set("completed_at", task.CompletedAt).
+		Set("recurrence", task.Recurrence).
 		Set("updated_at", task.UpdatedAt).
-		Where(sq.Eq{"id": task.ID}).
+		Where(sq.Eq{"id": task.ID, "user_id": task.UserID}).
 		ToSql()
 
 	if err != nil {
 		return repository.ErrBuildQuery.SetPlace(op).SetCause(err)
 	}
 
-	_, err = d.pool.Exec(ctx, query, args...)
+	tag, err := d.conn(ctx).Exec(ctx, query, args...)
 	if err != nil {
 		return repository.ErrQueryFailed.SetPlace(op).SetCause(err)
 	}
+	if tag.RowsAffected() == 0 {
+		return repository.ErrNotFound.SetPlace(op)
+	}
 
 	return nil
 }
 
-func (d *Database) DeleteTask(ctx context.Context, id models.TaskID) error {
+func (d *Database) DeleteTask(ctx context.Context, userID models.UserID, id models.TaskID) error {
 	const op = "postgres.DeleteTask"
 
 	query, args, err := sqBuilder.
 		Delete(tblTasks).
-		Where(sq.Eq{"id": id}).
+		Where(sq.Eq{"id": id, "user_id": userID}).
 		ToSql()
 
 	if err != nil {
 		return repository.ErrBuildQuery.SetPlace(op).SetCause(err)
 	}
 
-	_, err = d.pool.Exec(ctx, query, args...)
+	tag, err := d.conn(ctx).Exec(ctx, query, args...)
 	if err != nil {
 		return repository.ErrQueryFailed.SetPlace(op).SetCause(err)
 	}
+	if tag.RowsAffected() == 0 {
+		return repository.ErrNotFound.SetPlace(op)
+	}
 
 	return nil
 }
@@ -232,24 +355,38 @@ func (d *Database) DeleteTask(ctx context.Context, id models.TaskID) error {
 func (d *Database) scanTasks(rows pgx.Rows, op string) ([]models.Task, error) {
 	var tasks []models.Task
 	for rows.Next() {
-		var task models.Task
-		err := rows.Scan(
-			&task.ID,
-			&task.UserID,
-			&task.ContextID,
-			&task.Title,
-			&task.Description,
-			&task.Status,
-			&task.DueAt,
-			&task.CompletedAt,
-			&task.CreatedAt,
-			&task.UpdatedAt,
-		)
+		task, err := scanTask(rows)
 		if err != nil {
 			return nil, repository.ErrQueryFailed.SetPlace(op).SetCause(err)
 		}
 		tasks = append(tasks, task)
 	}
 
+	if err := rows.Err(); err != nil {
+		return nil, repository.ErrQueryFailed.SetPlace(op).SetCause(err)
+	}
+
 	return tasks, nil
 }
+
+func scanTask(row pgx.Row) (models.Task, error) {
+	var task models.Task
+	err := row.Scan(
+		&task.ID,
+		&task.UserID,
+		&task.ContextID,
+		&task.Title,
+		&task.Description,
+		&task.Status,
+		&task.Priority,
+		&task.DueAt,
+		&task.AllDay,
+		&task.CompletedAt,
+		&task.Recurrence,
+		&task.CreatedAt,
+		&task.UpdatedAt,
+		&task.Tags,
+	)
+
+	return task, err
+}
@@ -0,0 +1,53 @@
+package postgres
+
+import (
+	"context"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+
+	"github.com/singl3focus/uniflow/internal/core/ports/repository"
+)
+
+// querier - общие методы пула и транзакции, через которые выполняются запросы
+type querier interface {
+	Exec(ctx context.Context, sql string, args ...any) (pgconn.CommandTag, error)
+	Query(ctx context.Context, sql string, args ...any) (pgx.Rows, error)
+	QueryRow(ctx context.Context, sql string, args ...any) pgx.Row
+}
+
+type txKey struct{}
+
+// conn возвращает транзакцию, начатую WithinTransaction, или пул, если ctx вне транзакции
+func (d *Database) conn(ctx context.Context) querier {
+	if tx, ok := ctx.Value(txKey{}).(pgx.Tx); ok {
+		return tx
+	}
+	return d.pool
+}
+
+// WithinTransaction выполняет fn в одной транзакции. Вложенный вызов переиспользует внешнюю транзакцию.
+func (d *Database) WithinTransaction(ctx context.Context, fn func(ctx context.Context) error) error {
+	const op = "postgres.WithinTransaction"
+
+	if _, ok := ctx.Value(txKey{}).(pgx.Tx); ok {
+		return fn(ctx)
+	}
+
+	tx, err := d.pool.Begin(ctx)
+	if err != nil {
+		return repository.ErrQueryFailed.SetPlace(op).SetCause(err)
+	}
+	// После Commit откат ничего не делает
+	defer func() { _ = tx.Rollback(ctx) }()
+
+	if err = fn(context.WithValue(ctx, txKey{}, tx)); err != nil {
+		return err
+	}
+
+	if err = tx.Commit(ctx); err != nil {
+		return repository.ErrQueryFailed.SetPlace(op).SetCause(err)
+	}
+
+	return nil
+}
@@ -3,6 +3,9 @@ package postgres
 import (
 	"context"
 	"errors"
+	"fmt"
+	"strings"
+	"time"
 
 	sq "github.com/Masterminds/squirrel"
 	"github.com/jackc/pgx/v5"
@@ -12,20 +15,50 @@ import (
 	"github.com/singl3focus/uniflow/internal/core/ports/repository"
 )
 
+var userSelectColumns = []string{
+	"id", "max_user_id", "timezone", "language", "first_weekday", "reminder_offsets",
+	"morning_digest_at", "evening_review_at", "created_at", "updated_at",
+}
+
+// digestColumns - колонка со временем следующей сводки каждого вида
+var digestColumns = map[models.DigestKind]string{
+	models.DigestKindMorning: "next_morning_digest_at",
+	models.DigestKindEvening: "next_evening_review_at",
+}
+
+func scanUser(row pgx.Row) (models.User, error) {
+	var user models.User
+	err := row.Scan(
+		&user.ID,
+		&user.MaxUserID,
+		&user.Settings.Timezone,
+		&user.Settings.Language,
+		&user.Settings.FirstWeekday,
+		&user.Settings.ReminderOffsets,
+		&user.Settings.MorningDigestAt,
+		&user.Settings.EveningReviewAt,
+		&user.CreatedAt,
+		&user.UpdatedAt,
+	)
+	return user, err
+}
+
 func (d *Database) CreateUser(ctx context.Context, user models.User) error {
 	const op = "postgres.CreateUser"
 
 	query, args, err := sqBuilder.
 		Insert(tblUsers).
-		Columns("id", "max_user_id", "created_at", "updated_at").
-		Values(user.ID, user.MaxUserID, user.CreatedAt, user.UpdatedAt).
+		Columns(userSelectColumns...).
+		Values(user.ID, user.MaxUserID, user.Settings.Timezone, user.Settings.Language,
+			user.Settings.FirstWeekday, user.Settings.ReminderOffsets,
+			user.Settings.MorningDigestAt, user.Settings.EveningReviewAt, user.CreatedAt, user.UpdatedAt).
 		ToSql()
 
 	if err != nil {
 		return repository.ErrBuildQuery.SetPlace(op).SetCause(err)
 	}
 
-	_, err = d.pool.Exec(ctx, query, args...)
+	_, err = d.conn(ctx).Exec(ctx, query, args...)
 	if err != nil {
 		var pgErr *pgconn.PgError
 		if errors.As(err, &pgErr) && pgErr.Code == "23505" {
@@ -41,7 +74,7 @@ func (d *Database) GetUserByMaxUserID(ctx context.Context, maxUserID string) (mo
 	const op = "postgres.GetUserByMaxUserID"
 
 	query, args, err := sqBuilder.
-		Select("id", "max_user_id", "created_at", "updated_at").
+		Select(userSelectColumns...).
 		From(tblUsers).
 		Where(sq.Eq{"max_user_id": maxUserID}).
 		ToSql()
@@ -50,14 +83,7 @@ func (d *Database) GetUserByMaxUserID(ctx context.Context, maxUserID string) (mo
 		return models.User{}, repository.ErrBuildQuery.SetPlace(op).SetCause(err)
 	}
 
-	var user models.User
-	err = d.pool.QueryRow(ctx, query, args...).Scan(
-		&user.ID,
-		&user.MaxUserID,
-		&user.CreatedAt,
-		&user.UpdatedAt,
-	)
-
+	user, err := scanUser(d.conn(ctx).QueryRow(ctx, query, args...))
 	if err != nil {
 		if errors.Is(err, pgx.ErrNoRows) {
 			return models.User{}, repository.ErrNotFound.SetPlace(op).SetCause(err)
@@ -72,7 +98,7 @@ func (d *Database) GetUserByID(ctx context.Context, id models.UserID) (models.Us
 	const op = "postgres.GetUserByID"
 
 	query, args, err := sqBuilder.
-		Select("id", "max_user_id", "created_at", "updated_at").
+		Select(userSelectColumns...).
 		From(tblUsers).
 		Where(sq.Eq{"id": id}).
 		ToSql()
@@ -81,14 +107,7 @@ func (d *Database) GetUserByID(ctx context.Context, id models.UserID) (models.Us
 		return models.User{}, repository.ErrBuildQuery.SetPlace(op).SetCause(err)
 	}
 
-	var user models.User
-	err = d.pool.QueryRow(ctx, query, args...).Scan(
-		&user.ID,
-		&user.MaxUserID,
-		&user.CreatedAt,
-		&user.UpdatedAt,
-	)
-
+	user, err := scanUser(d.conn(ctx).QueryRow(ctx, query, args...))
 	if err != nil {
 		if errors.Is(err, pgx.ErrNoRows) {
 			return models.User{}, repository.ErrNotFound.SetPlace(op).SetCause(err)
@@ -98,3 +117,114 @@ func (d *Database) GetUserByID(ctx context.Context, id models.UserID) (models.Us
 
 	return user, nil
 }
+
+func (d *Database) UpdateUserSettings(ctx context.Context, id models.UserID, settings models.UserSettings, updatedAt time.Time) error {
+	const op = "postgres.UpdateUserSettings"
+
+	query, args, err := sqBuilder.
+		Update(tblUsers).
+		Set("timezone", settings.Timezone).
+		Set("language", settings.Language).
+		Set("first_weekday", settings.FirstWeekday).
+		Set("reminder_offsets", settings.ReminderOffsets).
+		Set("morning_digest_at", settings.MorningDigestAt).
+		Set("evening_review_at", settings.EveningReviewAt).
+		Set("updated_at", updatedAt).
+		Where(sq.Eq{"id": id}).
+		ToSql()
+
+	if err != nil {
+		return repository.ErrBuildQuery.SetPlace(op).SetCause(err)
+	}
+
+	tag, err := d.conn(ctx).Exec(ctx, query, args...)
+	if err != nil {
+		return repository.ErrQueryFailed.SetPlace(op).SetCause(err)
+	}
+
+	if tag.RowsAffected() == 0 {
+		return repository.ErrNotFound.SetPlace(op)
+	}
+
+	return nil
+}
+
+func (d *Database) SetNextDigestAt(ctx context.Context, id models.UserID, kind models.DigestKind, at *time.Time) error {
+	const op = "postgres.SetNextDigestAt"
+
+	column, ok := digestColumns[kind]
+	if !ok {
+		return repository.ErrBuildQuery.SetPlace(op).SetCause(fmt.Errorf("unknown digest kind %q", kind))
+	}
+
+	query, args, err := sqBuilder.
+		Update(tblUsers).
+		Set(column, at).
+		Where(sq.Eq{"id": id}).
+		ToSql()
+
+	if err != nil {
+		return repository.ErrBuildQuery.SetPlace(op).SetCause(err)
+	}
+
+	tag, err := d.conn(ctx).Exec(ctx, query, args...)
+	if err != nil {
+		return repository.ErrQueryFailed.SetPlace(op).SetCause(err)
+	}
+
+	if tag.RowsAffected() == 0 {
+		return repository.ErrNotFound.SetPlace(op)
+	}
+
+	return nil
+}
+
+func (d *Database) ClaimDueDigests(ctx context.Context, kind models.DigestKind, now, lockedUntil time.Time, limit int) ([]models.User, error) {
+	const op = "postgres.ClaimDueDigests"
+
+	column, ok := digestColumns[kind]
+	if !ok {
+		return nil, repository.ErrBuildQuery.SetPlace(op).SetCause(fmt.Errorf("unknown digest kind %q", kind))
+	}
+
+	// Как и в ClaimDueNotifications: SKIP LOCKED разводит реплики по разным пользователям,
+	// а сдвиг на lockedUntil вернет сводку в работу, если захватившая ее реплика упадет
+	due := sq.
+		Select("id").
+		From(tblUsers).
+		Where(sq.LtOrEq{column: now}).
+		OrderBy(column + " ASC").
+		Limit(uint64(limit)).
+		Suffix("FOR UPDATE SKIP LOCKED")
+
+	query, args, err := sqBuilder.
+		Update(tblUsers).
+		Set(column, lockedUntil).
+		Where(sq.Expr("id IN (?)", due)).
+		Suffix("RETURNING " + strings.Join(userSelectColumns, ", ")).
+		ToSql()
+
+	if err != nil {
+		return nil, repository.ErrBuildQuery.SetPlace(op).SetCause(err)
+	}
+
+	rows, err := d.conn(ctx).Query(ctx, query, args...)
+	if err != nil {
+		return nil, repository.ErrQueryFailed.SetPlace(op).SetCause(err)
+	}
+	defer rows.Close()
+
+	var users []models.User
+	for rows.Next() {
+		user, err := scanUser(rows)
+		if err != nil {
+			return nil, repository.ErrQueryFailed.SetPlace(op).SetCause(err)
+		}
+		users = append(users, user)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, repository.ErrQueryFailed.SetPlace(op).SetCause(err)
+	}
+
+	return users, nil
+}
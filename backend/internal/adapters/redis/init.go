@@ -2,9 +2,13 @@ package redis
 
 import (
 	"context"
+	"errors"
+	"strconv"
 	"time"
 
 	"github.com/redis/go-redis/v9"
+
+	"github.com/singl3focus/uniflow/internal/core/ports/cache"
 )
 
 type RedisAdapter struct {
@@ -26,13 +30,30 @@ func (r *RedisAdapter) Set(ctx context.Context, key string, value interface{}, t
 }
 
 func (r *RedisAdapter) Get(ctx context.Context, key string) (string, error) {
-	return r.client.Get(ctx, key).Result()
+	value, err := r.client.Get(ctx, key).Result()
+	if errors.Is(err, redis.Nil) {
+		return "", cache.ErrCacheMiss
+	}
+	return value, err
 }
 
 func (r *RedisAdapter) Del(ctx context.Context, keys ...string) error {
 	return r.client.Del(ctx, keys...).Err()
 }
 
+// delIfEqualScript сравнивает и удаляет ключ за одну операцию Redis
+var delIfEqualScript = redis.NewScript(`
+if redis.call("GET", KEYS[1]) == ARGV[1] then
+	return redis.call("DEL", KEYS[1])
+end
+return 0
+`)
+
+func (r *RedisAdapter) DelIfEqual(ctx context.Context, key, value string) (bool, error) {
+	deleted, err := delIfEqualScript.Run(ctx, r.client, []string{key}, value).Int64()
+	return deleted > 0, err
+}
+
 func (r *RedisAdapter) Exists(ctx context.Context, keys ...string) (int64, error) {
 	return r.client.Exists(ctx, keys...).Result()
 }
@@ -71,6 +92,27 @@ func (r *RedisAdapter) LRange(ctx context.Context, key string, start, stop int64
 	return r.client.LRange(ctx, key, start, stop).Result()
 }
 
+// --- Sorted sets ---
+
+func (r *RedisAdapter) ZAdd(ctx context.Context, key string, score float64, member string) error {
+	return r.client.ZAdd(ctx, key, redis.Z{Score: score, Member: member}).Err()
+}
+
+func (r *RedisAdapter) ZRangeByScore(ctx context.Context, key string, min, max float64) ([]string, error) {
+	return r.client.ZRangeByScore(ctx, key, &redis.ZRangeBy{
+		Min: strconv.FormatFloat(min, 'f', -1, 64),
+		Max: strconv.FormatFloat(max, 'f', -1, 64),
+	}).Result()
+}
+
+func (r *RedisAdapter) ZRem(ctx context.Context, key string, members ...string) (int64, error) {
+	args := make([]interface{}, len(members))
+	for i, m := range members {
+		args[i] = m
+	}
+	return r.client.ZRem(ctx, key, args...).Result()
+}
+
 // --- Management ---
 
 func (r *RedisAdapter) Ping(ctx context.Context) error {
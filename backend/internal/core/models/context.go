@@ -79,6 +79,19 @@ func isValidContextType(t ContextType) bool {
 	}
 }
 
+// SetType меняет тип контекста
+func (c *Context) SetType(t ContextType) error {
+	const op = "models.Context.SetType"
+
+	if !isValidContextType(t) {
+		return ErrInvalidContextType.SetPlace(op).SetCause(errors.New("invalid type"))
+	}
+
+	c.Type = t
+	c.UpdatedAt = time.Now()
+	return nil
+}
+
 func (c *Context) Update(title, description, color *string, deadlineAt *time.Time) {
 	if title != nil {
 		c.Title = *title
@@ -0,0 +1,30 @@
+package models
+
+import (
+	"time"
+)
+
+// DigestKind - вид ежедневной сводки
+type DigestKind string
+
+const (
+	DigestKindMorning DigestKind = "morning" // Утренняя сводка: задачи, просрочки и занятия на сегодня
+	DigestKindEvening DigestKind = "evening" // Вечерний обзор: незавершенные задачи дня
+)
+
+// DigestKinds - все виды сводок, которые рассылает планировщик
+var DigestKinds = []DigestKind{DigestKindMorning, DigestKindEvening}
+
+// DailyDigest - содержимое сводки на один день пользователя
+type DailyDigest struct {
+	Kind    DigestKind
+	Date    time.Time       // Полночь дня сводки в часовом поясе пользователя
+	Today   []Task          // Задачи со сроком сегодня; в вечернем обзоре только незавершенные
+	Overdue []Task          // Незавершенные задачи со сроком раньше сегодняшнего дня
+	Classes []ScheduleEntry // Занятия на сегодня; только в утренней сводке
+}
+
+// IsEmpty сообщает, что в сводке нет ни задач, ни занятий
+func (d DailyDigest) IsEmpty() bool {
+	return len(d.Today) == 0 && len(d.Overdue) == 0 && len(d.Classes) == 0
+}
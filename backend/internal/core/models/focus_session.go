@@ -1,9 +1,12 @@
 package models
 
 import (
+	"errors"
 	"time"
 
 	"github.com/google/uuid"
+
+	"github.com/singl3focus/uniflow/pkg/errs"
 )
 
 type FocusSessionID = uuid.UUID
@@ -12,19 +15,34 @@ func ParseFocusSessionID(id string) (FocusSessionID, error) {
 	return uuid.Parse(id)
 }
 
+const (
+	DefaultFocusMinutes = 25
+	MaxFocusMinutes     = 240
+)
+
 type FocusSession struct {
-	ID              FocusSessionID
-	UserID          UserID
-	ContextID       *ContextID // Опционально: привязка к контексту
-	DurationMinutes int
-	StartedAt       time.Time
-	EndedAt         *time.Time
-	CreatedAt       time.Time
-	UpdatedAt       time.Time
+	ID              FocusSessionID `json:"id"`
+	UserID          UserID         `json:"user_id"`
+	ContextID       *ContextID     `json:"context_id,omitempty"` // Опционально: привязка к контексту
+	DurationMinutes int            `json:"duration_minutes"`     // Запланированная длительность
+	StartedAt       time.Time      `json:"started_at"`
+	EndedAt         *time.Time     `json:"ended_at,omitempty"` // Заполняется при остановке сессии
+	CreatedAt       time.Time      `json:"created_at"`
+	UpdatedAt       time.Time      `json:"updated_at"`
 }
 
-func NewFocusSession(userID UserID, contextID *ContextID, durationMinutes int) FocusSession {
-	now := time.Now()
+var (
+	ErrInvalidFocusDuration = errs.New("invalid focus session duration")
+	ErrFocusSessionActive   = errs.New("focus session already active")
+	ErrFocusSessionEnded    = errs.New("focus session already ended")
+)
+
+func NewFocusSession(userID UserID, contextID *ContextID, durationMinutes int, now time.Time) (FocusSession, error) {
+	const op = "models.NewFocusSession"
+
+	if durationMinutes < 1 || durationMinutes > MaxFocusMinutes {
+		return FocusSession{}, ErrInvalidFocusDuration.SetPlace(op).SetCause(errors.New("duration must be 1-240 minutes"))
+	}
 
 	return FocusSession{
 		ID:              FocusSessionID(uuid.New()),
@@ -34,11 +52,40 @@ func NewFocusSession(userID UserID, contextID *ContextID, durationMinutes int) F
 		StartedAt:       now,
 		CreatedAt:       now,
 		UpdatedAt:       now,
+	}, nil
+}
+
+// PlannedEndAt - момент, когда истекает запланированное время сессии
+func (f *FocusSession) PlannedEndAt() time.Time {
+	return f.StartedAt.Add(time.Duration(f.DurationMinutes) * time.Minute)
+}
+
+// IsActive - сессия не остановлена и ее время еще не истекло
+func (f *FocusSession) IsActive(now time.Time) bool {
+	return f.EndedAt == nil && now.Before(f.PlannedEndAt())
+}
+
+// FocusedDuration - фактически отработанное время: до остановки, но не дольше запланированного
+func (f *FocusSession) FocusedDuration(now time.Time) time.Duration {
+	end := f.PlannedEndAt()
+	if f.EndedAt != nil && f.EndedAt.Before(end) {
+		end = *f.EndedAt
+	}
+	if now.Before(end) {
+		end = now
 	}
+	if end.Before(f.StartedAt) {
+		return 0
+	}
+	return end.Sub(f.StartedAt)
 }
 
-func (f *FocusSession) End() {
-	now := time.Now()
-	f.EndedAt = &now
+// End останавливает сессию в момент now. Если время уже истекло, окончанием считается запланированный конец.
+func (f *FocusSession) End(now time.Time) {
+	end := now
+	if planned := f.PlannedEndAt(); planned.Before(now) {
+		end = planned
+	}
+	f.EndedAt = &end
 	f.UpdatedAt = now
 }
@@ -25,30 +25,25 @@ const (
 )
 
 type Note struct {
-	ID         NoteID
-	UserID     UserID
-	ContextID  *ContextID // Опционально: привязка к контексту
-	Type       NoteType
-	ContentURL string // URL для медиа-файлов
-	Text       string // Текстовое содержимое
-	CreatedAt  time.Time
-	UpdatedAt  time.Time
+	ID         NoteID     `json:"id"`
+	UserID     UserID     `json:"user_id"`
+	ContextID  *ContextID `json:"context_id,omitempty"` // Опционально: привязка к контексту
+	Type       NoteType   `json:"type"`
+	ContentURL string     `json:"content_url,omitempty"` // URL для медиа-файлов
+	Text       string     `json:"text"`                  // Текстовое содержимое
+	CreatedAt  time.Time  `json:"created_at"`
+	UpdatedAt  time.Time  `json:"updated_at"`
 }
 
 var (
 	ErrInvalidNoteType = errs.New("invalid note type")
+	ErrEmptyNote       = errs.New("empty note")
 )
 
 func NewNote(userID UserID, contextID *ContextID, noteType NoteType, contentURL, text string) (Note, error) {
-	const op = "models.NewNote"
-
-	if !isValidNoteType(noteType) {
-		return Note{}, ErrInvalidNoteType.SetPlace(op).SetCause(errors.New("invalid note type"))
-	}
-
 	now := time.Now()
 
-	return Note{
+	note := Note{
 		ID:         NoteID(uuid.New()),
 		UserID:     userID,
 		ContextID:  contextID,
@@ -57,7 +52,32 @@ func NewNote(userID UserID, contextID *ContextID, noteType NoteType, contentURL,
 		Text:       text,
 		CreatedAt:  now,
 		UpdatedAt:  now,
-	}, nil
+	}
+
+	if err := note.Validate(); err != nil {
+		return Note{}, err
+	}
+
+	return note, nil
+}
+
+// Validate проверяет инварианты заметки: текстовой нужен текст, медиа - ссылка на файл
+func (n *Note) Validate() error {
+	const op = "models.Note.Validate"
+
+	if !isValidNoteType(n.Type) {
+		return ErrInvalidNoteType.SetPlace(op).SetCause(errors.New("invalid note type"))
+	}
+
+	if n.Type == NoteTypeText && n.Text == "" {
+		return ErrEmptyNote.SetPlace(op).SetCause(errors.New("text note cannot be empty"))
+	}
+
+	if n.Type != NoteTypeText && n.ContentURL == "" {
+		return ErrEmptyNote.SetPlace(op).SetCause(errors.New("media note requires content url"))
+	}
+
+	return nil
 }
 
 func isValidNoteType(t NoteType) bool {
@@ -78,3 +98,9 @@ func (n *Note) Update(text *string, contentURL *string) {
 	}
 	n.UpdatedAt = time.Now()
 }
+
+// AssignContext привязывает заметку к контексту (nil - отвязать)
+func (n *Note) AssignContext(contextID *ContextID) {
+	n.ContextID = contextID
+	n.UpdatedAt = time.Now()
+}
@@ -21,22 +21,27 @@ const (
 type NotificationStatus string
 
 const (
-	NotificationStatusPending NotificationStatus = "pending"
-	NotificationStatusSent    NotificationStatus = "sent"
-	NotificationStatusFailed  NotificationStatus = "failed"
+	NotificationStatusPending    NotificationStatus = "pending"
+	NotificationStatusProcessing NotificationStatus = "processing" // Захвачено воркером на время отправки
+	NotificationStatusSent       NotificationStatus = "sent"
+	NotificationStatusFailed     NotificationStatus = "failed"
 )
 
 type Notification struct {
-	ID        NotificationID
-	UserID    UserID
-	TaskID    *TaskID // Опционально: связанная задача
-	NotifyAt  time.Time
-	Channel   NotificationChannel
-	Status    NotificationStatus
-	Message   string
-	CreatedAt time.Time
-	UpdatedAt time.Time
-	SentAt    *time.Time
+	ID             NotificationID
+	UserID         UserID
+	TaskID         *TaskID         // Опционально: связанная задача
+	FocusSessionID *FocusSessionID // Опционально: фокус-сессия, о завершении которой уведомление
+	NotifyAt       time.Time
+	Channel        NotificationChannel
+	Status         NotificationStatus
+	Message        string
+	Attempts       int        // Количество попыток отправки
+	LastError      string     // Ошибка последней неудачной попытки
+	LockedUntil    *time.Time // До какого момента уведомление захвачено воркером
+	CreatedAt      time.Time
+	UpdatedAt      time.Time
+	SentAt         *time.Time
 }
 
 func NewNotification(userID UserID, taskID *TaskID, notifyAt time.Time, channel NotificationChannel, message string) Notification {
@@ -55,14 +60,32 @@ func NewNotification(userID UserID, taskID *TaskID, notifyAt time.Time, channel
 	}
 }
 
+// IsUnsent сообщает, что уведомление еще ждет отправки или захвачено воркером, но не доставлено
+func (n *Notification) IsUnsent() bool {
+	return n.Status == NotificationStatusPending || n.Status == NotificationStatusProcessing
+}
+
 func (n *Notification) MarkAsSent() {
 	n.Status = NotificationStatusSent
 	now := time.Now()
 	n.SentAt = &now
+	n.LockedUntil = nil
+	n.LastError = ""
 	n.UpdatedAt = now
 }
 
-func (n *Notification) MarkAsFailed() {
+func (n *Notification) MarkAsFailed(reason string) {
 	n.Status = NotificationStatusFailed
+	n.LastError = reason
+	n.LockedUntil = nil
+	n.UpdatedAt = time.Now()
+}
+
+// ScheduleRetry возвращает уведомление в очередь для повторной попытки в момент at
+func (n *Notification) ScheduleRetry(at time.Time, reason string) {
+	n.Status = NotificationStatusPending
+	n.NotifyAt = at
+	n.LastError = reason
+	n.LockedUntil = nil
 	n.UpdatedAt = time.Now()
 }
@@ -0,0 +1,124 @@
+package models
+
+import (
+	"errors"
+	"time"
+
+	"github.com/singl3focus/uniflow/pkg/errs"
+)
+
+type RecurrenceFrequency string
+
+const (
+	RecurrenceDaily   RecurrenceFrequency = "daily"   // Каждые Interval дней
+	RecurrenceWeekly  RecurrenceFrequency = "weekly"  // По дням Weekdays каждые Interval недель
+	RecurrenceMonthly RecurrenceFrequency = "monthly" // Каждые Interval месяцев в тот же день
+)
+
+// MaxRecurrenceInterval ограничивает шаг повторения, чтобы не создавать задачи через десятилетия
+const MaxRecurrenceInterval = 365
+
+// Recurrence - правило повторения задачи. Следующее вхождение отсчитывается от дедлайна текущего.
+type Recurrence struct {
+	Frequency RecurrenceFrequency `json:"frequency"`
+	Interval  int                 `json:"interval"`           // Шаг: дни, недели или месяцы (по умолчанию 1)
+	Weekdays  []Weekday           `json:"weekdays,omitempty"` // Для weekly: дни недели (0 - понедельник)
+	Until     *time.Time          `json:"until,omitempty"`    // Опционально: последняя допустимая дата
+}
+
+var (
+	ErrInvalidRecurrence        = errs.New("invalid recurrence")
+	ErrRecurrenceWithoutDueDate = errs.New("recurring task requires due date")
+)
+
+// Validate проверяет правило и приводит Interval к значению по умолчанию
+func (r *Recurrence) Validate() error {
+	const op = "models.Recurrence.Validate"
+
+	switch r.Frequency {
+	case RecurrenceDaily, RecurrenceWeekly, RecurrenceMonthly:
+	default:
+		return ErrInvalidRecurrence.SetPlace(op).SetCause(errors.New("frequency must be daily, weekly or monthly"))
+	}
+
+	if r.Interval == 0 {
+		r.Interval = 1
+	}
+	if r.Interval < 1 || r.Interval > MaxRecurrenceInterval {
+		return ErrInvalidRecurrence.SetPlace(op).SetCause(errors.New("interval must be 1-365"))
+	}
+
+	if r.Frequency != RecurrenceWeekly && len(r.Weekdays) > 0 {
+		return ErrInvalidRecurrence.SetPlace(op).SetCause(errors.New("weekdays are allowed only for weekly frequency"))
+	}
+	for _, w := range r.Weekdays {
+		if !isValidWeekday(w) {
+			return ErrInvalidRecurrence.SetPlace(op).SetCause(errors.New("weekday must be 0-6"))
+		}
+	}
+
+	return nil
+}
+
+// Next возвращает ближайшее вхождение после from, сохраняя время суток.
+// Дни недели и месяца определяются в часовом поясе from.
+// Второе значение false, если серия закончилась (следующая дата позже Until).
+func (r *Recurrence) Next(from time.Time) (time.Time, bool) {
+	interval := r.Interval
+	if interval < 1 {
+		interval = 1
+	}
+
+	var next time.Time
+	switch r.Frequency {
+	case RecurrenceDaily:
+		next = from.AddDate(0, 0, interval)
+	case RecurrenceWeekly:
+		next = r.nextWeekly(from, interval)
+	case RecurrenceMonthly:
+		next = addMonthsClamped(from, interval)
+	default:
+		return time.Time{}, false
+	}
+
+	if r.Until != nil && next.After(*r.Until) {
+		return time.Time{}, false
+	}
+
+	return next, true
+}
+
+// nextWeekly ищет ближайший выбранный день недели в "рабочих" неделях,
+// отстоящих от недели from на кратное interval число недель
+func (r *Recurrence) nextWeekly(from time.Time, interval int) time.Time {
+	days := make(map[Weekday]bool, len(r.Weekdays))
+	for _, w := range r.Weekdays {
+		days[w] = true
+	}
+	if len(days) == 0 {
+		days[WeekdayOf(from)] = true
+	}
+
+	// Номер дня недели from: по нему считаем, на какую по счету неделю попадает кандидат
+	offset := int(WeekdayOf(from))
+	for i := 1; ; i++ {
+		candidate := from.AddDate(0, 0, i)
+		week := (offset + i) / 7
+		if week%interval == 0 && days[WeekdayOf(candidate)] {
+			return candidate
+		}
+	}
+}
+
+// addMonthsClamped прибавляет месяцы, не перескакивая в следующий месяц:
+// 31 января + 1 месяц = 28 (29) февраля
+func addMonthsClamped(t time.Time, months int) time.Time {
+	year, month, day := t.Date()
+	first := time.Date(year, month+time.Month(months), 1, t.Hour(), t.Minute(), t.Second(), t.Nanosecond(), t.Location())
+
+	if last := first.AddDate(0, 1, -1).Day(); day > last {
+		day = last
+	}
+
+	return first.AddDate(0, 0, day-1)
+}
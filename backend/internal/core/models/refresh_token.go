@@ -0,0 +1,67 @@
+package models
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type RefreshTokenID = uuid.UUID
+
+// refreshTokenBytes - длина случайной части refresh-токена
+const refreshTokenBytes = 32
+
+// RefreshToken - серверная запись refresh-токена.
+// Сам токен на сервере не хранится, только его SHA-256 хеш.
+type RefreshToken struct {
+	ID              RefreshTokenID
+	UserID          UserID
+	TokenHash       string
+	AccessTokenID   string    // jti access-токена, выданного в паре с этим refresh-токеном
+	AccessExpiresAt time.Time // Срок действия этого access-токена
+	ExpiresAt       time.Time
+	RevokedAt       *time.Time // Токен использован для ротации или отозван при выходе
+	CreatedAt       time.Time
+}
+
+// NewRefreshToken создает запись refresh-токена и возвращает ее вместе с самим токеном,
+// который отдается клиенту один раз
+func NewRefreshToken(userID UserID, accessTokenID string, accessExpiresAt time.Time, ttl time.Duration, now time.Time) (RefreshToken, string, error) {
+	buf := make([]byte, refreshTokenBytes)
+	if _, err := rand.Read(buf); err != nil {
+		return RefreshToken{}, "", err
+	}
+	raw := base64.RawURLEncoding.EncodeToString(buf)
+
+	return RefreshToken{
+		ID:              RefreshTokenID(uuid.New()),
+		UserID:          userID,
+		TokenHash:       HashRefreshToken(raw),
+		AccessTokenID:   accessTokenID,
+		AccessExpiresAt: accessExpiresAt,
+		ExpiresAt:       now.Add(ttl),
+		CreatedAt:       now,
+	}, raw, nil
+}
+
+// HashRefreshToken возвращает хеш, под которым токен хранится в БД
+func HashRefreshToken(raw string) string {
+	sum := sha256.Sum256([]byte(raw))
+	return hex.EncodeToString(sum[:])
+}
+
+// IsActive сообщает, можно ли обменять токен на новую пару
+func (t RefreshToken) IsActive(now time.Time) bool {
+	return t.RevokedAt == nil && now.Before(t.ExpiresAt)
+}
+
+// TokenPair - выданные клиенту токены
+type TokenPair struct {
+	AccessToken     string    `json:"access_token"`
+	RefreshToken    string    `json:"refresh_token"`
+	AccessExpiresAt time.Time `json:"access_expires_at"`
+}
@@ -27,6 +27,9 @@ const (
 	Sunday
 )
 
+// ScheduleTimeLayout - формат времени начала и окончания занятия
+const ScheduleTimeLayout = "15:04"
+
 type ScheduleEntry struct {
 	ID        ScheduleEntryID
 	UserID    UserID
@@ -40,6 +43,16 @@ type ScheduleEntry struct {
 	UpdatedAt time.Time
 }
 
+// WeekdayOf возвращает день недели даты в нумерации расписания (понедельник = 0)
+func WeekdayOf(t time.Time) Weekday {
+	return Weekday((int(t.Weekday()) + 6) % 7)
+}
+
+// ParseScheduleTime разбирает время занятия в формате HH:MM
+func ParseScheduleTime(s string) (time.Time, error) {
+	return time.Parse(ScheduleTimeLayout, s)
+}
+
 var (
 	ErrInvalidScheduleTitle = errs.New("invalid schedule title")
 	ErrInvalidWeekday       = errs.New("invalid weekday")
@@ -81,13 +94,39 @@ func isValidWeekday(w Weekday) bool {
 	return w >= Monday && w <= Sunday
 }
 
-func (s *ScheduleEntry) Update(title *string, contextID *ContextID, weekday *Weekday, startAt, endAt *time.Time, location *string) {
+// Validate проверяет инварианты записи расписания после изменения
+func (s *ScheduleEntry) Validate() error {
+	const op = "models.ScheduleEntry.Validate"
+
+	if s.Title == "" {
+		return ErrInvalidScheduleTitle.SetPlace(op).SetCause(errors.New("title cannot be empty"))
+	}
+
+	if !isValidWeekday(s.Weekday) {
+		return ErrInvalidWeekday.SetPlace(op).SetCause(errors.New("weekday must be 0-6"))
+	}
+
+	if !s.StartAt.Before(s.EndAt) {
+		return ErrInvalidTimeRange.SetPlace(op).SetCause(errors.New("start time must be before end time"))
+	}
+
+	return nil
+}
+
+// ShortName возвращает сокращенное название дня недели
+func (w Weekday) ShortName() string {
+	return [...]string{"Пн", "Вт", "Ср", "Чт", "Пт", "Сб", "Вс"}[w]
+}
+
+// Name возвращает полное название дня недели
+func (w Weekday) Name() string {
+	return [...]string{"Понедельник", "Вторник", "Среда", "Четверг", "Пятница", "Суббота", "Воскресенье"}[w]
+}
+
+func (s *ScheduleEntry) Update(title *string, weekday *Weekday, startAt, endAt *time.Time, location *string) {
 	if title != nil {
 		s.Title = *title
 	}
-	if contextID != nil {
-		s.ContextID = contextID
-	}
 	if weekday != nil {
 		s.Weekday = *weekday
 	}
@@ -102,3 +141,9 @@ func (s *ScheduleEntry) Update(title *string, contextID *ContextID, weekday *Wee
 	}
 	s.UpdatedAt = time.Now()
 }
+
+// AssignContext привязывает занятие к контексту (nil - отвязать)
+func (s *ScheduleEntry) AssignContext(contextID *ContextID) {
+	s.ContextID = contextID
+	s.UpdatedAt = time.Now()
+}
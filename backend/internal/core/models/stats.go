@@ -0,0 +1,45 @@
+package models
+
+import "time"
+
+// Stats - сводка продуктивности пользователя за период
+type Stats struct {
+	From time.Time `json:"from"`
+	To   time.Time `json:"to"`
+
+	CompletedTotal  int            `json:"completed_total"` // Выполнено задач за период
+	CreatedTotal    int            `json:"created_total"`   // Создано задач за период
+	ActiveTotal     int            `json:"active_total"`    // Незавершенных задач на текущий момент
+	OverdueTotal    int            `json:"overdue_total"`   // Незавершенных задач с истекшим дедлайном
+	CompletedByDay  []DayCount     `json:"completed_by_day"`
+	CompletedByWeek []WeekCount    `json:"completed_by_week"`
+	ByContext       []ContextStats `json:"by_context"`
+
+	// Среднее время от создания до выполнения задач, выполненных за период
+	AvgLeadTimeHours float64 `json:"avg_lead_time_hours"`
+
+	FocusSessions int `json:"focus_sessions"` // Фокус-сессий, начатых за период
+	FocusMinutes  int `json:"focus_minutes"`  // Фактически отработанные минуты
+}
+
+// DayCount - количество за календарный день (дата в формате YYYY-MM-DD)
+type DayCount struct {
+	Date  string `json:"date"`
+	Count int    `json:"count"`
+}
+
+// WeekCount - количество за неделю, начинающуюся с понедельника WeekStart (YYYY-MM-DD)
+type WeekCount struct {
+	WeekStart string `json:"week_start"`
+	Count     int    `json:"count"`
+}
+
+// ContextStats - показатели по одному контексту. ContextID = nil - задачи без контекста.
+type ContextStats struct {
+	ContextID    *ContextID `json:"context_id,omitempty"`
+	Title        string     `json:"title"`
+	Active       int        `json:"active"`
+	Completed    int        `json:"completed"` // Выполнено за период
+	Overdue      int        `json:"overdue"`
+	FocusMinutes int        `json:"focus_minutes"`
+}
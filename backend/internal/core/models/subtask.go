@@ -0,0 +1,99 @@
+package models
+
+import (
+	"errors"
+	"time"
+	"unicode/utf8"
+
+	"github.com/google/uuid"
+
+	"github.com/singl3focus/uniflow/pkg/errs"
+)
+
+type SubtaskID = uuid.UUID
+
+func ParseSubtaskID(id string) (SubtaskID, error) {
+	return uuid.Parse(id)
+}
+
+const (
+	MaxSubtasksPerTask    = 50  // Ограничивает размер чек-листа одной задачи
+	MaxSubtaskTitleLength = 255 // Максимальная длина пункта в символах, как у колонки subtasks.title
+)
+
+// Subtask - пункт чек-листа задачи
+type Subtask struct {
+	ID        SubtaskID `json:"id"`
+	TaskID    TaskID    `json:"task_id"`
+	Title     string    `json:"title"`
+	Done      bool      `json:"done"`
+	Position  int       `json:"position"` // Порядок в чек-листе, начиная с 0
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+var (
+	ErrInvalidSubtaskTitle = errs.New("invalid subtask title")
+	ErrTooManySubtasks     = errs.New("too many subtasks")
+)
+
+func NewSubtask(taskID TaskID, title string, position int) (Subtask, error) {
+	const op = "models.NewSubtask"
+
+	if err := validateSubtaskTitle(title); err != nil {
+		return Subtask{}, ErrInvalidSubtaskTitle.SetPlace(op).SetCause(err)
+	}
+
+	now := time.Now()
+
+	return Subtask{
+		ID:        SubtaskID(uuid.New()),
+		TaskID:    taskID,
+		Title:     title,
+		Position:  position,
+		CreatedAt: now,
+		UpdatedAt: now,
+	}, nil
+}
+
+func (s *Subtask) Update(title *string, done *bool) error {
+	const op = "models.Subtask.Update"
+
+	if title != nil {
+		if err := validateSubtaskTitle(*title); err != nil {
+			return ErrInvalidSubtaskTitle.SetPlace(op).SetCause(err)
+		}
+		s.Title = *title
+	}
+	if done != nil {
+		s.Done = *done
+	}
+	s.UpdatedAt = time.Now()
+	return nil
+}
+
+func validateSubtaskTitle(title string) error {
+	if title == "" {
+		return errors.New("title cannot be empty")
+	}
+	if utf8.RuneCountInString(title) > MaxSubtaskTitleLength {
+		return errors.New("title is too long")
+	}
+	return nil
+}
+
+// Toggle переключает отметку о выполнении пункта
+func (s *Subtask) Toggle() {
+	s.Done = !s.Done
+	s.UpdatedAt = time.Now()
+}
+
+// SubtaskProgress возвращает число выполненных пунктов и общее число пунктов чек-листа
+func SubtaskProgress(subtasks []Subtask) (done, total int) {
+	for _, s := range subtasks {
+		if s.Done {
+			done++
+		}
+	}
+	return done, len(subtasks)
+}
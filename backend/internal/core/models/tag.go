@@ -0,0 +1,97 @@
+package models
+
+import (
+	"errors"
+	"strings"
+	"time"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/google/uuid"
+
+	"github.com/singl3focus/uniflow/pkg/errs"
+)
+
+type TagID = uuid.UUID
+
+func ParseTagID(id string) (TagID, error) {
+	return uuid.Parse(id)
+}
+
+const (
+	MaxTagNameLength = 32 // Максимальная длина имени тега в символах
+	MaxTagsPerTask   = 10 // Максимальное число тегов у одной задачи
+)
+
+// Tag - метка пользователя. Одна задача может иметь несколько тегов, один тег - много задач.
+type Tag struct {
+	ID        TagID     `json:"id"`
+	UserID    UserID    `json:"user_id"`
+	Name      string    `json:"name"` // Нормализованное имя: без '#', в нижнем регистре
+	CreatedAt time.Time `json:"created_at"`
+}
+
+var (
+	ErrInvalidTagName = errs.New("invalid tag name")
+	ErrTooManyTags    = errs.New("too many tags")
+)
+
+func NewTag(userID UserID, name string) (Tag, error) {
+	const op = "models.NewTag"
+
+	normalized, err := NormalizeTagName(name)
+	if err != nil {
+		return Tag{}, ErrInvalidTagName.SetPlace(op).SetCause(err)
+	}
+
+	return Tag{
+		ID:        TagID(uuid.New()),
+		UserID:    userID,
+		Name:      normalized,
+		CreatedAt: time.Now(),
+	}, nil
+}
+
+// NormalizeTagName приводит имя тега к каноничному виду: "#Матан" -> "матан".
+// Допустимы буквы, цифры, '_' и '-'.
+func NormalizeTagName(name string) (string, error) {
+	name = strings.ToLower(strings.TrimPrefix(strings.TrimSpace(name), "#"))
+
+	if name == "" {
+		return "", errors.New("tag name cannot be empty")
+	}
+	if utf8.RuneCountInString(name) > MaxTagNameLength {
+		return "", errors.New("tag name is too long")
+	}
+	for _, r := range name {
+		if !unicode.IsLetter(r) && !unicode.IsDigit(r) && r != '_' && r != '-' {
+			return "", errors.New("tag name may contain only letters, digits, '_' and '-'")
+		}
+	}
+
+	return name, nil
+}
+
+// NormalizeTagNames нормализует список имен тегов и убирает повторы, сохраняя порядок
+func NormalizeTagNames(names []string) ([]string, error) {
+	const op = "models.NormalizeTagNames"
+
+	result := make([]string, 0, len(names))
+	seen := make(map[string]bool, len(names))
+	for _, name := range names {
+		normalized, err := NormalizeTagName(name)
+		if err != nil {
+			return nil, ErrInvalidTagName.SetPlace(op).SetCause(err)
+		}
+		if !seen[normalized] {
+			seen[normalized] = true
+			result = append(result, normalized)
+		}
+	}
+
+	if len(result) > MaxTagsPerTask {
+		return nil, ErrTooManyTags.SetPlace(op)
+	}
+
+	return result, nil
+}
@@ -2,6 +2,7 @@ package models
 
 import (
 	"errors"
+	"sort"
 	"time"
 
 	"github.com/google/uuid"
@@ -24,22 +25,58 @@ const (
 	TaskStatusCancelled  TaskStatus = "cancelled"
 )
 
+type TaskPriority string
+
+const (
+	TaskPriorityLow    TaskPriority = "low"
+	TaskPriorityNormal TaskPriority = "normal"
+	TaskPriorityHigh   TaskPriority = "high"
+	TaskPriorityUrgent TaskPriority = "urgent"
+)
+
+// TaskSort - порядок сортировки списка задач
+type TaskSort string
+
+const (
+	TaskSortCreated  TaskSort = "created"  // Сначала новые (по умолчанию)
+	TaskSortPriority TaskSort = "priority" // Сначала важные, при равенстве - ближайший дедлайн
+	TaskSortDue      TaskSort = "due"      // Ближайший дедлайн первым, задачи без дедлайна в конце
+)
+
 type Task struct {
-	ID          TaskID     `json:"id"`
-	UserID      UserID     `json:"user_id"`
-	ContextID   *ContextID `json:"context_id,omitempty"` // Опционально: привязка к контексту
-	Title       string     `json:"title"`
-	Description string     `json:"description"`
-	Status      TaskStatus `json:"status"`
-	DueAt       *time.Time `json:"due_at,omitempty"` // Опционально: дедлайн задачи
-	CompletedAt *time.Time `json:"completed_at,omitempty"`
-	CreatedAt   time.Time  `json:"created_at"`
-	UpdatedAt   time.Time  `json:"updated_at"`
+	ID          TaskID       `json:"id"`
+	UserID      UserID       `json:"user_id"`
+	ContextID   *ContextID   `json:"context_id,omitempty"` // Опционально: привязка к контексту
+	Title       string       `json:"title"`
+	Description string       `json:"description"`
+	Status      TaskStatus   `json:"status"`
+	Priority    TaskPriority `json:"priority"`
+	DueAt       *time.Time   `json:"due_at,omitempty"` // Опционально: дедлайн задачи
+	AllDay      bool         `json:"all_day"`          // Срок - весь день DueAt: время не задано, DueAt - конец дня в поясе пользователя
+	CompletedAt *time.Time   `json:"completed_at,omitempty"`
+	Recurrence  *Recurrence  `json:"recurrence,omitempty"` // Опционально: правило повторения
+	Tags        []string     `json:"tags"`                 // Имена тегов задачи; заполняются при чтении из БД
+	CreatedAt   time.Time    `json:"created_at"`
+	UpdatedAt   time.Time    `json:"updated_at"`
+}
+
+// DueDateLayout - формат срока на весь день: дата без времени
+const DueDateLayout = "2006-01-02"
+
+// AllDayReminderHour - от какого часа дня дедлайна отсчитываются напоминания задачи на весь день
+const AllDayReminderHour = 9
+
+// AllDayDue возвращает момент, которым хранится срок на весь день date: 23:59 этого дня в поясе loc.
+// Так задача попадает в свой день во всех фильтрах по дате и считается просроченной только после его окончания.
+func AllDayDue(date time.Time, loc *time.Location) time.Time {
+	return time.Date(date.Year(), date.Month(), date.Day(), 23, 59, 0, 0, loc)
 }
 
 var (
-	ErrInvalidTaskTitle  = errs.New("invalid task title")
-	ErrInvalidTaskStatus = errs.New("invalid task status")
+	ErrInvalidTaskTitle    = errs.New("invalid task title")
+	ErrInvalidTaskStatus   = errs.New("invalid task status")
+	ErrInvalidTaskPriority = errs.New("invalid task priority")
+	ErrInvalidTaskSort     = errs.New("invalid task sort")
 )
 
 func NewTask(userID UserID, contextID *ContextID, title, description string, dueAt *time.Time) (Task, error) {
@@ -58,6 +95,7 @@ func NewTask(userID UserID, contextID *ContextID, title, description string, due
 		Title:       title,
 		Description: description,
 		Status:      TaskStatusTodo,
+		Priority:    TaskPriorityNormal,
 		DueAt:       dueAt,
 		CreatedAt:   now,
 		UpdatedAt:   now,
@@ -76,10 +114,19 @@ func (t *Task) Update(title, description *string, contextID *ContextID, dueAt *t
 	}
 	if dueAt != nil {
 		t.DueAt = dueAt
+		t.AllDay = false
 	}
 	t.UpdatedAt = time.Now()
 }
 
+// SetDue задает срок задачи; allDay означает срок на весь день, а не к определенному времени.
+// nil снимает срок.
+func (t *Task) SetDue(dueAt *time.Time, allDay bool) {
+	t.DueAt = dueAt
+	t.AllDay = dueAt != nil && allDay
+	t.UpdatedAt = time.Now()
+}
+
 func (t *Task) ChangeStatus(status TaskStatus) error {
 	if !isValidTaskStatus(status) {
 		return ErrInvalidTaskStatus
@@ -89,11 +136,102 @@ func (t *Task) ChangeStatus(status TaskStatus) error {
 	if status == TaskStatusCompleted {
 		now := time.Now()
 		t.CompletedAt = &now
+	} else {
+		// Переоткрытая задача больше не считается выполненной
+		t.CompletedAt = nil
 	}
 	t.UpdatedAt = time.Now()
 	return nil
 }
 
+// SetRecurrence задает правило повторения. nil снимает повторение.
+// Повторяющейся задаче нужен дедлайн: от него отсчитываются следующие вхождения.
+func (t *Task) SetRecurrence(r *Recurrence) error {
+	const op = "models.Task.SetRecurrence"
+
+	if r == nil {
+		t.Recurrence = nil
+		return nil
+	}
+
+	if t.DueAt == nil {
+		return ErrRecurrenceWithoutDueDate.SetPlace(op).SetCause(errors.New("due date is required"))
+	}
+
+	rule := *r
+	rule.Weekdays = append([]Weekday(nil), r.Weekdays...)
+	if err := rule.Validate(); err != nil {
+		return err
+	}
+
+	t.Recurrence = &rule
+	return nil
+}
+
+// NextOccurrence создает следующее вхождение повторяющейся задачи с первым дедлайном позже now.
+// Правило переходит к новой задаче, поэтому повторное завершение текущей не порождает дубликатов.
+// Второе значение false, если задача не повторяется или серия закончилась.
+func (t *Task) NextOccurrence(now time.Time) (Task, bool) {
+	if t.Recurrence == nil || t.DueAt == nil {
+		return Task{}, false
+	}
+
+	// Дни недели и месяца считаем в часовом поясе now
+	due := t.DueAt.In(now.Location())
+	for {
+		next, ok := t.Recurrence.Next(due)
+		if !ok {
+			t.Recurrence = nil
+			return Task{}, false
+		}
+		due = next
+		if due.After(now) {
+			break
+		}
+	}
+
+	rule := *t.Recurrence
+	next := Task{
+		ID:          TaskID(uuid.New()),
+		UserID:      t.UserID,
+		ContextID:   t.ContextID,
+		Title:       t.Title,
+		Description: t.Description,
+		Status:      TaskStatusTodo,
+		Priority:    t.Priority,
+		DueAt:       &due,
+		AllDay:      t.AllDay,
+		Tags:        append([]string(nil), t.Tags...),
+		Recurrence:  &rule,
+		CreatedAt:   now,
+		UpdatedAt:   now,
+	}
+
+	t.Recurrence = nil
+	return next, true
+}
+
+// SetPriority меняет приоритет задачи. Пустое значение означает обычный приоритет.
+func (t *Task) SetPriority(p TaskPriority) error {
+	const op = "models.Task.SetPriority"
+
+	if p == "" {
+		p = TaskPriorityNormal
+	}
+	if !IsValidTaskPriority(p) {
+		return ErrInvalidTaskPriority.SetPlace(op).SetCause(errors.New("invalid priority"))
+	}
+
+	t.Priority = p
+	t.UpdatedAt = time.Now()
+	return nil
+}
+
+// IsActive сообщает, что задача еще не завершена и не отменена
+func (t *Task) IsActive() bool {
+	return t.Status != TaskStatusCompleted && t.Status != TaskStatusCancelled
+}
+
 // Validate checks if task has required fields
 func (t *Task) Validate() error {
 	const op = "models.Task.Validate"
@@ -106,6 +244,10 @@ func (t *Task) Validate() error {
 		return ErrInvalidTaskStatus.SetPlace(op).SetCause(errors.New("invalid status"))
 	}
 
+	if !IsValidTaskPriority(t.Priority) {
+		return ErrInvalidTaskPriority.SetPlace(op).SetCause(errors.New("invalid priority"))
+	}
+
 	return nil
 }
 
@@ -117,3 +259,79 @@ func isValidTaskStatus(s TaskStatus) bool {
 		return false
 	}
 }
+
+func IsValidTaskPriority(p TaskPriority) bool {
+	switch p {
+	case TaskPriorityLow, TaskPriorityNormal, TaskPriorityHigh, TaskPriorityUrgent:
+		return true
+	default:
+		return false
+	}
+}
+
+// Rank возвращает вес приоритета для сортировки: чем важнее, тем больше
+func (p TaskPriority) Rank() int {
+	switch p {
+	case TaskPriorityLow:
+		return 0
+	case TaskPriorityHigh:
+		return 2
+	case TaskPriorityUrgent:
+		return 3
+	default:
+		return 1
+	}
+}
+
+// IsImportant сообщает, что задачу стоит выделить в списках
+func (p TaskPriority) IsImportant() bool {
+	return p.Rank() >= TaskPriorityHigh.Rank()
+}
+
+func IsValidTaskSort(s TaskSort) bool {
+	switch s {
+	case TaskSortCreated, TaskSortPriority, TaskSortDue:
+		return true
+	default:
+		return false
+	}
+}
+
+// SortTasks сортирует задачи на месте в заданном порядке
+func SortTasks(tasks []Task, by TaskSort) {
+	sort.SliceStable(tasks, func(i, j int) bool {
+		a, b := tasks[i], tasks[j]
+		switch by {
+		case TaskSortPriority:
+			if a.Priority.Rank() != b.Priority.Rank() {
+				return a.Priority.Rank() > b.Priority.Rank()
+			}
+			if !dueEqual(a.DueAt, b.DueAt) {
+				return dueBefore(a.DueAt, b.DueAt)
+			}
+		case TaskSortDue:
+			if !dueEqual(a.DueAt, b.DueAt) {
+				return dueBefore(a.DueAt, b.DueAt)
+			}
+		}
+		return a.CreatedAt.After(b.CreatedAt)
+	})
+}
+
+// dueBefore сравнивает дедлайны; задача без дедлайна идет после задач с дедлайном
+func dueBefore(a, b *time.Time) bool {
+	if a == nil {
+		return false
+	}
+	if b == nil {
+		return true
+	}
+	return a.Before(*b)
+}
+
+func dueEqual(a, b *time.Time) bool {
+	if a == nil || b == nil {
+		return a == nil && b == nil
+	}
+	return a.Equal(*b)
+}
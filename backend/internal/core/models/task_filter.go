@@ -0,0 +1,132 @@
+package models
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"time"
+
+	"github.com/singl3focus/uniflow/pkg/errs"
+)
+
+// MaxTaskPageSize ограничивает размер страницы при постраничной выдаче задач
+const MaxTaskPageSize = 100
+
+// TaskFilter - условия выборки задач пользователя. Пустые поля выборку не ограничивают.
+type TaskFilter struct {
+	Statuses   []TaskStatus
+	ContextID  *ContextID
+	InboxOnly  bool // Только задачи без контекста
+	Priorities []TaskPriority
+	Tag        string     // Нормализованное имя тега
+	DueFrom    *time.Time // Дедлайн не раньше DueFrom
+	DueTo      *time.Time // Дедлайн строго раньше DueTo
+	Text       string     // Подстрока в названии или описании
+	Sort       TaskSort
+	Cursor     *TaskCursor // Продолжение выдачи после задачи, на которой закончилась предыдущая страница
+	Limit      int         // 0 - без ограничения
+}
+
+var (
+	ErrInvalidTaskFilter = errs.New("invalid task filter")
+	ErrInvalidTaskCursor = errs.New("invalid task cursor")
+)
+
+// Validate проверяет фильтр и подставляет значения по умолчанию
+func (f *TaskFilter) Validate() error {
+	const op = "models.TaskFilter.Validate"
+
+	if f.Sort == "" {
+		f.Sort = TaskSortCreated
+	}
+	if !IsValidTaskSort(f.Sort) {
+		return ErrInvalidTaskSort.SetPlace(op)
+	}
+
+	for _, s := range f.Statuses {
+		if !isValidTaskStatus(s) {
+			return ErrInvalidTaskStatus.SetPlace(op)
+		}
+	}
+	for _, p := range f.Priorities {
+		if !IsValidTaskPriority(p) {
+			return ErrInvalidTaskPriority.SetPlace(op)
+		}
+	}
+
+	if f.Tag != "" {
+		tag, err := NormalizeTagName(f.Tag)
+		if err != nil {
+			return ErrInvalidTagName.SetPlace(op).SetCause(err)
+		}
+		f.Tag = tag
+	}
+
+	if f.InboxOnly && f.ContextID != nil {
+		return ErrInvalidTaskFilter.SetPlace(op).SetCause(errors.New("inbox and context filters are mutually exclusive"))
+	}
+	if f.DueFrom != nil && f.DueTo != nil && !f.DueFrom.Before(*f.DueTo) {
+		return ErrInvalidTaskFilter.SetPlace(op).SetCause(errors.New("due_from must be before due_to"))
+	}
+	if f.Limit < 0 || f.Limit > MaxTaskPageSize {
+		return ErrInvalidTaskFilter.SetPlace(op).SetCause(errors.New("limit must be 1-100"))
+	}
+	if f.Cursor != nil && f.Cursor.Sort != f.Sort {
+		return ErrInvalidTaskCursor.SetPlace(op).SetCause(errors.New("cursor was issued for another sort"))
+	}
+
+	return nil
+}
+
+// TaskCursor - ключ сортировки последней задачи страницы.
+// Следующая страница начинается со следующей за ней задачи в том же порядке.
+type TaskCursor struct {
+	Sort         TaskSort   `json:"s"`
+	PriorityRank int        `json:"p"`
+	DueAt        *time.Time `json:"d,omitempty"`
+	CreatedAt    time.Time  `json:"c"`
+	ID           TaskID     `json:"i"`
+}
+
+// NewTaskCursor строит курсор, указывающий на задачу task при сортировке by
+func NewTaskCursor(task Task, by TaskSort) TaskCursor {
+	return TaskCursor{
+		Sort:         by,
+		PriorityRank: task.Priority.Rank(),
+		DueAt:        task.DueAt,
+		CreatedAt:    task.CreatedAt,
+		ID:           task.ID,
+	}
+}
+
+// String кодирует курсор в непрозрачную строку для клиента
+func (c TaskCursor) String() string {
+	data, _ := json.Marshal(c)
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+// ParseTaskCursor разбирает строку, полученную из TaskCursor.String
+func ParseTaskCursor(s string) (TaskCursor, error) {
+	const op = "models.ParseTaskCursor"
+
+	data, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		return TaskCursor{}, ErrInvalidTaskCursor.SetPlace(op).SetCause(err)
+	}
+
+	var c TaskCursor
+	if err = json.Unmarshal(data, &c); err != nil {
+		return TaskCursor{}, ErrInvalidTaskCursor.SetPlace(op).SetCause(err)
+	}
+	if !IsValidTaskSort(c.Sort) {
+		return TaskCursor{}, ErrInvalidTaskCursor.SetPlace(op).SetCause(ErrInvalidTaskSort)
+	}
+
+	return c, nil
+}
+
+// TaskPage - страница списка задач. NextCursor пуст, если задач больше нет.
+type TaskPage struct {
+	Tasks      []Task `json:"tasks"`
+	NextCursor string `json:"next_cursor,omitempty"`
+}
@@ -16,10 +16,11 @@ func ParseUserID(id string) (UserID, error) {
 }
 
 type User struct {
-	ID        UserID    `json:"id"`
-	MaxUserID string    `json:"max_user_id"` // ID пользователя в MAX
-	CreatedAt time.Time `json:"created_at"`
-	UpdatedAt time.Time `json:"updated_at"`
+	ID        UserID       `json:"id"`
+	MaxUserID string       `json:"max_user_id"` // ID пользователя в MAX
+	Settings  UserSettings `json:"settings"`
+	CreatedAt time.Time    `json:"created_at"`
+	UpdatedAt time.Time    `json:"updated_at"`
 }
 
 var (
@@ -39,6 +40,7 @@ func NewUser(maxUserID string) (User, error) {
 	return User{
 		ID:        UserID(uuid.New()),
 		MaxUserID: maxUserID,
+		Settings:  DefaultUserSettings(),
 		CreatedAt: now,
 		UpdatedAt: now,
 	}, nil
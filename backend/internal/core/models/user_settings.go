@@ -0,0 +1,174 @@
+package models
+
+import (
+	"errors"
+	"time"
+
+	"github.com/singl3focus/uniflow/pkg/errs"
+)
+
+const (
+	DefaultTimezone = "Europe/Moscow"
+	DefaultLanguage = "ru"
+
+	// MaxReminderOffsets - сколько напоминаний о задаче можно настроить
+	MaxReminderOffsets = 5
+	// MaxReminderOffsetMinutes - напоминание не раньше чем за 30 дней до дедлайна
+	MaxReminderOffsetMinutes = 30 * 24 * 60
+
+	// DigestTimeLayout - формат времени ежедневных сводок
+	DigestTimeLayout = "15:04"
+)
+
+// SupportedLanguages - языки интерфейса
+var SupportedLanguages = []string{"ru", "en"}
+
+// UserSettings - персональные настройки пользователя
+type UserSettings struct {
+	Timezone     string  `json:"timezone"`      // Часовой пояс IANA, например Europe/Moscow
+	Language     string  `json:"language"`      // Язык интерфейса: ru, en
+	FirstWeekday Weekday `json:"first_weekday"` // С какого дня начинается неделя: 0 - понедельник, 6 - воскресенье
+	// За сколько минут до дедлайна напоминать о задаче.
+	// nil - напоминания по умолчанию, пустой список - без напоминаний.
+	ReminderOffsets []int `json:"reminder_offsets"`
+	// Местное время утренней сводки и вечернего обзора в формате ЧЧ:ММ; nil - не присылать
+	MorningDigestAt *string `json:"morning_digest_at"`
+	EveningReviewAt *string `json:"evening_review_at"`
+}
+
+var (
+	ErrInvalidTimezone       = errs.New("invalid timezone")
+	ErrInvalidLanguage       = errs.New("invalid language")
+	ErrInvalidFirstWeekday   = errs.New("invalid first weekday")
+	ErrInvalidReminderOffset = errs.New("invalid reminder offset")
+	ErrInvalidDigestTime     = errs.New("invalid digest time")
+)
+
+func DefaultUserSettings() UserSettings {
+	return UserSettings{
+		Timezone:     DefaultTimezone,
+		Language:     DefaultLanguage,
+		FirstWeekday: Monday,
+	}
+}
+
+// Validate проверяет настройки
+func (s UserSettings) Validate() error {
+	const op = "models.UserSettings.Validate"
+
+	if _, err := time.LoadLocation(s.Timezone); err != nil || s.Timezone == "" || s.Timezone == "Local" {
+		return ErrInvalidTimezone.SetPlace(op).SetCause(errors.New("unknown timezone " + s.Timezone))
+	}
+
+	supported := false
+	for _, lang := range SupportedLanguages {
+		if s.Language == lang {
+			supported = true
+			break
+		}
+	}
+	if !supported {
+		return ErrInvalidLanguage.SetPlace(op).SetCause(errors.New("unsupported language " + s.Language))
+	}
+
+	// Неделя начинается с понедельника или с воскресенья
+	if s.FirstWeekday != Monday && s.FirstWeekday != Sunday {
+		return ErrInvalidFirstWeekday.SetPlace(op).SetCause(errors.New("must be monday or sunday"))
+	}
+
+	if len(s.ReminderOffsets) > MaxReminderOffsets {
+		return ErrInvalidReminderOffset.SetPlace(op).SetCause(errors.New("too many reminders"))
+	}
+	for _, m := range s.ReminderOffsets {
+		if m <= 0 || m > MaxReminderOffsetMinutes {
+			return ErrInvalidReminderOffset.SetPlace(op).SetCause(errors.New("offset must be 1-43200 minutes"))
+		}
+	}
+
+	for _, at := range []*string{s.MorningDigestAt, s.EveningReviewAt} {
+		if at == nil {
+			continue
+		}
+		if _, err := time.Parse(DigestTimeLayout, *at); err != nil {
+			return ErrInvalidDigestTime.SetPlace(op).SetCause(err)
+		}
+	}
+
+	return nil
+}
+
+// Location возвращает часовой пояс пользователя; некорректный пояс заменяется поясом по умолчанию
+func (s UserSettings) Location() *time.Location {
+	if s.Timezone != "" {
+		if loc, err := time.LoadLocation(s.Timezone); err == nil {
+			return loc
+		}
+	}
+
+	loc, err := time.LoadLocation(DefaultTimezone)
+	if err != nil {
+		return time.UTC
+	}
+	return loc
+}
+
+// ReminderDurations возвращает смещения напоминаний; ok == false, если используются напоминания по умолчанию
+func (s UserSettings) ReminderDurations() (offsets []time.Duration, ok bool) {
+	if s.ReminderOffsets == nil {
+		return nil, false
+	}
+
+	offsets = make([]time.Duration, 0, len(s.ReminderOffsets))
+	for _, m := range s.ReminderOffsets {
+		offsets = append(offsets, time.Duration(m)*time.Minute)
+	}
+	return offsets, true
+}
+
+// StartOfDay возвращает полночь дня t в часовом поясе пользователя
+func (s UserSettings) StartOfDay(t time.Time) time.Time {
+	y, m, d := t.In(s.Location()).Date()
+	return time.Date(y, m, d, 0, 0, 0, 0, s.Location())
+}
+
+// StartOfWeek возвращает полночь первого дня недели, в которую попадает t
+func (s UserSettings) StartOfWeek(t time.Time) time.Time {
+	day := s.StartOfDay(t)
+	shift := (int(WeekdayOf(day)) - int(s.FirstWeekday) + 7) % 7
+	return day.AddDate(0, 0, -shift)
+}
+
+// DigestAt возвращает местное время сводки данного вида; ok == false, если сводка выключена
+func (s UserSettings) DigestAt(kind DigestKind) (at string, ok bool) {
+	var v *string
+	switch kind {
+	case DigestKindMorning:
+		v = s.MorningDigestAt
+	case DigestKindEvening:
+		v = s.EveningReviewAt
+	}
+	if v == nil {
+		return "", false
+	}
+	return *v, true
+}
+
+// NextDigestAt возвращает ближайший после after момент отправки сводки данного вида; nil - сводка выключена
+func (s UserSettings) NextDigestAt(kind DigestKind, after time.Time) *time.Time {
+	at, ok := s.DigestAt(kind)
+	if !ok {
+		return nil
+	}
+	clock, err := time.Parse(DigestTimeLayout, at)
+	if err != nil {
+		return nil
+	}
+
+	// time.Date сам сдвигает несуществующее из-за перевода часов время
+	day := s.StartOfDay(after)
+	next := time.Date(day.Year(), day.Month(), day.Day(), clock.Hour(), clock.Minute(), 0, 0, day.Location())
+	if !next.After(after) {
+		next = time.Date(day.Year(), day.Month(), day.Day()+1, clock.Hour(), clock.Minute(), 0, 0, day.Location())
+	}
+	return &next
+}
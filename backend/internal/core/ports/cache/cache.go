@@ -2,14 +2,20 @@ package cache
 
 import (
 	"context"
+	"errors"
 	"time"
 )
 
+// ErrCacheMiss возвращается Get, если ключа нет
+var ErrCacheMiss = errors.New("cache miss")
+
 // Adapter описывает интерфейс для работы с кешем
 type Cache interface {
 	Set(ctx context.Context, key string, value interface{}, ttl time.Duration) error
 	Get(ctx context.Context, key string) (string, error)
 	Del(ctx context.Context, keys ...string) error
+	// DelIfEqual атомарно удаляет key, только если его значение все еще равно value
+	DelIfEqual(ctx context.Context, key, value string) (bool, error)
 	Exists(ctx context.Context, keys ...string) (int64, error)
 	Expire(ctx context.Context, key string, ttl time.Duration) (bool, error)
 
@@ -23,6 +29,13 @@ type Cache interface {
 	RPop(ctx context.Context, key string) (string, error)
 	LRange(ctx context.Context, key string, start, stop int64) ([]string, error)
 
+	// Работа с упорядоченными множествами
+	ZAdd(ctx context.Context, key string, score float64, member string) error
+	// ZRangeByScore возвращает элементы со score в диапазоне [min, max]
+	ZRangeByScore(ctx context.Context, key string, min, max float64) ([]string, error)
+	// ZRem возвращает число действительно удаленных элементов
+	ZRem(ctx context.Context, key string, members ...string) (int64, error)
+
 	// Управление
 	Ping(ctx context.Context) error
 	Close() error
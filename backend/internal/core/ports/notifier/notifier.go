@@ -0,0 +1,17 @@
+package notifier
+
+import (
+	"context"
+
+	"github.com/singl3focus/uniflow/internal/core/models"
+)
+
+// Sender описывает интерфейс доставки уведомления по конкретному каналу
+type Sender interface {
+	Send(ctx context.Context, recipient models.User, notification models.Notification) error
+}
+
+// DigestSender описывает доставку ежедневных сводок: утренней и вечернего обзора
+type DigestSender interface {
+	SendDailySummary(ctx context.Context, recipient models.User, digest models.DailyDigest) error
+}
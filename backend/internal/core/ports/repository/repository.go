@@ -21,10 +21,17 @@ type Repository interface {
 	UserRepository
 	ContextRepository
 	TaskRepository
+	SubtaskRepository
+	TagRepository
 	ScheduleRepository
 	NotificationRepository
 	NoteRepository
 	FocusSessionRepository
+	AuthTokenRepository
+
+	// WithinTransaction выполняет fn в одной транзакции: вызовы репозитория с переданным в fn ctx
+	// фиксируются вместе или откатываются все, если fn вернула ошибку
+	WithinTransaction(ctx context.Context, fn func(ctx context.Context) error) error
 
 	// Управление
 	Ping(ctx context.Context) error
@@ -36,6 +43,12 @@ type UserRepository interface {
 	CreateUser(ctx context.Context, user models.User) error
 	GetUserByMaxUserID(ctx context.Context, maxUserID string) (models.User, error)
 	GetUserByID(ctx context.Context, id models.UserID) (models.User, error)
+	UpdateUserSettings(ctx context.Context, id models.UserID, settings models.UserSettings, updatedAt time.Time) error
+	// SetNextDigestAt задает, когда отправить следующую сводку вида kind; nil - не отправлять
+	SetNextDigestAt(ctx context.Context, id models.UserID, kind models.DigestKind, at *time.Time) error
+	// ClaimDueDigests атомарно захватывает до limit пользователей, которым пора отправить сводку вида kind,
+	// и откладывает их следующую сводку до lockedUntil
+	ClaimDueDigests(ctx context.Context, kind models.DigestKind, now, lockedUntil time.Time, limit int) ([]models.User, error)
 }
 
 // ContextRepository - интерфейс для работы с контекстами
@@ -44,8 +57,10 @@ type ContextRepository interface {
 	GetContextByID(ctx context.Context, id models.ContextID) (models.Context, error)
 	GetContextsByUserID(ctx context.Context, userID models.UserID) ([]models.Context, error)
 	SearchContexts(ctx context.Context, userID models.UserID, query string) ([]models.Context, error)
+	// UpdateContext обновляет контекст только если он принадлежит context.UserID, иначе ErrNotFound
 	UpdateContext(ctx context.Context, context models.Context) error
-	DeleteContext(ctx context.Context, id models.ContextID) error
+	// DeleteContext удаляет контекст пользователя userID; чужой или отсутствующий контекст - ErrNotFound
+	DeleteContext(ctx context.Context, userID models.UserID, id models.ContextID) error
 }
 
 // TaskRepository - интерфейс для работы с задачами
@@ -53,11 +68,37 @@ type TaskRepository interface {
 	CreateTask(ctx context.Context, task models.Task) error
 	GetTaskByID(ctx context.Context, id models.TaskID) (models.Task, error)
 	GetTasksByUserID(ctx context.Context, userID models.UserID) ([]models.Task, error)
+	// ListTasks возвращает задачи пользователя, подходящие под фильтр; фильтрация,
+	// сортировка и постраничная выдача выполняются на стороне БД
+	ListTasks(ctx context.Context, userID models.UserID, filter models.TaskFilter) ([]models.Task, error)
 	GetTasksByContextID(ctx context.Context, contextID models.ContextID) ([]models.Task, error)
-	GetTasksDueToday(ctx context.Context, userID models.UserID) ([]models.Task, error)
 	SearchTasks(ctx context.Context, userID models.UserID, query string) ([]models.Task, error)
+	// UpdateTask обновляет задачу только если она принадлежит task.UserID, иначе ErrNotFound
 	UpdateTask(ctx context.Context, task models.Task) error
-	DeleteTask(ctx context.Context, id models.TaskID) error
+	// DeleteTask удаляет задачу пользователя userID; чужая или отсутствующая задача - ErrNotFound
+	DeleteTask(ctx context.Context, userID models.UserID, id models.TaskID) error
+}
+
+// SubtaskRepository - интерфейс для работы с пунктами чек-листов задач
+type SubtaskRepository interface {
+	CreateSubtask(ctx context.Context, subtask models.Subtask) error
+	GetSubtaskByID(ctx context.Context, id models.SubtaskID) (models.Subtask, error)
+	// GetSubtasksByTaskID возвращает чек-лист задачи в порядке position
+	GetSubtasksByTaskID(ctx context.Context, taskID models.TaskID) ([]models.Subtask, error)
+	UpdateSubtask(ctx context.Context, subtask models.Subtask) error
+	DeleteSubtask(ctx context.Context, id models.SubtaskID) error
+}
+
+// TagRepository - интерфейс для работы с тегами и их связями с задачами
+type TagRepository interface {
+	CreateTag(ctx context.Context, tag models.Tag) error
+	GetTagByID(ctx context.Context, id models.TagID) (models.Tag, error)
+	GetTagByName(ctx context.Context, userID models.UserID, name string) (models.Tag, error)
+	GetTagsByUserID(ctx context.Context, userID models.UserID) ([]models.Tag, error)
+	DeleteTag(ctx context.Context, id models.TagID) error
+	// AddTagToTask привязывает тег к задаче; повторная привязка не является ошибкой
+	AddTagToTask(ctx context.Context, taskID models.TaskID, tagID models.TagID) error
+	RemoveTagFromTask(ctx context.Context, taskID models.TaskID, tagID models.TagID) error
 }
 
 // ScheduleRepository - интерфейс для работы с расписанием
@@ -75,8 +116,17 @@ type NotificationRepository interface {
 	CreateNotification(ctx context.Context, notification models.Notification) error
 	GetNotificationByID(ctx context.Context, id models.NotificationID) (models.Notification, error)
 	GetPendingNotifications(ctx context.Context, before time.Time) ([]models.Notification, error)
+	// ClaimDueNotifications атомарно захватывает до limit уведомлений, время которых наступило,
+	// и продлевает их аренду до lockedUntil. Безопасно при нескольких репликах приложения.
+	ClaimDueNotifications(ctx context.Context, now, lockedUntil time.Time, limit int) ([]models.Notification, error)
 	UpdateNotification(ctx context.Context, notification models.Notification) error
 	DeleteNotification(ctx context.Context, id models.NotificationID) error
+	// DeletePendingNotificationsByTaskID удаляет еще не отправленные уведомления задачи,
+	// в том числе захваченные воркером: отправка удаленного уведомления не фиксируется и не повторяется
+	DeletePendingNotificationsByTaskID(ctx context.Context, taskID models.TaskID) error
+	// DeletePendingNotificationsByFocusSessionID удаляет еще не отправленное уведомление о завершении фокус-сессии,
+	// в том числе захваченное воркером
+	DeletePendingNotificationsByFocusSessionID(ctx context.Context, sessionID models.FocusSessionID) error
 }
 
 // NoteRepository - интерфейс для работы с заметками
@@ -95,5 +145,25 @@ type FocusSessionRepository interface {
 	CreateFocusSession(ctx context.Context, session models.FocusSession) error
 	GetFocusSessionByID(ctx context.Context, id models.FocusSessionID) (models.FocusSession, error)
 	GetFocusSessionsByUserID(ctx context.Context, userID models.UserID) ([]models.FocusSession, error)
+	// GetActiveFocusSession возвращает неостановленную сессию, время которой к моменту now еще не истекло
+	GetActiveFocusSession(ctx context.Context, userID models.UserID, now time.Time) (models.FocusSession, error)
 	UpdateFocusSession(ctx context.Context, session models.FocusSession) error
+	// LockUserFocusSessions до конца транзакции не дает другим запросам запустить сессию пользователя,
+	// чтобы параллельные запуски не прошли проверку активной сессии одновременно. Вызывается в WithinTransaction.
+	LockUserFocusSessions(ctx context.Context, userID models.UserID) error
+}
+
+// AuthTokenRepository - интерфейс для хранения refresh-токенов и отозванных access-токенов
+type AuthTokenRepository interface {
+	CreateRefreshToken(ctx context.Context, token models.RefreshToken) error
+	GetRefreshTokenByHash(ctx context.Context, tokenHash string) (models.RefreshToken, error)
+	// RevokeRefreshToken отзывает активный токен; если токен уже отозван (например, параллельной ротацией), возвращает ErrNotFound
+	RevokeRefreshToken(ctx context.Context, id models.RefreshTokenID, revokedAt time.Time) error
+	// RevokeUserRefreshTokens отзывает все активные refresh-токены пользователя
+	RevokeUserRefreshTokens(ctx context.Context, userID models.UserID, revokedAt time.Time) error
+	// RevokeAccessToken запоминает jti отозванного access-токена до окончания его срока действия
+	RevokeAccessToken(ctx context.Context, tokenID string, expiresAt time.Time) error
+	IsAccessTokenRevoked(ctx context.Context, tokenID string) (bool, error)
+	// DeleteExpiredAuthTokens удаляет истекшие refresh-токены и записи об отзыве
+	DeleteExpiredAuthTokens(ctx context.Context, now time.Time) error
 }
@@ -0,0 +1,137 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/singl3focus/uniflow/internal/core/models"
+	"github.com/singl3focus/uniflow/internal/core/ports/repository"
+)
+
+// DefaultRefreshTokenTTL - срок действия refresh-токена по умолчанию
+const DefaultRefreshTokenTTL = 30 * 24 * time.Hour
+
+var (
+	errRefreshTokenInactive = errors.New("refresh token expired or revoked")
+	errRefreshTokenReused   = errors.New("refresh token reuse detected")
+)
+
+// RefreshTokens обменивает refresh-токен на новую пару токенов (ротация).
+// Старый refresh-токен становится недействительным. Повторное предъявление уже
+// использованного токена означает его утечку: отзываются все сессии пользователя.
+func (u *Usecase) RefreshTokens(ctx context.Context, refreshToken string) (models.TokenPair, error) {
+	const op = "usecase.RefreshTokens"
+
+	token, err := u.repo.GetRefreshTokenByHash(ctx, models.HashRefreshToken(refreshToken))
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return models.TokenPair{}, ErrUnauthorized.SetPlace(op).SetCause(err)
+		}
+		return models.TokenPair{}, handleRepositoryError(op, err)
+	}
+
+	now := u.now()
+
+	if token.RevokedAt != nil {
+		if err = u.repo.RevokeUserRefreshTokens(ctx, token.UserID, now); err != nil {
+			return models.TokenPair{}, handleRepositoryError(op, err)
+		}
+		return models.TokenPair{}, ErrUnauthorized.SetPlace(op).SetCause(errRefreshTokenReused)
+	}
+
+	if !token.IsActive(now) {
+		return models.TokenPair{}, ErrUnauthorized.SetPlace(op).SetCause(errRefreshTokenInactive)
+	}
+
+	// Старый токен отзывается вместе с выпуском новой пары: если выпуск не удался,
+	// клиент может повторить запрос с тем же токеном, не попав под проверку на повторное использование
+	var tokens models.TokenPair
+	err = u.repo.WithinTransaction(ctx, func(ctx context.Context) error {
+		if err := u.repo.RevokeRefreshToken(ctx, token.ID, now); err != nil {
+			return err
+		}
+
+		var err error
+		tokens, err = u.issueTokens(ctx, token.UserID)
+		return err
+	})
+	if err != nil {
+		// Токен успели использовать параллельно
+		if errors.Is(err, repository.ErrNotFound) {
+			return models.TokenPair{}, ErrUnauthorized.SetPlace(op).SetCause(errRefreshTokenInactive)
+		}
+		return models.TokenPair{}, handleRepositoryError(op, err)
+	}
+
+	return tokens, nil
+}
+
+// Logout завершает сессию: отзывает refresh-токен и выданный вместе с ним access-токен.
+// Неизвестный или уже отозванный токен ошибкой не считается.
+func (u *Usecase) Logout(ctx context.Context, refreshToken string) error {
+	const op = "usecase.Logout"
+
+	token, err := u.repo.GetRefreshTokenByHash(ctx, models.HashRefreshToken(refreshToken))
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return nil
+		}
+		return handleRepositoryError(op, err)
+	}
+
+	now := u.now()
+
+	if err = u.repo.RevokeRefreshToken(ctx, token.ID, now); err != nil && !errors.Is(err, repository.ErrNotFound) {
+		return handleRepositoryError(op, err)
+	}
+
+	if token.AccessExpiresAt.After(now) {
+		if err = u.repo.RevokeAccessToken(ctx, token.AccessTokenID, token.AccessExpiresAt); err != nil {
+			return handleRepositoryError(op, err)
+		}
+	}
+
+	// Заодно чистим истекшие записи, чтобы таблицы не росли бесконечно
+	if err = u.repo.DeleteExpiredAuthTokens(ctx, now); err != nil {
+		return handleRepositoryError(op, err)
+	}
+
+	return nil
+}
+
+// IsAccessTokenRevoked сообщает, отозван ли access-токен с данным jti
+func (u *Usecase) IsAccessTokenRevoked(ctx context.Context, tokenID string) (bool, error) {
+	const op = "usecase.IsAccessTokenRevoked"
+
+	revoked, err := u.repo.IsAccessTokenRevoked(ctx, tokenID)
+	if err != nil {
+		return false, handleRepositoryError(op, err)
+	}
+
+	return revoked, nil
+}
+
+// issueTokens выпускает access-токен и сохраняет парный ему refresh-токен
+func (u *Usecase) issueTokens(ctx context.Context, userID models.UserID) (models.TokenPair, error) {
+	access, claims, err := u.jwtManager.GenerateToken(userID)
+	if err != nil {
+		return models.TokenPair{}, err
+	}
+	accessExpiresAt := claims.ExpiresAt.Time
+
+	token, raw, err := models.NewRefreshToken(userID, claims.ID, accessExpiresAt, u.refreshTokenTTL, u.now())
+	if err != nil {
+		return models.TokenPair{}, err
+	}
+
+	if err = u.repo.CreateRefreshToken(ctx, token); err != nil {
+		return models.TokenPair{}, err
+	}
+
+	return models.TokenPair{
+		AccessToken:     access,
+		RefreshToken:    raw,
+		AccessExpiresAt: accessExpiresAt,
+	}, nil
+}
@@ -0,0 +1,123 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+
+	jwtpkg "github.com/singl3focus/uniflow/pkg/jwt"
+)
+
+func newAuthTestUsecase(repo *fakeRepo) *Usecase {
+	return NewUsecase(repo, jwtpkg.NewJWTManager("test-secret", 15*time.Minute))
+}
+
+func TestRefreshTokens_Rotation(t *testing.T) {
+	ctx := context.Background()
+	repo := newFakeRepo()
+	uc := newAuthTestUsecase(repo)
+	userID := uuid.New()
+
+	first, err := uc.issueTokens(ctx, userID)
+	if err != nil {
+		t.Fatalf("issueTokens() error = %v", err)
+	}
+
+	second, err := uc.RefreshTokens(ctx, first.RefreshToken)
+	if err != nil {
+		t.Fatalf("RefreshTokens() error = %v", err)
+	}
+	if second.RefreshToken == first.RefreshToken || second.AccessToken == first.AccessToken {
+		t.Fatalf("RefreshTokens() returned the same tokens")
+	}
+
+	// Повторное использование обмененного токена - признак утечки: отзываются все сессии
+	if _, err = uc.RefreshTokens(ctx, first.RefreshToken); !errors.Is(err, ErrUnauthorized) {
+		t.Fatalf("RefreshTokens() with used token error = %v, want ErrUnauthorized", err)
+	}
+	if _, err = uc.RefreshTokens(ctx, second.RefreshToken); !errors.Is(err, ErrUnauthorized) {
+		t.Errorf("RefreshTokens() after reuse error = %v, want ErrUnauthorized", err)
+	}
+
+	if _, err = uc.RefreshTokens(ctx, "unknown"); !errors.Is(err, ErrUnauthorized) {
+		t.Errorf("RefreshTokens() with unknown token error = %v, want ErrUnauthorized", err)
+	}
+}
+
+func TestRefreshTokens_IssueFailure(t *testing.T) {
+	ctx := context.Background()
+	repo := newFakeRepo()
+	uc := newAuthTestUsecase(repo)
+
+	first, err := uc.issueTokens(ctx, uuid.New())
+	if err != nil {
+		t.Fatalf("issueTokens() error = %v", err)
+	}
+
+	repo.refreshTokenErr = errors.New("connection reset")
+	if _, err = uc.RefreshTokens(ctx, first.RefreshToken); err == nil || errors.Is(err, ErrUnauthorized) {
+		t.Fatalf("RefreshTokens() error = %v, want internal error", err)
+	}
+
+	// Старый токен не отозван: повтор не считается повторным использованием
+	repo.refreshTokenErr = nil
+	if _, err = uc.RefreshTokens(ctx, first.RefreshToken); err != nil {
+		t.Errorf("retried RefreshTokens() error = %v", err)
+	}
+}
+
+func TestRefreshTokens_Expired(t *testing.T) {
+	ctx := context.Background()
+	repo := newFakeRepo()
+	uc := newAuthTestUsecase(repo)
+
+	tokens, err := uc.issueTokens(ctx, uuid.New())
+	if err != nil {
+		t.Fatalf("issueTokens() error = %v", err)
+	}
+
+	uc.now = func() time.Time { return time.Now().Add(DefaultRefreshTokenTTL + time.Hour) }
+	if _, err = uc.RefreshTokens(ctx, tokens.RefreshToken); !errors.Is(err, ErrUnauthorized) {
+		t.Errorf("RefreshTokens() with expired token error = %v, want ErrUnauthorized", err)
+	}
+}
+
+func TestLogout_RevokesTokens(t *testing.T) {
+	ctx := context.Background()
+	repo := newFakeRepo()
+	uc := newAuthTestUsecase(repo)
+	jm := jwtpkg.NewJWTManager("test-secret", 15*time.Minute)
+
+	tokens, err := uc.issueTokens(ctx, uuid.New())
+	if err != nil {
+		t.Fatalf("issueTokens() error = %v", err)
+	}
+
+	claims, err := jm.ValidateToken(tokens.AccessToken)
+	if err != nil {
+		t.Fatalf("ValidateToken() error = %v", err)
+	}
+
+	if revoked, _ := uc.IsAccessTokenRevoked(ctx, claims.ID); revoked {
+		t.Fatalf("access token revoked before logout")
+	}
+
+	if err = uc.Logout(ctx, tokens.RefreshToken); err != nil {
+		t.Fatalf("Logout() error = %v", err)
+	}
+
+	if revoked, _ := uc.IsAccessTokenRevoked(ctx, claims.ID); !revoked {
+		t.Errorf("access token is not revoked after logout")
+	}
+	if _, err = uc.RefreshTokens(ctx, tokens.RefreshToken); !errors.Is(err, ErrUnauthorized) {
+		t.Errorf("RefreshTokens() after logout error = %v, want ErrUnauthorized", err)
+	}
+
+	// Повторный выход не ошибка
+	if err = uc.Logout(ctx, tokens.RefreshToken); err != nil {
+		t.Errorf("second Logout() error = %v", err)
+	}
+}
@@ -0,0 +1,43 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/singl3focus/uniflow/internal/core/models"
+)
+
+func TestUpdateContext(t *testing.T) {
+	ctx := context.Background()
+	uc := newTestUsecase(newFakeRepo(), time.Now())
+	userID := uuid.New().String()
+	deadlineAt := time.Now().AddDate(0, 1, 0).Format(time.RFC3339)
+
+	c, err := uc.CreateContext(ctx, userID, models.ContextTypeOther, "Диплом", "", "#3B82F6", nil, &deadlineAt)
+	if err != nil {
+		t.Fatalf("CreateContext() error = %v", err)
+	}
+	id := c.ID.String()
+
+	if _, err = uc.UpdateContext(ctx, userID, id, "hobby", nil, nil, nil, nil, nil); !errors.Is(err, ErrInvalidData) {
+		t.Errorf("UpdateContext() with unknown type error = %v, want ErrInvalidData", err)
+	}
+
+	empty := ""
+	if _, err = uc.UpdateContext(ctx, userID, id, "", &empty, nil, nil, nil, nil); !errors.Is(err, ErrInvalidData) {
+		t.Errorf("UpdateContext() with empty title error = %v, want ErrInvalidData", err)
+	}
+
+	color := "#EF4444"
+	updated, err := uc.UpdateContext(ctx, userID, id, string(models.ContextTypeProject), nil, nil, &color, nil, &empty)
+	if err != nil {
+		t.Fatalf("UpdateContext() error = %v", err)
+	}
+	if updated.Type != models.ContextTypeProject || updated.Color != color || updated.DeadlineAt != nil || updated.Title != "Диплом" {
+		t.Errorf("UpdateContext() = %+v, want project type, new color, no deadline and the same title", updated)
+	}
+}
@@ -0,0 +1,98 @@
+package usecase
+
+import (
+	"context"
+	"time"
+
+	"github.com/singl3focus/uniflow/internal/core/models"
+)
+
+// ===========================
+// Daily digest use cases
+// ===========================
+
+// ComposeDailyDigest собирает сводку вида kind на день пользователя, в который попадает now
+func (u *Usecase) ComposeDailyDigest(ctx context.Context, user models.User, kind models.DigestKind, now time.Time) (models.DailyDigest, error) {
+	const op = "usecase.ComposeDailyDigest"
+
+	dayStart := user.Settings.StartOfDay(now)
+	dayEnd := dayStart.AddDate(0, 0, 1)
+	active := []models.TaskStatus{models.TaskStatusTodo, models.TaskStatusInProgress}
+
+	todayFilter := models.TaskFilter{
+		DueFrom: &dayStart,
+		DueTo:   &dayEnd,
+		Sort:    models.TaskSortDue,
+	}
+	// Вечером завершенные за день задачи уже не интересны
+	if kind == models.DigestKindEvening {
+		todayFilter.Statuses = active
+	}
+
+	today, err := u.repo.ListTasks(ctx, user.ID, todayFilter)
+	if err != nil {
+		return models.DailyDigest{}, handleRepositoryError(op, err)
+	}
+
+	overdue, err := u.repo.ListTasks(ctx, user.ID, models.TaskFilter{
+		Statuses: active,
+		DueTo:    &dayStart,
+		Sort:     models.TaskSortDue,
+	})
+	if err != nil {
+		return models.DailyDigest{}, handleRepositoryError(op, err)
+	}
+
+	digest := models.DailyDigest{
+		Kind:    kind,
+		Date:    dayStart,
+		Today:   today,
+		Overdue: overdue,
+	}
+
+	if kind == models.DigestKindMorning {
+		digest.Classes, err = u.repo.GetScheduleEntriesByWeekday(ctx, user.ID, models.WeekdayOf(dayStart))
+		if err != nil {
+			return models.DailyDigest{}, handleRepositoryError(op, err)
+		}
+	}
+
+	return digest, nil
+}
+
+// PostponeTaskToTomorrow переносит срок задачи на завтра по часовому поясу пользователя.
+// Время дня сохраняется; задача без срока или на весь день получает срок на весь завтрашний день.
+func (u *Usecase) PostponeTaskToTomorrow(ctx context.Context, userIDStr, taskIDStr string) (models.Task, error) {
+	const op = "usecase.PostponeTaskToTomorrow"
+
+	task, err := u.getOwnTask(ctx, userIDStr, taskIDStr)
+	if err != nil {
+		return models.Task{}, handleOwnershipError(op, err)
+	}
+
+	settings, err := u.userSettings(ctx, task.UserID)
+	if err != nil {
+		return models.Task{}, handleRepositoryError(op, err)
+	}
+	loc := settings.Location()
+
+	tomorrow := settings.StartOfDay(u.now()).AddDate(0, 0, 1)
+	dueAt := tomorrow.Format(models.DueDateLayout)
+	if task.DueAt != nil && !task.AllDay {
+		due := task.DueAt.In(loc)
+		dueAt = time.Date(tomorrow.Year(), tomorrow.Month(), tomorrow.Day(), due.Hour(), due.Minute(), 0, 0, loc).Format(time.RFC3339)
+	}
+
+	return u.UpdateTask(ctx, userIDStr, taskIDStr, nil, nil, nil, &dueAt, nil, nil, nil)
+}
+
+// scheduleUserDigests пересчитывает время следующих сводок пользователя по его настройкам
+func (u *Usecase) scheduleUserDigests(ctx context.Context, userID models.UserID, settings models.UserSettings) error {
+	now := u.now()
+	for _, kind := range models.DigestKinds {
+		if err := u.repo.SetNextDigestAt(ctx, userID, kind, settings.NextDigestAt(kind, now)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,95 @@
+package usecase
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/singl3focus/uniflow/internal/core/models"
+)
+
+func TestComposeDailyDigest(t *testing.T) {
+	ctx := context.Background()
+	// Понедельник, 08:00 по Москве
+	now := time.Date(2025, 3, 10, 5, 0, 0, 0, time.UTC)
+
+	repo := newFakeRepo()
+	uc := newTestUsecase(repo, now)
+
+	user, err := models.NewUser("42")
+	if err != nil {
+		t.Fatalf("NewUser() error = %v", err)
+	}
+	_ = repo.CreateUser(ctx, user)
+	userID := user.ID.String()
+
+	create := func(title string, dueAt time.Time) models.Task {
+		due := dueAt.Format(time.RFC3339)
+		task, err := uc.CreateTask(ctx, userID, nil, title, "", &due, nil, "", nil)
+		if err != nil {
+			t.Fatalf("CreateTask() error = %v", err)
+		}
+		return task
+	}
+
+	create("Просроченная", now.AddDate(0, 0, -2))
+	create("Сегодня", now.Add(6*time.Hour))
+	done := create("Сделанная", now.Add(8*time.Hour))
+	create("Завтра", now.Add(24*time.Hour))
+	if err = uc.UpdateTaskStatus(ctx, userID, done.ID.String(), models.TaskStatusCompleted); err != nil {
+		t.Fatalf("UpdateTaskStatus() error = %v", err)
+	}
+	if _, err = uc.CreateScheduleEntry(ctx, userID, nil, "Матанализ", models.Monday, "10:15", "11:50", ""); err != nil {
+		t.Fatalf("CreateScheduleEntry() error = %v", err)
+	}
+
+	morning, err := uc.ComposeDailyDigest(ctx, user, models.DigestKindMorning, now)
+	if err != nil {
+		t.Fatalf("ComposeDailyDigest(morning) error = %v", err)
+	}
+	if len(morning.Today) != 2 || len(morning.Overdue) != 1 || len(morning.Classes) != 1 {
+		t.Errorf("morning digest: today = %d, overdue = %d, classes = %d, want 2, 1, 1",
+			len(morning.Today), len(morning.Overdue), len(morning.Classes))
+	}
+
+	// Вечером сделанная задача и занятия в обзор не попадают
+	evening, err := uc.ComposeDailyDigest(ctx, user, models.DigestKindEvening, now.Add(13*time.Hour))
+	if err != nil {
+		t.Fatalf("ComposeDailyDigest(evening) error = %v", err)
+	}
+	if len(evening.Today) != 1 || len(evening.Overdue) != 1 || len(evening.Classes) != 0 {
+		t.Errorf("evening review: today = %d, overdue = %d, classes = %d, want 1, 1, 0",
+			len(evening.Today), len(evening.Overdue), len(evening.Classes))
+	}
+}
+
+func TestPostponeTaskToTomorrow(t *testing.T) {
+	ctx := context.Background()
+	// 23:30 по Москве 10 марта
+	now := time.Date(2025, 3, 10, 20, 30, 0, 0, time.UTC)
+
+	repo := newFakeRepo()
+	uc := newTestUsecase(repo, now)
+
+	user, err := models.NewUser("42")
+	if err != nil {
+		t.Fatalf("NewUser() error = %v", err)
+	}
+	_ = repo.CreateUser(ctx, user)
+	userID := user.ID.String()
+
+	// Срок 10 марта 18:00 по Москве: перенос на 11 марта, время сохраняется
+	dueAt := time.Date(2025, 3, 10, 15, 0, 0, 0, time.UTC).Format(time.RFC3339)
+	task, err := uc.CreateTask(ctx, userID, nil, "Отчет", "", &dueAt, nil, "", nil)
+	if err != nil {
+		t.Fatalf("CreateTask() error = %v", err)
+	}
+
+	postponed, err := uc.PostponeTaskToTomorrow(ctx, userID, task.ID.String())
+	if err != nil {
+		t.Fatalf("PostponeTaskToTomorrow() error = %v", err)
+	}
+	if want := time.Date(2025, 3, 11, 15, 0, 0, 0, time.UTC); !postponed.DueAt.Equal(want) {
+		t.Errorf("due_at = %v, want %v", postponed.DueAt.UTC(), want)
+	}
+}
@@ -0,0 +1,163 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/singl3focus/uniflow/internal/core/models"
+	"github.com/singl3focus/uniflow/internal/core/ports/repository"
+)
+
+// ===========================
+// Focus session use cases
+// ===========================
+
+// StartFocusSession запускает фокус-сессию и ставит в очередь уведомление о ее завершении.
+// Уведомление хранится в БД, поэтому будет отправлено и после перезапуска сервера.
+func (u *Usecase) StartFocusSession(ctx context.Context, userIDStr string, contextID *string, durationMinutes int) (models.FocusSession, error) {
+	const op = "usecase.StartFocusSession"
+
+	userID, err := models.ParseUserID(userIDStr)
+	if err != nil {
+		return models.FocusSession{}, ErrInvalidData.SetPlace(op).SetCause(err)
+	}
+
+	var contextIDCleaned *models.ContextID
+	if contextID != nil && *contextID != "" {
+		cid, err := u.getOwnContextID(ctx, userID, *contextID)
+		if err != nil {
+			return models.FocusSession{}, handleOwnershipError(op, err)
+		}
+		contextIDCleaned = &cid
+	}
+
+	session, err := models.NewFocusSession(userID, contextIDCleaned, durationMinutes, u.now())
+	if err != nil {
+		return models.FocusSession{}, ErrInvalidData.SetPlace(op).SetCause(err)
+	}
+
+	notification := models.NewNotification(userID, nil, session.PlannedEndAt(), models.NotificationChannelMax, focusSessionEndMessage(session))
+	notification.FocusSessionID = &session.ID
+
+	// Проверка активной сессии и обе записи - одна транзакция: сессия без уведомления
+	// не остается висеть, а параллельный запуск ждет блокировки и видит созданную сессию
+	err = u.repo.WithinTransaction(ctx, func(ctx context.Context) error {
+		if err := u.repo.LockUserFocusSessions(ctx, userID); err != nil {
+			return err
+		}
+
+		if _, err := u.repo.GetActiveFocusSession(ctx, userID, u.now()); err == nil {
+			return models.ErrFocusSessionActive
+		} else if !errors.Is(err, repository.ErrNotFound) {
+			return err
+		}
+
+		if err := u.repo.CreateFocusSession(ctx, session); err != nil {
+			return err
+		}
+		return u.repo.CreateNotification(ctx, notification)
+	})
+	if errors.Is(err, models.ErrFocusSessionActive) {
+		return models.FocusSession{}, ErrInvalidData.SetPlace(op).SetCause(err)
+	}
+	if err != nil {
+		return models.FocusSession{}, handleRepositoryError(op, err)
+	}
+
+	return session, nil
+}
+
+// StopFocusSession досрочно останавливает сессию и отменяет уведомление о ее завершении
+func (u *Usecase) StopFocusSession(ctx context.Context, userIDStr, sessionIDStr string) (models.FocusSession, error) {
+	const op = "usecase.StopFocusSession"
+
+	session, err := u.getOwnFocusSession(ctx, userIDStr, sessionIDStr)
+	if err != nil {
+		return models.FocusSession{}, handleOwnershipError(op, err)
+	}
+
+	if session.EndedAt != nil {
+		return models.FocusSession{}, ErrInvalidData.SetPlace(op).SetCause(models.ErrFocusSessionEnded)
+	}
+
+	session.End(u.now())
+
+	err = u.repo.WithinTransaction(ctx, func(ctx context.Context) error {
+		if err := u.repo.UpdateFocusSession(ctx, session); err != nil {
+			return err
+		}
+		return u.repo.DeletePendingNotificationsByFocusSessionID(ctx, session.ID)
+	})
+	if err != nil {
+		return models.FocusSession{}, handleRepositoryError(op, err)
+	}
+
+	return session, nil
+}
+
+// GetActiveFocusSession возвращает идущую сессию пользователя или ErrNotFound
+func (u *Usecase) GetActiveFocusSession(ctx context.Context, userIDStr string) (models.FocusSession, error) {
+	const op = "usecase.GetActiveFocusSession"
+
+	userID, err := models.ParseUserID(userIDStr)
+	if err != nil {
+		return models.FocusSession{}, ErrInvalidData.SetPlace(op).SetCause(err)
+	}
+
+	session, err := u.repo.GetActiveFocusSession(ctx, userID, u.now())
+	if err != nil {
+		return models.FocusSession{}, handleRepositoryError(op, err)
+	}
+
+	return session, nil
+}
+
+func (u *Usecase) GetFocusSessions(ctx context.Context, userIDStr string) ([]models.FocusSession, error) {
+	const op = "usecase.GetFocusSessions"
+
+	userID, err := models.ParseUserID(userIDStr)
+	if err != nil {
+		return nil, ErrInvalidData.SetPlace(op).SetCause(err)
+	}
+
+	sessions, err := u.repo.GetFocusSessionsByUserID(ctx, userID)
+	if err != nil {
+		return nil, handleRepositoryError(op, err)
+	}
+
+	return sessions, nil
+}
+
+// getOwnFocusSession загружает фокус-сессию и проверяет, что она принадлежит пользователю
+func (u *Usecase) getOwnFocusSession(ctx context.Context, userIDStr, sessionIDStr string) (models.FocusSession, error) {
+	userID, err := models.ParseUserID(userIDStr)
+	if err != nil {
+		return models.FocusSession{}, ErrInvalidData.SetCause(err)
+	}
+
+	sessionID, err := models.ParseFocusSessionID(sessionIDStr)
+	if err != nil {
+		return models.FocusSession{}, ErrInvalidData.SetCause(err)
+	}
+
+	session, err := u.repo.GetFocusSessionByID(ctx, sessionID)
+	if err != nil {
+		return models.FocusSession{}, err
+	}
+
+	if session.UserID != userID {
+		return models.FocusSession{}, ErrNotFound.SetCause(errForeignResource)
+	}
+
+	return session, nil
+}
+
+func focusSessionEndMessage(session models.FocusSession) string {
+	return fmt.Sprintf(
+		"✅ Фокус-сессия завершена!\n\n"+
+			"⏱ Время работы: %d минут\n"+
+			"💪 Отличная работа! Не забудьте сделать перерыв.",
+		session.DurationMinutes,
+	)
+}
@@ -0,0 +1,104 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/singl3focus/uniflow/internal/core/models"
+)
+
+func TestFocusSession_Lifecycle(t *testing.T) {
+	ctx := context.Background()
+	now := time.Date(2025, 3, 10, 12, 0, 0, 0, time.UTC)
+	repo := newFakeRepo()
+	uc := newTestUsecase(repo, now)
+	userID := uuid.New().String()
+
+	session, err := uc.StartFocusSession(ctx, userID, nil, 25)
+	if err != nil {
+		t.Fatalf("StartFocusSession() error = %v", err)
+	}
+
+	if len(repo.notifications) != 1 {
+		t.Fatalf("expected 1 end notification, got %d", len(repo.notifications))
+	}
+	for _, n := range repo.notifications {
+		if n.FocusSessionID == nil || *n.FocusSessionID != session.ID {
+			t.Errorf("notification is not linked to the session")
+		}
+		if want := now.Add(25 * time.Minute); !n.NotifyAt.Equal(want) {
+			t.Errorf("notify_at = %v, want %v", n.NotifyAt, want)
+		}
+	}
+
+	if _, err := uc.StartFocusSession(ctx, userID, nil, 25); !errors.Is(err, models.ErrFocusSessionActive) {
+		t.Errorf("second StartFocusSession() error = %v, want ErrFocusSessionActive", err)
+	}
+
+	if _, err := uc.StopFocusSession(ctx, uuid.New().String(), session.ID.String()); !errors.Is(err, ErrNotFound) {
+		t.Errorf("StopFocusSession() by stranger error = %v, want ErrNotFound", err)
+	}
+
+	// Уведомление, уже захваченное воркером, тоже отменяется остановкой
+	for id, n := range repo.notifications {
+		n.Status = models.NotificationStatusProcessing
+		repo.notifications[id] = n
+	}
+
+	// Досрочная остановка через 10 минут
+	stopAt := now.Add(10 * time.Minute)
+	uc.now = func() time.Time { return stopAt }
+
+	stopped, err := uc.StopFocusSession(ctx, userID, session.ID.String())
+	if err != nil {
+		t.Fatalf("StopFocusSession() error = %v", err)
+	}
+	if stopped.EndedAt == nil || !stopped.EndedAt.Equal(stopAt) {
+		t.Errorf("ended_at = %v, want %v", stopped.EndedAt, stopAt)
+	}
+	if got := stopped.FocusedDuration(stopAt.Add(time.Hour)); got != 10*time.Minute {
+		t.Errorf("focused duration = %v, want 10m", got)
+	}
+	if len(repo.notifications) != 0 {
+		t.Errorf("end notification was not cancelled, %d left", len(repo.notifications))
+	}
+
+	if _, err := uc.StopFocusSession(ctx, userID, session.ID.String()); !errors.Is(err, models.ErrFocusSessionEnded) {
+		t.Errorf("repeated StopFocusSession() error = %v, want ErrFocusSessionEnded", err)
+	}
+}
+
+func TestStartFocusSession_NotificationFailure(t *testing.T) {
+	ctx := context.Background()
+	repo := newFakeRepo()
+	uc := newTestUsecase(repo, time.Date(2025, 3, 10, 12, 0, 0, 0, time.UTC))
+	userID := uuid.New().String()
+
+	repo.notificationErr = errors.New("connection reset")
+	if _, err := uc.StartFocusSession(ctx, userID, nil, 25); err == nil {
+		t.Fatal("StartFocusSession() error = nil, want notification error")
+	}
+	if len(repo.focus) != 0 {
+		t.Fatalf("session without end notification was kept: %d sessions", len(repo.focus))
+	}
+
+	// Повтор не упирается в брошенную сессию
+	repo.notificationErr = nil
+	if _, err := uc.StartFocusSession(ctx, userID, nil, 25); err != nil {
+		t.Errorf("retried StartFocusSession() error = %v", err)
+	}
+}
+
+func TestFocusSession_InvalidDuration(t *testing.T) {
+	uc := newTestUsecase(newFakeRepo(), time.Now())
+
+	for _, minutes := range []int{0, -5, models.MaxFocusMinutes + 1} {
+		if _, err := uc.StartFocusSession(context.Background(), uuid.New().String(), nil, minutes); !errors.Is(err, ErrInvalidData) {
+			t.Errorf("StartFocusSession(%d) error = %v, want ErrInvalidData", minutes, err)
+		}
+	}
+}
@@ -0,0 +1,182 @@
+package usecase
+
+import (
+	"context"
+
+	"github.com/singl3focus/uniflow/internal/core/models"
+)
+
+// ===========================
+// Note use cases
+// ===========================
+
+func (u *Usecase) CreateNote(ctx context.Context, userIDStr string, contextID *string, noteType models.NoteType, contentURL, text string) (models.Note, error) {
+	const op = "usecase.CreateNote"
+
+	userID, err := models.ParseUserID(userIDStr)
+	if err != nil {
+		return models.Note{}, ErrInvalidData.SetPlace(op).SetCause(err)
+	}
+
+	var contextIDCleaned *models.ContextID
+	if contextID != nil && *contextID != "" {
+		cid, err := u.getOwnContextID(ctx, userID, *contextID)
+		if err != nil {
+			return models.Note{}, handleOwnershipError(op, err)
+		}
+		contextIDCleaned = &cid
+	}
+
+	note, err := models.NewNote(userID, contextIDCleaned, noteType, contentURL, text)
+	if err != nil {
+		return models.Note{}, ErrInvalidData.SetPlace(op).SetCause(err)
+	}
+
+	if err = u.repo.CreateNote(ctx, note); err != nil {
+		return models.Note{}, handleRepositoryError(op, err)
+	}
+
+	return note, nil
+}
+
+func (u *Usecase) GetNotesByUserID(ctx context.Context, userIDStr string) ([]models.Note, error) {
+	const op = "usecase.GetNotesByUserID"
+
+	userID, err := models.ParseUserID(userIDStr)
+	if err != nil {
+		return nil, ErrInvalidData.SetPlace(op).SetCause(err)
+	}
+
+	notes, err := u.repo.GetNotesByUserID(ctx, userID)
+	if err != nil {
+		return nil, handleRepositoryError(op, err)
+	}
+
+	return notes, nil
+}
+
+func (u *Usecase) GetNotesByContextID(ctx context.Context, userIDStr, contextIDStr string) ([]models.Note, error) {
+	const op = "usecase.GetNotesByContextID"
+
+	userID, err := models.ParseUserID(userIDStr)
+	if err != nil {
+		return nil, ErrInvalidData.SetPlace(op).SetCause(err)
+	}
+
+	contextID, err := u.getOwnContextID(ctx, userID, contextIDStr)
+	if err != nil {
+		return nil, handleOwnershipError(op, err)
+	}
+
+	notes, err := u.repo.GetNotesByContextID(ctx, contextID)
+	if err != nil {
+		return nil, handleRepositoryError(op, err)
+	}
+
+	return notes, nil
+}
+
+func (u *Usecase) GetNoteByID(ctx context.Context, userIDStr, noteIDStr string) (models.Note, error) {
+	const op = "usecase.GetNoteByID"
+
+	note, err := u.getOwnNote(ctx, userIDStr, noteIDStr)
+	if err != nil {
+		return models.Note{}, handleOwnershipError(op, err)
+	}
+
+	return note, nil
+}
+
+// UpdateNote обновляет текст, ссылку и привязку заметки к контексту.
+// Пустая строка в contextID отвязывает заметку от контекста.
+func (u *Usecase) UpdateNote(ctx context.Context, userIDStr, noteIDStr string, contextID, text, contentURL *string) (models.Note, error) {
+	const op = "usecase.UpdateNote"
+
+	note, err := u.getOwnNote(ctx, userIDStr, noteIDStr)
+	if err != nil {
+		return models.Note{}, handleOwnershipError(op, err)
+	}
+
+	if contextID != nil {
+		if *contextID == "" {
+			note.AssignContext(nil)
+		} else {
+			cid, err := u.getOwnContextID(ctx, note.UserID, *contextID)
+			if err != nil {
+				return models.Note{}, handleOwnershipError(op, err)
+			}
+			note.AssignContext(&cid)
+		}
+	}
+
+	// Обновляем только переданные поля
+	note.Update(text, contentURL)
+
+	if err = note.Validate(); err != nil {
+		return models.Note{}, ErrInvalidData.SetPlace(op).SetCause(err)
+	}
+
+	if err = u.repo.UpdateNote(ctx, note); err != nil {
+		return models.Note{}, handleRepositoryError(op, err)
+	}
+
+	return note, nil
+}
+
+func (u *Usecase) DeleteNote(ctx context.Context, userIDStr, noteIDStr string) error {
+	const op = "usecase.DeleteNote"
+
+	note, err := u.getOwnNote(ctx, userIDStr, noteIDStr)
+	if err != nil {
+		return handleOwnershipError(op, err)
+	}
+
+	if err = u.repo.DeleteNote(ctx, note.ID); err != nil {
+		return handleRepositoryError(op, err)
+	}
+
+	return nil
+}
+
+// getOwnNote загружает заметку и проверяет, что она принадлежит пользователю
+func (u *Usecase) getOwnNote(ctx context.Context, userIDStr, noteIDStr string) (models.Note, error) {
+	userID, err := models.ParseUserID(userIDStr)
+	if err != nil {
+		return models.Note{}, ErrInvalidData.SetCause(err)
+	}
+
+	noteID, err := models.ParseNoteID(noteIDStr)
+	if err != nil {
+		return models.Note{}, ErrInvalidData.SetCause(err)
+	}
+
+	note, err := u.repo.GetNoteByID(ctx, noteID)
+	if err != nil {
+		return models.Note{}, err
+	}
+
+	if note.UserID != userID {
+		return models.Note{}, ErrNotFound.SetCause(errForeignResource)
+	}
+
+	return note, nil
+}
+
+// getOwnContextID проверяет, что контекст существует и принадлежит пользователю
+func (u *Usecase) getOwnContextID(ctx context.Context, userID models.UserID, contextIDStr string) (models.ContextID, error) {
+	contextID, err := models.ParseContextID(contextIDStr)
+	if err != nil {
+		return models.ContextID{}, ErrInvalidData.SetCause(err)
+	}
+
+	c, err := u.repo.GetContextByID(ctx, contextID)
+	if err != nil {
+		return models.ContextID{}, err
+	}
+
+	if c.UserID != userID {
+		return models.ContextID{}, ErrNotFound.SetCause(errForeignResource)
+	}
+
+	return contextID, nil
+}
@@ -0,0 +1,98 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/singl3focus/uniflow/internal/core/models"
+)
+
+func TestNote_ContextOwnership(t *testing.T) {
+	ctx := context.Background()
+	repo := newFakeRepo()
+	uc := newTestUsecase(repo, time.Now())
+
+	owner := uuid.New()
+	stranger := uuid.New()
+
+	own, _ := models.NewContext(owner, models.ContextTypeSubject, "Физика", "", "", nil, nil)
+	foreign, _ := models.NewContext(stranger, models.ContextTypeSubject, "Химия", "", "", nil, nil)
+	repo.contexts[own.ID] = own
+	repo.contexts[foreign.ID] = foreign
+
+	ownID, foreignID := own.ID.String(), foreign.ID.String()
+
+	if _, err := uc.CreateNote(ctx, owner.String(), &foreignID, models.NoteTypeText, "", "конспект"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("CreateNote() in foreign context error = %v, want ErrNotFound", err)
+	}
+
+	note, err := uc.CreateNote(ctx, owner.String(), &ownID, models.NoteTypeText, "", "конспект")
+	if err != nil {
+		t.Fatalf("CreateNote() error = %v", err)
+	}
+
+	if _, err := uc.UpdateNote(ctx, owner.String(), note.ID.String(), &foreignID, nil, nil); !errors.Is(err, ErrNotFound) {
+		t.Errorf("UpdateNote() moving to foreign context error = %v, want ErrNotFound", err)
+	}
+
+	if _, err := uc.GetNoteByID(ctx, stranger.String(), note.ID.String()); !errors.Is(err, ErrNotFound) {
+		t.Errorf("GetNoteByID() by stranger error = %v, want ErrNotFound", err)
+	}
+
+	detach := ""
+	updated, err := uc.UpdateNote(ctx, owner.String(), note.ID.String(), &detach, nil, nil)
+	if err != nil {
+		t.Fatalf("UpdateNote() error = %v", err)
+	}
+	if updated.ContextID != nil {
+		t.Errorf("context_id = %v, want nil after detach", updated.ContextID)
+	}
+}
+
+func TestCreateNote_RequiresContent(t *testing.T) {
+	uc := newTestUsecase(newFakeRepo(), time.Now())
+	userID := uuid.New().String()
+
+	if _, err := uc.CreateNote(context.Background(), userID, nil, models.NoteTypeText, "", ""); !errors.Is(err, ErrInvalidData) {
+		t.Errorf("CreateNote() empty text error = %v, want ErrInvalidData", err)
+	}
+
+	if _, err := uc.CreateNote(context.Background(), userID, nil, models.NoteTypeImage, "", "подпись"); !errors.Is(err, ErrInvalidData) {
+		t.Errorf("CreateNote() image without url error = %v, want ErrInvalidData", err)
+	}
+}
+
+func TestUpdateNote_KeepsContent(t *testing.T) {
+	ctx := context.Background()
+	repo := newFakeRepo()
+	uc := newTestUsecase(repo, time.Now())
+	userID := uuid.New().String()
+
+	text, err := uc.CreateNote(ctx, userID, nil, models.NoteTypeText, "", "конспект")
+	if err != nil {
+		t.Fatalf("CreateNote() error = %v", err)
+	}
+	image, err := uc.CreateNote(ctx, userID, nil, models.NoteTypeImage, "https://example.com/board.jpg", "")
+	if err != nil {
+		t.Fatalf("CreateNote() error = %v", err)
+	}
+
+	empty := ""
+	if _, err = uc.UpdateNote(ctx, userID, text.ID.String(), nil, &empty, nil); !errors.Is(err, ErrInvalidData) {
+		t.Errorf("UpdateNote() clearing text error = %v, want ErrInvalidData", err)
+	}
+	if _, err = uc.UpdateNote(ctx, userID, image.ID.String(), nil, nil, &empty); !errors.Is(err, ErrInvalidData) {
+		t.Errorf("UpdateNote() clearing content url error = %v, want ErrInvalidData", err)
+	}
+
+	if stored := repo.notes[text.ID]; stored.Text != "конспект" {
+		t.Errorf("text = %q, want unchanged", stored.Text)
+	}
+	if stored := repo.notes[image.ID]; stored.ContentURL == "" {
+		t.Error("content url was cleared")
+	}
+}
@@ -0,0 +1,108 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/singl3focus/uniflow/internal/core/models"
+)
+
+func TestTask_Ownership(t *testing.T) {
+	ctx := context.Background()
+	repo := newFakeRepo()
+	uc := newTestUsecase(repo, time.Now())
+
+	owner := uuid.New().String()
+	stranger := uuid.New().String()
+
+	task, err := uc.CreateTask(ctx, owner, nil, "Курсовая", "", nil, nil, "", nil)
+	if err != nil {
+		t.Fatalf("CreateTask() error = %v", err)
+	}
+
+	if _, err := uc.GetTaskByID(ctx, stranger, task.ID.String()); !errors.Is(err, ErrNotFound) {
+		t.Errorf("GetTaskByID() by stranger error = %v, want ErrNotFound", err)
+	}
+
+	title := "Чужая"
+	if _, err := uc.UpdateTask(ctx, stranger, task.ID.String(), nil, &title, nil, nil, nil, nil, nil); !errors.Is(err, ErrNotFound) {
+		t.Errorf("UpdateTask() by stranger error = %v, want ErrNotFound", err)
+	}
+
+	if err := uc.UpdateTaskStatus(ctx, stranger, task.ID.String(), models.TaskStatusCompleted); !errors.Is(err, ErrNotFound) {
+		t.Errorf("UpdateTaskStatus() by stranger error = %v, want ErrNotFound", err)
+	}
+
+	if err := uc.DeleteTask(ctx, stranger, task.ID.String()); !errors.Is(err, ErrNotFound) {
+		t.Errorf("DeleteTask() by stranger error = %v, want ErrNotFound", err)
+	}
+
+	stored, ok := repo.tasks[task.ID]
+	if !ok {
+		t.Fatalf("task was deleted by another user")
+	}
+	if stored.Title != "Курсовая" || stored.Status != models.TaskStatusTodo {
+		t.Errorf("task was modified by another user: title = %q, status = %q", stored.Title, stored.Status)
+	}
+
+	if err := uc.DeleteTask(ctx, owner, task.ID.String()); err != nil {
+		t.Errorf("DeleteTask() by owner error = %v", err)
+	}
+}
+
+func TestContext_Ownership(t *testing.T) {
+	ctx := context.Background()
+	repo := newFakeRepo()
+	uc := newTestUsecase(repo, time.Now())
+
+	owner := uuid.New().String()
+	stranger := uuid.New().String()
+
+	c, err := uc.CreateContext(ctx, owner, models.ContextTypeSubject, "Матанализ", "", "", nil, nil)
+	if err != nil {
+		t.Fatalf("CreateContext() error = %v", err)
+	}
+
+	if _, err := uc.GetContextByID(ctx, stranger, c.ID.String()); !errors.Is(err, ErrNotFound) {
+		t.Errorf("GetContextByID() by stranger error = %v, want ErrNotFound", err)
+	}
+
+	title := "Чужой"
+	if _, err := uc.UpdateContext(ctx, stranger, c.ID.String(), "", &title, nil, nil, nil, nil); !errors.Is(err, ErrNotFound) {
+		t.Errorf("UpdateContext() by stranger error = %v, want ErrNotFound", err)
+	}
+
+	if err := uc.DeleteContext(ctx, stranger, c.ID.String()); !errors.Is(err, ErrNotFound) {
+		t.Errorf("DeleteContext() by stranger error = %v, want ErrNotFound", err)
+	}
+
+	// Нельзя привязать свою задачу к чужому контексту
+	contextID := c.ID.String()
+	if _, err := uc.CreateTask(ctx, stranger, &contextID, "Подлог", "", nil, nil, "", nil); !errors.Is(err, ErrNotFound) {
+		t.Errorf("CreateTask() with foreign context error = %v, want ErrNotFound", err)
+	}
+
+	own, err := uc.CreateTask(ctx, stranger, nil, "Своя", "", nil, nil, "", nil)
+	if err != nil {
+		t.Fatalf("CreateTask() error = %v", err)
+	}
+	if _, err := uc.UpdateTask(ctx, stranger, own.ID.String(), &contextID, nil, nil, nil, nil, nil, nil); !errors.Is(err, ErrNotFound) {
+		t.Errorf("UpdateTask() with foreign context error = %v, want ErrNotFound", err)
+	}
+
+	stored, ok := repo.contexts[c.ID]
+	if !ok {
+		t.Fatalf("context was deleted by another user")
+	}
+	if stored.Title != "Матанализ" {
+		t.Errorf("context was modified by another user: title = %q", stored.Title)
+	}
+
+	if err := uc.DeleteContext(ctx, owner, c.ID.String()); err != nil {
+		t.Errorf("DeleteContext() by owner error = %v", err)
+	}
+}
@@ -0,0 +1,162 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/singl3focus/uniflow/internal/core/models"
+)
+
+func TestCompleteRecurringTask(t *testing.T) {
+	// Понедельник, 10 марта 2025
+	monday := time.Date(2025, 3, 10, 9, 0, 0, 0, time.UTC)
+	until := time.Date(2025, 3, 20, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name       string
+		due        time.Time
+		now        time.Time
+		recurrence models.Recurrence
+		wantNext   *time.Time
+	}{
+		{
+			name:       "daily every 3 days",
+			due:        monday,
+			now:        monday.Add(-time.Hour),
+			recurrence: models.Recurrence{Frequency: models.RecurrenceDaily, Interval: 3},
+			wantNext:   ptrTime(monday.AddDate(0, 0, 3)),
+		},
+		{
+			name:       "weekly on chosen weekdays",
+			due:        monday,
+			now:        monday.Add(-time.Hour),
+			recurrence: models.Recurrence{Frequency: models.RecurrenceWeekly, Weekdays: []models.Weekday{models.Monday, models.Wednesday}},
+			wantNext:   ptrTime(monday.AddDate(0, 0, 2)),
+		},
+		{
+			name:       "every other week skips the next week",
+			due:        monday.AddDate(0, 0, 2),
+			now:        monday,
+			recurrence: models.Recurrence{Frequency: models.RecurrenceWeekly, Interval: 2, Weekdays: []models.Weekday{models.Monday, models.Wednesday}},
+			wantNext:   ptrTime(monday.AddDate(0, 0, 14)),
+		},
+		{
+			name:       "monthly clamps to the last day of month",
+			due:        time.Date(2025, 1, 31, 9, 0, 0, 0, time.UTC),
+			now:        time.Date(2025, 1, 31, 8, 0, 0, 0, time.UTC),
+			recurrence: models.Recurrence{Frequency: models.RecurrenceMonthly},
+			wantNext:   ptrTime(time.Date(2025, 2, 28, 9, 0, 0, 0, time.UTC)),
+		},
+		{
+			name:       "overdue occurrence jumps past now",
+			due:        monday,
+			now:        monday.AddDate(0, 0, 2),
+			recurrence: models.Recurrence{Frequency: models.RecurrenceDaily},
+			wantNext:   ptrTime(monday.AddDate(0, 0, 3)),
+		},
+		{
+			name:       "series ends after until",
+			due:        monday.AddDate(0, 0, 7),
+			now:        monday,
+			recurrence: models.Recurrence{Frequency: models.RecurrenceWeekly, Until: &until},
+			wantNext:   nil,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			repo := newFakeRepo()
+			uc := newTestUsecase(repo, tt.now)
+
+			dueAt := tt.due.Format(time.RFC3339)
+			userID := uuid.New().String()
+			task, err := uc.CreateTask(ctx, userID, nil, "Домашка", "", &dueAt, &tt.recurrence, "", nil)
+			if err != nil {
+				t.Fatalf("CreateTask() error = %v", err)
+			}
+
+			if err = uc.UpdateTaskStatus(ctx, userID, task.ID.String(), models.TaskStatusCompleted); err != nil {
+				t.Fatalf("UpdateTaskStatus() error = %v", err)
+			}
+
+			// Повторное завершение после возобновления не должно порождать дубликат
+			if err = uc.UpdateTaskStatus(ctx, userID, task.ID.String(), models.TaskStatusTodo); err != nil {
+				t.Fatalf("UpdateTaskStatus() error = %v", err)
+			}
+			if err = uc.UpdateTaskStatus(ctx, userID, task.ID.String(), models.TaskStatusCompleted); err != nil {
+				t.Fatalf("UpdateTaskStatus() error = %v", err)
+			}
+
+			var next []models.Task
+			for _, tsk := range repo.tasks {
+				if tsk.ID != task.ID {
+					next = append(next, tsk)
+				}
+			}
+
+			if tt.wantNext == nil {
+				if len(next) != 0 {
+					t.Fatalf("got %d next occurrences, want none", len(next))
+				}
+				return
+			}
+
+			if len(next) != 1 {
+				t.Fatalf("got %d next occurrences, want 1", len(next))
+			}
+			if got := next[0]; !got.DueAt.Equal(*tt.wantNext) || got.Status != models.TaskStatusTodo || got.Recurrence == nil {
+				t.Errorf("next occurrence = due %v, status %s, recurrence %v; want due %v", got.DueAt, got.Status, got.Recurrence, tt.wantNext)
+			}
+			if len(repo.taskNotifications(next[0].ID)) == 0 {
+				t.Errorf("next occurrence has no reminders")
+			}
+		})
+	}
+}
+
+func TestCreateRecurringTaskRequiresDueDate(t *testing.T) {
+	uc := newTestUsecase(newFakeRepo(), time.Now())
+
+	_, err := uc.CreateTask(context.Background(), uuid.New().String(), nil, "Зарядка", "", nil,
+		&models.Recurrence{Frequency: models.RecurrenceDaily}, "", nil)
+	if err == nil {
+		t.Fatal("CreateTask() error = nil, want error for recurrence without due date")
+	}
+}
+
+func TestUpdateTaskClearDueAt(t *testing.T) {
+	ctx := context.Background()
+	uc := newTestUsecase(newFakeRepo(), time.Now())
+	userID := uuid.New().String()
+	dueAt := time.Now().Add(24 * time.Hour).Format(time.RFC3339)
+	clear := ""
+
+	task, err := uc.CreateTask(ctx, userID, nil, "Зарядка", "", &dueAt,
+		&models.Recurrence{Frequency: models.RecurrenceDaily}, "", nil)
+	if err != nil {
+		t.Fatalf("CreateTask() error = %v", err)
+	}
+
+	// Срок повторяющейся задачи нельзя снять, не сняв повторение
+	if _, err = uc.UpdateTask(ctx, userID, task.ID.String(), nil, nil, nil, &clear, nil, nil, nil); !errors.Is(err, models.ErrRecurrenceWithoutDueDate) {
+		t.Fatalf("UpdateTask() error = %v, want ErrRecurrenceWithoutDueDate", err)
+	}
+
+	updated, err := uc.UpdateTask(ctx, userID, task.ID.String(), nil, nil, nil, &clear, nil,
+		&models.Recurrence{}, nil)
+	if err != nil {
+		t.Fatalf("UpdateTask() error = %v", err)
+	}
+	if updated.DueAt != nil || updated.Recurrence != nil {
+		t.Errorf("UpdateTask() due = %v, recurrence = %v; want both cleared", updated.DueAt, updated.Recurrence)
+	}
+}
+
+func ptrTime(t time.Time) *time.Time {
+	return &t
+}
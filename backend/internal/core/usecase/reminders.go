@@ -0,0 +1,76 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/singl3focus/uniflow/internal/core/models"
+)
+
+// DefaultReminderOffsets - напоминания за сутки и за час до дедлайна, если пользователь не настроил свои
+var DefaultReminderOffsets = []time.Duration{24 * time.Hour, time.Hour}
+
+// ===========================
+// Task reminders
+// ===========================
+
+// scheduleTaskReminders пересоздает ожидающие напоминания задачи по ее текущему дедлайну.
+// Для завершенных и отмененных задач, а также задач без дедлайна напоминания только снимаются.
+func (u *Usecase) scheduleTaskReminders(ctx context.Context, task models.Task) error {
+	if err := u.repo.DeletePendingNotificationsByTaskID(ctx, task.ID); err != nil {
+		return err
+	}
+
+	if task.DueAt == nil || !task.IsActive() {
+		return nil
+	}
+
+	settings, err := u.userSettings(ctx, task.UserID)
+	if err != nil {
+		return err
+	}
+
+	offsets, ok := settings.ReminderDurations()
+	if !ok {
+		offsets = u.reminderOffsets
+	}
+
+	// У задачи на весь день нет времени дедлайна: напоминания отсчитываются от начала рабочего дня
+	base := *task.DueAt
+	if task.AllDay {
+		due := task.DueAt.In(settings.Location())
+		base = time.Date(due.Year(), due.Month(), due.Day(), models.AllDayReminderHour, 0, 0, 0, due.Location())
+	}
+
+	now := u.now()
+	for _, offset := range offsets {
+		notifyAt := base.Add(-offset)
+		if !notifyAt.After(now) {
+			continue
+		}
+
+		notification := models.NewNotification(task.UserID, &task.ID, notifyAt, models.NotificationChannelMax, taskReminderMessage(task, settings.Location()))
+		if err := u.repo.CreateNotification(ctx, notification); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// taskReminderMessage формирует текст напоминания; срок показывается в часовом поясе пользователя
+func taskReminderMessage(task models.Task, loc *time.Location) string {
+	due := task.DueAt.In(loc).Format("02.01.2006 15:04")
+	if task.AllDay {
+		due = task.DueAt.In(loc).Format("02.01.2006") + ", весь день"
+	}
+
+	return fmt.Sprintf(
+		"⏰ Напоминание о задаче:\n\n"+
+			"📝 %s\n"+
+			"📅 Срок: %s",
+		task.Title,
+		due,
+	)
+}
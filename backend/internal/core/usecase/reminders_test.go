@@ -0,0 +1,105 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/singl3focus/uniflow/internal/core/models"
+)
+
+func TestTaskReminders(t *testing.T) {
+	ctx := context.Background()
+	now := time.Date(2025, 3, 10, 12, 0, 0, 0, time.UTC)
+	userID := uuid.New().String()
+
+	repo := newFakeRepo()
+	uc := newTestUsecase(repo, now)
+
+	// Дедлайн через 3 часа: напоминание за сутки уже в прошлом, остается только за час
+	dueAt := now.Add(3 * time.Hour).Format(time.RFC3339)
+	task, err := uc.CreateTask(ctx, userID, nil, "Сдать лабу", "", &dueAt, nil, "", nil)
+	if err != nil {
+		t.Fatalf("CreateTask() error = %v", err)
+	}
+
+	reminders := repo.taskNotifications(task.ID)
+	if len(reminders) != 1 {
+		t.Fatalf("after create: got %d reminders, want 1", len(reminders))
+	}
+	if want := now.Add(2 * time.Hour); !reminders[0].NotifyAt.Equal(want) {
+		t.Errorf("reminder notify_at = %v, want %v", reminders[0].NotifyAt, want)
+	}
+
+	// Перенос дедлайна на 2 дня пересоздает оба напоминания
+	newDueAt := now.Add(48 * time.Hour).Format(time.RFC3339)
+	if _, err = uc.UpdateTask(ctx, userID, task.ID.String(), nil, nil, nil, &newDueAt, nil, nil, nil); err != nil {
+		t.Fatalf("UpdateTask() error = %v", err)
+	}
+	if got := len(repo.taskNotifications(task.ID)); got != 2 {
+		t.Fatalf("after due date change: got %d reminders, want 2", got)
+	}
+
+	// Напоминание, уже захваченное воркером, тоже снимается
+	for id, n := range repo.notifications {
+		n.Status = models.NotificationStatusProcessing
+		repo.notifications[id] = n
+		break
+	}
+
+	// Завершение задачи снимает напоминания
+	if err = uc.UpdateTaskStatus(ctx, userID, task.ID.String(), models.TaskStatusCompleted); err != nil {
+		t.Fatalf("UpdateTaskStatus() error = %v", err)
+	}
+	if got := len(repo.taskNotifications(task.ID)); got != 0 {
+		t.Fatalf("after completion: got %d reminders, want 0", got)
+	}
+
+	// Возобновление задачи возвращает напоминания
+	if err = uc.UpdateTaskStatus(ctx, userID, task.ID.String(), models.TaskStatusTodo); err != nil {
+		t.Fatalf("UpdateTaskStatus() error = %v", err)
+	}
+	if got := len(repo.taskNotifications(task.ID)); got != 2 {
+		t.Fatalf("after reopen: got %d reminders, want 2", got)
+	}
+}
+
+func TestTaskRemindersFailureRollsBack(t *testing.T) {
+	ctx := context.Background()
+	now := time.Date(2025, 3, 10, 12, 0, 0, 0, time.UTC)
+	userID := uuid.New().String()
+
+	repo := newFakeRepo()
+	uc := newTestUsecase(repo, now)
+
+	dueAt := now.Add(48 * time.Hour).Format(time.RFC3339)
+	task, err := uc.CreateTask(ctx, userID, nil, "Сдать лабу", "", &dueAt, nil, "", nil)
+	if err != nil {
+		t.Fatalf("CreateTask() error = %v", err)
+	}
+
+	repo.notificationErr = errors.New("connection reset")
+
+	// Задача без напоминаний не сохраняется, повтор запроса не создаст дубликат
+	if _, err = uc.CreateTask(ctx, userID, nil, "Курсовая", "", &dueAt, nil, "", []string{"учеба"}); err == nil {
+		t.Fatal("CreateTask() error = nil, want reminder scheduling error")
+	}
+	if len(repo.tasks) != 1 || len(repo.tags) != 0 {
+		t.Errorf("after failed create: %d tasks, %d tags; want 1, 0", len(repo.tasks), len(repo.tags))
+	}
+
+	// Изменение срока откатывается вместе со старыми напоминаниями
+	newDueAt := now.Add(72 * time.Hour).Format(time.RFC3339)
+	if _, err = uc.UpdateTask(ctx, userID, task.ID.String(), nil, nil, nil, &newDueAt, nil, nil, nil); err == nil {
+		t.Fatal("UpdateTask() error = nil, want reminder scheduling error")
+	}
+	if stored := repo.tasks[task.ID]; !stored.DueAt.Equal(*task.DueAt) {
+		t.Errorf("due_at = %v, want unchanged %v", stored.DueAt, task.DueAt)
+	}
+	if got := len(repo.taskNotifications(task.ID)); got != 2 {
+		t.Errorf("after failed update: got %d reminders, want 2", got)
+	}
+}
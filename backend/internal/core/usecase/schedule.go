@@ -0,0 +1,195 @@
+package usecase
+
+import (
+	"context"
+	"time"
+
+	"github.com/singl3focus/uniflow/internal/core/models"
+)
+
+// ===========================
+// Schedule use cases
+// ===========================
+
+func (u *Usecase) CreateScheduleEntry(ctx context.Context, userIDStr string, contextID *string, title string, weekday models.Weekday, startAt, endAt string, location string) (models.ScheduleEntry, error) {
+	const op = "usecase.CreateScheduleEntry"
+
+	userID, err := models.ParseUserID(userIDStr)
+	if err != nil {
+		return models.ScheduleEntry{}, ErrInvalidData.SetPlace(op).SetCause(err)
+	}
+
+	var contextIDCleaned *models.ContextID
+	if contextID != nil && *contextID != "" {
+		cid, err := u.getOwnContextID(ctx, userID, *contextID)
+		if err != nil {
+			return models.ScheduleEntry{}, handleOwnershipError(op, err)
+		}
+		contextIDCleaned = &cid
+	}
+
+	start, err := models.ParseScheduleTime(startAt)
+	if err != nil {
+		return models.ScheduleEntry{}, ErrInvalidData.SetPlace(op).SetCause(err)
+	}
+
+	end, err := models.ParseScheduleTime(endAt)
+	if err != nil {
+		return models.ScheduleEntry{}, ErrInvalidData.SetPlace(op).SetCause(err)
+	}
+
+	entry, err := models.NewScheduleEntry(userID, contextIDCleaned, title, weekday, start, end, location)
+	if err != nil {
+		return models.ScheduleEntry{}, ErrInvalidData.SetPlace(op).SetCause(err)
+	}
+
+	if err = u.repo.CreateScheduleEntry(ctx, entry); err != nil {
+		return models.ScheduleEntry{}, handleRepositoryError(op, err)
+	}
+
+	return entry, nil
+}
+
+func (u *Usecase) GetScheduleEntries(ctx context.Context, userIDStr string) ([]models.ScheduleEntry, error) {
+	const op = "usecase.GetScheduleEntries"
+
+	userID, err := models.ParseUserID(userIDStr)
+	if err != nil {
+		return nil, ErrInvalidData.SetPlace(op).SetCause(err)
+	}
+
+	entries, err := u.repo.GetScheduleEntriesByUserID(ctx, userID)
+	if err != nil {
+		return nil, handleRepositoryError(op, err)
+	}
+
+	return entries, nil
+}
+
+func (u *Usecase) GetScheduleByWeekday(ctx context.Context, userIDStr string, weekday models.Weekday) ([]models.ScheduleEntry, error) {
+	const op = "usecase.GetScheduleByWeekday"
+
+	userID, err := models.ParseUserID(userIDStr)
+	if err != nil {
+		return nil, ErrInvalidData.SetPlace(op).SetCause(err)
+	}
+
+	if weekday < models.Monday || weekday > models.Sunday {
+		return nil, ErrInvalidData.SetPlace(op).SetCause(models.ErrInvalidWeekday)
+	}
+
+	entries, err := u.repo.GetScheduleEntriesByWeekday(ctx, userID, weekday)
+	if err != nil {
+		return nil, handleRepositoryError(op, err)
+	}
+
+	return entries, nil
+}
+
+// GetScheduleForDate возвращает занятия на день недели указанной даты
+func (u *Usecase) GetScheduleForDate(ctx context.Context, userIDStr string, date time.Time) ([]models.ScheduleEntry, error) {
+	return u.GetScheduleByWeekday(ctx, userIDStr, models.WeekdayOf(date))
+}
+
+func (u *Usecase) GetScheduleEntryByID(ctx context.Context, userIDStr, entryIDStr string) (models.ScheduleEntry, error) {
+	const op = "usecase.GetScheduleEntryByID"
+
+	entry, err := u.getOwnScheduleEntry(ctx, userIDStr, entryIDStr)
+	if err != nil {
+		return models.ScheduleEntry{}, handleOwnershipError(op, err)
+	}
+
+	return entry, nil
+}
+
+// UpdateScheduleEntry обновляет переданные поля занятия.
+// Пустая строка в contextID отвязывает занятие от контекста.
+func (u *Usecase) UpdateScheduleEntry(ctx context.Context, userIDStr, entryIDStr string, contextID, title *string, weekday *models.Weekday, startAt, endAt, location *string) (models.ScheduleEntry, error) {
+	const op = "usecase.UpdateScheduleEntry"
+
+	entry, err := u.getOwnScheduleEntry(ctx, userIDStr, entryIDStr)
+	if err != nil {
+		return models.ScheduleEntry{}, handleOwnershipError(op, err)
+	}
+
+	if contextID != nil {
+		if *contextID == "" {
+			entry.AssignContext(nil)
+		} else {
+			cid, err := u.getOwnContextID(ctx, entry.UserID, *contextID)
+			if err != nil {
+				return models.ScheduleEntry{}, handleOwnershipError(op, err)
+			}
+			entry.AssignContext(&cid)
+		}
+	}
+
+	var start, end *time.Time
+	if startAt != nil {
+		t, err := models.ParseScheduleTime(*startAt)
+		if err != nil {
+			return models.ScheduleEntry{}, ErrInvalidData.SetPlace(op).SetCause(err)
+		}
+		start = &t
+	}
+	if endAt != nil {
+		t, err := models.ParseScheduleTime(*endAt)
+		if err != nil {
+			return models.ScheduleEntry{}, ErrInvalidData.SetPlace(op).SetCause(err)
+		}
+		end = &t
+	}
+
+	// Обновляем только переданные поля
+	entry.Update(title, weekday, start, end, location)
+
+	if err = entry.Validate(); err != nil {
+		return models.ScheduleEntry{}, ErrInvalidData.SetPlace(op).SetCause(err)
+	}
+
+	if err = u.repo.UpdateScheduleEntry(ctx, entry); err != nil {
+		return models.ScheduleEntry{}, handleRepositoryError(op, err)
+	}
+
+	return entry, nil
+}
+
+func (u *Usecase) DeleteScheduleEntry(ctx context.Context, userIDStr, entryIDStr string) error {
+	const op = "usecase.DeleteScheduleEntry"
+
+	entry, err := u.getOwnScheduleEntry(ctx, userIDStr, entryIDStr)
+	if err != nil {
+		return handleOwnershipError(op, err)
+	}
+
+	if err = u.repo.DeleteScheduleEntry(ctx, entry.ID); err != nil {
+		return handleRepositoryError(op, err)
+	}
+
+	return nil
+}
+
+// getOwnScheduleEntry загружает запись расписания и проверяет, что она принадлежит пользователю.
+// Чужая запись неотличима от несуществующей.
+func (u *Usecase) getOwnScheduleEntry(ctx context.Context, userIDStr, entryIDStr string) (models.ScheduleEntry, error) {
+	userID, err := models.ParseUserID(userIDStr)
+	if err != nil {
+		return models.ScheduleEntry{}, ErrInvalidData.SetCause(err)
+	}
+
+	entryID, err := models.ParseScheduleEntryID(entryIDStr)
+	if err != nil {
+		return models.ScheduleEntry{}, ErrInvalidData.SetCause(err)
+	}
+
+	entry, err := u.repo.GetScheduleEntryByID(ctx, entryID)
+	if err != nil {
+		return models.ScheduleEntry{}, err
+	}
+
+	if entry.UserID != userID {
+		return models.ScheduleEntry{}, ErrNotFound.SetCause(errForeignResource)
+	}
+
+	return entry, nil
+}
@@ -0,0 +1,109 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/singl3focus/uniflow/internal/core/models"
+)
+
+func TestScheduleEntry_Ownership(t *testing.T) {
+	ctx := context.Background()
+	repo := newFakeRepo()
+	uc := newTestUsecase(repo, time.Now())
+
+	owner := uuid.New().String()
+	stranger := uuid.New().String()
+
+	entry, err := uc.CreateScheduleEntry(ctx, owner, nil, "Матанализ", models.Tuesday, "10:15", "11:50", "ауд. 301")
+	if err != nil {
+		t.Fatalf("CreateScheduleEntry() error = %v", err)
+	}
+
+	if _, err := uc.GetScheduleEntryByID(ctx, stranger, entry.ID.String()); !errors.Is(err, ErrNotFound) {
+		t.Errorf("GetScheduleEntryByID() by stranger error = %v, want ErrNotFound", err)
+	}
+
+	title := "Чужое"
+	if _, err := uc.UpdateScheduleEntry(ctx, stranger, entry.ID.String(), nil, &title, nil, nil, nil, nil); !errors.Is(err, ErrNotFound) {
+		t.Errorf("UpdateScheduleEntry() by stranger error = %v, want ErrNotFound", err)
+	}
+
+	if err := uc.DeleteScheduleEntry(ctx, stranger, entry.ID.String()); !errors.Is(err, ErrNotFound) {
+		t.Errorf("DeleteScheduleEntry() by stranger error = %v, want ErrNotFound", err)
+	}
+
+	if _, ok := repo.schedule[entry.ID]; !ok {
+		t.Fatalf("entry was deleted by another user")
+	}
+
+	if err := uc.DeleteScheduleEntry(ctx, owner, entry.ID.String()); err != nil {
+		t.Errorf("DeleteScheduleEntry() by owner error = %v", err)
+	}
+}
+
+func TestScheduleEntry_ContextOwnership(t *testing.T) {
+	ctx := context.Background()
+	repo := newFakeRepo()
+	uc := newTestUsecase(repo, time.Now())
+
+	owner := uuid.New()
+	stranger := uuid.New()
+
+	own, _ := models.NewContext(owner, models.ContextTypeSubject, "Матанализ", "", "", nil, nil)
+	foreign, _ := models.NewContext(stranger, models.ContextTypeSubject, "Химия", "", "", nil, nil)
+	repo.contexts[own.ID] = own
+	repo.contexts[foreign.ID] = foreign
+
+	ownID, foreignID := own.ID.String(), foreign.ID.String()
+
+	if _, err := uc.CreateScheduleEntry(ctx, owner.String(), &foreignID, "Химия", models.Monday, "10:15", "11:50", ""); !errors.Is(err, ErrNotFound) {
+		t.Errorf("CreateScheduleEntry() in foreign context error = %v, want ErrNotFound", err)
+	}
+
+	entry, err := uc.CreateScheduleEntry(ctx, owner.String(), &ownID, "Матанализ", models.Monday, "10:15", "11:50", "")
+	if err != nil {
+		t.Fatalf("CreateScheduleEntry() error = %v", err)
+	}
+
+	if _, err := uc.UpdateScheduleEntry(ctx, owner.String(), entry.ID.String(), &foreignID, nil, nil, nil, nil, nil); !errors.Is(err, ErrNotFound) {
+		t.Errorf("UpdateScheduleEntry() moving to foreign context error = %v, want ErrNotFound", err)
+	}
+	if stored := repo.schedule[entry.ID]; stored.ContextID == nil || *stored.ContextID != own.ID {
+		t.Errorf("context_id = %v, want %v", stored.ContextID, own.ID)
+	}
+
+	detach := ""
+	updated, err := uc.UpdateScheduleEntry(ctx, owner.String(), entry.ID.String(), &detach, nil, nil, nil, nil, nil)
+	if err != nil {
+		t.Fatalf("UpdateScheduleEntry() error = %v", err)
+	}
+	if updated.ContextID != nil {
+		t.Errorf("context_id = %v, want nil after detach", updated.ContextID)
+	}
+}
+
+func TestUpdateScheduleEntry_Validation(t *testing.T) {
+	ctx := context.Background()
+	repo := newFakeRepo()
+	uc := newTestUsecase(repo, time.Now())
+
+	owner := uuid.New().String()
+	entry, err := uc.CreateScheduleEntry(ctx, owner, nil, "Физика", models.Friday, "09:00", "10:30", "")
+	if err != nil {
+		t.Fatalf("CreateScheduleEntry() error = %v", err)
+	}
+
+	endAt := "08:00"
+	if _, err := uc.UpdateScheduleEntry(ctx, owner, entry.ID.String(), nil, nil, nil, nil, &endAt, nil); !errors.Is(err, ErrInvalidData) {
+		t.Errorf("UpdateScheduleEntry() with end before start error = %v, want ErrInvalidData", err)
+	}
+
+	if got := repo.schedule[entry.ID]; !got.EndAt.Equal(entry.EndAt) {
+		t.Errorf("invalid update was persisted: end_at = %v", got.EndAt)
+	}
+}
@@ -0,0 +1,186 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"time"
+
+	"github.com/singl3focus/uniflow/internal/core/models"
+	"github.com/singl3focus/uniflow/internal/core/ports/repository"
+)
+
+// ===========================
+// User settings use cases
+// ===========================
+
+func (u *Usecase) GetUserSettings(ctx context.Context, userIDStr string) (models.UserSettings, error) {
+	const op = "usecase.GetUserSettings"
+
+	userID, err := models.ParseUserID(userIDStr)
+	if err != nil {
+		return models.UserSettings{}, ErrInvalidData.SetPlace(op).SetCause(err)
+	}
+
+	user, err := u.repo.GetUserByID(ctx, userID)
+	if err != nil {
+		return models.UserSettings{}, handleRepositoryError(op, err)
+	}
+
+	return user.Settings, nil
+}
+
+// UpdateUserSettings обновляет переданные настройки пользователя; nil означает, что настройка не меняется.
+// reminderOffsets, указывающий на nil-срез, возвращает напоминания по умолчанию.
+// Пустое время утренней сводки или вечернего обзора выключает их.
+func (u *Usecase) UpdateUserSettings(ctx context.Context, userIDStr string, timezone, language *string, firstWeekday *models.Weekday, reminderOffsets *[]int, morningDigestAt, eveningReviewAt *string) (models.UserSettings, error) {
+	const op = "usecase.UpdateUserSettings"
+
+	userID, err := models.ParseUserID(userIDStr)
+	if err != nil {
+		return models.UserSettings{}, ErrInvalidData.SetPlace(op).SetCause(err)
+	}
+
+	user, err := u.repo.GetUserByID(ctx, userID)
+	if err != nil {
+		return models.UserSettings{}, handleRepositoryError(op, err)
+	}
+
+	settings := user.Settings
+	if timezone != nil {
+		settings.Timezone = *timezone
+	}
+	if language != nil {
+		settings.Language = *language
+	}
+	if firstWeekday != nil {
+		settings.FirstWeekday = *firstWeekday
+	}
+	if reminderOffsets != nil {
+		settings.ReminderOffsets = nil
+		if *reminderOffsets != nil {
+			// Порядок не важен, дубликаты не нужны; пустой список - без напоминаний, а не по умолчанию
+			settings.ReminderOffsets = append([]int{}, slices.Compact(slices.Sorted(slices.Values(*reminderOffsets)))...)
+		}
+	}
+
+	if morningDigestAt != nil {
+		if settings.MorningDigestAt, err = normalizeDigestAt(*morningDigestAt); err != nil {
+			return models.UserSettings{}, ErrInvalidData.SetPlace(op).SetCause(err)
+		}
+	}
+	if eveningReviewAt != nil {
+		if settings.EveningReviewAt, err = normalizeDigestAt(*eveningReviewAt); err != nil {
+			return models.UserSettings{}, ErrInvalidData.SetPlace(op).SetCause(err)
+		}
+	}
+
+	if err = settings.Validate(); err != nil {
+		return models.UserSettings{}, ErrInvalidData.SetPlace(op).SetCause(err)
+	}
+
+	// Настройки и все, что от них зависит, сохраняются вместе: после ошибки повтор запроса
+	// снова увидит старый пояс и перенесет задачи на весь день
+	err = u.repo.WithinTransaction(ctx, func(ctx context.Context) error {
+		if err := u.repo.UpdateUserSettings(ctx, userID, settings, u.now()); err != nil {
+			return err
+		}
+
+		// Задачи на весь день остаются в своем дне и в новом поясе
+		if timezone != nil {
+			if err := u.moveAllDayTasks(ctx, userID, user.Settings.Location(), settings.Location()); err != nil {
+				return err
+			}
+		}
+
+		// Напоминания уже запланированных задач пересоздаются по новым смещениям и поясу
+		if reminderOffsets != nil || timezone != nil {
+			if err := u.rescheduleUserReminders(ctx, userID); err != nil {
+				return err
+			}
+		}
+
+		// Время сводок зависит и от самих настроек, и от часового пояса
+		if morningDigestAt != nil || eveningReviewAt != nil || timezone != nil {
+			return u.scheduleUserDigests(ctx, userID, settings)
+		}
+		return nil
+	})
+	if err != nil {
+		return models.UserSettings{}, handleRepositoryError(op, err)
+	}
+
+	return settings, nil
+}
+
+// normalizeDigestAt приводит время сводки к виду ЧЧ:ММ; пустая строка выключает сводку
+func normalizeDigestAt(at string) (*string, error) {
+	if at == "" {
+		return nil, nil
+	}
+
+	clock, err := time.Parse(models.DigestTimeLayout, at)
+	if err != nil {
+		return nil, models.ErrInvalidDigestTime.SetCause(err)
+	}
+
+	normalized := clock.Format(models.DigestTimeLayout)
+	return &normalized, nil
+}
+
+// userSettings возвращает настройки пользователя; для неизвестного пользователя - настройки по умолчанию
+func (u *Usecase) userSettings(ctx context.Context, userID models.UserID) (models.UserSettings, error) {
+	user, err := u.repo.GetUserByID(ctx, userID)
+	if errors.Is(err, repository.ErrNotFound) {
+		return models.DefaultUserSettings(), nil
+	}
+	if err != nil {
+		return models.UserSettings{}, err
+	}
+
+	return user.Settings, nil
+}
+
+// moveAllDayTasks переносит сроки активных задач на весь день из пояса from в пояс to, сохраняя дату
+func (u *Usecase) moveAllDayTasks(ctx context.Context, userID models.UserID, from, to *time.Location) error {
+	if from.String() == to.String() {
+		return nil
+	}
+
+	tasks, err := u.repo.GetTasksByUserID(ctx, userID)
+	if err != nil {
+		return err
+	}
+
+	for _, task := range tasks {
+		if task.DueAt == nil || !task.AllDay || !task.IsActive() {
+			continue
+		}
+		due := models.AllDayDue(task.DueAt.In(from), to)
+		task.SetDue(&due, true)
+		if err = u.repo.UpdateTask(ctx, task); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// rescheduleUserReminders пересоздает напоминания активных задач пользователя с дедлайном
+func (u *Usecase) rescheduleUserReminders(ctx context.Context, userID models.UserID) error {
+	tasks, err := u.repo.GetTasksByUserID(ctx, userID)
+	if err != nil {
+		return err
+	}
+
+	for _, task := range tasks {
+		if task.DueAt == nil || !task.IsActive() {
+			continue
+		}
+		if err = u.scheduleTaskReminders(ctx, task); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,194 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/singl3focus/uniflow/internal/core/models"
+)
+
+func TestUpdateUserSettings(t *testing.T) {
+	ctx := context.Background()
+	now := time.Date(2025, 3, 10, 12, 0, 0, 0, time.UTC)
+
+	repo := newFakeRepo()
+	uc := newTestUsecase(repo, now)
+
+	user, err := models.NewUser("42")
+	if err != nil {
+		t.Fatalf("NewUser() error = %v", err)
+	}
+	_ = repo.CreateUser(ctx, user)
+	userID := user.ID.String()
+
+	// Дедлайн через 3 дня: по умолчанию два напоминания
+	dueAt := now.Add(72 * time.Hour).Format(time.RFC3339)
+	task, err := uc.CreateTask(ctx, userID, nil, "Курсовая", "", &dueAt, nil, "", nil)
+	if err != nil {
+		t.Fatalf("CreateTask() error = %v", err)
+	}
+	if got := len(repo.taskNotifications(task.ID)); got != 2 {
+		t.Fatalf("default reminders: got %d, want 2", got)
+	}
+
+	badTZ := "Mars/Olympus"
+	if _, err = uc.UpdateUserSettings(ctx, userID, &badTZ, nil, nil, nil, nil, nil); !errors.Is(err, ErrInvalidData) {
+		t.Errorf("UpdateUserSettings() with unknown timezone error = %v, want ErrInvalidData", err)
+	}
+
+	// Дубликаты схлопываются, смена напоминаний пересоздает их у активных задач
+	tz := "Asia/Novosibirsk"
+	offsets := []int{15, 180, 15}
+	settings, err := uc.UpdateUserSettings(ctx, userID, &tz, nil, nil, &offsets, nil, nil)
+	if err != nil {
+		t.Fatalf("UpdateUserSettings() error = %v", err)
+	}
+	if settings.Timezone != tz || len(settings.ReminderOffsets) != 2 {
+		t.Errorf("settings = %+v, want timezone %s and 2 offsets", settings, tz)
+	}
+	if got := len(repo.taskNotifications(task.ID)); got != 2 {
+		t.Errorf("after offsets change: got %d reminders, want 2", got)
+	}
+
+	// Пустой список выключает напоминания и не превращается в "по умолчанию"
+	none := []int{}
+	settings, err = uc.UpdateUserSettings(ctx, userID, nil, nil, nil, &none, nil, nil)
+	if err != nil {
+		t.Fatalf("UpdateUserSettings() error = %v", err)
+	}
+	if settings.ReminderOffsets == nil {
+		t.Error("empty reminder offsets became default")
+	}
+	if got := len(repo.taskNotifications(task.ID)); got != 0 {
+		t.Errorf("reminders disabled: got %d reminders, want 0", got)
+	}
+}
+
+func TestGetTasksDueTodayUsesUserTimezone(t *testing.T) {
+	ctx := context.Background()
+	// 22:00 UTC - в Новосибирске (UTC+7) уже 11 марта
+	now := time.Date(2025, 3, 10, 22, 0, 0, 0, time.UTC)
+
+	repo := newFakeRepo()
+	uc := newTestUsecase(repo, now)
+
+	user, err := models.NewUser("42")
+	if err != nil {
+		t.Fatalf("NewUser() error = %v", err)
+	}
+	user.Settings.Timezone = "Asia/Novosibirsk"
+	_ = repo.CreateUser(ctx, user)
+	userID := user.ID.String()
+
+	// По UTC сегодня - первая задача, а по Новосибирску (10 и 11 марта, 17:00) - вторая
+	yesterday := time.Date(2025, 3, 10, 10, 0, 0, 0, time.UTC).Format(time.RFC3339)
+	userToday := time.Date(2025, 3, 11, 10, 0, 0, 0, time.UTC).Format(time.RFC3339)
+	if _, err = uc.CreateTask(ctx, userID, nil, "Вчерашняя", "", &yesterday, nil, "", nil); err != nil {
+		t.Fatalf("CreateTask() error = %v", err)
+	}
+	want, err := uc.CreateTask(ctx, userID, nil, "Сегодняшняя", "", &userToday, nil, "", nil)
+	if err != nil {
+		t.Fatalf("CreateTask() error = %v", err)
+	}
+
+	tasks, err := uc.GetTasksDueToday(ctx, userID)
+	if err != nil {
+		t.Fatalf("GetTasksDueToday() error = %v", err)
+	}
+	if len(tasks) != 1 || tasks[0].ID != want.ID {
+		t.Errorf("GetTasksDueToday() = %v, want only %q", tasks, want.Title)
+	}
+}
+
+func TestUpdateUserSettingsSchedulesDigests(t *testing.T) {
+	ctx := context.Background()
+	now := time.Date(2025, 3, 10, 12, 0, 0, 0, time.UTC)
+
+	repo := newFakeRepo()
+	uc := newTestUsecase(repo, now)
+
+	user, err := models.NewUser("42")
+	if err != nil {
+		t.Fatalf("NewUser() error = %v", err)
+	}
+	_ = repo.CreateUser(ctx, user)
+	userID := user.ID.String()
+
+	bad := "25:00"
+	if _, err = uc.UpdateUserSettings(ctx, userID, nil, nil, nil, nil, &bad, nil); !errors.Is(err, ErrInvalidData) {
+		t.Errorf("UpdateUserSettings() with invalid digest time error = %v, want ErrInvalidData", err)
+	}
+
+	// Утро 8:00 по Москве уже прошло - сводка завтра; вечер 21:00 - сегодня
+	morning, evening := "8:00", "21:00"
+	settings, err := uc.UpdateUserSettings(ctx, userID, nil, nil, nil, nil, &morning, &evening)
+	if err != nil {
+		t.Fatalf("UpdateUserSettings() error = %v", err)
+	}
+	if settings.MorningDigestAt == nil || *settings.MorningDigestAt != "08:00" {
+		t.Errorf("morning digest at = %v, want 08:00", settings.MorningDigestAt)
+	}
+
+	next := repo.nextDigests[user.ID]
+	if want := time.Date(2025, 3, 11, 5, 0, 0, 0, time.UTC); !next[models.DigestKindMorning].Equal(want) {
+		t.Errorf("next morning digest = %v, want %v", next[models.DigestKindMorning].UTC(), want)
+	}
+	if want := time.Date(2025, 3, 10, 18, 0, 0, 0, time.UTC); !next[models.DigestKindEvening].Equal(want) {
+		t.Errorf("next evening review = %v, want %v", next[models.DigestKindEvening].UTC(), want)
+	}
+
+	// Пустое время выключает сводку
+	off := ""
+	if _, err = uc.UpdateUserSettings(ctx, userID, nil, nil, nil, nil, nil, &off); err != nil {
+		t.Fatalf("UpdateUserSettings() error = %v", err)
+	}
+	if _, ok := repo.nextDigests[user.ID][models.DigestKindEvening]; ok {
+		t.Error("evening review still scheduled after it was turned off")
+	}
+}
+
+func TestUpdateUserSettingsFailureRollsBack(t *testing.T) {
+	ctx := context.Background()
+	now := time.Date(2025, 3, 10, 12, 0, 0, 0, time.UTC)
+
+	repo := newFakeRepo()
+	uc := newTestUsecase(repo, now)
+
+	user, err := models.NewUser("42")
+	if err != nil {
+		t.Fatalf("NewUser() error = %v", err)
+	}
+	_ = repo.CreateUser(ctx, user)
+	userID := user.ID.String()
+
+	dueAt := "2025-03-12"
+	task, err := uc.CreateTask(ctx, userID, nil, "Курсовая", "", &dueAt, nil, "", nil)
+	if err != nil {
+		t.Fatalf("CreateTask() error = %v", err)
+	}
+
+	// Напоминания не пересоздаются: ни пояс, ни срок задачи не меняются
+	repo.notificationErr = errors.New("connection reset")
+	tz := "Asia/Novosibirsk"
+	if _, err = uc.UpdateUserSettings(ctx, userID, &tz, nil, nil, nil, nil, nil); err == nil {
+		t.Fatal("UpdateUserSettings() error = nil, want reminder scheduling error")
+	}
+	if got := repo.users[user.ID].Settings.Timezone; got != user.Settings.Timezone {
+		t.Errorf("timezone = %q, want unchanged %q", got, user.Settings.Timezone)
+	}
+	if stored := repo.tasks[task.ID]; !stored.DueAt.Equal(*task.DueAt) {
+		t.Errorf("due_at = %v, want unchanged %v", stored.DueAt, task.DueAt)
+	}
+
+	// Повтор после сбоя переносит задачу на весь день в новый пояс
+	repo.notificationErr = nil
+	if _, err = uc.UpdateUserSettings(ctx, userID, &tz, nil, nil, nil, nil, nil); err != nil {
+		t.Fatalf("UpdateUserSettings() error = %v", err)
+	}
+	novosibirsk, _ := time.LoadLocation(tz)
+	if want := time.Date(2025, 3, 12, 23, 59, 0, 0, novosibirsk); !repo.tasks[task.ID].DueAt.Equal(want) {
+		t.Errorf("after retry due_at = %v, want %v", repo.tasks[task.ID].DueAt, want)
+	}
+}
@@ -0,0 +1,158 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"math"
+	"time"
+
+	"github.com/singl3focus/uniflow/internal/core/models"
+)
+
+const (
+	DefaultStatsDays = 7
+	MaxStatsDays     = 366
+)
+
+const dayLayout = "2006-01-02"
+
+// ===========================
+// Stats use cases
+// ===========================
+
+// GetStats считает статистику продуктивности за последние days дней, включая сегодняшний
+func (u *Usecase) GetStats(ctx context.Context, userIDStr string, days int) (models.Stats, error) {
+	const op = "usecase.GetStats"
+
+	userID, err := models.ParseUserID(userIDStr)
+	if err != nil {
+		return models.Stats{}, ErrInvalidData.SetPlace(op).SetCause(err)
+	}
+
+	if days < 1 || days > MaxStatsDays {
+		return models.Stats{}, ErrInvalidData.SetPlace(op).SetCause(errors.New("days must be 1-366"))
+	}
+
+	tasks, err := u.repo.GetTasksByUserID(ctx, userID)
+	if err != nil {
+		return models.Stats{}, handleRepositoryError(op, err)
+	}
+
+	contexts, err := u.repo.GetContextsByUserID(ctx, userID)
+	if err != nil {
+		return models.Stats{}, handleRepositoryError(op, err)
+	}
+
+	sessions, err := u.repo.GetFocusSessionsByUserID(ctx, userID)
+	if err != nil {
+		return models.Stats{}, handleRepositoryError(op, err)
+	}
+
+	settings, err := u.userSettings(ctx, userID)
+	if err != nil {
+		return models.Stats{}, handleRepositoryError(op, err)
+	}
+
+	return computeStats(u.now(), settings, days, tasks, contexts, sessions), nil
+}
+
+// computeStats агрегирует статистику за days календарных дней, заканчивая днем now.
+// Границы дней и недель считаются по часовому поясу и первому дню недели из settings.
+func computeStats(now time.Time, settings models.UserSettings, days int, tasks []models.Task, contexts []models.Context, sessions []models.FocusSession) models.Stats {
+	now = now.In(settings.Location())
+	from := settings.StartOfDay(now).AddDate(0, 0, -(days - 1))
+	inPeriod := func(t time.Time) bool {
+		return !t.Before(from) && !t.After(now)
+	}
+
+	stats := models.Stats{
+		From:            from,
+		To:              now,
+		CompletedByDay:  make([]models.DayCount, 0, days),
+		CompletedByWeek: []models.WeekCount{},
+		ByContext:       []models.ContextStats{},
+	}
+
+	// Заготовки по дням и неделям, чтобы в ответе были и дни без выполненных задач
+	dayIndex := make(map[string]int, days)
+	weekIndex := make(map[string]int)
+	for d := from; !d.After(now); d = d.AddDate(0, 0, 1) {
+		key := d.Format(dayLayout)
+		dayIndex[key] = len(stats.CompletedByDay)
+		stats.CompletedByDay = append(stats.CompletedByDay, models.DayCount{Date: key})
+
+		week := settings.StartOfWeek(d).Format(dayLayout)
+		if _, ok := weekIndex[week]; !ok {
+			weekIndex[week] = len(stats.CompletedByWeek)
+			stats.CompletedByWeek = append(stats.CompletedByWeek, models.WeekCount{WeekStart: week})
+		}
+	}
+
+	// Контексты в порядке, в котором их вернул репозиторий, задачи без контекста - в конце
+	contextIndex := make(map[models.ContextID]int, len(contexts))
+	for _, c := range contexts {
+		id := c.ID
+		contextIndex[id] = len(stats.ByContext)
+		stats.ByContext = append(stats.ByContext, models.ContextStats{ContextID: &id, Title: c.Title})
+	}
+	noContext := models.ContextStats{Title: "Без контекста"}
+	bucket := func(id *models.ContextID) *models.ContextStats {
+		if id != nil {
+			if i, ok := contextIndex[*id]; ok {
+				return &stats.ByContext[i]
+			}
+		}
+		return &noContext
+	}
+
+	var leadTimeTotal time.Duration
+	for _, task := range tasks {
+		b := bucket(task.ContextID)
+
+		if inPeriod(task.CreatedAt) {
+			stats.CreatedTotal++
+		}
+
+		if task.IsActive() {
+			stats.ActiveTotal++
+			b.Active++
+			if task.DueAt != nil && task.DueAt.Before(now) {
+				stats.OverdueTotal++
+				b.Overdue++
+			}
+			continue
+		}
+
+		if task.Status != models.TaskStatusCompleted || task.CompletedAt == nil || !inPeriod(*task.CompletedAt) {
+			continue
+		}
+
+		completedAt := task.CompletedAt.In(now.Location())
+		stats.CompletedTotal++
+		b.Completed++
+		stats.CompletedByDay[dayIndex[completedAt.Format(dayLayout)]].Count++
+		stats.CompletedByWeek[weekIndex[settings.StartOfWeek(completedAt).Format(dayLayout)]].Count++
+		leadTimeTotal += completedAt.Sub(task.CreatedAt)
+	}
+
+	if stats.CompletedTotal > 0 {
+		avg := leadTimeTotal / time.Duration(stats.CompletedTotal)
+		stats.AvgLeadTimeHours = math.Round(avg.Hours()*10) / 10
+	}
+
+	for _, session := range sessions {
+		if !inPeriod(session.StartedAt) {
+			continue
+		}
+		minutes := int(session.FocusedDuration(now).Minutes())
+		stats.FocusSessions++
+		stats.FocusMinutes += minutes
+		bucket(session.ContextID).FocusMinutes += minutes
+	}
+
+	if noContext.Active+noContext.Completed+noContext.FocusMinutes > 0 {
+		stats.ByContext = append(stats.ByContext, noContext)
+	}
+
+	return stats
+}
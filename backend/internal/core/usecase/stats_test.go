@@ -0,0 +1,94 @@
+package usecase
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/singl3focus/uniflow/internal/core/models"
+)
+
+func TestComputeStats(t *testing.T) {
+	// Среда, 12 марта 2025
+	now := time.Date(2025, 3, 12, 18, 0, 0, 0, time.UTC)
+	userID := uuid.New()
+
+	study, _ := models.NewContext(userID, models.ContextTypeSubject, "Учеба", "", "", nil, nil)
+
+	at := func(day, hour int) *time.Time {
+		t := time.Date(2025, 3, day, hour, 0, 0, 0, time.UTC)
+		return &t
+	}
+	task := func(contextID *models.ContextID, status models.TaskStatus, created time.Time, completed, due *time.Time) models.Task {
+		return models.Task{ID: uuid.New(), UserID: userID, ContextID: contextID, Status: status, CreatedAt: created, CompletedAt: completed, DueAt: due}
+	}
+
+	tasks := []models.Task{
+		// Выполнена во вторник, lead time 10 часов
+		task(&study.ID, models.TaskStatusCompleted, *at(11, 0), at(11, 10), nil),
+		// Выполнена в пятницу прошлой недели, lead time 30 часов
+		task(nil, models.TaskStatusCompleted, *at(6, 6), at(7, 12), nil),
+		// Выполнена до начала периода - не учитывается
+		task(&study.ID, models.TaskStatusCompleted, *at(1, 0), at(2, 0), nil),
+		// Просрочена
+		task(&study.ID, models.TaskStatusTodo, *at(10, 0), nil, at(11, 9)),
+		// Активна, дедлайн впереди
+		task(nil, models.TaskStatusInProgress, *at(12, 9), nil, at(14, 9)),
+	}
+
+	sessions := []models.FocusSession{
+		{ID: uuid.New(), UserID: userID, ContextID: &study.ID, DurationMinutes: 25, StartedAt: *at(12, 9)},
+		{ID: uuid.New(), UserID: userID, DurationMinutes: 50, StartedAt: *at(11, 9), EndedAt: at(11, 9)},
+	}
+
+	settings := models.UserSettings{Timezone: "UTC", FirstWeekday: models.Monday}
+	stats := computeStats(now, settings, 7, tasks, []models.Context{study}, sessions)
+
+	if got, want := stats.From, time.Date(2025, 3, 6, 0, 0, 0, 0, time.UTC); !got.Equal(want) {
+		t.Errorf("from = %v, want %v", got, want)
+	}
+	if stats.CompletedTotal != 2 {
+		t.Errorf("completed_total = %d, want 2", stats.CompletedTotal)
+	}
+	if stats.OverdueTotal != 1 || stats.ActiveTotal != 2 {
+		t.Errorf("overdue = %d, active = %d, want 1 and 2", stats.OverdueTotal, stats.ActiveTotal)
+	}
+	if stats.AvgLeadTimeHours != 20 {
+		t.Errorf("avg_lead_time_hours = %v, want 20", stats.AvgLeadTimeHours)
+	}
+	if stats.FocusSessions != 2 || stats.FocusMinutes != 25 {
+		t.Errorf("focus sessions = %d, minutes = %d, want 2 and 25", stats.FocusSessions, stats.FocusMinutes)
+	}
+
+	if len(stats.CompletedByDay) != 7 {
+		t.Fatalf("completed_by_day has %d days, want 7", len(stats.CompletedByDay))
+	}
+	byDay := make(map[string]int)
+	for _, d := range stats.CompletedByDay {
+		byDay[d.Date] = d.Count
+	}
+	if byDay["2025-03-11"] != 1 || byDay["2025-03-07"] != 1 || byDay["2025-03-12"] != 0 {
+		t.Errorf("completed_by_day = %+v", stats.CompletedByDay)
+	}
+
+	wantWeeks := []models.WeekCount{{WeekStart: "2025-03-03", Count: 1}, {WeekStart: "2025-03-10", Count: 1}}
+	if len(stats.CompletedByWeek) != len(wantWeeks) {
+		t.Fatalf("completed_by_week = %+v, want %+v", stats.CompletedByWeek, wantWeeks)
+	}
+	for i := range wantWeeks {
+		if stats.CompletedByWeek[i] != wantWeeks[i] {
+			t.Errorf("completed_by_week[%d] = %+v, want %+v", i, stats.CompletedByWeek[i], wantWeeks[i])
+		}
+	}
+
+	if len(stats.ByContext) != 2 {
+		t.Fatalf("by_context = %+v, want study and no-context buckets", stats.ByContext)
+	}
+	if c := stats.ByContext[0]; c.Completed != 1 || c.Overdue != 1 || c.Active != 1 || c.FocusMinutes != 25 {
+		t.Errorf("study context stats = %+v", c)
+	}
+	if c := stats.ByContext[1]; c.ContextID != nil || c.Completed != 1 || c.Active != 1 {
+		t.Errorf("no-context stats = %+v", c)
+	}
+}
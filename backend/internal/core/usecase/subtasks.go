@@ -0,0 +1,186 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/singl3focus/uniflow/internal/core/models"
+)
+
+// ===========================
+// Subtask use cases
+// ===========================
+
+func (u *Usecase) CreateSubtask(ctx context.Context, userIDStr, taskIDStr, title string) (models.Subtask, error) {
+	const op = "usecase.CreateSubtask"
+
+	task, err := u.getOwnTask(ctx, userIDStr, taskIDStr)
+	if err != nil {
+		return models.Subtask{}, handleOwnershipError(op, err)
+	}
+
+	subtasks, err := u.repo.GetSubtasksByTaskID(ctx, task.ID)
+	if err != nil {
+		return models.Subtask{}, handleRepositoryError(op, err)
+	}
+
+	if len(subtasks) >= models.MaxSubtasksPerTask {
+		return models.Subtask{}, ErrInvalidData.SetPlace(op).SetCause(
+			models.ErrTooManySubtasks.SetCause(fmt.Errorf("limit is %d", models.MaxSubtasksPerTask)),
+		)
+	}
+
+	// Новый пункт добавляется в конец чек-листа
+	position := 0
+	if len(subtasks) > 0 {
+		position = subtasks[len(subtasks)-1].Position + 1
+	}
+
+	subtask, err := models.NewSubtask(task.ID, title, position)
+	if err != nil {
+		return models.Subtask{}, ErrInvalidData.SetPlace(op).SetCause(err)
+	}
+
+	if err = u.repo.CreateSubtask(ctx, subtask); err != nil {
+		return models.Subtask{}, handleRepositoryError(op, err)
+	}
+
+	return subtask, nil
+}
+
+func (u *Usecase) GetSubtasks(ctx context.Context, userIDStr, taskIDStr string) ([]models.Subtask, error) {
+	const op = "usecase.GetSubtasks"
+
+	task, err := u.getOwnTask(ctx, userIDStr, taskIDStr)
+	if err != nil {
+		return nil, handleOwnershipError(op, err)
+	}
+
+	subtasks, err := u.repo.GetSubtasksByTaskID(ctx, task.ID)
+	if err != nil {
+		return nil, handleRepositoryError(op, err)
+	}
+
+	return subtasks, nil
+}
+
+func (u *Usecase) UpdateSubtask(ctx context.Context, userIDStr, taskIDStr, subtaskIDStr string, title *string, done *bool) (models.Subtask, error) {
+	const op = "usecase.UpdateSubtask"
+
+	subtask, err := u.getOwnSubtask(ctx, userIDStr, taskIDStr, subtaskIDStr)
+	if err != nil {
+		return models.Subtask{}, handleOwnershipError(op, err)
+	}
+
+	if err = subtask.Update(title, done); err != nil {
+		return models.Subtask{}, ErrInvalidData.SetPlace(op).SetCause(err)
+	}
+
+	if err = u.repo.UpdateSubtask(ctx, subtask); err != nil {
+		return models.Subtask{}, handleRepositoryError(op, err)
+	}
+
+	return subtask, nil
+}
+
+// ToggleSubtask переключает отметку пункта чек-листа. Задача определяется по самому пункту.
+func (u *Usecase) ToggleSubtask(ctx context.Context, userIDStr, subtaskIDStr string) (models.Subtask, error) {
+	const op = "usecase.ToggleSubtask"
+
+	subtask, err := u.getOwnSubtask(ctx, userIDStr, "", subtaskIDStr)
+	if err != nil {
+		return models.Subtask{}, handleOwnershipError(op, err)
+	}
+
+	subtask.Toggle()
+
+	if err = u.repo.UpdateSubtask(ctx, subtask); err != nil {
+		return models.Subtask{}, handleRepositoryError(op, err)
+	}
+
+	return subtask, nil
+}
+
+func (u *Usecase) DeleteSubtask(ctx context.Context, userIDStr, taskIDStr, subtaskIDStr string) error {
+	const op = "usecase.DeleteSubtask"
+
+	subtask, err := u.getOwnSubtask(ctx, userIDStr, taskIDStr, subtaskIDStr)
+	if err != nil {
+		return handleOwnershipError(op, err)
+	}
+
+	if err = u.repo.DeleteSubtask(ctx, subtask.ID); err != nil {
+		return handleRepositoryError(op, err)
+	}
+
+	return nil
+}
+
+// copySubtasks переносит чек-лист задачи в ее следующее вхождение со снятыми отметками
+func (u *Usecase) copySubtasks(ctx context.Context, from, to models.TaskID) error {
+	subtasks, err := u.repo.GetSubtasksByTaskID(ctx, from)
+	if err != nil {
+		return err
+	}
+
+	for _, s := range subtasks {
+		subtask, err := models.NewSubtask(to, s.Title, s.Position)
+		if err != nil {
+			return err
+		}
+		if err = u.repo.CreateSubtask(ctx, subtask); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// getOwnTask загружает задачу и проверяет, что она принадлежит пользователю.
+// Чужая задача неотличима от несуществующей.
+func (u *Usecase) getOwnTask(ctx context.Context, userIDStr, taskIDStr string) (models.Task, error) {
+	userID, err := models.ParseUserID(userIDStr)
+	if err != nil {
+		return models.Task{}, ErrInvalidData.SetCause(err)
+	}
+
+	taskID, err := models.ParseTaskID(taskIDStr)
+	if err != nil {
+		return models.Task{}, ErrInvalidData.SetCause(err)
+	}
+
+	task, err := u.repo.GetTaskByID(ctx, taskID)
+	if err != nil {
+		return models.Task{}, err
+	}
+
+	if task.UserID != userID {
+		return models.Task{}, ErrNotFound.SetCause(errForeignResource)
+	}
+
+	return task, nil
+}
+
+// getOwnSubtask загружает пункт чек-листа и проверяет владельца его задачи.
+// Если taskIDStr не пуст, пункт также должен принадлежать этой задаче.
+func (u *Usecase) getOwnSubtask(ctx context.Context, userIDStr, taskIDStr, subtaskIDStr string) (models.Subtask, error) {
+	subtaskID, err := models.ParseSubtaskID(subtaskIDStr)
+	if err != nil {
+		return models.Subtask{}, ErrInvalidData.SetCause(err)
+	}
+
+	subtask, err := u.repo.GetSubtaskByID(ctx, subtaskID)
+	if err != nil {
+		return models.Subtask{}, err
+	}
+
+	if taskIDStr != "" && subtask.TaskID.String() != taskIDStr {
+		return models.Subtask{}, ErrNotFound.SetCause(errForeignResource)
+	}
+
+	if _, err = u.getOwnTask(ctx, userIDStr, subtask.TaskID.String()); err != nil {
+		return models.Subtask{}, err
+	}
+
+	return subtask, nil
+}
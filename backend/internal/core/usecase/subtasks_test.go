@@ -0,0 +1,121 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/singl3focus/uniflow/internal/core/models"
+)
+
+func TestSubtasks(t *testing.T) {
+	ctx := context.Background()
+	now := time.Date(2025, 3, 10, 12, 0, 0, 0, time.UTC)
+	userID := uuid.New().String()
+
+	repo := newFakeRepo()
+	uc := newTestUsecase(repo, now)
+
+	task, err := uc.CreateTask(ctx, userID, nil, "Курсовая", "", nil, nil, "", nil)
+	if err != nil {
+		t.Fatalf("CreateTask() error = %v", err)
+	}
+
+	var ids []string
+	for _, title := range []string{"Введение", "Обзор литературы", "Заключение"} {
+		s, err := uc.CreateSubtask(ctx, userID, task.ID.String(), title)
+		if err != nil {
+			t.Fatalf("CreateSubtask(%q) error = %v", title, err)
+		}
+		ids = append(ids, s.ID.String())
+	}
+
+	if _, err = uc.CreateSubtask(ctx, userID, task.ID.String(), ""); !errors.Is(err, ErrInvalidData) {
+		t.Errorf("CreateSubtask(empty) error = %v, want ErrInvalidData", err)
+	}
+
+	// Длина считается в символах, а не в байтах
+	tooLong := strings.Repeat("я", models.MaxSubtaskTitleLength+1)
+	if _, err = uc.CreateSubtask(ctx, userID, task.ID.String(), tooLong); !errors.Is(err, ErrInvalidData) {
+		t.Errorf("CreateSubtask(too long) error = %v, want ErrInvalidData", err)
+	}
+	if _, err = uc.UpdateSubtask(ctx, userID, task.ID.String(), ids[0], &tooLong, nil); !errors.Is(err, ErrInvalidData) {
+		t.Errorf("UpdateSubtask(too long) error = %v, want ErrInvalidData", err)
+	}
+
+	toggled, err := uc.ToggleSubtask(ctx, userID, ids[1])
+	if err != nil || !toggled.Done {
+		t.Fatalf("ToggleSubtask() = %+v, %v; want done", toggled, err)
+	}
+
+	subtasks, err := uc.GetSubtasks(ctx, userID, task.ID.String())
+	if err != nil {
+		t.Fatalf("GetSubtasks() error = %v", err)
+	}
+	if subtasks[0].Title != "Введение" || subtasks[2].Title != "Заключение" {
+		t.Errorf("subtasks order = %+v", subtasks)
+	}
+	if done, total := models.SubtaskProgress(subtasks); done != 1 || total != 3 {
+		t.Errorf("progress = %d/%d, want 1/3", done, total)
+	}
+
+	// Чужой пользователь не видит и не меняет чек-лист
+	stranger := uuid.New().String()
+	if _, err = uc.GetSubtasks(ctx, stranger, task.ID.String()); !errors.Is(err, ErrNotFound) {
+		t.Errorf("GetSubtasks(stranger) error = %v, want ErrNotFound", err)
+	}
+	if _, err = uc.ToggleSubtask(ctx, stranger, ids[0]); !errors.Is(err, ErrNotFound) {
+		t.Errorf("ToggleSubtask(stranger) error = %v, want ErrNotFound", err)
+	}
+
+	// Пункт другой задачи недоступен по пути этой задачи
+	other, _ := uc.CreateTask(ctx, userID, nil, "Другая", "", nil, nil, "", nil)
+	if err = uc.DeleteSubtask(ctx, userID, other.ID.String(), ids[0]); !errors.Is(err, ErrNotFound) {
+		t.Errorf("DeleteSubtask(other task) error = %v, want ErrNotFound", err)
+	}
+
+	if err = uc.DeleteSubtask(ctx, userID, task.ID.String(), ids[0]); err != nil {
+		t.Fatalf("DeleteSubtask() error = %v", err)
+	}
+	if subtasks, _ = uc.GetSubtasks(ctx, userID, task.ID.String()); len(subtasks) != 2 {
+		t.Errorf("after delete: got %d subtasks, want 2", len(subtasks))
+	}
+}
+
+func TestRecurringTaskCopiesChecklist(t *testing.T) {
+	ctx := context.Background()
+	now := time.Date(2025, 3, 10, 12, 0, 0, 0, time.UTC)
+	userID := uuid.New().String()
+
+	repo := newFakeRepo()
+	uc := newTestUsecase(repo, now)
+
+	dueAt := now.Add(time.Hour).Format(time.RFC3339)
+	task, err := uc.CreateTask(ctx, userID, nil, "Зарядка", "", &dueAt, &models.Recurrence{Frequency: models.RecurrenceDaily}, "", nil)
+	if err != nil {
+		t.Fatalf("CreateTask() error = %v", err)
+	}
+
+	s, _ := uc.CreateSubtask(ctx, userID, task.ID.String(), "Разминка")
+	if _, err = uc.ToggleSubtask(ctx, userID, s.ID.String()); err != nil {
+		t.Fatalf("ToggleSubtask() error = %v", err)
+	}
+
+	if err = uc.UpdateTaskStatus(ctx, userID, task.ID.String(), models.TaskStatusCompleted); err != nil {
+		t.Fatalf("UpdateTaskStatus() error = %v", err)
+	}
+
+	for _, next := range repo.tasks {
+		if next.ID == task.ID {
+			continue
+		}
+		subtasks, _ := uc.GetSubtasks(ctx, userID, next.ID.String())
+		if len(subtasks) != 1 || subtasks[0].Title != "Разминка" || subtasks[0].Done {
+			t.Errorf("next occurrence checklist = %+v, want one unchecked item", subtasks)
+		}
+	}
+}
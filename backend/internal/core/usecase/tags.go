@@ -0,0 +1,212 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+
+	"github.com/singl3focus/uniflow/internal/core/models"
+	"github.com/singl3focus/uniflow/internal/core/ports/repository"
+)
+
+// ===========================
+// Tag use cases
+// ===========================
+
+func (u *Usecase) GetTags(ctx context.Context, userIDStr string) ([]models.Tag, error) {
+	const op = "usecase.GetTags"
+
+	userID, err := models.ParseUserID(userIDStr)
+	if err != nil {
+		return nil, ErrInvalidData.SetPlace(op).SetCause(err)
+	}
+
+	tags, err := u.repo.GetTagsByUserID(ctx, userID)
+	if err != nil {
+		return nil, handleRepositoryError(op, err)
+	}
+
+	return tags, nil
+}
+
+func (u *Usecase) CreateTag(ctx context.Context, userIDStr, name string) (models.Tag, error) {
+	const op = "usecase.CreateTag"
+
+	userID, err := models.ParseUserID(userIDStr)
+	if err != nil {
+		return models.Tag{}, ErrInvalidData.SetPlace(op).SetCause(err)
+	}
+
+	tag, err := models.NewTag(userID, name)
+	if err != nil {
+		return models.Tag{}, ErrInvalidData.SetPlace(op).SetCause(err)
+	}
+
+	// Повторное создание тега с тем же именем вернет ErrInvalidData (ErrAlreadyExists в репозитории)
+	if err = u.repo.CreateTag(ctx, tag); err != nil {
+		return models.Tag{}, handleRepositoryError(op, err)
+	}
+
+	return tag, nil
+}
+
+// DeleteTag удаляет тег; связи с задачами удаляются каскадно
+func (u *Usecase) DeleteTag(ctx context.Context, userIDStr, tagIDStr string) error {
+	const op = "usecase.DeleteTag"
+
+	tag, err := u.getOwnTag(ctx, userIDStr, tagIDStr)
+	if err != nil {
+		return handleOwnershipError(op, err)
+	}
+
+	if err = u.repo.DeleteTag(ctx, tag.ID); err != nil {
+		return handleRepositoryError(op, err)
+	}
+
+	return nil
+}
+
+// AddTaskTags привязывает к задаче теги по именам, создавая отсутствующие.
+// Возвращает задачу с актуальным списком тегов.
+func (u *Usecase) AddTaskTags(ctx context.Context, userIDStr, taskIDStr string, names []string) (models.Task, error) {
+	const op = "usecase.AddTaskTags"
+
+	task, err := u.getOwnTask(ctx, userIDStr, taskIDStr)
+	if err != nil {
+		return models.Task{}, handleOwnershipError(op, err)
+	}
+
+	normalized, err := models.NormalizeTagNames(names)
+	if err != nil {
+		return models.Task{}, ErrInvalidData.SetPlace(op).SetCause(err)
+	}
+
+	if err = u.attachTags(ctx, task, normalized); err != nil {
+		if errors.Is(err, models.ErrTooManyTags) {
+			return models.Task{}, ErrInvalidData.SetPlace(op).SetCause(err)
+		}
+		return models.Task{}, handleRepositoryError(op, err)
+	}
+
+	task, err = u.repo.GetTaskByID(ctx, task.ID)
+	if err != nil {
+		return models.Task{}, handleRepositoryError(op, err)
+	}
+
+	return task, nil
+}
+
+// RemoveTaskTag отвязывает тег от задачи. Сам тег остается у пользователя.
+func (u *Usecase) RemoveTaskTag(ctx context.Context, userIDStr, taskIDStr, name string) (models.Task, error) {
+	const op = "usecase.RemoveTaskTag"
+
+	task, err := u.getOwnTask(ctx, userIDStr, taskIDStr)
+	if err != nil {
+		return models.Task{}, handleOwnershipError(op, err)
+	}
+
+	normalized, err := models.NormalizeTagName(name)
+	if err != nil {
+		return models.Task{}, ErrInvalidData.SetPlace(op).SetCause(models.ErrInvalidTagName.SetCause(err))
+	}
+
+	tag, err := u.repo.GetTagByName(ctx, task.UserID, normalized)
+	if err != nil {
+		return models.Task{}, handleRepositoryError(op, err)
+	}
+
+	if err = u.repo.RemoveTagFromTask(ctx, task.ID, tag.ID); err != nil {
+		return models.Task{}, handleRepositoryError(op, err)
+	}
+
+	task, err = u.repo.GetTaskByID(ctx, task.ID)
+	if err != nil {
+		return models.Task{}, handleRepositoryError(op, err)
+	}
+
+	return task, nil
+}
+
+// attachTags привязывает к задаче уже нормализованные теги, создавая отсутствующие.
+// Общее число тегов задачи не может превысить MaxTagsPerTask.
+func (u *Usecase) attachTags(ctx context.Context, task models.Task, names []string) error {
+	current := make(map[string]bool, len(task.Tags))
+	for _, name := range task.Tags {
+		current[name] = true
+	}
+
+	var added []string
+	for _, name := range names {
+		if !current[name] {
+			current[name] = true
+			added = append(added, name)
+		}
+	}
+
+	if len(current) > models.MaxTagsPerTask {
+		return models.ErrTooManyTags
+	}
+
+	for _, name := range added {
+		tag, err := u.getOrCreateTag(ctx, task.UserID, name)
+		if err != nil {
+			return err
+		}
+
+		if err = u.repo.AddTagToTask(ctx, task.ID, tag.ID); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// getOrCreateTag находит тег пользователя по имени или создает новый.
+// Если тег успели создать параллельно, повторно читает его.
+func (u *Usecase) getOrCreateTag(ctx context.Context, userID models.UserID, name string) (models.Tag, error) {
+	tag, err := u.repo.GetTagByName(ctx, userID, name)
+	if err == nil {
+		return tag, nil
+	}
+	if !errors.Is(err, repository.ErrNotFound) {
+		return models.Tag{}, err
+	}
+
+	tag, err = models.NewTag(userID, name)
+	if err != nil {
+		return models.Tag{}, err
+	}
+
+	if err = u.repo.CreateTag(ctx, tag); err != nil {
+		if errors.Is(err, repository.ErrAlreadyExists) {
+			return u.repo.GetTagByName(ctx, userID, name)
+		}
+		return models.Tag{}, err
+	}
+
+	return tag, nil
+}
+
+// getOwnTag загружает тег и проверяет, что он принадлежит пользователю.
+// Чужой тег неотличим от несуществующего.
+func (u *Usecase) getOwnTag(ctx context.Context, userIDStr, tagIDStr string) (models.Tag, error) {
+	userID, err := models.ParseUserID(userIDStr)
+	if err != nil {
+		return models.Tag{}, ErrInvalidData.SetCause(err)
+	}
+
+	tagID, err := models.ParseTagID(tagIDStr)
+	if err != nil {
+		return models.Tag{}, ErrInvalidData.SetCause(err)
+	}
+
+	tag, err := u.repo.GetTagByID(ctx, tagID)
+	if err != nil {
+		return models.Tag{}, err
+	}
+
+	if tag.UserID != userID {
+		return models.Tag{}, ErrNotFound.SetCause(errForeignResource)
+	}
+
+	return tag, nil
+}
@@ -0,0 +1,132 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/singl3focus/uniflow/internal/core/models"
+)
+
+func TestCreateTaskWithTags(t *testing.T) {
+	ctx := context.Background()
+	repo := newFakeRepo()
+	uc := newTestUsecase(repo, time.Now())
+	userID := uuid.New().String()
+
+	task, err := uc.CreateTask(ctx, userID, nil, "Лабораторная", "", nil, nil, "", []string{"#Матан", "лаба", "матан"})
+	if err != nil {
+		t.Fatalf("CreateTask() error = %v", err)
+	}
+
+	stored, err := uc.GetTaskByID(ctx, userID, task.ID.String())
+	if err != nil {
+		t.Fatalf("GetTaskByID() error = %v", err)
+	}
+	if want := []string{"лаба", "матан"}; !slices.Equal(stored.Tags, want) {
+		t.Errorf("task tags = %v, want %v", stored.Tags, want)
+	}
+	if len(repo.tags) != 2 {
+		t.Errorf("created %d tags, want 2", len(repo.tags))
+	}
+
+	// Существующий тег переиспользуется, а не создается повторно
+	if _, err = uc.CreateTask(ctx, userID, nil, "Коллоквиум", "", nil, nil, "", []string{"матан"}); err != nil {
+		t.Fatalf("CreateTask() error = %v", err)
+	}
+	if len(repo.tags) != 2 {
+		t.Errorf("tags after second task = %d, want 2", len(repo.tags))
+	}
+
+	if _, err = uc.CreateTask(ctx, userID, nil, "Задача", "", nil, nil, "", []string{"два слова"}); !errors.Is(err, ErrInvalidData) {
+		t.Errorf("CreateTask(invalid tag) error = %v, want ErrInvalidData", err)
+	}
+}
+
+func TestListTasksByTag(t *testing.T) {
+	ctx := context.Background()
+	repo := newFakeRepo()
+	uc := newTestUsecase(repo, time.Now())
+	userID := uuid.New().String()
+
+	tagged, err := uc.CreateTask(ctx, userID, nil, "Курсовая", "", nil, nil, "", []string{"диплом"})
+	if err != nil {
+		t.Fatalf("CreateTask() error = %v", err)
+	}
+	if _, err = uc.CreateTask(ctx, userID, nil, "Купить хлеб", "", nil, nil, "", nil); err != nil {
+		t.Fatalf("CreateTask() error = %v", err)
+	}
+	// Одноименный тег другого пользователя не должен попадать в выборку
+	if _, err = uc.CreateTask(ctx, uuid.New().String(), nil, "Чужая", "", nil, nil, "", []string{"диплом"}); err != nil {
+		t.Fatalf("CreateTask() error = %v", err)
+	}
+
+	page, err := uc.ListTasks(ctx, userID, models.TaskFilter{Tag: "#Диплом"})
+	if err != nil {
+		t.Fatalf("ListTasks(tag) error = %v", err)
+	}
+	if len(page.Tasks) != 1 || page.Tasks[0].ID != tagged.ID {
+		t.Errorf("ListTasks(tag=диплом) = %v, want only %q", page.Tasks, tagged.Title)
+	}
+
+	page, err = uc.ListTasks(ctx, userID, models.TaskFilter{Tag: "несуществующий"})
+	if err != nil {
+		t.Fatalf("ListTasks(unknown tag) error = %v", err)
+	}
+	if len(page.Tasks) != 0 {
+		t.Errorf("ListTasks(unknown tag) returned %d tasks, want 0", len(page.Tasks))
+	}
+}
+
+func TestTaskTagsOwnership(t *testing.T) {
+	ctx := context.Background()
+	repo := newFakeRepo()
+	uc := newTestUsecase(repo, time.Now())
+	owner := uuid.New().String()
+	stranger := uuid.New().String()
+
+	task, err := uc.CreateTask(ctx, owner, nil, "Проект", "", nil, nil, "", nil)
+	if err != nil {
+		t.Fatalf("CreateTask() error = %v", err)
+	}
+
+	if _, err = uc.AddTaskTags(ctx, stranger, task.ID.String(), []string{"чужое"}); !errors.Is(err, ErrNotFound) {
+		t.Errorf("AddTaskTags(foreign task) error = %v, want ErrNotFound", err)
+	}
+
+	task, err = uc.AddTaskTags(ctx, owner, task.ID.String(), []string{"работа"})
+	if err != nil {
+		t.Fatalf("AddTaskTags() error = %v", err)
+	}
+	if !slices.Equal(task.Tags, []string{"работа"}) {
+		t.Fatalf("task tags = %v, want [работа]", task.Tags)
+	}
+
+	tag, err := uc.getOrCreateTag(ctx, task.UserID, "работа")
+	if err != nil {
+		t.Fatalf("getOrCreateTag() error = %v", err)
+	}
+	if err = uc.DeleteTag(ctx, stranger, tag.ID.String()); !errors.Is(err, ErrNotFound) {
+		t.Errorf("DeleteTag(foreign tag) error = %v, want ErrNotFound", err)
+	}
+
+	task, err = uc.RemoveTaskTag(ctx, owner, task.ID.String(), "#работа")
+	if err != nil {
+		t.Fatalf("RemoveTaskTag() error = %v", err)
+	}
+	if len(task.Tags) != 0 {
+		t.Errorf("task tags after removal = %v, want none", task.Tags)
+	}
+
+	tooMany := make([]string, 0, models.MaxTagsPerTask+1)
+	for i := 0; i <= models.MaxTagsPerTask; i++ {
+		tooMany = append(tooMany, string(rune('a'+i)))
+	}
+	if _, err = uc.AddTaskTags(ctx, owner, task.ID.String(), tooMany); !errors.Is(err, ErrInvalidData) {
+		t.Errorf("AddTaskTags(%d tags) error = %v, want ErrInvalidData", len(tooMany), err)
+	}
+}
@@ -0,0 +1,240 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/singl3focus/uniflow/internal/core/models"
+)
+
+func TestListTasksByPriority(t *testing.T) {
+	ctx := context.Background()
+	now := time.Date(2025, 3, 10, 12, 0, 0, 0, time.UTC)
+	userID := uuid.New()
+
+	repo := newFakeRepo()
+	uc := newTestUsecase(repo, now)
+
+	due := func(hours int) *time.Time {
+		t := now.Add(time.Duration(hours) * time.Hour)
+		return &t
+	}
+	add := func(title string, priority models.TaskPriority, dueAt *time.Time, createdAgo int) {
+		task := models.Task{
+			ID:        uuid.New(),
+			UserID:    userID,
+			Title:     title,
+			Status:    models.TaskStatusTodo,
+			Priority:  priority,
+			DueAt:     dueAt,
+			CreatedAt: now.Add(-time.Duration(createdAgo) * time.Hour),
+		}
+		repo.tasks[task.ID] = task
+	}
+	add("Прочитать статью", models.TaskPriorityLow, nil, 1)
+	add("Сдать отчет", models.TaskPriorityHigh, due(48), 2)
+	add("Оплатить общежитие", models.TaskPriorityUrgent, due(72), 3)
+	add("Подготовить доклад", models.TaskPriorityHigh, due(24), 4)
+	add("Купить тетради", models.TaskPriorityNormal, nil, 5)
+
+	titles := func(tasks []models.Task) []string {
+		res := make([]string, 0, len(tasks))
+		for _, task := range tasks {
+			res = append(res, task.Title)
+		}
+		return res
+	}
+
+	page, err := uc.ListTasks(ctx, userID.String(), models.TaskFilter{Sort: models.TaskSortPriority})
+	if err != nil {
+		t.Fatalf("ListTasks() error = %v", err)
+	}
+	want := []string{"Оплатить общежитие", "Подготовить доклад", "Сдать отчет", "Купить тетради", "Прочитать статью"}
+	if got := titles(page.Tasks); len(got) != len(want) {
+		t.Fatalf("ListTasks(sort=priority) = %v, want %v", got, want)
+	} else {
+		for i := range want {
+			if got[i] != want[i] {
+				t.Fatalf("ListTasks(sort=priority) = %v, want %v", got, want)
+			}
+		}
+	}
+
+	page, err = uc.ListTasks(ctx, userID.String(), models.TaskFilter{
+		Priorities: []models.TaskPriority{models.TaskPriorityHigh, models.TaskPriorityUrgent},
+	})
+	if err != nil {
+		t.Fatalf("ListTasks() error = %v", err)
+	}
+	// Без сортировки - сначала новые
+	want = []string{"Сдать отчет", "Оплатить общежитие", "Подготовить доклад"}
+	for i, got := range titles(page.Tasks) {
+		if i >= len(want) || got != want[i] {
+			t.Fatalf("ListTasks(priority=high,urgent) = %v, want %v", titles(page.Tasks), want)
+		}
+	}
+
+	if _, err = uc.ListTasks(ctx, userID.String(), models.TaskFilter{Priorities: []models.TaskPriority{"critical"}}); !errors.Is(err, ErrInvalidData) {
+		t.Errorf("ListTasks(priority=critical) error = %v, want ErrInvalidData", err)
+	}
+	if _, err = uc.ListTasks(ctx, userID.String(), models.TaskFilter{Sort: "title"}); !errors.Is(err, ErrInvalidData) {
+		t.Errorf("ListTasks(sort=title) error = %v, want ErrInvalidData", err)
+	}
+}
+
+func TestListTasksFilterAndPagination(t *testing.T) {
+	ctx := context.Background()
+	now := time.Date(2025, 3, 10, 12, 0, 0, 0, time.UTC)
+	userID := uuid.New()
+	contextID := uuid.New()
+
+	repo := newFakeRepo()
+	uc := newTestUsecase(repo, now)
+
+	for i := 0; i < 5; i++ {
+		dueAt := now.Add(time.Duration(i) * time.Hour)
+		task := models.Task{
+			ID:        uuid.New(),
+			UserID:    userID,
+			Title:     fmt.Sprintf("Задача %d", i),
+			Status:    models.TaskStatusTodo,
+			Priority:  models.TaskPriorityNormal,
+			DueAt:     &dueAt,
+			CreatedAt: now.Add(-time.Duration(i) * time.Minute),
+		}
+		if i%2 == 0 {
+			task.ContextID = &contextID
+		}
+		repo.tasks[task.ID] = task
+	}
+
+	// Обходим все задачи страницами по 2 в порядке дедлайна
+	var got []string
+	filter := models.TaskFilter{Sort: models.TaskSortDue, Limit: 2}
+	for pages := 0; ; pages++ {
+		if pages > 3 {
+			t.Fatal("ListTasks() pagination does not terminate")
+		}
+
+		page, err := uc.ListTasks(ctx, userID.String(), filter)
+		if err != nil {
+			t.Fatalf("ListTasks() error = %v", err)
+		}
+		for _, task := range page.Tasks {
+			got = append(got, task.Title)
+		}
+		if page.NextCursor == "" {
+			break
+		}
+
+		cursor, err := models.ParseTaskCursor(page.NextCursor)
+		if err != nil {
+			t.Fatalf("ParseTaskCursor() error = %v", err)
+		}
+		filter.Cursor = &cursor
+	}
+	want := []string{"Задача 0", "Задача 1", "Задача 2", "Задача 3", "Задача 4"}
+	if !slices.Equal(got, want) {
+		t.Errorf("paginated ListTasks() = %v, want %v", got, want)
+	}
+
+	// Курсор, выданный для другой сортировки, отклоняется
+	filter.Sort = models.TaskSortCreated
+	if _, err := uc.ListTasks(ctx, userID.String(), filter); !errors.Is(err, ErrInvalidData) {
+		t.Errorf("ListTasks(cursor for another sort) error = %v, want ErrInvalidData", err)
+	}
+
+	dueFrom, dueTo := now.Add(time.Hour), now.Add(3*time.Hour)
+	page, err := uc.ListTasks(ctx, userID.String(), models.TaskFilter{InboxOnly: true, DueFrom: &dueFrom, DueTo: &dueTo})
+	if err != nil {
+		t.Fatalf("ListTasks(inbox, due range) error = %v", err)
+	}
+	if len(page.Tasks) != 1 || page.Tasks[0].Title != "Задача 1" {
+		t.Errorf("ListTasks(inbox, due range) = %v, want [Задача 1]", page.Tasks)
+	}
+
+	if _, err = uc.ListTasks(ctx, userID.String(), models.TaskFilter{InboxOnly: true, ContextID: &contextID}); !errors.Is(err, ErrInvalidData) {
+		t.Errorf("ListTasks(inbox and context) error = %v, want ErrInvalidData", err)
+	}
+	if _, err = uc.ListTasks(ctx, userID.String(), models.TaskFilter{Limit: models.MaxTaskPageSize + 1}); !errors.Is(err, ErrInvalidData) {
+		t.Errorf("ListTasks(limit too large) error = %v, want ErrInvalidData", err)
+	}
+}
+
+func TestAllDayTask(t *testing.T) {
+	ctx := context.Background()
+	// 15:00 по Москве 10 марта
+	now := time.Date(2025, 3, 10, 12, 0, 0, 0, time.UTC)
+
+	repo := newFakeRepo()
+	uc := newTestUsecase(repo, now)
+
+	user, err := models.NewUser("42")
+	if err != nil {
+		t.Fatalf("NewUser() error = %v", err)
+	}
+	_ = repo.CreateUser(ctx, user)
+	userID := user.ID.String()
+	moscow := user.Settings.Location()
+
+	// Дата без времени - срок на весь день, до 23:59 в поясе пользователя
+	dueAt := "2025-03-12"
+	task, err := uc.CreateTask(ctx, userID, nil, "Курсовая", "", &dueAt, nil, "", nil)
+	if err != nil {
+		t.Fatalf("CreateTask() error = %v", err)
+	}
+	if want := time.Date(2025, 3, 12, 23, 59, 0, 0, moscow); !task.AllDay || !task.DueAt.Equal(want) {
+		t.Fatalf("task = all_day %v, due_at %v; want all_day, %v", task.AllDay, task.DueAt, want)
+	}
+
+	// Напоминания отсчитываются от 09:00 дня срока
+	reminders := repo.taskNotifications(task.ID)
+	if len(reminders) != 2 {
+		t.Fatalf("got %d reminders, want 2", len(reminders))
+	}
+	want := []time.Time{time.Date(2025, 3, 11, 9, 0, 0, 0, moscow), time.Date(2025, 3, 12, 8, 0, 0, 0, moscow)}
+	for _, r := range reminders {
+		if !slices.ContainsFunc(want, r.NotifyAt.Equal) {
+			t.Errorf("reminder notify_at = %v, want one of %v", r.NotifyAt.In(moscow), want)
+		}
+	}
+
+	// Перенос на завтра сохраняет срок на весь день
+	postponed, err := uc.PostponeTaskToTomorrow(ctx, userID, task.ID.String())
+	if err != nil {
+		t.Fatalf("PostponeTaskToTomorrow() error = %v", err)
+	}
+	if want := time.Date(2025, 3, 11, 23, 59, 0, 0, moscow); !postponed.AllDay || !postponed.DueAt.Equal(want) {
+		t.Errorf("postponed = all_day %v, due_at %v; want all_day, %v", postponed.AllDay, postponed.DueAt, want)
+	}
+
+	// Смена пояса сохраняет дату задачи на весь день
+	tz := "Asia/Novosibirsk"
+	if _, err = uc.UpdateUserSettings(ctx, userID, &tz, nil, nil, nil, nil, nil); err != nil {
+		t.Fatalf("UpdateUserSettings() error = %v", err)
+	}
+	novosibirsk, _ := time.LoadLocation(tz)
+	moved, err := uc.GetTaskByID(ctx, userID, task.ID.String())
+	if err != nil {
+		t.Fatalf("GetTaskByID() error = %v", err)
+	}
+	if want := time.Date(2025, 3, 11, 23, 59, 0, 0, novosibirsk); !moved.AllDay || !moved.DueAt.Equal(want) {
+		t.Errorf("after timezone change due_at = %v, want %v", moved.DueAt, want)
+	}
+
+	// Срок со временем снимает признак "весь день"
+	timed := time.Date(2025, 3, 11, 18, 0, 0, 0, novosibirsk).Format(time.RFC3339)
+	updated, err := uc.UpdateTask(ctx, userID, task.ID.String(), nil, nil, nil, &timed, nil, nil, nil)
+	if err != nil {
+		t.Fatalf("UpdateTask() error = %v", err)
+	}
+	if updated.AllDay {
+		t.Error("timed due date kept all_day")
+	}
+}
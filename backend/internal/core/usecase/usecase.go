@@ -3,6 +3,7 @@ package usecase
 import (
 	"context"
 	"errors"
+	"slices"
 	"time"
 
 	"github.com/singl3focus/uniflow/internal/core/models"
@@ -12,19 +13,52 @@ import (
 )
 
 type Usecase struct {
-	repo       repository.Repository
-	jwtManager *jwtpkg.JWTManager
+	repo            repository.Repository
+	jwtManager      *jwtpkg.JWTManager
+	reminderOffsets []time.Duration
+	refreshTokenTTL time.Duration
+	now             func() time.Time
 }
 
-func NewUsecase(r repository.Repository, j *jwtpkg.JWTManager) *Usecase {
-	return &Usecase{repo: r, jwtManager: j}
+// Option настраивает необязательные параметры Usecase
+type Option func(*Usecase)
+
+// WithReminderOffsets задает, за сколько до дедлайна задачи отправлять напоминания
+func WithReminderOffsets(offsets []time.Duration) Option {
+	return func(u *Usecase) {
+		u.reminderOffsets = offsets
+	}
+}
+
+// WithRefreshTokenTTL задает срок действия refresh-токенов
+func WithRefreshTokenTTL(ttl time.Duration) Option {
+	return func(u *Usecase) {
+		u.refreshTokenTTL = ttl
+	}
+}
+
+func NewUsecase(r repository.Repository, j *jwtpkg.JWTManager, opts ...Option) *Usecase {
+	u := &Usecase{
+		repo:            r,
+		jwtManager:      j,
+		reminderOffsets: DefaultReminderOffsets,
+		refreshTokenTTL: DefaultRefreshTokenTTL,
+		now:             time.Now,
+	}
+
+	for _, opt := range opts {
+		opt(u)
+	}
+
+	return u
 }
 
 var (
-	ErrInvalidData = errs.New("invalid data")
-	ErrNotFound    = errs.New("not found")
-	ErrInternal    = errs.New("internal error")
-	ErrUnexpected  = errs.New("unexpected error")
+	ErrInvalidData  = errs.New("invalid data")
+	ErrNotFound     = errs.New("not found")
+	ErrUnauthorized = errs.New("unauthorized")
+	ErrInternal     = errs.New("internal error")
+	ErrUnexpected   = errs.New("unexpected error")
 )
 
 func handleRepositoryError(op string, err error) error {
@@ -40,28 +74,46 @@ func handleRepositoryError(op string, err error) error {
 	}
 }
 
+// errForeignResource - сущность принадлежит другому пользователю.
+// Наружу отдается как ErrNotFound, чтобы не раскрывать существование чужих данных.
+var errForeignResource = errors.New("resource belongs to another user")
+
+// handleOwnershipError преобразует ошибку загрузки сущности с проверкой владельца:
+// ошибки usecase пробрасываются как есть, ошибки репозитория - через handleRepositoryError
+func handleOwnershipError(op string, err error) error {
+	switch {
+	case errors.Is(err, ErrInvalidData):
+		return ErrInvalidData.SetPlace(op).SetCause(err)
+	case errors.Is(err, ErrNotFound):
+		return ErrNotFound.SetPlace(op).SetCause(err)
+	default:
+		return handleRepositoryError(op, err)
+	}
+}
+
 // ===========================
 // Auth Usecases
 // ===========================
 
-func (u *Usecase) Login(ctx context.Context, maxUserID string) (models.User, string, error) {
+// Login выдает пользователю MAX пару access/refresh токенов, создавая пользователя при первом входе
+func (u *Usecase) Login(ctx context.Context, maxUserID string) (models.User, models.TokenPair, error) {
 	const op = "usecase.Login"
 
 	if maxUserID == "" { // 1v1, changes
-		return models.User{}, "", ErrInvalidData.SetPlace(op)
+		return models.User{}, models.TokenPair{}, ErrInvalidData.SetPlace(op)
 	}
 
 	user, err := u.GetOrCreateUserByMaxID(ctx, maxUserID)
 	if err != nil {
-		return models.User{}, "", ErrInternal.SetPlace(op).SetCause(err)
+		return models.User{}, models.TokenPair{}, ErrInternal.SetPlace(op).SetCause(err)
 	}
 
-	token, err := u.jwtManager.GenerateToken(user.ID)
+	tokens, err := u.issueTokens(ctx, user.ID)
 	if err != nil {
-		return models.User{}, "", ErrInvalidData.SetPlace(op).SetCause(err)
+		return models.User{}, models.TokenPair{}, ErrInternal.SetPlace(op).SetCause(err)
 	}
 
-	return user, token, nil
+	return user, tokens, nil
 }
 
 func (u *Usecase) GetOrCreateUserByMaxID(ctx context.Context, maxUserID string) (models.User, error) {
@@ -138,23 +190,18 @@ func (u *Usecase) GetContextsByUserID(ctx context.Context, userIDStr string) ([]
 	return contexts, nil
 }
 
-func (u *Usecase) GetContextByID(ctx context.Context, contextIDStr string) (models.Context, error) {
+func (u *Usecase) GetContextByID(ctx context.Context, userIDStr, contextIDStr string) (models.Context, error) {
 	const op = "usecase.GetContextByID"
 
-	contextID, err := models.ParseContextID(contextIDStr)
-	if err != nil {
-		return models.Context{}, ErrInvalidData.SetPlace(op).SetCause(err)
-	}
-
-	context, err := u.repo.GetContextByID(ctx, contextID)
+	context, err := u.getOwnContext(ctx, userIDStr, contextIDStr)
 	if err != nil {
-		return models.Context{}, handleRepositoryError(op, err)
+		return models.Context{}, handleOwnershipError(op, err)
 	}
 
 	return context, nil
 }
 
-func (u *Usecase) UpdateContext(ctx context.Context, contextIDStr string, contextType string, title, description, color *string, subjectID *string, deadlineAt *string) (models.Context, error) {
+func (u *Usecase) UpdateContext(ctx context.Context, userIDStr, contextIDStr string, contextType string, title, description, color *string, subjectID *string, deadlineAt *string) (models.Context, error) {
 	const op = "usecase.UpdateContext"
 
 	var contextTypeCleaned *models.ContextType
@@ -163,21 +210,21 @@ func (u *Usecase) UpdateContext(ctx context.Context, contextIDStr string, contex
 		contextTypeCleaned = &ct
 	}
 
-	contextID, err := models.ParseContextID(contextIDStr)
+	context, err := u.getOwnContext(ctx, userIDStr, contextIDStr)
 	if err != nil {
-		return models.Context{}, ErrInvalidData.SetPlace(op).SetCause(err)
+		return models.Context{}, handleOwnershipError(op, err)
 	}
 
-	context, err := u.repo.GetContextByID(ctx, contextID)
-	if err != nil {
-		return models.Context{}, handleRepositoryError(op, err)
-	}
-
-	// Обновляем только переданные поля
+	// Обновляем только переданные поля; пустой deadline_at снимает дедлайн
 	if contextTypeCleaned != nil {
-		context.Type = *contextTypeCleaned
+		if err = context.SetType(*contextTypeCleaned); err != nil {
+			return models.Context{}, ErrInvalidData.SetPlace(op).SetCause(err)
+		}
 	}
 	if title != nil {
+		if *title == "" {
+			return models.Context{}, ErrInvalidData.SetPlace(op).SetCause(models.ErrInvalidContextTitle)
+		}
 		context.Title = *title
 	}
 	if description != nil {
@@ -189,7 +236,9 @@ func (u *Usecase) UpdateContext(ctx context.Context, contextIDStr string, contex
 	if subjectID != nil {
 		context.SubjectID = subjectID
 	}
-	if deadlineAt != nil {
+	if deadlineAt != nil && *deadlineAt == "" {
+		context.DeadlineAt = nil
+	} else if deadlineAt != nil {
 		t, err := time.Parse(time.RFC3339, *deadlineAt)
 		if err != nil {
 			return models.Context{}, ErrInvalidData.SetPlace(op).SetCause(err)
@@ -206,26 +255,51 @@ func (u *Usecase) UpdateContext(ctx context.Context, contextIDStr string, contex
 	return context, nil
 }
 
-func (u *Usecase) DeleteContext(ctx context.Context, contextIDStr string) error {
+func (u *Usecase) DeleteContext(ctx context.Context, userIDStr, contextIDStr string) error {
 	const op = "usecase.DeleteContext"
 
-	contextID, err := models.ParseContextID(contextIDStr)
+	context, err := u.getOwnContext(ctx, userIDStr, contextIDStr)
 	if err != nil {
-		return ErrInvalidData.SetPlace(op).SetCause(err)
+		return handleOwnershipError(op, err)
 	}
 
-	if err := u.repo.DeleteContext(ctx, contextID); err != nil {
+	if err = u.repo.DeleteContext(ctx, context.UserID, context.ID); err != nil {
 		return handleRepositoryError(op, err)
 	}
 
 	return nil
 }
 
+// getOwnContext загружает контекст и проверяет, что он принадлежит пользователю.
+// Чужой контекст неотличим от несуществующего.
+func (u *Usecase) getOwnContext(ctx context.Context, userIDStr, contextIDStr string) (models.Context, error) {
+	userID, err := models.ParseUserID(userIDStr)
+	if err != nil {
+		return models.Context{}, ErrInvalidData.SetCause(err)
+	}
+
+	contextID, err := models.ParseContextID(contextIDStr)
+	if err != nil {
+		return models.Context{}, ErrInvalidData.SetCause(err)
+	}
+
+	context, err := u.repo.GetContextByID(ctx, contextID)
+	if err != nil {
+		return models.Context{}, err
+	}
+
+	if context.UserID != userID {
+		return models.Context{}, ErrNotFound.SetCause(errForeignResource)
+	}
+
+	return context, nil
+}
+
 // ===========================
 // Task use cases
 // ===========================
 
-func (u *Usecase) CreateTask(ctx context.Context, userIDStr string, contextID *string, title, description string, dueAt *string) (models.Task, error) {
+func (u *Usecase) CreateTask(ctx context.Context, userIDStr string, contextID *string, title, description string, dueAt *string, recurrence *models.Recurrence, priority models.TaskPriority, tags []string) (models.Task, error) {
 	const op = "usecase.CreateTask"
 
 	userID, err := models.ParseUserID(userIDStr)
@@ -234,29 +308,58 @@ func (u *Usecase) CreateTask(ctx context.Context, userIDStr string, contextID *s
 	}
 
 	var contextIDCleaned *models.ContextID
-	if contextID != nil {
-		cid, err := models.ParseContextID(*contextID)
-		if err == nil {
-			contextIDCleaned = &cid
+	if contextID != nil && *contextID != "" {
+		cid, err := u.getOwnContextID(ctx, userID, *contextID)
+		if err != nil {
+			return models.Task{}, handleOwnershipError(op, err)
 		}
+		contextIDCleaned = &cid
+	}
+
+	task, err := models.NewTask(userID, contextIDCleaned, title, description, nil)
+	if err != nil {
+		return models.Task{}, ErrInvalidData.SetPlace(op).SetCause(err)
 	}
 
-	var dueAtCleaned *time.Time
-	if dueAt != nil {
-		t, err := time.Parse(time.RFC3339, *dueAt)
-		if err == nil {
-			dueAtCleaned = &t
+	if dueAt != nil && *dueAt != "" {
+		settings, err := u.userSettings(ctx, userID)
+		if err != nil {
+			return models.Task{}, handleRepositoryError(op, err)
+		}
+		// Нераспознанный срок, как и раньше, просто не устанавливается
+		if t, allDay, err := parseTaskDue(*dueAt, settings.Location()); err == nil {
+			task.SetDue(&t, allDay)
 		}
 	}
 
-	task, err := models.NewTask(userID, contextIDCleaned, title, description, dueAtCleaned)
+	if err = task.SetRecurrence(recurrence); err != nil {
+		return models.Task{}, ErrInvalidData.SetPlace(op).SetCause(err)
+	}
+
+	if err = task.SetPriority(priority); err != nil {
+		return models.Task{}, ErrInvalidData.SetPlace(op).SetCause(err)
+	}
+
+	tagNames, err := models.NormalizeTagNames(tags)
 	if err != nil {
 		return models.Task{}, ErrInvalidData.SetPlace(op).SetCause(err)
 	}
 
-	if err = u.repo.CreateTask(ctx, task); err != nil {
+	// Задача, ее теги и напоминания сохраняются вместе: при ошибке повтор запроса не создаст дубликат
+	err = u.repo.WithinTransaction(ctx, func(ctx context.Context) error {
+		if err := u.repo.CreateTask(ctx, task); err != nil {
+			return err
+		}
+		if err := u.attachTags(ctx, task, tagNames); err != nil {
+			return err
+		}
+		return u.scheduleTaskReminders(ctx, task)
+	})
+	if err != nil {
 		return models.Task{}, handleRepositoryError(op, err)
 	}
+	// Теги возвращаются в том же порядке, что и при чтении из БД
+	task.Tags = slices.Sorted(slices.Values(tagNames))
 
 	return task, nil
 }
@@ -277,6 +380,45 @@ func (u *Usecase) GetTasksByUserID(ctx context.Context, userIDStr string) ([]mod
 	return tasks, nil
 }
 
+// ListTasks возвращает задачи пользователя, подходящие под filter, в порядке filter.Sort
+// (по умолчанию - сначала новые). При filter.Limit > 0 выдача постраничная:
+// NextCursor указывает на продолжение и пуст на последней странице.
+func (u *Usecase) ListTasks(ctx context.Context, userIDStr string, filter models.TaskFilter) (models.TaskPage, error) {
+	const op = "usecase.ListTasks"
+
+	userID, err := models.ParseUserID(userIDStr)
+	if err != nil {
+		return models.TaskPage{}, ErrInvalidData.SetPlace(op).SetCause(err)
+	}
+
+	if err = filter.Validate(); err != nil {
+		return models.TaskPage{}, ErrInvalidData.SetPlace(op).SetCause(err)
+	}
+
+	// Запрашиваем на одну задачу больше, чтобы узнать, есть ли следующая страница
+	query := filter
+	if filter.Limit > 0 {
+		query.Limit = filter.Limit + 1
+	}
+
+	tasks, err := u.repo.ListTasks(ctx, userID, query)
+	if err != nil {
+		return models.TaskPage{}, handleRepositoryError(op, err)
+	}
+
+	page := models.TaskPage{Tasks: tasks}
+	if filter.Limit > 0 && len(tasks) > filter.Limit {
+		page.Tasks = tasks[:filter.Limit]
+		page.NextCursor = models.NewTaskCursor(page.Tasks[filter.Limit-1], filter.Sort).String()
+	}
+	if page.Tasks == nil {
+		page.Tasks = []models.Task{}
+	}
+
+	return page, nil
+}
+
+// GetTasksDueToday возвращает задачи с дедлайном в текущий день по часовому поясу пользователя
 func (u *Usecase) GetTasksDueToday(ctx context.Context, userIDStr string) ([]models.Task, error) {
 	const op = "usecase.GetTasksDueToday"
 
@@ -285,7 +427,19 @@ func (u *Usecase) GetTasksDueToday(ctx context.Context, userIDStr string) ([]mod
 		return nil, ErrInvalidData.SetPlace(op).SetCause(err)
 	}
 
-	tasks, err := u.repo.GetTasksDueToday(ctx, userID)
+	settings, err := u.userSettings(ctx, userID)
+	if err != nil {
+		return nil, handleRepositoryError(op, err)
+	}
+
+	dayStart := settings.StartOfDay(u.now())
+	dayEnd := dayStart.AddDate(0, 0, 1)
+
+	tasks, err := u.repo.ListTasks(ctx, userID, models.TaskFilter{
+		DueFrom: &dayStart,
+		DueTo:   &dayEnd,
+		Sort:    models.TaskSortDue,
+	})
 	if err != nil {
 		return nil, handleRepositoryError(op, err)
 	}
@@ -293,40 +447,47 @@ func (u *Usecase) GetTasksDueToday(ctx context.Context, userIDStr string) ([]mod
 	return tasks, nil
 }
 
-func (u *Usecase) GetTaskByID(ctx context.Context, taskIDStr string) (models.Task, error) {
+func (u *Usecase) GetTaskByID(ctx context.Context, userIDStr, taskIDStr string) (models.Task, error) {
 	const op = "usecase.GetTaskByID"
 
-	taskID, err := models.ParseTaskID(taskIDStr)
+	task, err := u.getOwnTask(ctx, userIDStr, taskIDStr)
 	if err != nil {
-		return models.Task{}, ErrInvalidData.SetPlace(op).SetCause(err)
-	}
-
-	task, err := u.repo.GetTaskByID(ctx, taskID)
-	if err != nil {
-		return models.Task{}, handleRepositoryError(op, err)
+		return models.Task{}, handleOwnershipError(op, err)
 	}
 
 	return task, nil
 }
 
-func (u *Usecase) UpdateTask(ctx context.Context, taskIDStr string, contextID *string, title, description *string, dueAt *string, status *models.TaskStatus) (models.Task, error) {
-	const op = "usecase.UpdateTask"
+// parseTaskDue разбирает срок задачи: RFC 3339 - срок к определенному времени,
+// дата YYYY-MM-DD - срок на весь день в часовом поясе пользователя loc
+func parseTaskDue(value string, loc *time.Location) (time.Time, bool, error) {
+	if t, err := time.Parse(time.RFC3339, value); err == nil {
+		return t, false, nil
+	}
 
-	taskID, err := models.ParseTaskID(taskIDStr)
+	date, err := time.Parse(models.DueDateLayout, value)
 	if err != nil {
-		return models.Task{}, ErrInvalidData.SetPlace(op).SetCause(err)
+		return time.Time{}, false, err
 	}
+	return models.AllDayDue(date, loc), true, nil
+}
 
-	task, err := u.repo.GetTaskByID(ctx, taskID)
+// UpdateTask обновляет переданные поля задачи. Правило повторения без частоты снимает повторение.
+func (u *Usecase) UpdateTask(ctx context.Context, userIDStr, taskIDStr string, contextID *string, title, description *string, dueAt *string, status *models.TaskStatus, recurrence *models.Recurrence, priority *models.TaskPriority) (models.Task, error) {
+	const op = "usecase.UpdateTask"
+
+	task, err := u.getOwnTask(ctx, userIDStr, taskIDStr)
 	if err != nil {
-		return models.Task{}, handleRepositoryError(op, err)
+		return models.Task{}, handleOwnershipError(op, err)
 	}
 
-	// Обновляем только переданные поля
-	if contextID != nil {
-		cid, err := models.ParseContextID(*contextID)
+	// Обновляем только переданные поля; пустые context_id и due_at снимают контекст и дедлайн
+	if contextID != nil && *contextID == "" {
+		task.ContextID = nil
+	} else if contextID != nil {
+		cid, err := u.getOwnContextID(ctx, task.UserID, *contextID)
 		if err != nil {
-			return models.Task{}, ErrInvalidData.SetPlace(op).SetCause(err)
+			return models.Task{}, handleOwnershipError(op, err)
 		}
 		task.ContextID = &cid
 	}
@@ -336,61 +497,152 @@ func (u *Usecase) UpdateTask(ctx context.Context, taskIDStr string, contextID *s
 	if description != nil {
 		task.Description = *description
 	}
-	if dueAt != nil {
-		t, err := time.Parse(time.RFC3339, *dueAt)
+	if dueAt != nil && *dueAt == "" {
+		task.SetDue(nil, false)
+	} else if dueAt != nil {
+		settings, err := u.userSettings(ctx, task.UserID)
+		if err != nil {
+			return models.Task{}, handleRepositoryError(op, err)
+		}
+		t, allDay, err := parseTaskDue(*dueAt, settings.Location())
 		if err != nil {
 			return models.Task{}, ErrInvalidData.SetPlace(op).SetCause(err)
 		}
-		task.DueAt = &t
+		task.SetDue(&t, allDay)
+	}
+	if recurrence != nil {
+		rule := recurrence
+		if rule.Frequency == "" {
+			rule = nil
+		}
+		if err = task.SetRecurrence(rule); err != nil {
+			return models.Task{}, ErrInvalidData.SetPlace(op).SetCause(err)
+		}
 	}
+	if priority != nil {
+		if err = task.SetPriority(*priority); err != nil {
+			return models.Task{}, ErrInvalidData.SetPlace(op).SetCause(err)
+		}
+	}
+	// Дедлайн снят, а повторение осталось
+	if task.Recurrence != nil && task.DueAt == nil {
+		return models.Task{}, ErrInvalidData.SetPlace(op).SetCause(models.ErrRecurrenceWithoutDueDate)
+	}
+
+	var next *models.Task
 	if status != nil {
-		if err = task.ChangeStatus(*status); err != nil {
+		settings, err := u.userSettings(ctx, task.UserID)
+		if err != nil {
+			return models.Task{}, handleRepositoryError(op, err)
+		}
+		if next, err = u.changeTaskStatus(&task, *status, settings.Location()); err != nil {
 			return models.Task{}, ErrInvalidData.SetPlace(op).SetCause(err)
 		}
 	}
 
 	task.UpdatedAt = time.Now()
 
-	if err = u.repo.UpdateTask(ctx, task); err != nil {
+	if err = u.saveTask(ctx, task, next); err != nil {
 		return models.Task{}, handleRepositoryError(op, err)
 	}
 
 	return task, nil
 }
 
-func (u *Usecase) UpdateTaskStatus(ctx context.Context, taskIDStr string, status models.TaskStatus) error {
+func (u *Usecase) UpdateTaskStatus(ctx context.Context, userIDStr, taskIDStr string, status models.TaskStatus) error {
 	const op = "usecase.UpdateTaskStatus"
 
-	taskID, err := models.ParseTaskID(taskIDStr)
+	task, err := u.getOwnTask(ctx, userIDStr, taskIDStr)
 	if err != nil {
-		return ErrInvalidData.SetPlace(op).SetCause(err)
+		return handleOwnershipError(op, err)
 	}
 
-	task, err := u.repo.GetTaskByID(ctx, taskID)
+	settings, err := u.userSettings(ctx, task.UserID)
 	if err != nil {
 		return handleRepositoryError(op, err)
 	}
 
-	if err = task.ChangeStatus(status); err != nil {
+	next, err := u.changeTaskStatus(&task, status, settings.Location())
+	if err != nil {
 		return ErrInvalidData.SetPlace(op).SetCause(err)
 	}
 
-	if err = u.repo.UpdateTask(ctx, task); err != nil {
+	if err = u.saveTask(ctx, task, next); err != nil {
 		return handleRepositoryError(op, err)
 	}
 
 	return nil
 }
 
-func (u *Usecase) DeleteTask(ctx context.Context, taskIDStr string) error {
+// saveTask в одной транзакции сохраняет измененную задачу, пересоздает ее напоминания
+// и создает следующее вхождение повторяющейся задачи, если оно есть
+func (u *Usecase) saveTask(ctx context.Context, task models.Task, next *models.Task) error {
+	return u.repo.WithinTransaction(ctx, func(ctx context.Context) error {
+		if err := u.repo.UpdateTask(ctx, task); err != nil {
+			return err
+		}
+		if err := u.scheduleTaskReminders(ctx, task); err != nil {
+			return err
+		}
+		return u.createNextOccurrence(ctx, task.ID, next)
+	})
+}
+
+// changeTaskStatus меняет статус задачи. При завершении повторяющейся задачи
+// возвращает ее следующее вхождение, которое нужно сохранить после самой задачи.
+// Дни недели и месяца повторения считаются в часовом поясе пользователя loc.
+func (u *Usecase) changeTaskStatus(task *models.Task, status models.TaskStatus, loc *time.Location) (*models.Task, error) {
+	wasCompleted := task.Status == models.TaskStatusCompleted
+
+	if err := task.ChangeStatus(status); err != nil {
+		return nil, err
+	}
+
+	if wasCompleted || status != models.TaskStatusCompleted {
+		return nil, nil
+	}
+
+	next, ok := task.NextOccurrence(u.now().In(loc))
+	if !ok {
+		return nil, nil
+	}
+
+	return &next, nil
+}
+
+// createNextOccurrence сохраняет следующее вхождение повторяющейся задачи,
+// переносит в него чек-лист и планирует напоминания
+func (u *Usecase) createNextOccurrence(ctx context.Context, prevID models.TaskID, next *models.Task) error {
+	if next == nil {
+		return nil
+	}
+
+	if err := u.repo.CreateTask(ctx, *next); err != nil {
+		return err
+	}
+
+	if err := u.copySubtasks(ctx, prevID, next.ID); err != nil {
+		return err
+	}
+
+	// Теги хранятся отдельно от задачи, поэтому привязываем их к новому вхождению явно
+	if err := u.attachTags(ctx, models.Task{ID: next.ID, UserID: next.UserID}, next.Tags); err != nil {
+		return err
+	}
+
+	return u.scheduleTaskReminders(ctx, *next)
+}
+
+func (u *Usecase) DeleteTask(ctx context.Context, userIDStr, taskIDStr string) error {
 	const op = "usecase.DeleteTask"
 
-	taskID, err := models.ParseTaskID(taskIDStr)
+	task, err := u.getOwnTask(ctx, userIDStr, taskIDStr)
 	if err != nil {
-		return ErrInvalidData.SetPlace(op).SetCause(err)
+		return handleOwnershipError(op, err)
 	}
 
-	if err := u.repo.DeleteTask(ctx, taskID); err != nil {
+	// Напоминания задачи удаляются каскадно (notifications.task_id ON DELETE CASCADE)
+	if err = u.repo.DeleteTask(ctx, task.UserID, task.ID); err != nil {
 		return handleRepositoryError(op, err)
 	}
 
@@ -413,6 +665,7 @@ func (u *Usecase) Search(ctx context.Context, userIDStr string, query string) (m
 		return map[string]interface{}{
 			"tasks":    []models.Task{},
 			"contexts": []models.Context{},
+			"notes":    []models.Note{},
 		}, nil
 	}
 
@@ -428,8 +681,15 @@ func (u *Usecase) Search(ctx context.Context, userIDStr string, query string) (m
 		return nil, handleRepositoryError(op, err)
 	}
 
+	// Поиск заметок
+	notes, err := u.repo.SearchNotes(ctx, userID, query)
+	if err != nil {
+		return nil, handleRepositoryError(op, err)
+	}
+
 	return map[string]interface{}{
 		"tasks":    tasks,
 		"contexts": contexts,
+		"notes":    notes,
 	}, nil
 }
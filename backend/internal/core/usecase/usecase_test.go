@@ -0,0 +1,493 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"maps"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/singl3focus/uniflow/internal/core/models"
+	"github.com/singl3focus/uniflow/internal/core/ports/repository"
+)
+
+// fakeRepo - in-memory реализация нужной тестам части repository.Repository.
+// Вызов нереализованного метода приводит к панике из-за nil встраиваемого интерфейса.
+type fakeRepo struct {
+	repository.Repository
+
+	users         map[models.UserID]models.User
+	nextDigests   map[models.UserID]map[models.DigestKind]time.Time
+	tasks         map[models.TaskID]models.Task
+	notifications map[models.NotificationID]models.Notification
+	schedule      map[models.ScheduleEntryID]models.ScheduleEntry
+	contexts      map[models.ContextID]models.Context
+	notes         map[models.NoteID]models.Note
+	focus         map[models.FocusSessionID]models.FocusSession
+	subtasks      map[models.SubtaskID]models.Subtask
+	tags          map[models.TagID]models.Tag
+	refreshTokens map[models.RefreshTokenID]models.RefreshToken
+	revokedAccess map[string]time.Time
+
+	notificationErr error // Ошибка, которую вернет CreateNotification
+	refreshTokenErr error // Ошибка, которую вернет CreateRefreshToken
+}
+
+func newFakeRepo() *fakeRepo {
+	return &fakeRepo{
+		users:         make(map[models.UserID]models.User),
+		nextDigests:   make(map[models.UserID]map[models.DigestKind]time.Time),
+		tasks:         make(map[models.TaskID]models.Task),
+		notifications: make(map[models.NotificationID]models.Notification),
+		schedule:      make(map[models.ScheduleEntryID]models.ScheduleEntry),
+		contexts:      make(map[models.ContextID]models.Context),
+		notes:         make(map[models.NoteID]models.Note),
+		focus:         make(map[models.FocusSessionID]models.FocusSession),
+		subtasks:      make(map[models.SubtaskID]models.Subtask),
+		tags:          make(map[models.TagID]models.Tag),
+		refreshTokens: make(map[models.RefreshTokenID]models.RefreshToken),
+		revokedAccess: make(map[string]time.Time),
+	}
+}
+
+func newTestUsecase(repo *fakeRepo, now time.Time) *Usecase {
+	uc := NewUsecase(repo, nil)
+	uc.now = func() time.Time { return now }
+	return uc
+}
+
+// WithinTransaction откатывает пользователей, задачи, теги, фокус-сессии, уведомления
+// и refresh-токены, если fn вернула ошибку
+func (r *fakeRepo) WithinTransaction(ctx context.Context, fn func(ctx context.Context) error) error {
+	users, tasks, tags := maps.Clone(r.users), maps.Clone(r.tasks), maps.Clone(r.tags)
+	focus, notifications, refreshTokens := maps.Clone(r.focus), maps.Clone(r.notifications), maps.Clone(r.refreshTokens)
+	if err := fn(ctx); err != nil {
+		r.users, r.tasks, r.tags = users, tasks, tags
+		r.focus, r.notifications, r.refreshTokens = focus, notifications, refreshTokens
+		return err
+	}
+	return nil
+}
+
+func (r *fakeRepo) CreateUser(_ context.Context, user models.User) error {
+	r.users[user.ID] = user
+	return nil
+}
+
+func (r *fakeRepo) GetUserByID(_ context.Context, id models.UserID) (models.User, error) {
+	user, ok := r.users[id]
+	if !ok {
+		return models.User{}, repository.ErrNotFound.SetCause(errors.New("user not found"))
+	}
+	return user, nil
+}
+
+func (r *fakeRepo) UpdateUserSettings(_ context.Context, id models.UserID, settings models.UserSettings, updatedAt time.Time) error {
+	user, ok := r.users[id]
+	if !ok {
+		return repository.ErrNotFound.SetCause(errors.New("user not found"))
+	}
+	user.Settings = settings
+	user.UpdatedAt = updatedAt
+	r.users[id] = user
+	return nil
+}
+
+func (r *fakeRepo) SetNextDigestAt(_ context.Context, id models.UserID, kind models.DigestKind, at *time.Time) error {
+	if _, ok := r.users[id]; !ok {
+		return repository.ErrNotFound.SetCause(errors.New("user not found"))
+	}
+	if r.nextDigests[id] == nil {
+		r.nextDigests[id] = make(map[models.DigestKind]time.Time)
+	}
+	if at == nil {
+		delete(r.nextDigests[id], kind)
+	} else {
+		r.nextDigests[id][kind] = *at
+	}
+	return nil
+}
+
+func (r *fakeRepo) CreateTask(_ context.Context, task models.Task) error {
+	// Как и в postgres, теги не сохраняются вместе с задачей, а привязываются через AddTagToTask
+	task.Tags = nil
+	r.tasks[task.ID] = task
+	return nil
+}
+
+func (r *fakeRepo) GetTaskByID(_ context.Context, id models.TaskID) (models.Task, error) {
+	task, ok := r.tasks[id]
+	if !ok {
+		return models.Task{}, repository.ErrNotFound.SetCause(errors.New("task not found"))
+	}
+	return task, nil
+}
+
+func (r *fakeRepo) GetTasksByUserID(_ context.Context, userID models.UserID) ([]models.Task, error) {
+	var res []models.Task
+	for _, task := range r.tasks {
+		if task.UserID == userID {
+			res = append(res, task)
+		}
+	}
+	sort.Slice(res, func(i, j int) bool { return res[i].CreatedAt.After(res[j].CreatedAt) })
+	return res, nil
+}
+
+// ListTasks повторяет в памяти фильтрацию и keyset-пагинацию postgres.ListTasks.
+// Курсор ищется по ID задачи, поэтому задача курсора должна оставаться в хранилище.
+func (r *fakeRepo) ListTasks(_ context.Context, userID models.UserID, f models.TaskFilter) ([]models.Task, error) {
+	var res []models.Task
+	for _, task := range r.tasks {
+		switch {
+		case task.UserID != userID,
+			len(f.Statuses) > 0 && !slices.Contains(f.Statuses, task.Status),
+			len(f.Priorities) > 0 && !slices.Contains(f.Priorities, task.Priority),
+			f.ContextID != nil && (task.ContextID == nil || *task.ContextID != *f.ContextID),
+			f.InboxOnly && task.ContextID != nil,
+			f.Tag != "" && !slices.Contains(task.Tags, f.Tag),
+			f.DueFrom != nil && (task.DueAt == nil || task.DueAt.Before(*f.DueFrom)),
+			f.DueTo != nil && (task.DueAt == nil || !task.DueAt.Before(*f.DueTo)),
+			f.Text != "" && !strings.Contains(strings.ToLower(task.Title+" "+task.Description), strings.ToLower(f.Text)):
+			continue
+		}
+		res = append(res, task)
+	}
+
+	sort.Slice(res, func(i, j int) bool { return res[i].CreatedAt.After(res[j].CreatedAt) })
+	models.SortTasks(res, f.Sort)
+
+	if f.Cursor != nil {
+		for i, task := range res {
+			if task.ID == f.Cursor.ID {
+				res = res[i+1:]
+				break
+			}
+		}
+	}
+	if f.Limit > 0 && len(res) > f.Limit {
+		res = res[:f.Limit]
+	}
+	return res, nil
+}
+
+// UpdateTask, как и postgres, обновляет задачу только в пределах ее владельца
+func (r *fakeRepo) UpdateTask(_ context.Context, task models.Task) error {
+	stored, ok := r.tasks[task.ID]
+	if !ok || stored.UserID != task.UserID {
+		return repository.ErrNotFound.SetCause(errors.New("task not found"))
+	}
+	r.tasks[task.ID] = task
+	return nil
+}
+
+func (r *fakeRepo) DeleteTask(_ context.Context, userID models.UserID, id models.TaskID) error {
+	stored, ok := r.tasks[id]
+	if !ok || stored.UserID != userID {
+		return repository.ErrNotFound.SetCause(errors.New("task not found"))
+	}
+	delete(r.tasks, id)
+	return nil
+}
+
+func (r *fakeRepo) CreateNotification(_ context.Context, n models.Notification) error {
+	if r.notificationErr != nil {
+		return r.notificationErr
+	}
+	r.notifications[n.ID] = n
+	return nil
+}
+
+func (r *fakeRepo) DeletePendingNotificationsByTaskID(_ context.Context, taskID models.TaskID) error {
+	for id, n := range r.notifications {
+		if n.TaskID != nil && *n.TaskID == taskID && n.IsUnsent() {
+			delete(r.notifications, id)
+		}
+	}
+	return nil
+}
+
+func (r *fakeRepo) CreateScheduleEntry(_ context.Context, entry models.ScheduleEntry) error {
+	r.schedule[entry.ID] = entry
+	return nil
+}
+
+func (r *fakeRepo) GetScheduleEntryByID(_ context.Context, id models.ScheduleEntryID) (models.ScheduleEntry, error) {
+	entry, ok := r.schedule[id]
+	if !ok {
+		return models.ScheduleEntry{}, repository.ErrNotFound.SetCause(errors.New("schedule entry not found"))
+	}
+	return entry, nil
+}
+
+func (r *fakeRepo) GetScheduleEntriesByWeekday(_ context.Context, userID models.UserID, weekday models.Weekday) ([]models.ScheduleEntry, error) {
+	var res []models.ScheduleEntry
+	for _, entry := range r.schedule {
+		if entry.UserID == userID && entry.Weekday == weekday {
+			res = append(res, entry)
+		}
+	}
+	return res, nil
+}
+
+func (r *fakeRepo) UpdateScheduleEntry(_ context.Context, entry models.ScheduleEntry) error {
+	r.schedule[entry.ID] = entry
+	return nil
+}
+
+func (r *fakeRepo) DeleteScheduleEntry(_ context.Context, id models.ScheduleEntryID) error {
+	delete(r.schedule, id)
+	return nil
+}
+
+func (r *fakeRepo) CreateContext(_ context.Context, c models.Context) error {
+	r.contexts[c.ID] = c
+	return nil
+}
+
+func (r *fakeRepo) GetContextByID(_ context.Context, id models.ContextID) (models.Context, error) {
+	c, ok := r.contexts[id]
+	if !ok {
+		return models.Context{}, repository.ErrNotFound.SetCause(errors.New("context not found"))
+	}
+	return c, nil
+}
+
+func (r *fakeRepo) UpdateContext(_ context.Context, c models.Context) error {
+	stored, ok := r.contexts[c.ID]
+	if !ok || stored.UserID != c.UserID {
+		return repository.ErrNotFound.SetCause(errors.New("context not found"))
+	}
+	r.contexts[c.ID] = c
+	return nil
+}
+
+func (r *fakeRepo) DeleteContext(_ context.Context, userID models.UserID, id models.ContextID) error {
+	stored, ok := r.contexts[id]
+	if !ok || stored.UserID != userID {
+		return repository.ErrNotFound.SetCause(errors.New("context not found"))
+	}
+	delete(r.contexts, id)
+	return nil
+}
+
+func (r *fakeRepo) CreateNote(_ context.Context, note models.Note) error {
+	r.notes[note.ID] = note
+	return nil
+}
+
+func (r *fakeRepo) GetNoteByID(_ context.Context, id models.NoteID) (models.Note, error) {
+	note, ok := r.notes[id]
+	if !ok {
+		return models.Note{}, repository.ErrNotFound.SetCause(errors.New("note not found"))
+	}
+	return note, nil
+}
+
+func (r *fakeRepo) UpdateNote(_ context.Context, note models.Note) error {
+	r.notes[note.ID] = note
+	return nil
+}
+
+func (r *fakeRepo) CreateFocusSession(_ context.Context, session models.FocusSession) error {
+	r.focus[session.ID] = session
+	return nil
+}
+
+func (r *fakeRepo) GetFocusSessionByID(_ context.Context, id models.FocusSessionID) (models.FocusSession, error) {
+	session, ok := r.focus[id]
+	if !ok {
+		return models.FocusSession{}, repository.ErrNotFound.SetCause(errors.New("focus session not found"))
+	}
+	return session, nil
+}
+
+func (r *fakeRepo) GetActiveFocusSession(_ context.Context, userID models.UserID, now time.Time) (models.FocusSession, error) {
+	for _, session := range r.focus {
+		if session.UserID == userID && session.IsActive(now) {
+			return session, nil
+		}
+	}
+	return models.FocusSession{}, repository.ErrNotFound.SetCause(errors.New("no active focus session"))
+}
+
+func (r *fakeRepo) LockUserFocusSessions(context.Context, models.UserID) error {
+	return nil
+}
+
+func (r *fakeRepo) UpdateFocusSession(_ context.Context, session models.FocusSession) error {
+	r.focus[session.ID] = session
+	return nil
+}
+
+func (r *fakeRepo) DeletePendingNotificationsByFocusSessionID(_ context.Context, sessionID models.FocusSessionID) error {
+	for id, n := range r.notifications {
+		if n.FocusSessionID != nil && *n.FocusSessionID == sessionID && n.IsUnsent() {
+			delete(r.notifications, id)
+		}
+	}
+	return nil
+}
+
+func (r *fakeRepo) CreateSubtask(_ context.Context, subtask models.Subtask) error {
+	r.subtasks[subtask.ID] = subtask
+	return nil
+}
+
+func (r *fakeRepo) GetSubtaskByID(_ context.Context, id models.SubtaskID) (models.Subtask, error) {
+	subtask, ok := r.subtasks[id]
+	if !ok {
+		return models.Subtask{}, repository.ErrNotFound.SetCause(errors.New("subtask not found"))
+	}
+	return subtask, nil
+}
+
+func (r *fakeRepo) GetSubtasksByTaskID(_ context.Context, taskID models.TaskID) ([]models.Subtask, error) {
+	var res []models.Subtask
+	for _, s := range r.subtasks {
+		if s.TaskID == taskID {
+			res = append(res, s)
+		}
+	}
+	sort.Slice(res, func(i, j int) bool { return res[i].Position < res[j].Position })
+	return res, nil
+}
+
+func (r *fakeRepo) UpdateSubtask(_ context.Context, subtask models.Subtask) error {
+	r.subtasks[subtask.ID] = subtask
+	return nil
+}
+
+func (r *fakeRepo) DeleteSubtask(_ context.Context, id models.SubtaskID) error {
+	delete(r.subtasks, id)
+	return nil
+}
+
+func (r *fakeRepo) CreateTag(_ context.Context, tag models.Tag) error {
+	for _, t := range r.tags {
+		if t.UserID == tag.UserID && t.Name == tag.Name {
+			return repository.ErrAlreadyExists
+		}
+	}
+	r.tags[tag.ID] = tag
+	return nil
+}
+
+func (r *fakeRepo) GetTagByID(_ context.Context, id models.TagID) (models.Tag, error) {
+	tag, ok := r.tags[id]
+	if !ok {
+		return models.Tag{}, repository.ErrNotFound.SetCause(errors.New("tag not found"))
+	}
+	return tag, nil
+}
+
+func (r *fakeRepo) GetTagByName(_ context.Context, userID models.UserID, name string) (models.Tag, error) {
+	for _, tag := range r.tags {
+		if tag.UserID == userID && tag.Name == name {
+			return tag, nil
+		}
+	}
+	return models.Tag{}, repository.ErrNotFound.SetCause(errors.New("tag not found"))
+}
+
+// AddTagToTask хранит связи прямо в Task.Tags, как их собирает подзапрос в postgres
+func (r *fakeRepo) AddTagToTask(_ context.Context, taskID models.TaskID, tagID models.TagID) error {
+	task := r.tasks[taskID]
+	name := r.tags[tagID].Name
+	for _, t := range task.Tags {
+		if t == name {
+			return nil
+		}
+	}
+	task.Tags = append(task.Tags, name)
+	sort.Strings(task.Tags)
+	r.tasks[taskID] = task
+	return nil
+}
+
+func (r *fakeRepo) RemoveTagFromTask(_ context.Context, taskID models.TaskID, tagID models.TagID) error {
+	task := r.tasks[taskID]
+	name := r.tags[tagID].Name
+	tags := task.Tags[:0]
+	for _, t := range task.Tags {
+		if t != name {
+			tags = append(tags, t)
+		}
+	}
+	task.Tags = tags
+	r.tasks[taskID] = task
+	return nil
+}
+
+func (r *fakeRepo) CreateRefreshToken(_ context.Context, token models.RefreshToken) error {
+	if r.refreshTokenErr != nil {
+		return r.refreshTokenErr
+	}
+	r.refreshTokens[token.ID] = token
+	return nil
+}
+
+func (r *fakeRepo) GetRefreshTokenByHash(_ context.Context, tokenHash string) (models.RefreshToken, error) {
+	for _, token := range r.refreshTokens {
+		if token.TokenHash == tokenHash {
+			return token, nil
+		}
+	}
+	return models.RefreshToken{}, repository.ErrNotFound.SetCause(errors.New("refresh token not found"))
+}
+
+func (r *fakeRepo) RevokeRefreshToken(_ context.Context, id models.RefreshTokenID, revokedAt time.Time) error {
+	token, ok := r.refreshTokens[id]
+	if !ok || token.RevokedAt != nil {
+		return repository.ErrNotFound.SetCause(errors.New("refresh token not found"))
+	}
+	token.RevokedAt = &revokedAt
+	r.refreshTokens[id] = token
+	return nil
+}
+
+func (r *fakeRepo) RevokeUserRefreshTokens(_ context.Context, userID models.UserID, revokedAt time.Time) error {
+	for id, token := range r.refreshTokens {
+		if token.UserID == userID && token.RevokedAt == nil {
+			token.RevokedAt = &revokedAt
+			r.refreshTokens[id] = token
+		}
+	}
+	return nil
+}
+
+func (r *fakeRepo) RevokeAccessToken(_ context.Context, tokenID string, expiresAt time.Time) error {
+	r.revokedAccess[tokenID] = expiresAt
+	return nil
+}
+
+func (r *fakeRepo) IsAccessTokenRevoked(_ context.Context, tokenID string) (bool, error) {
+	_, ok := r.revokedAccess[tokenID]
+	return ok, nil
+}
+
+func (r *fakeRepo) DeleteExpiredAuthTokens(_ context.Context, now time.Time) error {
+	for id, token := range r.refreshTokens {
+		if token.ExpiresAt.Before(now) {
+			delete(r.refreshTokens, id)
+		}
+	}
+	for id, expiresAt := range r.revokedAccess {
+		if expiresAt.Before(now) {
+			delete(r.revokedAccess, id)
+		}
+	}
+	return nil
+}
+
+func (r *fakeRepo) taskNotifications(taskID models.TaskID) []models.Notification {
+	var res []models.Notification
+	for _, n := range r.notifications {
+		if n.TaskID != nil && *n.TaskID == taskID {
+			res = append(res, n)
+		}
+	}
+	return res
+}
@@ -0,0 +1,118 @@
+package workers
+
+import (
+	"context"
+	"time"
+
+	"github.com/singl3focus/uniflow/internal/core/models"
+	"github.com/singl3focus/uniflow/internal/core/ports/notifier"
+	"github.com/singl3focus/uniflow/internal/core/ports/repository"
+	"github.com/singl3focus/uniflow/pkg/logger"
+)
+
+// DigestComposer собирает содержимое сводки; реализуется usecase
+type DigestComposer interface {
+	ComposeDailyDigest(ctx context.Context, user models.User, kind models.DigestKind, now time.Time) (models.DailyDigest, error)
+}
+
+// DigestSchedulerConfig - параметры планировщика ежедневных сводок
+type DigestSchedulerConfig struct {
+	PollInterval time.Duration // Период проверки, кому пора отправить сводку
+	BatchSize    int           // Сколько пользователей захватывать за один проход
+	Lease        time.Duration // Через сколько сводку повторит другая реплика, если захватившая упадет
+}
+
+func DefaultDigestSchedulerConfig() DigestSchedulerConfig {
+	return DigestSchedulerConfig{
+		PollInterval: time.Minute,
+		BatchSize:    50,
+		Lease:        10 * time.Minute,
+	}
+}
+
+// DigestScheduler рассылает утренние сводки и вечерние обзоры в выбранное пользователями время
+type DigestScheduler struct {
+	repo     repository.UserRepository
+	composer DigestComposer
+	sender   notifier.DigestSender
+	log      logger.Logger
+	cfg      DigestSchedulerConfig
+	now      func() time.Time
+}
+
+func NewDigestScheduler(repo repository.UserRepository, composer DigestComposer, sender notifier.DigestSender, log logger.Logger, cfg DigestSchedulerConfig) *DigestScheduler {
+	return &DigestScheduler{
+		repo:     repo,
+		composer: composer,
+		sender:   sender,
+		log:      log,
+		cfg:      cfg,
+		now:      time.Now,
+	}
+}
+
+// Run рассылает наступившие сводки до отмены ctx
+func (s *DigestScheduler) Run(ctx context.Context) error {
+	ticker := time.NewTicker(s.cfg.PollInterval)
+	defer ticker.Stop()
+
+	for {
+		for _, kind := range models.DigestKinds {
+			// Забираем пачки, пока не разошлем все наступившие сводки этого вида
+			for {
+				n, err := s.SendDueOnce(ctx, kind)
+				if err != nil {
+					s.log.Error("failed to send digests", "error", err, "kind", kind)
+					break
+				}
+				if n < s.cfg.BatchSize || ctx.Err() != nil {
+					break
+				}
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
+
+// SendDueOnce захватывает одну пачку пользователей, которым пора отправить сводку вида kind,
+// и отправляет ее. Возвращает количество захваченных пользователей.
+func (s *DigestScheduler) SendDueOnce(ctx context.Context, kind models.DigestKind) (int, error) {
+	now := s.now()
+
+	users, err := s.repo.ClaimDueDigests(ctx, kind, now, now.Add(s.cfg.Lease), s.cfg.BatchSize)
+	if err != nil {
+		return 0, err
+	}
+
+	for _, user := range users {
+		s.deliver(ctx, user, kind, now)
+	}
+
+	return len(users), nil
+}
+
+func (s *DigestScheduler) deliver(ctx context.Context, user models.User, kind models.DigestKind, now time.Time) {
+	log := s.log.With("user_id", user.ID.String(), "kind", kind)
+
+	// Сводка за прошедший день бесполезна, поэтому неудачная отправка не повторяется:
+	// следующая попытка будет уже в следующий день
+	digest, err := s.composer.ComposeDailyDigest(ctx, user, kind, now)
+	if err == nil {
+		err = s.sender.SendDailySummary(ctx, user, digest)
+	}
+	if err != nil {
+		log.Error("failed to send daily digest", "error", err)
+	}
+
+	// Как и у диспетчера уведомлений, расписание сохраняется даже при отмене ctx,
+	// иначе сводка уйдет повторно после истечения аренды
+	next := user.Settings.NextDigestAt(kind, now)
+	if err := s.repo.SetNextDigestAt(context.WithoutCancel(ctx), user.ID, kind, next); err != nil {
+		log.Error("failed to schedule next digest", "error", err)
+	}
+}
@@ -0,0 +1,91 @@
+package workers
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/singl3focus/uniflow/internal/core/models"
+	"github.com/singl3focus/uniflow/internal/core/ports/repository"
+)
+
+type fakeDigestRepo struct {
+	repository.UserRepository
+
+	due  []models.User
+	next map[models.UserID]*time.Time
+}
+
+func (r *fakeDigestRepo) ClaimDueDigests(_ context.Context, _ models.DigestKind, _, _ time.Time, limit int) ([]models.User, error) {
+	claimed := r.due[:min(limit, len(r.due))]
+	r.due = r.due[len(claimed):]
+	return claimed, nil
+}
+
+func (r *fakeDigestRepo) SetNextDigestAt(_ context.Context, id models.UserID, _ models.DigestKind, at *time.Time) error {
+	r.next[id] = at
+	return nil
+}
+
+type fakeDigestComposer struct{}
+
+func (fakeDigestComposer) ComposeDailyDigest(_ context.Context, _ models.User, kind models.DigestKind, now time.Time) (models.DailyDigest, error) {
+	return models.DailyDigest{Kind: kind, Date: now}, nil
+}
+
+type fakeDigestSender struct {
+	err  error
+	sent int
+}
+
+func (s *fakeDigestSender) SendDailySummary(context.Context, models.User, models.DailyDigest) error {
+	s.sent++
+	return s.err
+}
+
+func TestDigestScheduler_SendDueOnce(t *testing.T) {
+	// 05:00 UTC - 08:00 в Москве, время утренней сводки
+	now := time.Date(2025, 3, 10, 5, 0, 0, 0, time.UTC)
+	at := "08:00"
+	wantNext := time.Date(2025, 3, 11, 5, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name    string
+		sendErr error
+	}{
+		{name: "sent digest is scheduled for the next day"},
+		{name: "failed digest is not retried today", sendErr: errors.New("network error")},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			user := models.User{ID: uuid.New(), MaxUserID: "42", Settings: models.DefaultUserSettings()}
+			user.Settings.MorningDigestAt = &at
+
+			repo := &fakeDigestRepo{due: []models.User{user}, next: make(map[models.UserID]*time.Time)}
+			sender := &fakeDigestSender{err: tt.sendErr}
+
+			s := NewDigestScheduler(repo, fakeDigestComposer{}, sender, nopLogger{}, DefaultDigestSchedulerConfig())
+			s.now = func() time.Time { return now }
+
+			claimed, err := s.SendDueOnce(context.Background(), models.DigestKindMorning)
+			if err != nil {
+				t.Fatalf("SendDueOnce() error = %v", err)
+			}
+			if claimed != 1 || sender.sent != 1 {
+				t.Fatalf("claimed = %d, sent = %d, want 1 and 1", claimed, sender.sent)
+			}
+
+			next, ok := repo.next[user.ID]
+			if !ok || next == nil {
+				t.Fatalf("next digest was not scheduled")
+			}
+			if !next.Equal(wantNext) {
+				t.Errorf("next digest at = %v, want %v", next.UTC(), wantNext)
+			}
+		})
+	}
+}
@@ -0,0 +1,159 @@
+package workers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/singl3focus/uniflow/internal/core/models"
+	"github.com/singl3focus/uniflow/internal/core/ports/notifier"
+	"github.com/singl3focus/uniflow/internal/core/ports/repository"
+	"github.com/singl3focus/uniflow/pkg/logger"
+)
+
+// DispatcherRepository - данные, необходимые диспетчеру уведомлений
+type DispatcherRepository interface {
+	repository.NotificationRepository
+	repository.UserRepository
+}
+
+// DispatcherConfig - параметры диспетчера уведомлений
+type DispatcherConfig struct {
+	PollInterval time.Duration // Период опроса таблицы уведомлений
+	BatchSize    int           // Сколько уведомлений захватывать за один проход
+	MaxAttempts  int           // После стольких неудачных попыток уведомление помечается failed
+	Lease        time.Duration // На сколько захватывается уведомление одной репликой
+	RetryBase    time.Duration // Базовая задержка экспоненциального backoff
+	RetryMax     time.Duration // Максимальная задержка между попытками
+}
+
+func DefaultDispatcherConfig() DispatcherConfig {
+	return DispatcherConfig{
+		PollInterval: 15 * time.Second,
+		BatchSize:    50,
+		MaxAttempts:  5,
+		Lease:        2 * time.Minute,
+		RetryBase:    30 * time.Second,
+		RetryMax:     time.Hour,
+	}
+}
+
+var errNoSender = errors.New("no sender configured for channel")
+
+// NotificationDispatcher периодически забирает наступившие уведомления
+// и доставляет их через отправщика соответствующего канала
+type NotificationDispatcher struct {
+	repo    DispatcherRepository
+	senders map[models.NotificationChannel]notifier.Sender
+	log     logger.Logger
+	cfg     DispatcherConfig
+	now     func() time.Time
+}
+
+func NewNotificationDispatcher(repo DispatcherRepository, senders map[models.NotificationChannel]notifier.Sender, log logger.Logger, cfg DispatcherConfig) *NotificationDispatcher {
+	return &NotificationDispatcher{
+		repo:    repo,
+		senders: senders,
+		log:     log,
+		cfg:     cfg,
+		now:     time.Now,
+	}
+}
+
+// Run опрашивает очередь уведомлений до отмены ctx
+func (d *NotificationDispatcher) Run(ctx context.Context) error {
+	ticker := time.NewTicker(d.cfg.PollInterval)
+	defer ticker.Stop()
+
+	for {
+		// Забираем пачки, пока очередь не опустеет, затем ждем следующего тика
+		for {
+			n, err := d.DispatchOnce(ctx)
+			if err != nil {
+				d.log.Error("failed to dispatch notifications", "error", err)
+				break
+			}
+			if n < d.cfg.BatchSize || ctx.Err() != nil {
+				break
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
+
+// DispatchOnce захватывает одну пачку уведомлений и пытается их доставить.
+// Возвращает количество захваченных уведомлений.
+func (d *NotificationDispatcher) DispatchOnce(ctx context.Context) (int, error) {
+	now := d.now()
+
+	notifications, err := d.repo.ClaimDueNotifications(ctx, now, now.Add(d.cfg.Lease), d.cfg.BatchSize)
+	if err != nil {
+		return 0, err
+	}
+
+	for _, n := range notifications {
+		d.deliver(ctx, n)
+	}
+
+	return len(notifications), nil
+}
+
+func (d *NotificationDispatcher) deliver(ctx context.Context, n models.Notification) {
+	log := d.log.With("notification_id", n.ID.String(), "attempt", n.Attempts)
+
+	err := d.send(ctx, n)
+	switch {
+	case err == nil:
+		n.MarkAsSent()
+	case errors.Is(err, errNoSender) || n.Attempts >= d.cfg.MaxAttempts:
+		log.Error("notification delivery failed permanently", "error", err)
+		n.MarkAsFailed(err.Error())
+	default:
+		retryAt := d.now().Add(RetryBackoff(n.Attempts, d.cfg.RetryBase, d.cfg.RetryMax))
+		log.Warn("notification delivery failed, will retry", "error", err, "retry_at", retryAt)
+		n.ScheduleRetry(retryAt, err.Error())
+	}
+
+	// Сохраняем результат даже если ctx уже отменен, иначе уведомление
+	// останется захваченным до истечения аренды и может уйти повторно
+	if err := d.repo.UpdateNotification(context.WithoutCancel(ctx), n); err != nil {
+		log.Error("failed to save notification state", "error", err, "status", n.Status)
+	}
+}
+
+func (d *NotificationDispatcher) send(ctx context.Context, n models.Notification) error {
+	sender, ok := d.senders[n.Channel]
+	if !ok {
+		return fmt.Errorf("%w: %s", errNoSender, n.Channel)
+	}
+
+	user, err := d.repo.GetUserByID(ctx, n.UserID)
+	if err != nil {
+		return fmt.Errorf("failed to get recipient: %w", err)
+	}
+
+	return sender.Send(ctx, user, n)
+}
+
+// RetryBackoff вычисляет задержку перед следующей попыткой: base * 2^(attempt-1), но не больше limit
+func RetryBackoff(attempt int, base, limit time.Duration) time.Duration {
+	if attempt < 1 {
+		attempt = 1
+	}
+
+	delay := base
+	for i := 1; i < attempt; i++ {
+		delay *= 2
+		if delay >= limit {
+			return limit
+		}
+	}
+
+	return delay
+}
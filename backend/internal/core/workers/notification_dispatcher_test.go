@@ -0,0 +1,177 @@
+package workers
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/singl3focus/uniflow/internal/core/models"
+	"github.com/singl3focus/uniflow/internal/core/ports/notifier"
+	"github.com/singl3focus/uniflow/pkg/logger"
+)
+
+type nopLogger struct{}
+
+func (nopLogger) Debug(string, ...interface{})                {}
+func (nopLogger) Info(string, ...interface{})                 {}
+func (nopLogger) Warn(string, ...interface{})                 {}
+func (nopLogger) Error(string, ...interface{})                {}
+func (nopLogger) Fatal(string, ...interface{})                {}
+func (nopLogger) Log(string, string, ...interface{})          {}
+func (nopLogger) SetLevel(string) error                       { return nil }
+func (nopLogger) Shutdown() error                             { return nil }
+func (nopLogger) Flush() error                                { return nil }
+func (l nopLogger) With(...interface{}) logger.Logger         { return l }
+func (l nopLogger) WithContext(context.Context) logger.Logger { return l }
+
+type fakeDispatcherRepo struct {
+	DispatcherRepository
+
+	due     []models.Notification
+	updated map[models.NotificationID]models.Notification
+}
+
+func (r *fakeDispatcherRepo) ClaimDueNotifications(_ context.Context, _, lockedUntil time.Time, limit int) ([]models.Notification, error) {
+	claimed := r.due[:min(limit, len(r.due))]
+	r.due = r.due[len(claimed):]
+	for i := range claimed {
+		claimed[i].Status = models.NotificationStatusProcessing
+		claimed[i].LockedUntil = &lockedUntil
+		claimed[i].Attempts++
+	}
+	return claimed, nil
+}
+
+func (r *fakeDispatcherRepo) UpdateNotification(_ context.Context, n models.Notification) error {
+	r.updated[n.ID] = n
+	return nil
+}
+
+func (r *fakeDispatcherRepo) GetUserByID(_ context.Context, id models.UserID) (models.User, error) {
+	return models.User{ID: id, MaxUserID: "42"}, nil
+}
+
+type fakeSender struct {
+	err  error
+	sent int
+}
+
+func (s *fakeSender) Send(context.Context, models.User, models.Notification) error {
+	s.sent++
+	return s.err
+}
+
+func TestNotificationDispatcher_DispatchOnce(t *testing.T) {
+	now := time.Date(2025, 3, 10, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name          string
+		channel       models.NotificationChannel
+		prevAttempts  int
+		sendErr       error
+		wantStatus    models.NotificationStatus
+		wantNotifyAt  time.Time
+		wantLastError bool
+	}{
+		{
+			name:         "successful delivery marks notification as sent",
+			channel:      models.NotificationChannelMax,
+			wantStatus:   models.NotificationStatusSent,
+			wantNotifyAt: now,
+		},
+		{
+			name:          "transient error schedules retry with backoff",
+			channel:       models.NotificationChannelMax,
+			prevAttempts:  1,
+			sendErr:       errors.New("network error"),
+			wantStatus:    models.NotificationStatusPending,
+			wantNotifyAt:  now.Add(2 * time.Minute),
+			wantLastError: true,
+		},
+		{
+			name:          "last attempt marks notification as failed",
+			channel:       models.NotificationChannelMax,
+			prevAttempts:  2,
+			sendErr:       errors.New("network error"),
+			wantStatus:    models.NotificationStatusFailed,
+			wantNotifyAt:  now,
+			wantLastError: true,
+		},
+		{
+			name:          "unknown channel fails without retry",
+			channel:       "sms",
+			wantStatus:    models.NotificationStatusFailed,
+			wantNotifyAt:  now,
+			wantLastError: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			n := models.NewNotification(uuid.New(), nil, now, tt.channel, "test")
+			n.Attempts = tt.prevAttempts
+
+			repo := &fakeDispatcherRepo{
+				due:     []models.Notification{n},
+				updated: make(map[models.NotificationID]models.Notification),
+			}
+			sender := &fakeSender{err: tt.sendErr}
+
+			cfg := DefaultDispatcherConfig()
+			cfg.MaxAttempts = 3
+			cfg.RetryBase = time.Minute
+
+			d := NewNotificationDispatcher(repo, map[models.NotificationChannel]notifier.Sender{
+				models.NotificationChannelMax: sender,
+			}, nopLogger{}, cfg)
+			d.now = func() time.Time { return now }
+
+			claimed, err := d.DispatchOnce(context.Background())
+			if err != nil {
+				t.Fatalf("DispatchOnce() error = %v", err)
+			}
+			if claimed != 1 {
+				t.Fatalf("DispatchOnce() claimed = %d, want 1", claimed)
+			}
+
+			got, ok := repo.updated[n.ID]
+			if !ok {
+				t.Fatalf("notification state was not saved")
+			}
+			if got.Status != tt.wantStatus {
+				t.Errorf("status = %v, want %v", got.Status, tt.wantStatus)
+			}
+			if !got.NotifyAt.Equal(tt.wantNotifyAt) {
+				t.Errorf("notify_at = %v, want %v", got.NotifyAt, tt.wantNotifyAt)
+			}
+			if (got.LastError != "") != tt.wantLastError {
+				t.Errorf("last_error = %q, want set = %v", got.LastError, tt.wantLastError)
+			}
+			if got.LockedUntil != nil {
+				t.Errorf("locked_until should be released, got %v", got.LockedUntil)
+			}
+		})
+	}
+}
+
+func TestRetryBackoff(t *testing.T) {
+	tests := []struct {
+		attempt int
+		want    time.Duration
+	}{
+		{attempt: 0, want: 30 * time.Second},
+		{attempt: 1, want: 30 * time.Second},
+		{attempt: 2, want: time.Minute},
+		{attempt: 4, want: 4 * time.Minute},
+		{attempt: 20, want: 10 * time.Minute},
+	}
+
+	for _, tt := range tests {
+		if got := RetryBackoff(tt.attempt, 30*time.Second, 10*time.Minute); got != tt.want {
+			t.Errorf("RetryBackoff(%d) = %v, want %v", tt.attempt, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,74 @@
+package workers
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/singl3focus/uniflow/pkg/logger"
+)
+
+const (
+	restartDelayMin = time.Second
+	restartDelayMax = time.Minute
+)
+
+// Supervisor запускает фоновые воркеры, перезапускает их после ошибки или паники
+// и дожидается их завершения при остановке приложения
+type Supervisor struct {
+	log logger.Logger
+	wg  sync.WaitGroup
+}
+
+func NewSupervisor(log logger.Logger) *Supervisor {
+	return &Supervisor{log: log}
+}
+
+// Go запускает воркер в отдельной горутине. Воркер работает, пока не отменен ctx.
+func (s *Supervisor) Go(ctx context.Context, name string, run func(ctx context.Context) error) {
+	s.wg.Add(1)
+
+	go func() {
+		defer s.wg.Done()
+
+		log := s.log.With("worker", name)
+		delay := restartDelayMin
+
+		for {
+			log.Info("worker started")
+			err := s.runSafe(ctx, run)
+
+			if ctx.Err() != nil {
+				log.Info("worker stopped")
+				return
+			}
+
+			log.Error("worker exited unexpectedly, restarting", "error", err, "delay", delay.String())
+
+			select {
+			case <-ctx.Done():
+				log.Info("worker stopped")
+				return
+			case <-time.After(delay):
+			}
+
+			delay = min(delay*2, restartDelayMax)
+		}
+	}()
+}
+
+// Wait блокируется до завершения всех запущенных воркеров
+func (s *Supervisor) Wait() {
+	s.wg.Wait()
+}
+
+func (s *Supervisor) runSafe(ctx context.Context, run func(ctx context.Context) error) (err error) {
+	defer func() {
+		if rec := recover(); rec != nil {
+			err = fmt.Errorf("panic: %v", rec)
+		}
+	}()
+
+	return run(ctx)
+}
@@ -0,0 +1,21 @@
+-- +goose Up
+
+-- Поля для доставки уведомлений воркером: счетчик попыток, последняя ошибка
+-- и аренда (lease), защищающая строку от повторной выдачи другой репликой.
+ALTER TABLE uniflow.notifications
+    ADD COLUMN IF NOT EXISTS attempts     INTEGER NOT NULL DEFAULT 0,
+    ADD COLUMN IF NOT EXISTS last_error   TEXT,
+    ADD COLUMN IF NOT EXISTS locked_until TIMESTAMPTZ;
+
+CREATE INDEX IF NOT EXISTS idx_notifications_due
+    ON uniflow.notifications(notify_at)
+    WHERE status IN ('pending', 'processing');
+
+-- +goose Down
+
+DROP INDEX IF EXISTS uniflow.idx_notifications_due;
+
+ALTER TABLE uniflow.notifications
+    DROP COLUMN IF EXISTS locked_until,
+    DROP COLUMN IF EXISTS last_error,
+    DROP COLUMN IF EXISTS attempts;
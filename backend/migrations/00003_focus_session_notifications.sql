@@ -0,0 +1,18 @@
+-- +goose Up
+
+-- Уведомление о завершении фокус-сессии хранится в общей очереди уведомлений,
+-- поэтому таймер переживает перезапуск сервера. При досрочной остановке
+-- сессии ожидающее уведомление удаляется по focus_session_id.
+ALTER TABLE uniflow.notifications
+    ADD COLUMN IF NOT EXISTS focus_session_id UUID REFERENCES uniflow.focus_sessions(id) ON DELETE CASCADE;
+
+CREATE INDEX IF NOT EXISTS idx_notifications_focus_session_id
+    ON uniflow.notifications(focus_session_id)
+    WHERE focus_session_id IS NOT NULL;
+
+-- +goose Down
+
+DROP INDEX IF EXISTS uniflow.idx_notifications_focus_session_id;
+
+ALTER TABLE uniflow.notifications
+    DROP COLUMN IF EXISTS focus_session_id;
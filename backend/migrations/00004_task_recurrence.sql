@@ -0,0 +1,12 @@
+-- +goose Up
+
+-- Правило повторения задачи (models.Recurrence) в виде JSON:
+-- {"frequency": "weekly", "interval": 1, "weekdays": [0, 2], "until": "..."}.
+-- NULL - задача не повторяется.
+ALTER TABLE uniflow.tasks
+    ADD COLUMN IF NOT EXISTS recurrence JSONB;
+
+-- +goose Down
+
+ALTER TABLE uniflow.tasks
+    DROP COLUMN IF EXISTS recurrence;
@@ -0,0 +1,18 @@
+-- +goose Up
+
+-- Пункты чек-листа задачи. Удаляются вместе с задачей.
+CREATE TABLE IF NOT EXISTS uniflow.subtasks (
+    id         UUID PRIMARY KEY,
+    task_id    UUID NOT NULL REFERENCES uniflow.tasks(id) ON DELETE CASCADE,
+    title      VARCHAR(255) NOT NULL,
+    done       BOOLEAN NOT NULL DEFAULT FALSE,
+    position   INTEGER NOT NULL DEFAULT 0,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_subtasks_task_id ON uniflow.subtasks(task_id, position);
+
+-- +goose Down
+
+DROP TABLE IF EXISTS uniflow.subtasks;
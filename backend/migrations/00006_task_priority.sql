@@ -0,0 +1,29 @@
+-- +goose Up
+
+ALTER TABLE uniflow.tasks
+    ADD COLUMN IF NOT EXISTS priority VARCHAR(20) NOT NULL DEFAULT 'normal';
+
+-- +goose StatementBegin
+DO $$
+BEGIN
+    IF NOT EXISTS (
+        SELECT 1 FROM pg_constraint
+        WHERE conname = 'tasks_priority_check' AND conrelid = 'uniflow.tasks'::regclass
+    ) THEN
+        ALTER TABLE uniflow.tasks
+            ADD CONSTRAINT tasks_priority_check CHECK (priority IN ('low', 'normal', 'high', 'urgent'));
+    END IF;
+END $$;
+-- +goose StatementEnd
+
+CREATE INDEX IF NOT EXISTS idx_tasks_priority ON uniflow.tasks(user_id, priority);
+
+-- +goose Down
+
+DROP INDEX IF EXISTS uniflow.idx_tasks_priority;
+
+ALTER TABLE uniflow.tasks
+    DROP CONSTRAINT IF EXISTS tasks_priority_check;
+
+ALTER TABLE uniflow.tasks
+    DROP COLUMN IF EXISTS priority;
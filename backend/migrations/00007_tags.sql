@@ -0,0 +1,23 @@
+-- +goose Up
+
+CREATE TABLE IF NOT EXISTS uniflow.tags (
+    id         UUID PRIMARY KEY,
+    user_id    UUID NOT NULL REFERENCES uniflow.users(id) ON DELETE CASCADE,
+    name       VARCHAR(32) NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    UNIQUE (user_id, name)
+);
+
+-- Связь многие-ко-многим между задачами и тегами
+CREATE TABLE IF NOT EXISTS uniflow.task_tags (
+    task_id UUID NOT NULL REFERENCES uniflow.tasks(id) ON DELETE CASCADE,
+    tag_id  UUID NOT NULL REFERENCES uniflow.tags(id) ON DELETE CASCADE,
+    PRIMARY KEY (task_id, tag_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_task_tags_tag_id ON uniflow.task_tags(tag_id);
+
+-- +goose Down
+
+DROP TABLE IF EXISTS uniflow.task_tags;
+DROP TABLE IF EXISTS uniflow.tags;
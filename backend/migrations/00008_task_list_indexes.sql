@@ -0,0 +1,10 @@
+-- +goose Up
+
+-- Индексы под постраничную выдачу задач: порядок по умолчанию (created_at, id) и фильтр по дедлайну
+CREATE INDEX IF NOT EXISTS idx_tasks_user_created ON uniflow.tasks(user_id, created_at DESC, id DESC);
+CREATE INDEX IF NOT EXISTS idx_tasks_user_due ON uniflow.tasks(user_id, due_at);
+
+-- +goose Down
+
+DROP INDEX IF EXISTS uniflow.idx_tasks_user_due;
+DROP INDEX IF EXISTS uniflow.idx_tasks_user_created;
@@ -0,0 +1,28 @@
+-- +goose Up
+
+CREATE TABLE IF NOT EXISTS uniflow.refresh_tokens (
+    id                UUID PRIMARY KEY,
+    user_id           UUID NOT NULL REFERENCES uniflow.users(id) ON DELETE CASCADE,
+    token_hash        TEXT NOT NULL UNIQUE,
+    access_token_id   TEXT NOT NULL,
+    access_expires_at TIMESTAMPTZ NOT NULL,
+    expires_at        TIMESTAMPTZ NOT NULL,
+    revoked_at        TIMESTAMPTZ,
+    created_at        TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_refresh_tokens_user_id ON uniflow.refresh_tokens(user_id);
+CREATE INDEX IF NOT EXISTS idx_refresh_tokens_expires_at ON uniflow.refresh_tokens(expires_at);
+
+-- Отозванные до истечения срока access-токены (по jti); строка не нужна после expires_at
+CREATE TABLE IF NOT EXISTS uniflow.revoked_access_tokens (
+    token_id   TEXT PRIMARY KEY,
+    expires_at TIMESTAMPTZ NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS idx_revoked_access_tokens_expires_at ON uniflow.revoked_access_tokens(expires_at);
+
+-- +goose Down
+
+DROP TABLE IF EXISTS uniflow.revoked_access_tokens;
+DROP TABLE IF EXISTS uniflow.refresh_tokens;
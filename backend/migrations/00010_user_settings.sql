@@ -0,0 +1,33 @@
+-- +goose Up
+
+-- Персональные настройки пользователя (models.UserSettings).
+-- reminder_offsets - минуты до дедлайна; NULL - напоминания по умолчанию.
+ALTER TABLE uniflow.users
+    ADD COLUMN IF NOT EXISTS timezone VARCHAR(64) NOT NULL DEFAULT 'Europe/Moscow',
+    ADD COLUMN IF NOT EXISTS language VARCHAR(8) NOT NULL DEFAULT 'ru',
+    ADD COLUMN IF NOT EXISTS first_weekday SMALLINT NOT NULL DEFAULT 0,
+    ADD COLUMN IF NOT EXISTS reminder_offsets INTEGER[];
+
+-- +goose StatementBegin
+DO $$
+BEGIN
+    IF NOT EXISTS (
+        SELECT 1 FROM pg_constraint
+        WHERE conname = 'users_first_weekday_check' AND conrelid = 'uniflow.users'::regclass
+    ) THEN
+        ALTER TABLE uniflow.users
+            ADD CONSTRAINT users_first_weekday_check CHECK (first_weekday IN (0, 6));
+    END IF;
+END $$;
+-- +goose StatementEnd
+
+-- +goose Down
+
+ALTER TABLE uniflow.users
+    DROP CONSTRAINT IF EXISTS users_first_weekday_check;
+
+ALTER TABLE uniflow.users
+    DROP COLUMN IF EXISTS reminder_offsets,
+    DROP COLUMN IF EXISTS first_weekday,
+    DROP COLUMN IF EXISTS language,
+    DROP COLUMN IF EXISTS timezone;
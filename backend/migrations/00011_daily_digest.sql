@@ -0,0 +1,31 @@
+-- +goose Up
+
+-- Ежедневные сводки (models.UserSettings.MorningDigestAt / EveningReviewAt):
+-- местное время ЧЧ:ММ, NULL - сводка выключена.
+-- next_*_at - когда планировщику отправить следующую сводку. Захват сдвигает
+-- значение на время аренды, поэтому упавшая реплика не теряет сводку насовсем,
+-- а несколько реплик не отправляют ее дважды.
+ALTER TABLE uniflow.users
+    ADD COLUMN IF NOT EXISTS morning_digest_at VARCHAR(5),
+    ADD COLUMN IF NOT EXISTS evening_review_at VARCHAR(5),
+    ADD COLUMN IF NOT EXISTS next_morning_digest_at TIMESTAMPTZ,
+    ADD COLUMN IF NOT EXISTS next_evening_review_at TIMESTAMPTZ;
+
+CREATE INDEX IF NOT EXISTS idx_users_next_morning_digest_at
+    ON uniflow.users(next_morning_digest_at)
+    WHERE next_morning_digest_at IS NOT NULL;
+
+CREATE INDEX IF NOT EXISTS idx_users_next_evening_review_at
+    ON uniflow.users(next_evening_review_at)
+    WHERE next_evening_review_at IS NOT NULL;
+
+-- +goose Down
+
+DROP INDEX IF EXISTS uniflow.idx_users_next_evening_review_at;
+DROP INDEX IF EXISTS uniflow.idx_users_next_morning_digest_at;
+
+ALTER TABLE uniflow.users
+    DROP COLUMN IF EXISTS next_evening_review_at,
+    DROP COLUMN IF EXISTS next_morning_digest_at,
+    DROP COLUMN IF EXISTS evening_review_at,
+    DROP COLUMN IF EXISTS morning_digest_at;
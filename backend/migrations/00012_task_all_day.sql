@@ -0,0 +1,15 @@
+-- +goose Up
+
+-- Срок на весь день (models.Task.AllDay): время не задано, due_at хранит 23:59 этого дня
+-- в часовом поясе пользователя, чтобы задача попадала в свой день в фильтрах по дате.
+ALTER TABLE uniflow.tasks
+    ADD COLUMN IF NOT EXISTS due_all_day BOOLEAN NOT NULL DEFAULT FALSE;
+
+-- Существующие задачи не переносятся: бот и раньше записывал дату без времени как 23:59,
+-- но у задач не хранится, откуда пришел срок, и их нельзя отличить от заданных на 23:59 явно.
+-- Такие задачи остаются со временем 23:59 и прежними напоминаниями.
+
+-- +goose Down
+
+ALTER TABLE uniflow.tasks
+    DROP COLUMN IF EXISTS due_all_day;
@@ -30,19 +30,27 @@ func NewJWTManager(secret string, ttl time.Duration) *JWTManager {
 	}
 }
 
-// GenerateToken создает JWT токен для пользователя
-func (m *JWTManager) GenerateToken(userID uuid.UUID) (string, error) {
-	claims := Claims{
+// GenerateToken создает JWT токен для пользователя.
+// Каждый токен получает уникальный jti (claims.ID), по которому его можно отозвать.
+func (m *JWTManager) GenerateToken(userID uuid.UUID) (string, *Claims, error) {
+	now := time.Now()
+	claims := &Claims{
 		UserID: userID.String(),
 		RegisteredClaims: jwt.RegisteredClaims{
-			ExpiresAt: jwt.NewNumericDate(time.Now().Add(m.ttl)),
-			IssuedAt:  jwt.NewNumericDate(time.Now()),
-			NotBefore: jwt.NewNumericDate(time.Now()),
+			ID:        uuid.NewString(),
+			ExpiresAt: jwt.NewNumericDate(now.Add(m.ttl)),
+			IssuedAt:  jwt.NewNumericDate(now),
+			NotBefore: jwt.NewNumericDate(now),
 		},
 	}
 
 	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
-	return token.SignedString([]byte(m.secret))
+	signed, err := token.SignedString([]byte(m.secret))
+	if err != nil {
+		return "", nil, err
+	}
+
+	return signed, claims, nil
 }
 
 // ValidateToken проверяет JWT токен и возвращает claims
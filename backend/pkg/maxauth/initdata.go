@@ -0,0 +1,130 @@
+package maxauth
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var (
+	ErrNotConfigured    = errors.New("bot token is not configured")
+	ErrMissingHash      = errors.New("init data is not signed")
+	ErrInvalidSignature = errors.New("invalid init data signature")
+	ErrExpired          = errors.New("init data expired")
+	ErrInvalidData      = errors.New("invalid init data")
+)
+
+// clockSkew допускает небольшое расхождение часов клиента MAX и сервера
+const clockSkew = time.Minute
+
+// WebAppUser - пользователь MAX, открывший мини-приложение
+type WebAppUser struct {
+	ID           int64  `json:"id"`
+	FirstName    string `json:"first_name"`
+	LastName     string `json:"last_name,omitempty"`
+	Username     string `json:"username,omitempty"`
+	LanguageCode string `json:"language_code,omitempty"`
+	PhotoURL     string `json:"photo_url,omitempty"`
+}
+
+// InitData - проверенные данные запуска мини-приложения (window.WebApp.initData)
+type InitData struct {
+	QueryID  string
+	AuthDate time.Time
+	User     WebAppUser
+}
+
+// Validator проверяет подпись init data, выданную MAX для бота
+type Validator struct {
+	botToken string
+	maxAge   time.Duration
+	now      func() time.Time
+}
+
+func NewValidator(botToken string, maxAge time.Duration) *Validator {
+	return &Validator{
+		botToken: botToken,
+		maxAge:   maxAge,
+		now:      time.Now,
+	}
+}
+
+// Validate проверяет HMAC-SHA256 подпись и срок давности init data и возвращает ее содержимое.
+// Ключ подписи - HMAC-SHA256("WebAppData", botToken), подписывается строка из пар
+// key=value всех параметров, кроме hash, отсортированных по ключу и разделенных '\n'.
+func (v *Validator) Validate(raw string) (InitData, error) {
+	if v.botToken == "" {
+		return InitData{}, ErrNotConfigured
+	}
+
+	values, err := url.ParseQuery(raw)
+	if err != nil {
+		return InitData{}, ErrInvalidData
+	}
+
+	hash := values.Get("hash")
+	if hash == "" {
+		return InitData{}, ErrMissingHash
+	}
+
+	expected, err := hex.DecodeString(hash)
+	if err != nil || !hmac.Equal(sign(v.botToken, dataCheckString(values)), expected) {
+		return InitData{}, ErrInvalidSignature
+	}
+
+	authUnix, err := strconv.ParseInt(values.Get("auth_date"), 10, 64)
+	if err != nil {
+		return InitData{}, ErrInvalidData
+	}
+	authDate := time.Unix(authUnix, 0)
+
+	age := v.now().Sub(authDate)
+	if age > v.maxAge || age < -clockSkew {
+		return InitData{}, ErrExpired
+	}
+
+	var user WebAppUser
+	if err = json.Unmarshal([]byte(values.Get("user")), &user); err != nil || user.ID == 0 {
+		return InitData{}, ErrInvalidData
+	}
+
+	return InitData{
+		QueryID:  values.Get("query_id"),
+		AuthDate: authDate,
+		User:     user,
+	}, nil
+}
+
+// dataCheckString собирает строку, по которой считается подпись
+func dataCheckString(values url.Values) string {
+	keys := make([]string, 0, len(values))
+	for k := range values {
+		if k != "hash" {
+			keys = append(keys, k)
+		}
+	}
+	sort.Strings(keys)
+
+	pairs := make([]string, 0, len(keys))
+	for _, k := range keys {
+		pairs = append(pairs, k+"="+values.Get(k))
+	}
+
+	return strings.Join(pairs, "\n")
+}
+
+func sign(botToken, data string) []byte {
+	secret := hmac.New(sha256.New, []byte("WebAppData"))
+	secret.Write([]byte(botToken))
+
+	mac := hmac.New(sha256.New, secret.Sum(nil))
+	mac.Write([]byte(data))
+	return mac.Sum(nil)
+}
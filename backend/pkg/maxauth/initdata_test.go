@@ -0,0 +1,77 @@
+package maxauth
+
+import (
+	"encoding/hex"
+	"errors"
+	"net/url"
+	"strconv"
+	"testing"
+	"time"
+)
+
+const testBotToken = "test-bot-token"
+
+// signedInitData строит init data так же, как ее подписывает MAX
+func signedInitData(token string, authDate time.Time, user string) string {
+	values := url.Values{}
+	values.Set("query_id", "AAH1")
+	values.Set("auth_date", strconv.FormatInt(authDate.Unix(), 10))
+	values.Set("user", user)
+	values.Set("hash", hex.EncodeToString(sign(token, dataCheckString(values))))
+	return values.Encode()
+}
+
+func TestValidator_Validate(t *testing.T) {
+	now := time.Date(2025, 3, 10, 12, 0, 0, 0, time.UTC)
+	user := `{"id":123456789,"first_name":"Иван","username":"ivan"}`
+
+	tampered, _ := url.ParseQuery(signedInitData(testBotToken, now, user))
+	tampered.Set("user", `{"id":1,"first_name":"Мэллори"}`)
+
+	unsigned, _ := url.ParseQuery(signedInitData(testBotToken, now, user))
+	unsigned.Del("hash")
+
+	tests := []struct {
+		name    string
+		raw     string
+		wantErr error
+	}{
+		{name: "valid", raw: signedInitData(testBotToken, now.Add(-time.Hour), user)},
+		{name: "unsigned", raw: unsigned.Encode(), wantErr: ErrMissingHash},
+		{name: "tampered payload", raw: tampered.Encode(), wantErr: ErrInvalidSignature},
+		{name: "foreign bot token", raw: signedInitData("other-token", now, user), wantErr: ErrInvalidSignature},
+		{name: "malformed hash", raw: "auth_date=1&hash=zz", wantErr: ErrInvalidSignature},
+		{name: "expired", raw: signedInitData(testBotToken, now.Add(-25*time.Hour), user), wantErr: ErrExpired},
+		{name: "from the future", raw: signedInitData(testBotToken, now.Add(time.Hour), user), wantErr: ErrExpired},
+		{name: "no user", raw: signedInitData(testBotToken, now, ""), wantErr: ErrInvalidData},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			v := NewValidator(testBotToken, 24*time.Hour)
+			v.now = func() time.Time { return now }
+
+			data, err := v.Validate(tt.raw)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("Validate() error = %v, want %v", err, tt.wantErr)
+			}
+			if tt.wantErr != nil {
+				return
+			}
+
+			if data.User.ID != 123456789 || data.User.Username != "ivan" {
+				t.Errorf("Validate() user = %+v", data.User)
+			}
+			if !data.AuthDate.Equal(now.Add(-time.Hour)) {
+				t.Errorf("Validate() auth date = %v", data.AuthDate)
+			}
+		})
+	}
+}
+
+func TestValidator_NotConfigured(t *testing.T) {
+	raw := signedInitData("", time.Now(), `{"id":1}`)
+	if _, err := NewValidator("", time.Hour).Validate(raw); !errors.Is(err, ErrNotConfigured) {
+		t.Errorf("Validate() error = %v, want ErrNotConfigured", err)
+	}
+}
@@ -20,6 +20,7 @@ import type {
   ContextCreate,
   ContextUpdate,
   AuthWithMAXResponse,
+  TokenPair,
   ErrorResponse,
 } from '../types/api';
 
@@ -52,7 +53,23 @@ class ApiClient {
     // Handle 401 errors
     this.client.interceptors.response.use(
       (response) => response,
-      (error: AxiosError<ErrorResponse>) => {
+      async (error: AxiosError<ErrorResponse>) => {
+        const original = error.config as (typeof error.config & { _retried?: boolean }) | undefined;
+        const refreshToken = localStorage.getItem('refresh_token');
+
+        // Access token expired: rotate tokens once and repeat the request
+        if (error.response?.status === 401 && original && !original._retried && refreshToken && !original.url?.startsWith('/api/auth/')) {
+          original._retried = true;
+          try {
+            const tokens = await this.refreshTokens(refreshToken);
+            localStorage.setItem('access_token', tokens.access_token);
+            localStorage.setItem('refresh_token', tokens.refresh_token);
+            return this.client(original);
+          } catch {
+            localStorage.removeItem('refresh_token');
+          }
+        }
+
         if (error.response?.status === 401) {
           localStorage.removeItem('access_token');
           console.error('[API] Unauthorized - token removed');
@@ -83,9 +100,9 @@ class ApiClient {
     return response.data;
   }
 
-  async loginWithMAX(max_user_id: string): Promise<AuthWithMAXResponse> {
+  async loginWithMAX(init_data: string): Promise<AuthWithMAXResponse> {
     const response = await this.client.post<AuthWithMAXResponse>('/api/auth/max', {
-      max_user_id
+      init_data
     }, {
       headers: {
         'Content-Type': 'application/json',
@@ -94,6 +111,15 @@ class ApiClient {
     return response.data;
   }
 
+  async refreshTokens(refresh_token: string): Promise<TokenPair> {
+    const response = await this.client.post<TokenPair>('/api/auth/refresh', { refresh_token });
+    return response.data;
+  }
+
+  async logoutMAX(refresh_token: string): Promise<void> {
+    await this.client.post('/api/auth/logout', { refresh_token });
+  }
+
   // Super Admin
   async createUniversity(data: UniversityCreate): Promise<University> {
     const response = await this.client.post<University>('/api/superadmin/universities/', data);
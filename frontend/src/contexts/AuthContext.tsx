@@ -1,13 +1,13 @@
 import { createContext, useContext, useState, ReactNode, useMemo, useEffect } from 'react';
 import { apiClient } from '../api/client';
-import { getMaxUserData, getMaxInitData, triggerHaptic } from '../lib/maxBridge';
+import { getMaxUserData, getMaxRawInitData, triggerHaptic } from '../lib/maxBridge';
 
 interface AuthContextType {
   isAuthenticated: boolean;
   login: (username: string, password: string) => Promise<void>;
   logout: () => void;
   token: string | null;
-  loginWithMaxId: (maxId: string) => Promise<void>;
+  loginWithMaxId: (initData: string) => Promise<void>;
   maxUser: ReturnType<typeof getMaxUserData>;
 }
 
@@ -23,15 +23,15 @@ export function AuthProvider({ children }: { children: ReactNode }) {
   useEffect(() => {
     const autoAuthWithMax = async () => {
       const userData = getMaxUserData();
-      const initData = getMaxInitData();
+      const initData = getMaxRawInitData();
       
-      if (userData && !token) {
+      if (userData && initData && !token) {
         console.log('[Auth] MAX user detected, attempting auto-login:', userData);
         setMaxUser(userData);
         
         try {
-          // Используем user ID из MAX для аутентификации
-          await loginWithMaxId(userData.id.toString());
+          // Бэкенд проверяет подпись init data и сам берет из нее user ID
+          await loginWithMaxId(initData);
           console.log('[Auth] Auto-login successful');
           triggerHaptic('success');
         } catch (error) {
@@ -53,11 +53,12 @@ export function AuthProvider({ children }: { children: ReactNode }) {
     triggerHaptic('success');
   };
 
-  const loginWithMaxId = async (maxId: string) => {
+  const loginWithMaxId = async (initData: string) => {
     try {
-      const response = await apiClient.loginWithMAX(maxId);
+      const response = await apiClient.loginWithMAX(initData);
       console.log('[Auth] Login response received:', { hasToken: !!response.access_token });
       localStorage.setItem('access_token', response.access_token);
+      localStorage.setItem('refresh_token', response.refresh_token);
       console.log('[Auth] Token saved to localStorage');
       setToken(response.access_token);
       console.log('[Auth] Token state updated');
@@ -65,6 +66,7 @@ export function AuthProvider({ children }: { children: ReactNode }) {
     } catch (e) {
       console.error('[Auth] MAX ID login error:', e);
       localStorage.removeItem('access_token');
+      localStorage.removeItem('refresh_token');
       setToken(null);
       triggerHaptic('error');
       throw e;
@@ -72,6 +74,11 @@ export function AuthProvider({ children }: { children: ReactNode }) {
   };
 
   const logout = () => {
+    const refreshToken = localStorage.getItem('refresh_token');
+    if (refreshToken) {
+      apiClient.logoutMAX(refreshToken).catch((e) => console.error('[Auth] Logout error:', e));
+    }
+    localStorage.removeItem('refresh_token');
     localStorage.removeItem('access_token');
     localStorage.removeItem('max_user_data');
     setToken(null);
@@ -118,6 +118,15 @@ export function getMaxInitData(): WebAppData | null {
   return webApp.initDataUnsafe || null;
 }
 
+/**
+ * Get raw signed init data string to send to backend for login
+ */
+export function getMaxRawInitData(): string | null {
+  const webApp = getMaxBridge();
+  if (!webApp) return null;
+  return webApp.initData || null;
+}
+
 /**
  * Trigger haptic feedback on user interaction
  */
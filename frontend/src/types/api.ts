@@ -162,13 +162,21 @@ export interface ContextUpdate {
 
 // Auth types
 export interface AuthWithMAXRequest {
-  max_user_id: string;
+  init_data: string; // window.WebApp.initData as is, validated by backend
 }
 
 export interface AuthWithMAXResponse {
   user_id: string; // UUID
   max_user_id: string;
   access_token: string; // JWT token
+  refresh_token: string; // one-time token for /api/auth/refresh
+  access_expires_at: string;
+}
+
+export interface TokenPair {
+  access_token: string;
+  refresh_token: string;
+  access_expires_at: string;
 }
 
 // Error response